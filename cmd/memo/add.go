@@ -7,9 +7,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 
+	"github.com/harper/memo/internal/dirconfig"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/notecontext"
 	"github.com/harper/memo/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -17,8 +20,20 @@ import (
 var addCmd = &cobra.Command{
 	Use:   "add <title>",
 	Short: "Add a new note",
-	Long:  `Create a new note with the given title. Content can be provided via --content, --file, or $EDITOR.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Create a new note with the given title. Content can be provided via --content, --file, or $EDITOR.
+
+Inside a project with a .memo.toml (searched upward from the current
+directory), its defaults apply: here = true tags notes with the current
+directory, tags = [...] adds default tags, and template = "..." (or
+"@file.md") seeds the editor.
+
+With record_context enabled ('memo config set record_context true'),
+notes added with --here also record the hostname and the git branch and
+commit checked out, shown by 'memo show' and filtered by 'memo list --branch'.
+
+With --quiet, only the new note's full ID is printed, for scripts:
+  id=$(memo add "Standup" --content "..." --quiet)`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		title := args[0]
 
@@ -26,10 +41,25 @@ var addCmd = &cobra.Command{
 		contentFlag, _ := cmd.Flags().GetString("content")
 		fileFlag, _ := cmd.Flags().GetString("file")
 		hereFlag, _ := cmd.Flags().GetBool("here")
+		quiet, _ := cmd.Flags().GetBool("quiet")
 
 		var content string
 		var err error
 
+		// Project defaults from the nearest .memo.toml
+		var template string
+		if pwd, wdErr := os.Getwd(); wdErr == nil {
+			projectCfg, err := dirconfig.Find(pwd)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %w", dirconfig.FileName, err)
+			}
+			if projectCfg != nil {
+				hereFlag = hereFlag || projectCfg.Here
+				tagsFlag = strings.Join(append([]string{tagsFlag}, projectCfg.Tags...), ",")
+				template = projectCfg.Template
+			}
+		}
+
 		switch {
 		case contentFlag != "":
 			content = contentFlag
@@ -40,7 +70,7 @@ var addCmd = &cobra.Command{
 			}
 			content = string(data)
 		default:
-			content, err = openEditor("")
+			content, err = openEditor(template)
 			if err != nil {
 				return fmt.Errorf("failed to open editor: %w", err)
 			}
@@ -50,14 +80,26 @@ var addCmd = &cobra.Command{
 			return fmt.Errorf("note content cannot be empty")
 		}
 
+		content = autoEnrich(content)
+
 		// Collect all tags
 		allTags := collectTags(tagsFlag, hereFlag)
 
 		note := models.NewNote(title, content)
+		if cfg := charmClient.Config(); hereFlag && cfg != nil && cfg.RecordContext {
+			if pwd, err := os.Getwd(); err == nil {
+				note.Context = notecontext.Capture(pwd)
+			}
+		}
 		if err := charmClient.CreateNote(note, allTags); err != nil {
 			return fmt.Errorf("failed to create note: %w", err)
 		}
 
+		setHookNote(note, allTags)
+		if quiet {
+			fmt.Println(note.ID)
+			return nil
+		}
 		fmt.Println(ui.Success(fmt.Sprintf("Created note %s", note.ID.String()[:6])))
 		return nil
 	},
@@ -68,26 +110,23 @@ func collectTags(tagsFlag string, hereFlag bool) []string {
 	var tags []string
 	if tagsFlag != "" {
 		for _, tag := range strings.Split(tagsFlag, ",") {
-			tag = strings.TrimSpace(tag)
-			if tag != "" {
-				tags = append(tags, strings.ToLower(tag))
+			tag = models.NormalizeTag(tag)
+			if tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
 			}
 		}
 	}
 	if hereFlag {
 		pwd, err := os.Getwd()
 		if err == nil {
-			tags = append(tags, strings.ToLower("dir:"+pwd))
+			tags = append(tags, models.NormalizeTag("dir:"+pwd))
 		}
 	}
 	return tags
 }
 
 func openEditor(initial string) (string, error) {
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = "vim"
-	}
+	editor := editorCommand()
 
 	tmpFile, err := os.CreateTemp("", "memo-*.md")
 	if err != nil {
@@ -129,5 +168,6 @@ func init() {
 	addCmd.Flags().String("content", "", "note content (inline)")
 	addCmd.Flags().String("file", "", "read content from file")
 	addCmd.Flags().Bool("here", false, "tag note with current directory")
+	addCmd.Flags().BoolP("quiet", "q", false, "print only the new note's ID")
 	rootCmd.AddCommand(addCmd)
 }
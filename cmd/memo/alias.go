@@ -0,0 +1,125 @@
+// ABOUTME: Alias command for naming notes.
+// ABOUTME: Provides set, rm, and list subcommands plus shell completion of aliases.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage note aliases",
+	Long: `Give notes short names that work anywhere an ID prefix does.
+
+Aliases are lowercase, start with a letter, and may contain digits, '-'
+and '_'. They can't be all hex digits, so they never clash with ID
+prefixes. Aliases sync with your notes and are removed when their note is
+deleted.
+
+Examples:
+  memo alias set abc123 roadmap
+  memo show roadmap
+  memo alias list`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <id-prefix> <alias>",
+	Short: "Name a note",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, _, err := getNote(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get note: %w", err)
+		}
+
+		name := charm.NormalizeAlias(args[1])
+		if err := charmClient.SetAlias(name, note.ID); err != nil {
+			return fmt.Errorf("failed to set alias: %w", err)
+		}
+
+		fmt.Println(ui.Success(fmt.Sprintf("Note %s is now %q", note.ID.String()[:6], name)))
+		return nil
+	},
+}
+
+var aliasRmCmd = &cobra.Command{
+	Use:               "rm <alias>",
+	Short:             "Remove an alias",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeAliases,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := charmClient.RemoveAlias(args[0]); err != nil {
+			return fmt.Errorf("failed to remove alias: %w", err)
+		}
+
+		fmt.Println(ui.Success(fmt.Sprintf("Removed alias %q", charm.NormalizeAlias(args[0]))))
+		return nil
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aliases, err := charmClient.ListAliases()
+		if err != nil {
+			return fmt.Errorf("failed to list aliases: %w", err)
+		}
+
+		if len(aliases) == 0 {
+			fmt.Println("No aliases found.")
+			return nil
+		}
+
+		for _, a := range aliases {
+			title := "(deleted note)"
+			if note, _, err := charmClient.GetNoteByID(a.NoteID); err == nil {
+				title = note.Title
+			}
+			fmt.Printf("%-20s %s  %s\n", a.Name, a.NoteID.String()[:6], title)
+		}
+		return nil
+	},
+}
+
+// completeAliases suggests aliases for the first argument of commands that
+// take a note.
+func completeAliases(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	client, err := charm.GetClient()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	aliases, err := client.ListAliases()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var names []string
+	for _, a := range aliases {
+		if strings.HasPrefix(a.Name, toComplete) {
+			names = append(names, a.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	showCmd.ValidArgsFunction = completeAliases
+	editCmd.ValidArgsFunction = completeAliases
+	rmCmd.ValidArgsFunction = completeAliases
+
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasRmCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
@@ -0,0 +1,202 @@
+// ABOUTME: Compressed archive format for full-fidelity backups.
+// ABOUTME: Writes and reads .tar.zst files holding metadata.json and raw attachments.
+
+package main
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/ui"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	archiveExt         = ".tar.zst"
+	archiveMetadata    = "metadata.json"
+	archiveAttachments = "attachments/"
+)
+
+// exportArchive writes notes and raw attachment files into a zstd-compressed
+// tarball. Attachments are streamed as they are read; metadata.json is
+// written last since it records each attachment's path in the archive.
+func exportArchive(entries []*exportEntry, outputPath string) error {
+	if outputPath == "" {
+		outputPath = "memo-" + time.Now().Format("2006-01-02") + archiveExt
+	}
+
+	out := os.Stdout
+	if outputPath != "-" {
+		f, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) //nolint:gosec // User-specified output path is expected CLI behavior
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }() // Backstop for early returns; Close below reports errors
+		out = f
+	}
+
+	zw, err := zstd.NewWriter(out)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(zw)
+
+	export := ExportData{ExportedAt: time.Now(), Version: "1.0"}
+	for _, e := range entries {
+		n, err := e.load()
+		if err != nil {
+			return err
+		}
+		if n == nil {
+			continue
+		}
+		attachments, _ := charmClient.ListAttachmentsByNote(n.ID)
+
+		en := ExportNote{
+			ID:        n.ID.String(),
+			Title:     n.Title,
+			Content:   n.Content,
+			Tags:      e.Tags,
+			CreatedAt: n.CreatedAt,
+			UpdatedAt: n.UpdatedAt,
+		}
+
+		for _, att := range attachments {
+			if skipMissing(att) {
+				continue
+			}
+			name := archiveAttachments + att.ID.String() + "-" + filepath.Base(att.Filename)
+			if err := writeTarFile(tw, name, att.Data, att.CreatedAt); err != nil {
+				return err
+			}
+			en.Attachments = append(en.Attachments, ExportAttachment{
+				ID:       att.ID.String(),
+				Filename: att.Filename,
+				MimeType: att.MimeType,
+				Path:     name,
+			})
+		}
+
+		export.Notes = append(export.Notes, en)
+	}
+
+	metadata, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, archiveMetadata, metadata, export.ExportedAt); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if out == os.Stdout {
+		return nil
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("Exported %d notes to %s", len(export.Notes), outputPath)))
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:     name,
+		Mode:     0600,
+		Size:     int64(len(data)),
+		ModTime:  modTime,
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// fromArchive imports an archive written by exportArchive. Attachments are
+// unpacked to a temporary directory so metadata.json can reference them.
+func (im *importer) fromArchive(archivePath string) error {
+	f, err := os.Open(archivePath) //nolint:gosec // User-specified file path is expected CLI behavior
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	tmpDir, err := os.MkdirTemp("", "memo-import-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	if err := os.Mkdir(filepath.Join(tmpDir, archiveAttachments), 0700); err != nil {
+		return err
+	}
+
+	var metadata []byte
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		switch {
+		case hdr.Name == archiveMetadata:
+			metadata, err = io.ReadAll(tr)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", archiveMetadata, err)
+			}
+		case strings.HasPrefix(hdr.Name, archiveAttachments):
+			// Only the base name is used so entries cannot escape tmpDir
+			dest := filepath.Join(tmpDir, archiveAttachments, path.Base(hdr.Name))
+			if err := extractTarFile(tr, dest); err != nil {
+				return err
+			}
+		}
+	}
+
+	if metadata == nil {
+		return fmt.Errorf("%s is not a memo archive: missing %s", archivePath, archiveMetadata)
+	}
+	return im.fromExportData(metadata, tmpDir)
+}
+
+func extractTarFile(r io.Reader, dest string) error {
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) //nolint:gosec // dest is confined to the import temp dir
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil { //nolint:gosec // Archive size is bounded by the user's own backup
+		_ = out.Close()
+		return fmt.Errorf("extract %s: %w", filepath.Base(dest), err)
+	}
+	return out.Close()
+}
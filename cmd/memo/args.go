@@ -0,0 +1,98 @@
+// ABOUTME: Resolves the notes a command acts on from its arguments.
+// ABOUTME: Accepts ID prefixes, aliases, last-list positions, a --tag filter, or --pick.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/lastlist"
+	"github.com/harper/memo/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// getNote finds a note by ID prefix or alias, or by its position in the
+// last listing when ref is a small number.
+func getNote(ref string) (*models.Note, []string, error) {
+	if n, ok := lastlist.Position(ref); ok {
+		id, err := lastlist.Lookup(charm.LastListPath(), n)
+		if err != nil {
+			return nil, nil, err
+		}
+		ref = id
+	}
+	return charmClient.GetNoteByPrefix(ref)
+}
+
+// noteArg resolves the note a command operates on: the picked note with
+// --pick, otherwise the ID prefix in args.
+func noteArg(cmd *cobra.Command, args []string) (*models.Note, []string, error) {
+	pick, _ := cmd.Flags().GetBool("pick")
+	switch {
+	case pick && len(args) > 0:
+		return nil, nil, fmt.Errorf("pass an ID prefix or --pick, not both")
+	case pick:
+		return pickNote(nil)
+	case len(args) == 0:
+		return nil, nil, fmt.Errorf("an ID prefix (or --pick) is required")
+	}
+
+	note, tags, err := getNote(args[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get note: %w", err)
+	}
+	return note, tags, nil
+}
+
+// noteArgs resolves the notes a batch command operates on: every note
+// matching the ID prefixes, plus every note with the --tag filter's tag,
+// or the picked note with --pick. Each note appears once.
+func noteArgs(cmd *cobra.Command, prefixes []string) ([]*charm.NoteWithTags, error) {
+	pick, _ := cmd.Flags().GetBool("pick")
+	tagFilter, _ := cmd.Flags().GetString("tag")
+
+	if pick {
+		if len(prefixes) > 0 || tagFilter != "" {
+			return nil, fmt.Errorf("--pick can't be combined with ID prefixes or --tag")
+		}
+		note, tags, err := pickNote(nil)
+		if err != nil {
+			return nil, err
+		}
+		return []*charm.NoteWithTags{{Note: note, Tags: tags}}, nil
+	}
+	if len(prefixes) == 0 && tagFilter == "" {
+		return nil, fmt.Errorf("at least one ID prefix (or --tag or --pick) is required")
+	}
+
+	var notes []*charm.NoteWithTags
+	seen := make(map[string]bool)
+	add := func(nt *charm.NoteWithTags) {
+		if id := nt.Note.ID.String(); !seen[id] {
+			seen[id] = true
+			notes = append(notes, nt)
+		}
+	}
+
+	for _, prefix := range prefixes {
+		note, tags, err := getNote(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get note %s: %w", prefix, err)
+		}
+		add(&charm.NoteWithTags{Note: note, Tags: tags})
+	}
+	if tagFilter != "" {
+		tagged, err := charmClient.ListNotesWithTags(&charm.NoteFilter{Tag: &tagFilter})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list notes: %w", err)
+		}
+		for _, nt := range tagged {
+			add(nt)
+		}
+		if len(tagged) == 0 && len(prefixes) == 0 {
+			return nil, fmt.Errorf("no notes tagged %q", tagFilter)
+		}
+	}
+	return notes, nil
+}
@@ -1,16 +1,20 @@
 // ABOUTME: Attach command for managing note attachments.
-// ABOUTME: Provides add and get subcommands for binary files.
+// ABOUTME: Provides add, get, largest, migrate, and import-note subcommands.
 
 package main
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/fatih/color"
+	"github.com/harper/memo/internal/charm"
 	"github.com/harper/memo/internal/models"
 	"github.com/harper/memo/internal/ui"
 	"github.com/spf13/cobra"
@@ -19,16 +23,27 @@ import (
 var attachCmd = &cobra.Command{
 	Use:   "attach <id-prefix> <file>",
 	Short: "Add an attachment to a note",
-	Args:  cobra.ExactArgs(2),
+	Long: `Add a file as an attachment to a note.
+
+Files larger than max_attachment_size (default 10 MiB, set with
+'memo config set max_attachment_size 25MB', 0 for no limit) are rejected
+unless --force is given. Use 'memo attach largest' to find what is
+taking up space.`,
+	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		prefix := args[0]
 		filePath := args[1]
+		force, _ := cmd.Flags().GetBool("force")
 
-		note, _, err := charmClient.GetNoteByPrefix(prefix)
+		note, _, err := getNote(prefix)
 		if err != nil {
 			return fmt.Errorf("failed to get note: %w", err)
 		}
 
+		if force {
+			charmClient.SetMaxAttachmentSize(0)
+		}
+
 		data, err := os.ReadFile(filePath) //nolint:gosec // User-specified file path is expected CLI behavior
 		if err != nil {
 			return fmt.Errorf("failed to read file: %w", err)
@@ -42,6 +57,9 @@ var attachCmd = &cobra.Command{
 
 		att := models.NewAttachment(note.ID, filename, mimeType, data)
 		if err := charmClient.CreateAttachment(att); err != nil {
+			if errors.Is(err, charm.ErrAttachmentTooLarge) {
+				return fmt.Errorf("%w (use --force to attach anyway)", err)
+			}
 			return fmt.Errorf("failed to create attachment: %w", err)
 		}
 
@@ -81,8 +99,148 @@ var attachGetCmd = &cobra.Command{
 	},
 }
 
+var attachImportNoteCmd = &cobra.Command{
+	Use:   "import-note <id-prefix> <file.md>",
+	Short: "Add a markdown file as a sub-note linked from a note",
+	Long: `Create a note from a text or markdown file and link it with the note:
+the note gets a link to the new one, which links back. Use this instead of
+attaching files you want to search and edit as notes.
+
+The title defaults to the filename without its extension.
+'memo note from-attachment' converts an existing attachment the same way.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		titleFlag, _ := cmd.Flags().GetString("title")
+		tagsFlag, _ := cmd.Flags().GetString("tags")
+
+		parent, parentTags, err := getNote(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get note: %w", err)
+		}
+		data, err := os.ReadFile(args[1]) //nolint:gosec // User-specified file path is expected CLI behavior
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+		if !isText(data) {
+			return fmt.Errorf("%s isn't a text file (use 'memo attach' for binary files)", args[1])
+		}
+
+		title := titleFlag
+		if title == "" {
+			name := filepath.Base(args[1])
+			title = strings.TrimSuffix(name, filepath.Ext(name))
+		}
+		sub, err := createSubNote(parent, parentTags, title, string(data), collectTags(tagsFlag, false))
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Success(fmt.Sprintf("Created note %s linked from %s", sub.ID.String()[:6], parent.ID.String()[:6])))
+		return nil
+	},
+}
+
+var attachLargestCmd = &cobra.Command{
+	Use:   "largest",
+	Short: "List the largest attachments",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		sizes, err := charmClient.LargestAttachments(limit)
+		if err != nil {
+			return fmt.Errorf("failed to list attachments: %w", err)
+		}
+		if len(sizes) == 0 {
+			fmt.Println("No attachments.")
+			return nil
+		}
+
+		for _, s := range sizes {
+			fmt.Printf("%10s  %s  note %s  %s\n",
+				ui.FormatSize(s.Size), s.ID.String()[:6], s.NoteID.String()[:6], s.Filename)
+		}
+		return nil
+	},
+}
+
+var attachMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move attachment data between KV, local blobs, and Charm FS",
+	Long: `Move attachment data between the Charm KV store, a local
+content-addressed blob store, and Charm FS, and make that the default
+for new attachments.
+
+  --to kv       data inline in KV values (default, synced with notes)
+  --to blobs    data in ` + "`$XDG_DATA_HOME/memo/blobs/<database>`" + `, metadata in KV. Blobs
+                live only on this device and are not synced.
+  --to charmfs  data uploaded to encrypted Charm FS files, metadata in KV.
+                Other devices download an attachment the first time it is
+                read and keep a local copy.
+
+Only attachments of at least --min-size are moved out of KV (default
+256KB for charmfs, everything otherwise), so small files keep syncing
+with their notes. Attachments whose data isn't on this device, like
+blobs added on another one, are skipped and listed.
+
+Examples:
+  memo attach migrate --to charmfs
+  memo attach migrate --to charmfs --min-size 5MB
+  memo attach migrate --to blobs
+  memo attach migrate --to kv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		minSizeFlag, _ := cmd.Flags().GetString("min-size")
+
+		var minSize int64
+		if minSizeFlag != "" {
+			var err error
+			if minSize, err = charm.ParseSize(minSizeFlag); err != nil {
+				return fmt.Errorf("invalid --min-size: %w", err)
+			}
+		} else if to == charm.StorageCharmFS {
+			minSize = charm.CharmFSMinSize
+		}
+
+		result, err := charmClient.MigrateAttachments(to, minSize)
+		if err != nil {
+			if result != nil && result.Moved > 0 {
+				fmt.Println(ui.Success(fmt.Sprintf("Moved %d attachments to %s storage", result.Moved, to)))
+			}
+			return fmt.Errorf("failed to migrate attachments: %w", err)
+		}
+
+		cfg, err := charm.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		cfg.AttachmentStorage = to
+		if err := charm.SaveConfig(cfg); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+
+		fmt.Println(ui.Success(fmt.Sprintf("Moved %d attachments to %s storage", result.Moved, to)))
+		if len(result.Missing) > 0 {
+			color.Yellow("  ⚠ Skipped %s whose data isn't on this device (run migrate on the device that added them): %s",
+				ui.Plural(len(result.Missing), "attachment"), strings.Join(result.Missing, ", "))
+		}
+		return nil
+	},
+}
+
 func init() {
+	attachMigrateCmd.Flags().String("to", "", "target storage: kv, blobs, or charmfs")
+	attachMigrateCmd.Flags().String("min-size", "", "only move attachments at least this big (e.g. 5MB)")
+	_ = attachMigrateCmd.MarkFlagRequired("to")
+	attachCmd.AddCommand(attachMigrateCmd)
+	attachCmd.Flags().Bool("force", false, "attach even if the file exceeds max_attachment_size")
+	attachLargestCmd.Flags().IntP("limit", "n", 10, "number of attachments to show (0 for all)")
+	attachCmd.AddCommand(attachLargestCmd)
 	attachGetCmd.Flags().StringP("output", "o", "", "output path (default: original filename)")
 	attachCmd.AddCommand(attachGetCmd)
+	attachImportNoteCmd.Flags().String("title", "", "note title (default: the filename without its extension)")
+	attachImportNoteCmd.Flags().String("tags", "", "comma-separated tags for the new note")
+	attachCmd.AddCommand(attachImportNoteCmd)
 	rootCmd.AddCommand(attachCmd)
 }
@@ -0,0 +1,129 @@
+// ABOUTME: Audit command for showing who changed a note and when.
+// ABOUTME: Lists audit log entries with the CLI command or MCP tool and client, and the device.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/harper/memo/internal/charm"
+	"github.com/spf13/cobra"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [id]",
+	Short: "Show the audit log of changes to a note",
+	Long: `Show every recorded change to a note, oldest first: when it happened,
+what changed (create, update, tag, star, status, attach, detach, delete),
+and who made it: the memo command, the MCP tool and the name of the
+client that called it, or a program using the Go library. Changes that
+arrived by sync show the host and device they were made on.
+
+The log is append-only and syncs with your notes. Entries outlive deleted
+notes; look those up by ID prefix. 'memo sync compact' deletes entries
+older than audit_retention (default one year). Without an ID, the latest changes to
+all notes are shown.
+
+Examples:
+  memo audit abc123
+  memo audit abc123 --source mcp
+  memo audit -n 50
+  memo audit abc123 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		source, _ := cmd.Flags().GetString("source")
+		jsonFlag, _ := cmd.Flags().GetBool("json")
+
+		prefix := ""
+		if len(args) == 1 {
+			note, _, err := getNote(args[0])
+			switch {
+			case err == nil:
+				prefix = note.ID.String()
+			case errors.Is(err, charm.ErrNoteNotFound):
+				prefix = args[0] // Deleted notes are only in the log
+			default:
+				return fmt.Errorf("failed to get note: %w", err)
+			}
+		}
+
+		entries, err := charmClient.AuditLog(prefix)
+		if err != nil {
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+		if prefix != "" && !sameNote(entries) {
+			return fmt.Errorf("%w: %s matches changes to several notes", charm.ErrAmbiguousPrefix, prefix)
+		}
+		if source != "" {
+			kept := entries[:0]
+			for _, e := range entries {
+				if e.Source == source {
+					kept = append(kept, e)
+				}
+			}
+			entries = kept
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+
+		if jsonFlag {
+			data, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		if len(entries) == 0 {
+			fmt.Println("No changes recorded.")
+			return nil
+		}
+
+		device, _ := charm.DeviceID()
+		for _, e := range entries {
+			fmt.Print(e.Time.Local().Format("2006-01-02 15:04:05"))
+			if prefix == "" {
+				fmt.Print("  " + color.New(color.Faint).Sprint(shortID(e.NoteID)))
+			}
+			fmt.Printf("  %-7s %s", e.Action, e.Actor)
+			if e.Detail != "" {
+				fmt.Printf("  %s", color.CyanString(e.Detail))
+			}
+			if e.Device != device {
+				fmt.Print(color.New(color.Faint).Sprintf("  on %s (%s)", e.Host, shortID(e.Device)))
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+// sameNote reports whether every entry is about one note.
+func sameNote(entries []*charm.AuditEntry) bool {
+	for _, e := range entries {
+		if e.NoteID != entries[0].NoteID {
+			return false
+		}
+	}
+	return true
+}
+
+// shortID returns the first six characters of an ID.
+func shortID(id string) string {
+	if len(id) > 6 {
+		return id[:6]
+	}
+	return id
+}
+
+func init() {
+	auditCmd.Flags().IntP("limit", "n", 0, "show only the latest n changes (default: all)")
+	auditCmd.Flags().String("source", "", "only changes from this source: cli, mcp, or library")
+	auditCmd.Flags().Bool("json", false, "print entries as JSON")
+	rootCmd.AddCommand(auditCmd)
+}
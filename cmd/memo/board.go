@@ -0,0 +1,235 @@
+// ABOUTME: Board command showing tagged notes as a terminal kanban board.
+// ABOUTME: Columns are tags; moving a card swaps its column tag for the neighbor's.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var boardCmd = &cobra.Command{
+	Use:   "board",
+	Short: "Show tagged notes as a kanban board",
+	Long: `Show notes as a kanban board with one column per tag, in the order given
+by --tags. A note with several column tags appears in the rightmost one.
+
+Keys:
+  ←/→ or h/l        move between columns
+  ↑/↓ or k/j        move within a column
+  H/L or Shift-←/→  move the selected note to the previous/next column
+  Enter             quit and print the selected note's ID
+  q or Esc          quit
+
+Moving a note replaces its column tag with the new column's, so the board
+and 'memo list --tag doing' always agree. The board draws on stderr, so
+the chosen ID can be captured.
+
+Examples:
+  memo board
+  memo board --tags backlog,doing,review,done
+  memo edit $(memo board)`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagsFlag, _ := cmd.Flags().GetString("tags")
+		var columns []string
+		for _, t := range strings.Split(tagsFlag, ",") {
+			if t = models.NormalizeTag(t); t != "" && !slices.Contains(columns, t) {
+				columns = append(columns, t)
+			}
+		}
+		if len(columns) < 2 {
+			return fmt.Errorf("a board needs at least two --tags columns")
+		}
+
+		notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{ContentLimit: charm.NoContent})
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+
+		b := newBoard(columns, notes)
+		final, err := tea.NewProgram(b, tea.WithOutput(os.Stderr), tea.WithAltScreen()).Run()
+		if err != nil {
+			return fmt.Errorf("board failed: %w", err)
+		}
+		b = final.(*board)
+		if b.err != nil {
+			return b.err
+		}
+		if b.chosen != nil {
+			fmt.Println(b.chosen.Note.ID)
+		}
+		return nil
+	},
+}
+
+// board is the bubbletea model for the kanban view.
+type board struct {
+	columns []string
+	cards   [][]*charm.NoteWithTags // Per column, most recently updated first
+	col     int
+	row     []int // Cursor row per column
+	width   int
+	height  int
+	status  string
+	chosen  *charm.NoteWithTags
+	err     error
+}
+
+func newBoard(columns []string, notes []*charm.NoteWithTags) *board {
+	b := &board{
+		columns: columns,
+		cards:   make([][]*charm.NoteWithTags, len(columns)),
+		row:     make([]int, len(columns)),
+		width:   80,
+		height:  24,
+	}
+	for _, nt := range notes {
+		if i := b.columnOf(nt.Tags); i >= 0 {
+			b.cards[i] = append(b.cards[i], nt)
+		}
+	}
+	return b
+}
+
+// columnOf returns the rightmost column whose tag is in tags, or -1.
+func (b *board) columnOf(tags []string) int {
+	for i := len(b.columns) - 1; i >= 0; i-- {
+		if slices.Contains(tags, b.columns[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (b *board) selected() *charm.NoteWithTags {
+	if len(b.cards[b.col]) == 0 {
+		return nil
+	}
+	return b.cards[b.col][b.row[b.col]]
+}
+
+// move shifts the selected note by delta columns, retagging it.
+func (b *board) move(delta int) {
+	card, target := b.selected(), b.col+delta
+	if card == nil || target < 0 || target >= len(b.columns) {
+		return
+	}
+
+	note, tags, err := charmClient.GetNoteByID(card.Note.ID)
+	if err != nil {
+		b.status = "Move failed: " + err.Error()
+		return
+	}
+	kept := slices.DeleteFunc(tags, func(t string) bool { return slices.Contains(b.columns, t) })
+	kept = append(kept, b.columns[target])
+	if err := charmClient.UpdateNote(note, kept); err != nil {
+		b.status = "Move failed: " + err.Error()
+		return
+	}
+	card.Tags = kept
+
+	from := b.cards[b.col]
+	b.cards[b.col] = slices.Delete(from, b.row[b.col], b.row[b.col]+1)
+	b.row[b.col] = min(b.row[b.col], max(0, len(b.cards[b.col])-1))
+	b.cards[target] = append([]*charm.NoteWithTags{card}, b.cards[target]...)
+	b.col, b.row[target] = target, 0
+	b.status = fmt.Sprintf("Moved %q to %s", card.Note.Title, b.columns[target])
+}
+
+func (b *board) Init() tea.Cmd { return nil }
+
+func (b *board) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.width, b.height = msg.Width, msg.Height
+	case tea.KeyMsg:
+		b.status = ""
+		switch msg.String() {
+		case "ctrl+c", "esc", "q":
+			return b, tea.Quit
+		case "enter":
+			b.chosen = b.selected()
+			return b, tea.Quit
+		case "left", "h":
+			b.col = max(0, b.col-1)
+		case "right", "l":
+			b.col = min(len(b.columns)-1, b.col+1)
+		case "up", "k":
+			b.row[b.col] = max(0, b.row[b.col]-1)
+		case "down", "j":
+			b.row[b.col] = max(0, min(len(b.cards[b.col])-1, b.row[b.col]+1))
+		case "shift+left", "H":
+			b.move(-1)
+		case "shift+right", "L":
+			b.move(1)
+		}
+	}
+	return b, nil
+}
+
+func (b *board) View() string {
+	colWidth := max(12, b.width/len(b.columns)-1)
+	visible := max(1, b.height-4) // Header, rule, status, and help lines
+
+	var sb strings.Builder
+	for i, name := range b.columns {
+		header := fmt.Sprintf("%s (%d)", name, len(b.cards[i]))
+		if i == b.col {
+			header = "[" + header + "]"
+		}
+		sb.WriteString(pad(header, colWidth) + " ")
+	}
+	sb.WriteString("\n")
+	for range b.columns {
+		sb.WriteString(strings.Repeat("─", colWidth) + " ")
+	}
+	sb.WriteString("\n")
+
+	// Scroll each column so its cursor stays visible
+	starts := make([]int, len(b.columns))
+	for i := range b.columns {
+		if b.row[i] >= visible {
+			starts[i] = b.row[i] - visible + 1
+		}
+	}
+	for line := range visible {
+		for i := range b.columns {
+			cell := ""
+			if r := starts[i] + line; r < len(b.cards[i]) {
+				marker := "  "
+				if i == b.col && r == b.row[i] {
+					marker = "▸ "
+				}
+				cell = marker + b.cards[i][r].Note.Title
+			}
+			sb.WriteString(pad(cell, colWidth) + " ")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(b.status + "\n")
+	sb.WriteString("←→ column  ↑↓ note  H/L move note  enter choose  q quit")
+	return sb.String()
+}
+
+// pad truncates or pads s with spaces to exactly width runes.
+func pad(s string, width int) string {
+	r := []rune(s)
+	if len(r) > width {
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(r))
+}
+
+func init() {
+	boardCmd.Flags().String("tags", "todo,doing,done", "comma-separated column tags, left to right")
+	rootCmd.AddCommand(boardCmd)
+}
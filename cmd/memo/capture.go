@@ -0,0 +1,50 @@
+// ABOUTME: Capture command for saving piped output as a note.
+// ABOUTME: Passes stdin through to stdout while recording it, for `cmd |& memo capture`.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/harper/memo/internal/capture"
+	"github.com/spf13/cobra"
+)
+
+var captureCmd = &cobra.Command{
+	Use:   "capture",
+	Short: "Save piped output as a note",
+	Long: `Read output from stdin, pass it through to stdout, and save it as a new
+note tagged with the current directory when the input ends. The note
+records when capture started and how long the input took; use 'memo run'
+to also record the command and its exit status.
+
+Examples:
+  make build |& memo capture --title "build log"
+  journalctl -u app --since today | memo capture --tags ops`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pwd, _ := os.Getwd()
+		rec := &capture.Record{Dir: pwd, Started: time.Now()}
+		buf := capture.NewBuffer(capture.DefaultLimit)
+		if _, err := io.Copy(io.MultiWriter(os.Stdout, buf), os.Stdin); err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+		rec.Duration = time.Since(rec.Started)
+		rec.Output, _ = buf.Output()
+
+		title, _ := cmd.Flags().GetString("title")
+		if title == "" {
+			title = "Captured output " + rec.Started.Format("2006-01-02 15:04")
+		}
+		return saveCapture(cmd, title, rec)
+	},
+}
+
+func init() {
+	captureCmd.Flags().String("title", "", "note title (default: capture time)")
+	captureCmd.Flags().String("tags", "", "comma-separated tags to add")
+	rootCmd.AddCommand(captureCmd)
+}
@@ -0,0 +1,241 @@
+// ABOUTME: Check command for finding problems in note content.
+// ABOUTME: 'check links' reports broken memo://, [[wiki]], and (optionally) dead web links.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/links"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// linkCheckWorkers is how many URLs are checked at once with --network.
+const linkCheckWorkers = 8
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check notes for problems",
+}
+
+var checkLinksCmd = &cobra.Command{
+	Use:   "links",
+	Short: "Find links to missing notes and dead URLs",
+	Long: `Scan notes for links that no longer resolve:
+
+  memo://<id-or-alias>   the note ID prefix or alias doesn't match a note
+  [[Title]]              no note has that title, alias, or ID prefix
+  http(s)://...          with --network, the URL is unreachable or 404/410
+
+Links inside fenced code blocks are ignored. --fix replaces broken memo://
+and [[wiki]] links with their text after confirmation; dead URLs are only
+reported, since they are often temporarily down.
+
+Examples:
+  memo check links
+  memo check links --network --tag work
+  memo check links --fix`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagFlag, _ := cmd.Flags().GetString("tag")
+		network, _ := cmd.Flags().GetBool("network")
+		fix, _ := cmd.Flags().GetBool("fix")
+		force, _ := cmd.Flags().GetBool("force")
+
+		all, err := charmClient.ListNotesWithTags(&charm.NoteFilter{ContentLimit: charm.NoContent})
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+		aliases, err := charmClient.ListAliases()
+		if err != nil {
+			return fmt.Errorf("failed to list aliases: %w", err)
+		}
+		resolver := newNoteResolver(all, aliases)
+
+		var filter *charm.NoteFilter
+		if tagFlag != "" {
+			filter = &charm.NoteFilter{Tag: &tagFlag}
+		}
+		notes, err := charmClient.ListNotesWithTags(filter)
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+
+		type brokenLink struct {
+			link   links.Link
+			reason string
+		}
+		found := make(map[*charm.NoteWithTags][]links.Link, len(notes))
+		var urls []string
+		for _, nt := range notes {
+			found[nt] = links.Find(nt.Note.Content)
+			for _, l := range found[nt] {
+				if l.Kind == links.KindURL {
+					urls = append(urls, l.Target)
+				}
+			}
+		}
+		var dead map[string]string
+		if network {
+			dead = checkURLs(urls)
+		}
+
+		broken := make(map[*charm.NoteWithTags][]brokenLink)
+		for _, nt := range notes {
+			for _, l := range found[nt] {
+				if l.Kind != links.KindURL && !resolver.resolves(l) {
+					broken[nt] = append(broken[nt], brokenLink{l, "no such note"})
+				} else if reason, ok := dead[l.Target]; ok && l.Kind == links.KindURL {
+					broken[nt] = append(broken[nt], brokenLink{l, reason})
+				}
+			}
+		}
+
+		var changed []noteChange
+		total, fixable := 0, 0
+		for _, nt := range notes {
+			bad := broken[nt]
+			if len(bad) == 0 {
+				continue
+			}
+			total += len(bad)
+			fmt.Printf("%s  %s\n", nt.Note.ID.String()[:6], nt.Note.Title)
+
+			var unlink []links.Link
+			for _, b := range bad {
+				fmt.Printf("  line %-4d %-5s %s  %s\n", b.link.Line, b.link.Kind, b.link.Target, b.reason)
+				if b.link.Kind != links.KindURL {
+					unlink = append(unlink, b.link)
+				}
+			}
+			if len(unlink) > 0 {
+				fixable += len(unlink)
+				changed = append(changed, noteChange{note: nt, content: links.Unlink(nt.Note.Content, unlink)})
+			}
+		}
+
+		if total == 0 {
+			fmt.Println("No broken links found.")
+			return nil
+		}
+		fmt.Printf("\n%s found.\n", ui.Plural(total, "broken link"))
+		if !fix || fixable == 0 {
+			return nil
+		}
+
+		if !force {
+			ok, err := confirm(fmt.Sprintf("Unlink %s in %s?", ui.Plural(fixable, "broken note link"), ui.Plural(len(changed), "note")))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Canceled.")
+				return nil
+			}
+		}
+		if err := applyNoteChanges(changed); err != nil {
+			return err
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Unlinked %s", ui.Plural(fixable, "broken link"))))
+		return nil
+	},
+}
+
+// noteResolver answers whether memo:// and [[wiki]] targets name a note.
+type noteResolver struct {
+	ids     []string
+	titles  map[string]bool // Lowercased
+	aliases map[string]bool // Aliases whose note still exists
+}
+
+func newNoteResolver(notes []*charm.NoteWithTags, aliases []charm.Alias) *noteResolver {
+	r := &noteResolver{titles: make(map[string]bool), aliases: make(map[string]bool)}
+	exists := make(map[string]bool, len(notes))
+	for _, nt := range notes {
+		id := nt.Note.ID.String()
+		r.ids = append(r.ids, id)
+		exists[id] = true
+		r.titles[strings.ToLower(strings.TrimSpace(nt.Note.Title))] = true
+	}
+	for _, a := range aliases {
+		if exists[a.NoteID.String()] {
+			r.aliases[a.Name] = true
+		}
+	}
+	return r
+}
+
+// resolves reports whether a memo or wiki link points at an existing note.
+func (r *noteResolver) resolves(l links.Link) bool {
+	target := strings.TrimSpace(l.Target)
+	if l.Kind == links.KindWiki && r.titles[strings.ToLower(target)] {
+		return true
+	}
+	if r.aliases[charm.NormalizeAlias(target)] {
+		return true
+	}
+	return r.uniquePrefix(strings.ToLower(target))
+}
+
+// uniquePrefix reports whether exactly one note ID starts with prefix.
+func (r *noteResolver) uniquePrefix(prefix string) bool {
+	if len(prefix) < 6 {
+		return false
+	}
+	matches := 0
+	for _, id := range r.ids {
+		if strings.HasPrefix(id, prefix) {
+			matches++
+		}
+	}
+	return matches == 1
+}
+
+// checkURLs requests each distinct URL and returns the dead ones with the
+// reason they failed.
+func checkURLs(urls []string) map[string]string {
+	checker := links.NewChecker()
+	jobs := make(chan string)
+	dead := make(map[string]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for range linkCheckWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for url := range jobs {
+				if err := checker.Check(context.Background(), url); err != nil {
+					mu.Lock()
+					dead[url] = err.Error()
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	seen := make(map[string]bool)
+	for _, url := range urls {
+		if !seen[url] {
+			seen[url] = true
+			jobs <- url
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return dead
+}
+
+func init() {
+	checkLinksCmd.Flags().String("tag", "", "only check notes with this tag")
+	checkLinksCmd.Flags().Bool("network", false, "also request http(s) links to find dead ones")
+	checkLinksCmd.Flags().Bool("fix", false, "replace broken note links with their text")
+	checkLinksCmd.Flags().BoolP("force", "f", false, "skip the --fix confirmation")
+	checkCmd.AddCommand(checkLinksCmd)
+	rootCmd.AddCommand(checkCmd)
+}
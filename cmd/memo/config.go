@@ -0,0 +1,164 @@
+// ABOUTME: Config command for viewing and changing memo settings.
+// ABOUTME: Provides get, set, list, and edit subcommands over the config file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change settings",
+	Long: fmt.Sprintf(`View and change memo settings stored in the config file.
+
+Keys:
+%s
+Config file: %s`, configKeysHelp(), charm.ConfigPath()),
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := charm.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		value, err := cfg.Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := charm.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		if err := cfg.Set(args[0], args[1]); err != nil {
+			return err
+		}
+		if err := charm.SaveConfig(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Set %s = %s", args[0], maskSecret(args[0], args[1]))))
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := charm.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		width := configKeyWidth()
+		for _, key := range charm.ConfigKeys {
+			value, _ := cfg.Get(key.Name)
+			fmt.Printf("%-*s %s\n", width, key.Name, valueOrNone(maskSecret(key.Name, value)))
+		}
+		if len(cfg.ExportProfiles) > 0 {
+			fmt.Printf("%-*s %d defined (use 'memo config edit')\n", width, "export_profiles", len(cfg.ExportProfiles))
+		}
+		return nil
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the config file in your editor",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Make sure the file exists so the editor opens the real path
+		if !charm.ConfigExists() {
+			if err := charm.SaveConfig(charm.DefaultConfig()); err != nil {
+				return fmt.Errorf("failed to create config: %w", err)
+			}
+		}
+
+		editor := exec.Command(editorCommand(), charm.ConfigPath()) //nolint:gosec // Launching the configured editor is expected CLI behavior
+		editor.Stdin = os.Stdin
+		editor.Stdout = os.Stdout
+		editor.Stderr = os.Stderr
+		if err := editor.Run(); err != nil {
+			return fmt.Errorf("failed to open editor: %w", err)
+		}
+
+		if _, err := charm.LoadConfig(); err != nil {
+			return fmt.Errorf("config is no longer valid: %w", err)
+		}
+		fmt.Println(ui.Success("Config saved"))
+		return nil
+	},
+}
+
+// maskSecret hides the value of a secret setting so it stays out of
+// terminal scrollback; `memo config get` prints it.
+func maskSecret(key, value string) string {
+	if value == "" || !charm.SecretConfigKey(key) {
+		return value
+	}
+	return "********"
+}
+
+// configKeyWidth returns the column width that fits every key name.
+func configKeyWidth() int {
+	width := len("export_profiles")
+	for _, key := range charm.ConfigKeys {
+		width = max(width, len(key.Name))
+	}
+	return width
+}
+
+// configKeysHelp formats charm.ConfigKeys as the help's key list, one key
+// per line with continuation lines aligned under the description.
+func configKeysHelp() string {
+	width := configKeyWidth()
+	var b strings.Builder
+	for _, key := range charm.ConfigKeys {
+		for i, line := range strings.Split(key.Help, "\n") {
+			name := ""
+			if i == 0 {
+				name = key.Name
+			}
+			fmt.Fprintf(&b, "  %-*s  %s\n", width, name, line)
+		}
+	}
+	return b.String()
+}
+
+// editorCommand returns the configured editor, falling back to $EDITOR and vim.
+func editorCommand() string {
+	if cfg, err := charm.LoadConfig(); err == nil && cfg.Editor != "" {
+		return cfg.Editor
+	}
+	if editor := os.Getenv("EDITOR"); editor != "" {
+		return editor
+	}
+	return "vim"
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,159 @@
+// ABOUTME: Database command for local store maintenance.
+// ABOUTME: Provides maintain (integrity check, checkpoint, vacuum), reindex, and compress subcommands.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Maintain the local database",
+	Long:  `Inspect and maintain the local SQLite-backed KV store.`,
+}
+
+var dbMaintainCmd = &cobra.Command{
+	Use:   "maintain",
+	Short: "Run integrity check, WAL checkpoint, and vacuum",
+	Long: `Run PRAGMA integrity_check, checkpoint the write-ahead log, and vacuum
+the local database, reporting how much space was reclaimed.
+
+Set maintain_interval to run this automatically after commands once the
+interval has elapsed:
+
+  memo config set maintain_interval 168h
+
+Maintenance is safe while other memo processes, such as the MCP server,
+have the database open.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println("Maintaining database...")
+		result, err := charmClient.Maintain()
+		if err != nil {
+			return fmt.Errorf("maintenance failed: %w", err)
+		}
+
+		printMaintenance(result)
+		return nil
+	},
+}
+
+// printMaintenance reports the steps and space reclaimed by a maintenance run.
+func printMaintenance(result *charm.MaintenanceResult) {
+	fmt.Println()
+	if result.Orphans > 0 {
+		fmt.Printf("  ✓ Dropped %d orphaned keys\n", result.Orphans)
+	}
+	if result.Audit > 0 {
+		fmt.Printf("  ✓ Pruned %d audit log entries past audit_retention\n", result.Audit)
+	}
+	if result.Blobs > 0 {
+		fmt.Printf("  ✓ Removed %d unused attachment blobs\n", result.Blobs)
+	}
+	if result.Remote > 0 {
+		fmt.Printf("  ✓ Removed %d unused attachment files from Charm FS\n", result.Remote)
+	}
+	if result.RemoteErr != nil {
+		color.Yellow("  ⚠ Charm FS not swept: %v", result.RemoteErr)
+	}
+	if result.Indexed > 0 {
+		fmt.Printf("  ✓ Rebuilt %d index keys\n", result.Indexed)
+	}
+	if result.IntegrityOK {
+		color.Green("  ✓ Integrity check passed")
+	}
+	if result.Checkpointed {
+		fmt.Println("  ✓ WAL checkpointed")
+	}
+	if result.Vacuumed {
+		fmt.Println("  ✓ Database vacuumed")
+	}
+	if result.Warning != nil {
+		color.Yellow("  ⚠ %v", result.Warning)
+	}
+
+	fmt.Printf("\nSize:      %s → %s\n", ui.FormatSize(result.SizeBefore), ui.FormatSize(result.SizeAfter))
+	fmt.Println(ui.Success(fmt.Sprintf("Reclaimed %s", ui.FormatSize(result.Reclaimed()))))
+}
+
+var dbReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the tag and attachment indexes",
+	Long: `Rebuild the secondary index keys that let tag, directory, and
+attachment lookups skip unrelated notes. Until the first reindex, those
+lookups scan every note.
+
+Indexes are kept up to date on every write. Run this again after syncing
+notes written by an older memo version on another device.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := charmClient.Reindex()
+		if err != nil {
+			return fmt.Errorf("reindex failed: %w", err)
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Rebuilt %d index keys", n)))
+		return nil
+	},
+}
+
+var dbCompressCmd = &cobra.Command{
+	Use:   "compress",
+	Short: "Apply the compression setting to stored notes",
+	Long: `Rewrite stored note content and inline attachment data to match the
+compression setting: zstd-compressed when it is on, plain when it is off.
+
+With compression on, notes and attachments are compressed as they are
+written; this brings the ones stored earlier along. Run 'memo db
+maintain' afterwards to return the freed pages to the filesystem.
+
+Every device syncing the notes needs a memo that reads compressed
+content, so update them all before turning compression on.
+
+Examples:
+  memo config set compression true
+  memo db compress
+  memo db maintain`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notes, attachments, err := charmClient.Recompress()
+		if err != nil {
+			return fmt.Errorf("compress failed: %w", err)
+		}
+		state := "Compressed"
+		if !charmClient.Compression() {
+			state = "Expanded"
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("%s %d notes and %d attachments", state, notes, attachments)))
+		return nil
+	},
+}
+
+// maintainIfDue runs background maintenance when the configured interval has elapsed.
+func maintainIfDue(cmd *cobra.Command) {
+	// Never run under the long-lived MCP, LSP, and web servers or the maintain command itself
+	if charmClient == nil || cmd.Name() == "mcp" || cmd == lspCmd || cmd == serveCmd || cmd == dbMaintainCmd {
+		return
+	}
+	if !charmClient.MaintenanceDue() {
+		return
+	}
+	result, err := charmClient.Maintain()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Automatic maintenance failed: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Automatic maintenance reclaimed %s\n", ui.FormatSize(result.Reclaimed()))
+}
+
+func init() {
+	dbCmd.AddCommand(dbMaintainCmd)
+	dbCmd.AddCommand(dbReindexCmd)
+	dbCmd.AddCommand(dbCompressCmd)
+	rootCmd.AddCommand(dbCmd)
+}
@@ -0,0 +1,104 @@
+// ABOUTME: Diff command for comparing note content word by word.
+// ABOUTME: Compares two notes, or a note against a markdown file on disk.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <id-prefix> <id-prefix|file>",
+	Short: "Show a word-level diff between notes",
+	Long: `Compare the content of two notes, or a note and a file, word by word.
+Removed words are shown in red and added words in green; when output is
+not a terminal they are marked [-removed-] and {+added+} instead.
+
+If the second argument names an existing file, the note is compared with
+that file, which is handy for checking a note against an exported or
+fs-synced copy.
+
+Examples:
+  memo diff abc123 def456
+  memo diff roadmap ~/notes/roadmap.md
+  memo diff abc123 def456 --stat`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeAliases,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stat, _ := cmd.Flags().GetBool("stat")
+
+		note, _, err := getNote(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get note: %w", err)
+		}
+
+		otherName, otherContent, err := diffTarget(args[1])
+		if err != nil {
+			return err
+		}
+
+		chunks := ui.WordDiff(note.Content, otherContent)
+		printWordDiff(noteDiffName(note), otherName, chunks, stat)
+		return nil
+	},
+}
+
+// diffTarget resolves the second diff argument to a display name and
+// content, preferring a file on disk over a note reference.
+func diffTarget(ref string) (string, string, error) {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		data, err := os.ReadFile(ref) //nolint:gosec // User-specified input path is expected CLI behavior
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read file: %w", err)
+		}
+		return ref, string(data), nil
+	}
+
+	note, _, err := getNote(ref)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get note: %w", err)
+	}
+	return noteDiffName(note), note.Content, nil
+}
+
+// printWordDiff prints a header naming both sides, then the diff or, with
+// stat, just the word counts.
+func printWordDiff(from, to string, chunks []ui.DiffChunk, stat bool) {
+	if !hasChanges(chunks) {
+		fmt.Println("No differences.")
+		return
+	}
+
+	fmt.Println(ui.FormatDiffHeader(from, to))
+	if !stat {
+		fmt.Println(ui.FormatWordDiff(chunks))
+	}
+	added, removed := ui.DiffStat(chunks)
+	fmt.Printf("%d words added, %d removed\n", added, removed)
+}
+
+// hasChanges reports whether chunks contain any edit, including
+// whitespace-only ones that DiffStat does not count.
+func hasChanges(chunks []ui.DiffChunk) bool {
+	for _, c := range chunks {
+		if c.Op != ui.DiffEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// noteDiffName labels a note in diff headers.
+func noteDiffName(note *models.Note) string {
+	return fmt.Sprintf("%s %s", note.ID.String()[:6], note.Title)
+}
+
+func init() {
+	diffCmd.Flags().Bool("stat", false, "only print counts of added and removed words")
+	rootCmd.AddCommand(diffCmd)
+}
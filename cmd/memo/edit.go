@@ -1,38 +1,88 @@
 // ABOUTME: Edit command for modifying existing notes.
-// ABOUTME: Opens note content in $EDITOR for modification.
+// ABOUTME: Opens note content in $EDITOR, or applies --title/--content/--append for scripts.
 
 package main
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
 	"github.com/spf13/cobra"
 )
 
 var editCmd = &cobra.Command{
-	Use:   "edit <id-prefix>",
+	Use:   "edit [id-prefix]",
 	Short: "Edit a note",
-	Long:  `Open a note in $EDITOR for editing.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Open a note in $EDITOR for editing. With --quiet, print only the note's ID.
+
+For scripts, --title, --content, --content-file, and --append change the
+note without opening an editor. --content-file - reads from stdin, and
+--append adds a line after the existing (or replaced) content.
+
+With --diff, a word-level diff of your changes is shown before they are
+saved and you are asked to confirm.
+
+Examples:
+  memo edit abc123
+  memo edit abc123 --title "Q3 planning"
+  memo edit abc123 --content-file plan.md
+  memo edit abc123 --append "- [ ] follow up with design"`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		prefix := args[0]
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		showDiff, _ := cmd.Flags().GetBool("diff")
 
-		note, tags, err := charmClient.GetNoteByPrefix(prefix)
+		note, tags, err := noteArg(cmd, args)
 		if err != nil {
-			return fmt.Errorf("failed to get note: %w", err)
+			return err
+		}
+		if err := vault.CheckEdit(note.Title, note.Content); err != nil {
+			return err
 		}
 
-		newContent, err := openEditor(note.Content)
-		if err != nil {
-			return fmt.Errorf("failed to open editor: %w", err)
+		newTitle, newContent := note.Title, note.Content
+		if scripted(cmd) {
+			newTitle, newContent, err = scriptedEdit(cmd, note.Title, note.Content)
+			if err != nil {
+				return err
+			}
+		} else {
+			newContent, err = openEditor(note.Content)
+			if err != nil {
+				return fmt.Errorf("failed to open editor: %w", err)
+			}
+		}
+
+		if newContent != note.Content {
+			newContent = autoEnrich(newContent)
 		}
 
-		if newContent == note.Content {
-			fmt.Println("No changes made.")
+		if newTitle == note.Title && newContent == note.Content {
+			if quiet {
+				fmt.Println(note.ID)
+			} else {
+				fmt.Println("No changes made.")
+			}
 			return nil
 		}
 
+		if showDiff && newContent != note.Content {
+			printWordDiff(noteDiffName(note), "edited", ui.WordDiff(note.Content, newContent), false)
+			ok, err := confirm("Save changes?")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Changes discarded.")
+				return nil
+			}
+		}
+
+		note.Title = newTitle
 		note.Content = newContent
 		note.Touch()
 
@@ -40,11 +90,79 @@ var editCmd = &cobra.Command{
 			return fmt.Errorf("failed to update note: %w", err)
 		}
 
+		setHookNote(note, tags)
+		if quiet {
+			fmt.Println(note.ID)
+			return nil
+		}
 		fmt.Println(ui.Success(fmt.Sprintf("Updated note %s", note.ID.String()[:6])))
 		return nil
 	},
 }
 
+// editFlags are the flags that change a note without opening an editor.
+var editFlags = []string{"title", "content", "content-file", "append"}
+
+// scripted reports whether any non-interactive edit flag was given.
+func scripted(cmd *cobra.Command) bool {
+	for _, name := range editFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// scriptedEdit applies the non-interactive edit flags to a note's title and
+// content.
+func scriptedEdit(cmd *cobra.Command, title, content string) (string, string, error) {
+	if cmd.Flags().Changed("title") {
+		title, _ = cmd.Flags().GetString("title")
+		if strings.TrimSpace(title) == "" {
+			return "", "", fmt.Errorf("note title cannot be empty")
+		}
+	}
+
+	contentFile, _ := cmd.Flags().GetString("content-file")
+	switch {
+	case cmd.Flags().Changed("content"):
+		content, _ = cmd.Flags().GetString("content")
+	case contentFile == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		content = string(data)
+	case contentFile != "":
+		data, err := os.ReadFile(contentFile) //nolint:gosec // User-specified file path is expected CLI behavior
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read file: %w", err)
+		}
+		content = string(data)
+	}
+
+	if cmd.Flags().Changed("append") {
+		line, _ := cmd.Flags().GetString("append")
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += line + "\n"
+	}
+
+	if strings.TrimSpace(content) == "" {
+		return "", "", fmt.Errorf("note content cannot be empty")
+	}
+	return title, content, nil
+}
+
 func init() {
+	editCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	editCmd.Flags().String("title", "", "set the note's title")
+	editCmd.Flags().String("content", "", "replace the content (inline)")
+	editCmd.Flags().String("content-file", "", "replace the content from a file (- for stdin)")
+	editCmd.Flags().String("append", "", "append a line to the content")
+	editCmd.MarkFlagsMutuallyExclusive("content", "content-file")
+	editCmd.Flags().Bool("diff", false, "show a word diff of the changes and confirm before saving")
+	editCmd.Flags().BoolP("quiet", "q", false, "print only the note ID")
 	rootCmd.AddCommand(editCmd)
 }
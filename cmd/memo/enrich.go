@@ -0,0 +1,128 @@
+// ABOUTME: Enrich command for turning bare URLs into markdown links titled by their pages.
+// ABOUTME: Titles are cached on disk; auto_enrich applies the same step when notes are added or edited.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/links"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var enrichCmd = &cobra.Command{
+	Use:   "enrich [id-prefix...]",
+	Short: "Link bare URLs with their page titles",
+	Long: `Fetch the title of each bare http(s) URL in the selected notes and
+replace the URL with a markdown link, e.g.
+
+  https://go.dev/blog/slog  ->  [Structured Logging with slog - The Go Programming Language](https://go.dev/blog/slog)
+
+URLs already inside markdown links and in fenced code blocks are left
+alone, as are pages without an HTML title. Titles are cached, so running
+enrich again doesn't refetch; failed fetches are retried after a day.
+--refresh ignores the cache.
+
+Set auto_enrich to do this whenever a note is added or edited:
+  memo config set auto_enrich true
+
+Examples:
+  memo enrich abc123
+  memo enrich --tag reading --dry-run`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		refresh, _ := cmd.Flags().GetBool("refresh")
+
+		notes, err := noteArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		cache, err := links.LoadTitleCache(charm.LinkTitlesPath())
+		if err != nil {
+			return fmt.Errorf("failed to load title cache: %w", err)
+		}
+		title := titleResolver(cache, refresh)
+
+		var changed []noteChange
+		total := 0
+		for _, nt := range notes {
+			content, n := links.Enrich(nt.Note.Content, title)
+			if n == 0 {
+				continue
+			}
+			total += n
+			changed = append(changed, noteChange{note: nt, content: content})
+
+			fmt.Println(ui.FormatDiffHeader(noteDiffName(nt.Note), ui.Plural(n, "link")))
+			fmt.Println(ui.FormatWordDiffContext(ui.WordDiff(nt.Note.Content, content), diffContextLines))
+		}
+		if err := cache.Save(); err != nil {
+			return fmt.Errorf("failed to save title cache: %w", err)
+		}
+
+		if len(changed) == 0 {
+			fmt.Println("No bare URLs with titles found.")
+			return nil
+		}
+		summary := fmt.Sprintf("%s in %s", ui.Plural(total, "link"), ui.Plural(len(changed), "note"))
+		if dryRun {
+			fmt.Println(summary + " (dry run, nothing changed)")
+			return nil
+		}
+		if err := applyNoteChanges(changed); err != nil {
+			return err
+		}
+		fmt.Println(ui.Success("Added " + summary))
+		return nil
+	},
+}
+
+// titleResolver returns a title lookup for links.Enrich that answers from
+// the cache and fetches (and caches) what it doesn't know. Failed fetches
+// resolve to "" so the URL is left as is.
+func titleResolver(cache *links.TitleCache, refresh bool) func(url string) string {
+	checker := links.NewChecker()
+	return func(url string) string {
+		now := time.Now()
+		if !refresh {
+			if title, ok := cache.Lookup(url, now); ok {
+				return title
+			}
+		}
+		title, err := checker.FetchTitle(context.Background(), url)
+		if err != nil {
+			title = ""
+		}
+		cache.Store(url, title, now)
+		return title
+	}
+}
+
+// autoEnrich links bare URLs in content when auto_enrich is on. Enrichment
+// is best effort: if the cache can't be read, content is returned as is.
+func autoEnrich(content string) string {
+	if cfg := charmClient.Config(); cfg == nil || !cfg.AutoEnrich {
+		return content
+	}
+	cache, err := links.LoadTitleCache(charm.LinkTitlesPath())
+	if err != nil {
+		return content
+	}
+	enriched, _ := links.Enrich(content, titleResolver(cache, false))
+	_ = cache.Save()
+	return enriched
+}
+
+func init() {
+	enrichCmd.Flags().String("tag", "", "enrich notes with this tag")
+	enrichCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	enrichCmd.Flags().Bool("dry-run", false, "preview the links without saving")
+	enrichCmd.Flags().Bool("refresh", false, "refetch titles instead of using the cache")
+	rootCmd.AddCommand(enrichCmd)
+}
@@ -1,19 +1,25 @@
 // ABOUTME: Export command for backing up notes.
-// ABOUTME: Supports JSON and markdown export formats.
+// ABOUTME: Supports JSON, markdown, Org-mode, and pandoc-rendered PDF/DOCX formats.
 
 package main
 
 import (
+	"bufio"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"text/template"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/harper/memo/internal/charm"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/org"
 	"github.com/harper/memo/internal/ui"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -33,7 +39,8 @@ type ExportAttachment struct {
 	ID       string `json:"id"`
 	Filename string `json:"filename"`
 	MimeType string `json:"mime_type"`
-	Data     string `json:"data"` // base64 encoded
+	Data     string `json:"data,omitempty"` // base64 encoded
+	Path     string `json:"path,omitempty"` // sidecar file, relative to the export file
 }
 
 type ExportData struct {
@@ -43,107 +50,378 @@ type ExportData struct {
 }
 
 var exportCmd = &cobra.Command{
-	Use:   "export",
+	Use:   "export [note-id]",
 	Short: "Export notes",
-	Long:  `Export notes to JSON or markdown format.`,
+	Long: `Export notes to JSON, markdown, Org-mode, or archive format, or export
+a single note as PDF or DOCX.
+
+The archive format is the full-fidelity backup: a .tar.zst holding
+metadata.json plus every attachment as a raw file. It defaults to
+memo-<date>.tar.zst and is restored with 'memo import'.
+
+The pdf and docx formats render one note through pandoc, which must be on
+PATH (PDF output also needs a LaTeX engine). Image attachments are
+embedded in the document. The output defaults to <title>.pdf or
+<title>.docx in the current directory.
+
+The org format writes a single file with one top-level heading per note.
+Tags become heading tags, todo/done tags become TODO/DONE keywords, and
+IDs and timestamps are kept in a property drawer so the file can be
+re-imported without loss.
+
+JSON exports are streamed note by note. For large collections, use
+--attachments-dir to write attachments as files instead of inline
+base64; memo import reads them back from the recorded paths.
+
+Markdown files are named from --filename-template, a Go template with
+{{.Title}}, {{.ID}}, {{.ShortID}}, and {{.Date}} (default "{{.Title}}").
+When two notes map to the same name, the note's short ID is appended.
+Tags, ID, and timestamps are kept in each file's frontmatter.
+
+--tag, --since, --until, and --here limit the export to matching notes.
+Dates are YYYY-MM-DD and compare against when a note was created; both
+ends are inclusive. --here keeps notes tagged with the current directory.
+
+Named profiles in the config file (export_profiles) preset the format,
+output path, tag/search filters, and dir: tag scrubbing. Explicit flags
+override profile values.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		format, _ := cmd.Flags().GetString("format")
 		outputPath, _ := cmd.Flags().GetString("output")
 		notePrefix, _ := cmd.Flags().GetString("note")
+		profileName, _ := cmd.Flags().GetString("profile")
+		attachmentsDir, _ := cmd.Flags().GetString("attachments-dir")
+		filenameTemplate, _ := cmd.Flags().GetString("filename-template")
+
+		if len(args) == 1 {
+			notePrefix = args[0]
+		}
+
+		// List without content; each note is fetched as it is written
+		filter := &charm.NoteFilter{ContentLimit: charm.NoContent}
+		scrubDirTags := false
 
-		var notes []*models.Note
-		var noteTags [][]string
+		if err := applyExportFilterFlags(cmd, filter); err != nil {
+			return err
+		}
+
+		// Profile values apply unless overridden by explicit flags
+		if profileName != "" {
+			profile, err := charmClient.Config().ExportProfile(profileName)
+			if err != nil {
+				return err
+			}
+			if profile.Format != "" && !cmd.Flags().Changed("format") {
+				format = profile.Format
+			}
+			if profile.Output != "" && !cmd.Flags().Changed("output") {
+				outputPath = profile.Output
+			}
+			if profile.Tag != "" && filter.Tag == nil {
+				filter.Tag = &profile.Tag
+			}
+			filter.Search = profile.Search
+			scrubDirTags = profile.ScrubDirTags
+			if profile.FilenameTemplate != "" && !cmd.Flags().Changed("filename-template") {
+				filenameTemplate = profile.FilenameTemplate
+			}
+		}
 
+		if format == "pdf" || format == "docx" {
+			if notePrefix == "" {
+				return fmt.Errorf("%s export needs a note ID", format)
+			}
+			return exportPandoc(notePrefix, format, outputPath)
+		}
+
+		var entries []*exportEntry
 		if notePrefix != "" {
-			note, tags, err := charmClient.GetNoteByPrefix(notePrefix)
+			note, tags, err := getNote(notePrefix)
 			if err != nil {
 				return fmt.Errorf("failed to get note: %w", err)
 			}
-			notes = append(notes, note)
-			noteTags = append(noteTags, tags)
+			entries = append(entries, &exportEntry{ID: note.ID, Tags: tags, note: note})
 		} else {
-			filter := &charm.NoteFilter{Limit: 10000}
-			allNotes, err := charmClient.ListNotes(filter)
+			listed, err := charmClient.ListNotesWithTags(filter)
 			if err != nil {
 				return fmt.Errorf("failed to list notes: %w", err)
 			}
-			for _, n := range allNotes {
-				tags, _ := charmClient.GetNoteTags(n.ID)
-				notes = append(notes, n)
-				noteTags = append(noteTags, tags)
+			for _, nt := range listed {
+				entries = append(entries, &exportEntry{ID: nt.Note.ID, Tags: nt.Tags})
+			}
+		}
+
+		if scrubDirTags {
+			for _, e := range entries {
+				e.Tags = withoutDirTags(e.Tags)
 			}
 		}
 
 		switch format {
 		case "json":
-			return exportJSON(notes, noteTags, outputPath)
+			return exportJSON(entries, outputPath, attachmentsDir)
 		case "md":
-			return exportMarkdown(notes, noteTags, outputPath)
+			return exportMarkdown(entries, outputPath, filenameTemplate)
+		case "org":
+			return exportOrg(entries, outputPath)
+		case "archive":
+			return exportArchive(entries, outputPath)
 		default:
 			return fmt.Errorf("unknown format: %s", format)
 		}
 	},
 }
 
-func exportJSON(notes []*models.Note, noteTags [][]string, outputPath string) error {
-	export := ExportData{
-		ExportedAt: time.Now(),
-		Version:    "1.0",
+// exportEntry is a note picked for export. Only its ID and tags are held
+// until it is written, so an export never has every note's content in
+// memory at once.
+type exportEntry struct {
+	ID   uuid.UUID
+	Tags []string
+	note *models.Note // Already loaded, for single-note exports
+}
+
+// load fetches the entry's note. It returns nil if the note was deleted
+// after the export listed it.
+func (e *exportEntry) load() (*models.Note, error) {
+	if e.note != nil {
+		return e.note, nil
+	}
+	note, _, err := charmClient.GetNoteByID(e.ID)
+	if errors.Is(err, charm.ErrNoteNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note %s: %w", e.ID, err)
+	}
+	return note, nil
+}
+
+// applyExportFilterFlags narrows filter by the --tag, --since, --until, and --here flags.
+func applyExportFilterFlags(cmd *cobra.Command, filter *charm.NoteFilter) error {
+	if tag, _ := cmd.Flags().GetString("tag"); tag != "" {
+		filter.Tag = &tag
+	}
+
+	for name, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value, _ := cmd.Flags().GetString(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid --%s (want YYYY-MM-DD): %w", name, err)
+		}
+		*dst = parsed
+	}
+	if !filter.Until.IsZero() {
+		filter.Until = filter.Until.AddDate(0, 0, 1) // --until is inclusive of the whole day
 	}
 
-	for i, n := range notes {
+	if here, _ := cmd.Flags().GetBool("here"); here {
+		pwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		filter.DirTag = &pwd
+	}
+	return nil
+}
+
+// exportJSON streams notes to the output one at a time, so attachments for
+// only one note are held in memory. With attachmentsDir set, attachment
+// bytes go to files there and the JSON records their paths instead.
+func exportJSON(entries []*exportEntry, outputPath, attachmentsDir string) error {
+	out := os.Stdout
+	if outputPath != "" && outputPath != "-" {
+		f, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) //nolint:gosec // User-specified output path is expected CLI behavior
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }() // Backstop for early returns; Close below reports errors
+		out = f
+	}
+	w := bufio.NewWriter(out)
+
+	exportedAt, err := json.Marshal(time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "{\n  \"exported_at\": %s,\n  \"version\": \"1.0\",\n  \"notes\": [", exportedAt)
+
+	written := 0
+	for _, e := range entries {
+		n, err := e.load()
+		if err != nil {
+			return err
+		}
+		if n == nil {
+			continue
+		}
 		attachments, _ := charmClient.ListAttachmentsByNote(n.ID)
 
 		en := ExportNote{
 			ID:        n.ID.String(),
 			Title:     n.Title,
 			Content:   n.Content,
-			Tags:      noteTags[i],
+			Tags:      e.Tags,
 			CreatedAt: n.CreatedAt,
 			UpdatedAt: n.UpdatedAt,
 		}
 
 		for _, att := range attachments {
-			en.Attachments = append(en.Attachments, ExportAttachment{
+			if skipMissing(att) {
+				continue
+			}
+			ea := ExportAttachment{
 				ID:       att.ID.String(),
 				Filename: att.Filename,
 				MimeType: att.MimeType,
-				Data:     base64.StdEncoding.EncodeToString(att.Data),
-			})
+			}
+			if attachmentsDir == "" {
+				ea.Data = base64.StdEncoding.EncodeToString(att.Data)
+			} else {
+				ea.Path, err = writeSidecarAttachment(att, attachmentsDir, outputPath)
+				if err != nil {
+					return err
+				}
+			}
+			en.Attachments = append(en.Attachments, ea)
 		}
 
-		export.Notes = append(export.Notes, en)
+		data, err := json.MarshalIndent(en, "    ", "  ")
+		if err != nil {
+			return err
+		}
+		if written > 0 {
+			w.WriteString(",")
+		}
+		w.WriteString("\n    ")
+		w.Write(data)
+		written++
 	}
 
-	data, err := json.MarshalIndent(export, "", "  ")
-	if err != nil {
+	if written > 0 {
+		w.WriteString("\n  ")
+	}
+	w.WriteString("]\n}\n")
+	if err := w.Flush(); err != nil {
 		return err
 	}
+	if out != os.Stdout {
+		return out.Close()
+	}
+	return nil
+}
 
-	if outputPath == "" || outputPath == "-" {
-		fmt.Println(string(data))
-		return nil
+// skipMissing reports whether att's data isn't on this device, warning
+// that the attachment is left out.
+func skipMissing(att *models.Attachment) bool {
+	if !att.Missing {
+		return false
 	}
+	fmt.Fprintf(os.Stderr, "Warning: skipping %s: data not on this device\n", att.Filename)
+	return true
+}
 
-	return os.WriteFile(outputPath, data, 0600)
+// writeSidecarAttachment writes an attachment into dir and returns the path
+// to record in the export, relative to the export file when possible.
+func writeSidecarAttachment(att *models.Attachment, dir, outputPath string) (string, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create attachments dir: %w", err)
+	}
+	path := filepath.Join(dir, att.ID.String()+"-"+filepath.Base(att.Filename))
+	if err := os.WriteFile(path, att.Data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write attachment: %w", err)
+	}
+
+	if outputPath != "" && outputPath != "-" {
+		if rel, err := filepath.Rel(filepath.Dir(outputPath), path); err == nil {
+			return filepath.ToSlash(rel), nil
+		}
+	}
+	return filepath.Abs(path)
+}
+
+// filenameFields are the values available to --filename-template.
+type filenameFields struct {
+	Title   string
+	ID      string
+	ShortID string
+	Date    string // Created date, YYYY-MM-DD
 }
 
-func exportMarkdown(notes []*models.Note, noteTags [][]string, outputDir string) error {
+// markdownNamer picks unique markdown filenames from a template.
+type markdownNamer struct {
+	tmpl *template.Template
+	used map[string]bool
+}
+
+func newMarkdownNamer(pattern string) (*markdownNamer, error) {
+	if pattern == "" {
+		pattern = defaultFilenameTemplate
+	}
+	tmpl, err := template.New("filename").Option("missingkey=error").Parse(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filename template: %w", err)
+	}
+	return &markdownNamer{tmpl: tmpl, used: make(map[string]bool)}, nil
+}
+
+// name returns the filename for n. A name already used in this export gets
+// the note's short ID appended so notes with the same title don't overwrite
+// each other.
+func (m *markdownNamer) name(n *models.Note) (string, error) {
+	id := n.ID.String()
+	fields := filenameFields{Title: n.Title, ID: id, ShortID: id[:8], Date: n.CreatedAt.Format("2006-01-02")}
+
+	var sb strings.Builder
+	if err := m.tmpl.Execute(&sb, fields); err != nil {
+		return "", fmt.Errorf("filename template: %w", err)
+	}
+	base := sanitizeFilename(strings.TrimSpace(sb.String()))
+	if base == "" {
+		base = fields.ShortID
+	}
+
+	name := base + ".md"
+	if m.used[strings.ToLower(name)] {
+		name = base + "-" + fields.ShortID + ".md"
+	}
+	m.used[strings.ToLower(name)] = true
+	return name, nil
+}
+
+func exportMarkdown(entries []*exportEntry, outputDir, filenameTemplate string) error {
 	if outputDir == "" {
 		outputDir = "export"
 	}
 
+	namer, err := newMarkdownNamer(filenameTemplate)
+	if err != nil {
+		return err
+	}
+
 	if err := os.MkdirAll(outputDir, 0750); err != nil {
 		return err
 	}
 
-	for i, n := range notes {
+	written := 0
+	for _, e := range entries {
+		n, err := e.load()
+		if err != nil {
+			return err
+		}
+		if n == nil {
+			continue
+		}
 		attachments, _ := charmClient.ListAttachmentsByNote(n.ID)
 
 		en := ExportNote{
 			ID:        n.ID.String(),
 			Title:     n.Title,
-			Tags:      noteTags[i],
+			Tags:      e.Tags,
 			CreatedAt: n.CreatedAt,
 			UpdatedAt: n.UpdatedAt,
 		}
@@ -157,7 +435,10 @@ func exportMarkdown(notes []*models.Note, noteTags [][]string, outputDir string)
 		sb.WriteString("---\n\n")
 		sb.WriteString(n.Content)
 
-		filename := sanitizeFilename(n.Title) + ".md"
+		filename, err := namer.name(n)
+		if err != nil {
+			return err
+		}
 		filePath := filepath.Join(outputDir, filename)
 		if err := os.WriteFile(filePath, []byte(sb.String()), 0600); err != nil {
 			return err
@@ -171,18 +452,135 @@ func exportMarkdown(notes []*models.Note, noteTags [][]string, outputDir string)
 			}
 
 			for _, att := range attachments {
+				if skipMissing(att) {
+					continue
+				}
 				attPath := filepath.Join(attDir, att.Filename)
 				if err := os.WriteFile(attPath, att.Data, 0600); err != nil {
 					return fmt.Errorf("failed to write attachment: %w", err)
 				}
 			}
 		}
+		written++
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("Exported %d notes to %s", written, outputDir)))
+	return nil
+}
+
+func exportOrg(entries []*exportEntry, outputPath string) error {
+	headings := make([]org.Entry, 0, len(entries))
+	for _, e := range entries {
+		n, err := e.load()
+		if err != nil {
+			return err
+		}
+		if n == nil {
+			continue
+		}
+		headings = append(headings, org.Entry{
+			ID:        n.ID.String(),
+			Title:     n.Title,
+			Content:   n.Content,
+			Tags:      e.Tags,
+			CreatedAt: n.CreatedAt,
+			UpdatedAt: n.UpdatedAt,
+		})
+	}
+
+	if outputPath == "" || outputPath == "-" {
+		return org.Write(os.Stdout, headings)
+	}
+
+	f, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) //nolint:gosec // User-specified output path is expected CLI behavior
+	if err != nil {
+		return err
+	}
+	if err := org.Write(f, headings); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// exportPandoc renders a single note to PDF or DOCX with pandoc.
+func exportPandoc(notePrefix, format, outputPath string) error {
+	pandoc, err := exec.LookPath("pandoc")
+	if err != nil {
+		return errors.New("pandoc not found on PATH; install it from https://pandoc.org")
+	}
+
+	note, _, err := getNote(notePrefix)
+	if err != nil {
+		return fmt.Errorf("failed to get note: %w", err)
+	}
+	attachments, _ := charmClient.ListAttachmentsByNote(note.ID)
+
+	if outputPath == "" {
+		outputPath = sanitizeFilename(note.Title) + "." + format
+	}
+	outputPath, err = filepath.Abs(outputPath)
+	if err != nil {
+		return err
+	}
+
+	// Attachments are written next to the source so pandoc can resolve them
+	workDir, err := os.MkdirTemp("", "memo-export-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(workDir) }()
+
+	var sb strings.Builder
+	sb.WriteString(note.Content)
+	for _, att := range attachments {
+		if skipMissing(att) {
+			continue
+		}
+		name := filepath.Base(att.Filename)
+		if err := os.WriteFile(filepath.Join(workDir, name), att.Data, 0600); err != nil {
+			return fmt.Errorf("failed to write attachment: %w", err)
+		}
+		if strings.HasPrefix(att.MimeType, "image/") && !strings.Contains(note.Content, name) {
+			fmt.Fprintf(&sb, "\n\n![%s](%s)", name, name)
+		}
 	}
 
-	fmt.Println(ui.Success(fmt.Sprintf("Exported %d notes to %s", len(notes), outputDir)))
+	source := filepath.Join(workDir, "note.md")
+	if err := os.WriteFile(source, []byte(sb.String()), 0600); err != nil {
+		return err
+	}
+
+	pandocCmd := exec.Command(pandoc, source, //nolint:gosec // pandoc is resolved from PATH by design
+		"--from", "markdown",
+		"--metadata", "title="+note.Title,
+		"--resource-path", workDir,
+		"--output", outputPath)
+	pandocCmd.Dir = workDir
+	pandocCmd.Stdout = os.Stderr
+	pandocCmd.Stderr = os.Stderr
+	if err := pandocCmd.Run(); err != nil {
+		return fmt.Errorf("pandoc failed: %w", err)
+	}
+
+	fmt.Println(ui.Success(fmt.Sprintf("Exported %q to %s", note.Title, outputPath)))
 	return nil
 }
 
+// withoutDirTags returns tags with any dir: tags removed.
+func withoutDirTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if !strings.HasPrefix(strings.ToLower(t), "dir:") {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// defaultFilenameTemplate names markdown exports after the note title.
+const defaultFilenameTemplate = "{{.Title}}"
+
 func sanitizeFilename(name string) string {
 	// Replace unsafe characters
 	replacer := strings.NewReplacer(
@@ -197,8 +595,15 @@ func sanitizeFilename(name string) string {
 }
 
 func init() {
-	exportCmd.Flags().StringP("format", "f", "json", "export format (json|md)")
+	exportCmd.Flags().StringP("format", "f", "json", "export format (json|md|org|archive|pdf|docx)")
 	exportCmd.Flags().StringP("output", "o", "", "output path")
 	exportCmd.Flags().StringP("note", "n", "", "single note ID to export")
+	exportCmd.Flags().StringP("profile", "p", "", "named export profile from config")
+	exportCmd.Flags().StringP("tag", "t", "", "only export notes with this tag")
+	exportCmd.Flags().String("since", "", "only export notes created on or after this date (YYYY-MM-DD)")
+	exportCmd.Flags().String("until", "", "only export notes created on or before this date (YYYY-MM-DD)")
+	exportCmd.Flags().Bool("here", false, "only export notes for the current directory")
+	exportCmd.Flags().String("filename-template", "", "markdown filename template (default \"{{.Title}}\")")
+	exportCmd.Flags().String("attachments-dir", "", "write JSON attachments as files in this directory instead of inline base64")
 	rootCmd.AddCommand(exportCmd)
 }
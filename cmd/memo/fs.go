@@ -0,0 +1,86 @@
+// ABOUTME: Filesystem commands for editing notes as plain markdown files.
+// ABOUTME: Provides fs sync for two-way sync with a directory.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/fssync"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var fsCmd = &cobra.Command{
+	Use:   "fs",
+	Short: "Work with notes as markdown files",
+	Long:  `Mirror notes to a directory of markdown files you can edit with any editor.`,
+}
+
+var fsSyncCmd = &cobra.Command{
+	Use:   "sync <dir>",
+	Short: "Two-way sync notes with a markdown directory",
+	Long: `Keep a directory of markdown files and your notes in step.
+
+Each note is a <title>.md file with YAML frontmatter (id, title, tags,
+created, updated). On each run:
+
+  - notes changed in memo are rewritten to their files
+  - files edited on disk update their notes
+  - new .md files become new notes
+  - a deleted file deletes its note, and a deleted note removes its file
+  - a renamed file keeps its note, found by the id in its frontmatter
+
+If a note and its file both changed, the memo version wins and the file's
+version is kept next to it as <name>.md.conflict. The mapping lives in
+` + fssync.StateFile + ` inside the directory.
+
+With auto_sync enabled, changes are synced to Charm once at the end.
+
+Examples:
+  memo fs sync ~/notes
+  memo fs sync ~/notes --no-sync`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noSync, _ := cmd.Flags().GetBool("no-sync")
+
+		// Writes skip per-note sync; changes are synced once at the end
+		client, err := charm.NewClient(charm.WithAutoSync(false), charm.WithActor(cliActor(cmd)))
+		if err != nil {
+			return err
+		}
+
+		syncer := &fssync.Syncer{Repo: client, Dir: args[0]}
+		res, err := syncer.Sync()
+		if err != nil {
+			return fmt.Errorf("fs sync failed: %w", err)
+		}
+
+		for _, name := range res.Conflicts {
+			fmt.Printf("Warning: %s changed on both sides; disk version saved as %s.conflict\n", name, name)
+		}
+		for _, err := range res.Errors {
+			fmt.Fprintf(os.Stderr, "Warning: %v (retried on the next sync)\n", err)
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Synced %s", args[0])))
+		fmt.Printf("  written: %d  imported: %d  updated: %d  deleted: %d  removed: %d  conflicts: %d  errors: %d\n",
+			res.Written, res.Imported, res.Updated, res.Deleted, res.Removed, len(res.Conflicts), len(res.Errors))
+
+		if cfg := charmClient.Config(); noSync || !res.Changed() || cfg == nil || !cfg.AutoSync {
+			return nil
+		}
+		fmt.Println("Syncing...")
+		if err := client.Sync(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: sync failed, run 'memo sync now' to retry: %v\n", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	fsSyncCmd.Flags().Bool("no-sync", false, "don't sync to Charm after applying changes")
+	fsCmd.AddCommand(fsSyncCmd)
+	rootCmd.AddCommand(fsCmd)
+}
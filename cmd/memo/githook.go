@@ -0,0 +1,176 @@
+// ABOUTME: Hook command for installing memo's git hooks.
+// ABOUTME: The prepare-commit-msg hook offers notes tagged with the repository in the commit message.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/githook"
+	"github.com/harper/memo/internal/mdsection"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// commitLogTag marks the notes `memo log-commit` records commits in.
+const commitLogTag = "commits"
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Install git hooks that connect commits and notes",
+}
+
+var hookInstallGitCmd = &cobra.Command{
+	Use:   "install-git",
+	Short: "Install a prepare-commit-msg hook in the current git repository",
+	Long: `Install a prepare-commit-msg hook in the git repository containing the
+current directory. When you commit, the hook lists the most recently
+updated notes tagged with the repository's root (added there with --here)
+as comment lines in the message, so uncommenting one includes it.
+
+With --section, the body of that heading from the newest note that has it
+is added to the message itself, ready to edit, e.g. a "Commit" section you
+keep up to date while working.
+
+--log-commits also installs a post-commit hook that runs 'memo log-commit',
+recording every commit in the repository's commit log note.
+
+Existing hooks not written by memo are left alone unless --force is given.
+Merges, amends, and commits made with -m or -F are never changed.
+
+Examples:
+  memo hook install-git
+  memo hook install-git --section Commit --log-commits`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		section, _ := cmd.Flags().GetString("section")
+		limit, _ := cmd.Flags().GetInt("limit")
+		logCommits, _ := cmd.Flags().GetBool("log-commits")
+		force, _ := cmd.Flags().GetBool("force")
+
+		pwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+
+		hookArgs := []string{"hook", "prepare-commit-msg", "--limit", strconv.Itoa(limit)}
+		if section != "" {
+			hookArgs = append(hookArgs, "--section", section)
+		}
+		scripts := []struct{ name, script string }{
+			{"prepare-commit-msg", githook.Script(hookArgs...)},
+		}
+		if logCommits {
+			scripts = append(scripts, struct{ name, script string }{"post-commit", githook.Script("log-commit")})
+		}
+
+		for _, s := range scripts {
+			path, err := githook.HookPath(pwd, s.name)
+			if err != nil {
+				return fmt.Errorf("not in a git repository: %w", err)
+			}
+			if err := githook.Install(path, s.script, force); err != nil {
+				if errors.Is(err, githook.ErrForeignHook) {
+					return fmt.Errorf("%w (use --force to replace it)", err)
+				}
+				return fmt.Errorf("failed to install %s hook: %w", s.name, err)
+			}
+			fmt.Println(ui.Success("Installed " + path))
+		}
+		return nil
+	},
+}
+
+var hookPrepareCommitMsgCmd = &cobra.Command{
+	Use:    "prepare-commit-msg <message-file> [source [commit]]",
+	Short:  "Add notes to a commit message (run by the git hook)",
+	Hidden: true,
+	Args:   cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		section, _ := cmd.Flags().GetString("section")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		if len(args) > 1 && githook.SkipSource(args[1]) {
+			return nil
+		}
+
+		// Hooks run from the top of the working tree
+		pwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{DirTag: &pwd})
+		if err != nil {
+			return err
+		}
+
+		var block strings.Builder
+		if section != "" {
+			for _, nt := range notes {
+				if body := sectionBody(nt.Note.Content, section); body != "" {
+					block.WriteString(body)
+					break
+				}
+			}
+		}
+
+		var offered []string
+		for _, nt := range notes {
+			if len(offered) == limit {
+				break
+			}
+			if slices.Contains(nt.Tags, commitLogTag) {
+				continue
+			}
+			line := fmt.Sprintf("- %s (%s)", nt.Note.Title, nt.Note.ID.String()[:6])
+			if snippet := ui.Snippet(nt.Note.Content, "", 72); snippet != "" {
+				line += "\n  " + snippet
+			}
+			offered = append(offered, line)
+		}
+		if len(offered) > 0 {
+			if block.Len() > 0 {
+				block.WriteString("\n")
+			}
+			block.WriteString(githook.Comment("Notes for this repository (uncomment to include):\n" + strings.Join(offered, "\n")))
+		}
+		if block.Len() == 0 {
+			return nil
+		}
+
+		msg, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(args[0], []byte(githook.Insert(string(msg), block.String())), 0600)
+	},
+}
+
+// sectionBody returns the text under a note's named heading without the
+// heading line itself, or "" if the note has no such section.
+func sectionBody(content, name string) string {
+	section, err := mdsection.Extract(content, name)
+	if err != nil {
+		return ""
+	}
+	_, body, _ := strings.Cut(section, "\n")
+	return strings.TrimSpace(body)
+}
+
+func init() {
+	hookInstallGitCmd.Flags().String("section", "", "add this note section's text to commit messages")
+	hookInstallGitCmd.Flags().Int("limit", 5, "how many notes to offer")
+	hookInstallGitCmd.Flags().Bool("log-commits", false, "also install a post-commit hook running 'memo log-commit'")
+	hookInstallGitCmd.Flags().Bool("force", false, "replace existing hooks not installed by memo")
+	hookPrepareCommitMsgCmd.Flags().String("section", "", "note section to add to the message")
+	hookPrepareCommitMsgCmd.Flags().Int("limit", 5, "how many notes to offer")
+	hookCmd.AddCommand(hookInstallGitCmd)
+	hookCmd.AddCommand(hookPrepareCommitMsgCmd)
+	rootCmd.AddCommand(hookCmd)
+}
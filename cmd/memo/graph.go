@@ -0,0 +1,92 @@
+// ABOUTME: Graph command for exporting notes and tags as a graph.
+// ABOUTME: Writes Graphviz DOT or JSON for rendering and visualization tools.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/graph"
+	"github.com/spf13/cobra"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export notes and their tags as a graph",
+	Long: `Write a graph of notes joined to their tags, for rendering with
+Graphviz or loading into visualization tools. Notes and tags are nodes;
+each tagging is an edge, so notes that share tags cluster together.
+
+Formats:
+  dot   Graphviz (default): notes are boxes, tags are ellipses
+  json  {"nodes": [{id, type, label}], "edges": [{source, target, type}]}
+
+Directory tags (dir:...) are left out unless --dir-tags is given, since
+they are local paths.
+
+Examples:
+  memo graph | dot -Tsvg > notes.svg
+  memo graph --format json --tag work -o work.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		tagFlag, _ := cmd.Flags().GetString("tag")
+		outputPath, _ := cmd.Flags().GetString("output")
+		dirTags, _ := cmd.Flags().GetBool("dir-tags")
+
+		var write func(io.Writer, *graph.Graph) error
+		switch format {
+		case "dot":
+			write = graph.WriteDOT
+		case "json":
+			write = graph.WriteJSON
+		default:
+			return fmt.Errorf("unknown format %q (want dot or json)", format)
+		}
+
+		filter := &charm.NoteFilter{ContentLimit: charm.NoContent}
+		if tagFlag != "" {
+			filter.Tag = &tagFlag
+		}
+		notes, err := charmClient.ListNotesWithTags(filter)
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+
+		entries := make([]graph.Entry, len(notes))
+		for i, nt := range notes {
+			entries[i] = graph.Entry{ID: nt.Note.ID.String(), Title: nt.Note.Title}
+			for _, tag := range nt.Tags {
+				if dirTags || !strings.HasPrefix(tag, "dir:") {
+					entries[i].Tags = append(entries[i].Tags, tag)
+				}
+			}
+		}
+		g := graph.Build(entries)
+
+		if outputPath == "" || outputPath == "-" {
+			return write(os.Stdout, g)
+		}
+		f, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) //nolint:gosec // User-specified output path is expected CLI behavior
+		if err != nil {
+			return err
+		}
+		if err := write(f, g); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringP("format", "f", "dot", "output format: dot or json")
+	graphCmd.Flags().StringP("tag", "t", "", "only include notes with this tag")
+	graphCmd.Flags().StringP("output", "o", "", "output file (default: stdout)")
+	graphCmd.Flags().Bool("dir-tags", false, "include dir: tags")
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,79 @@
+// ABOUTME: Wiring for user-defined pre/post command hooks.
+// ABOUTME: Builds JSON payloads and runs hooks from the config hooks dir.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/hooks"
+	"github.com/harper/memo/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// hookNote is the note affected by the current command, included in post-hook payloads.
+var hookNote *ExportNote
+
+// hookPayload is the JSON document passed to hooks on stdin.
+type hookPayload struct {
+	Hook    string      `json:"hook"`
+	Command string      `json:"command"`
+	Args    []string    `json:"args"`
+	Note    *ExportNote `json:"note,omitempty"`
+}
+
+// setHookNote records the note a command acted on for post hooks.
+func setHookNote(note *models.Note, tags []string) {
+	hookNote = &ExportNote{
+		ID:        note.ID.String(),
+		Title:     note.Title,
+		Content:   note.Content,
+		Tags:      tags,
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+	}
+}
+
+// hookName returns the hook name for a command, keyed by its top-level command.
+func hookName(stage string, cmd *cobra.Command) string {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return stage + "-" + cmd.Name()
+}
+
+// runHook runs the stage hook (pre or post) for cmd.
+func runHook(stage string, cmd *cobra.Command, args []string) error {
+	name := hookName(stage, cmd)
+	runner := hooks.NewRunner(filepath.Join(charm.ConfigDir(), "hooks"))
+	if runner.Path(name) == "" {
+		return nil
+	}
+
+	command := commandName(cmd)
+	payload := hookPayload{Hook: name, Command: command, Args: args}
+	if stage == "post" {
+		payload.Note = hookNote
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	env := []string{"MEMO_COMMAND=" + command}
+	if payload.Note != nil {
+		env = append(env, "MEMO_NOTE_ID="+payload.Note.ID)
+	}
+	return runner.Run(name, data, env...)
+}
+
+// runPostHook runs the post hook, reporting failures without failing the command.
+func runPostHook(cmd *cobra.Command, args []string) {
+	if err := runHook("post", cmd, args); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
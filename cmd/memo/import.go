@@ -1,5 +1,5 @@
 // ABOUTME: Import command for restoring notes from backup.
-// ABOUTME: Supports JSON and markdown directory import.
+// ABOUTME: Supports JSON, Org-mode, and markdown import with duplicate detection.
 
 package main
 
@@ -10,51 +10,204 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/harper/memo/internal/charm"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/org"
 	"github.com/harper/memo/internal/ui"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
+// Import modes for notes whose ID already exists.
+const (
+	importConflict = ""       // Report and leave the existing note alone
+	importSkip     = "skip"   // Leave the existing note alone silently
+	importUpdate   = "update" // Overwrite the existing note
+)
+
 var importCmd = &cobra.Command{
 	Use:   "import <path>",
 	Short: "Import notes",
-	Long:  `Import notes from a JSON file or directory of markdown files.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Import notes from a JSON file, a .tar.zst archive made by
+'memo export --format archive', an Org-mode file, or a directory of
+markdown files.
+
+In .org files each top-level heading becomes a note. Nested headings are
+converted to markdown headings in the note body, and TODO/DONE keywords
+become todo/done tags.
+
+Imports are idempotent: a note with the same title and content as an
+existing note is skipped. A note whose original ID already exists is
+reported as a conflict unless --skip-existing or --update-existing is
+given. A summary of created, updated, and skipped notes is printed.
+
+With auto_sync enabled, notes are synced once after the whole import
+rather than after every note. Use --no-sync to skip that final sync.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		path := args[0]
+		skipExisting, _ := cmd.Flags().GetBool("skip-existing")
+		updateExisting, _ := cmd.Flags().GetBool("update-existing")
+		noSync, _ := cmd.Flags().GetBool("no-sync")
 
 		info, err := os.Stat(path)
 		if err != nil {
 			return fmt.Errorf("failed to stat path: %w", err)
 		}
 
-		if info.IsDir() {
-			return importMarkdownDir(path)
+		mode := importConflict
+		if skipExisting {
+			mode = importSkip
+		} else if updateExisting {
+			mode = importUpdate
+		}
+		// Writes skip per-note sync; the import is synced once at the end
+		client, err := charm.NewClient(charm.WithAutoSync(false), charm.WithActor(cliActor(cmd)))
+		if err != nil {
+			return err
+		}
+		im, err := newImporter(client, mode)
+		if err != nil {
+			return err
 		}
 
-		if strings.HasSuffix(path, ".json") {
-			return importJSON(path)
+		switch {
+		case info.IsDir():
+			err = im.fromMarkdownDir(path)
+		case strings.HasSuffix(path, ".json"):
+			err = im.fromJSON(path)
+		case strings.HasSuffix(path, archiveExt):
+			err = im.fromArchive(path)
+		case strings.HasSuffix(path, ".org"):
+			err = im.fromOrg(path)
+		default:
+			err = im.fromMarkdownFile(path)
 		}
+		if err != nil {
+			return err
+		}
+
+		im.printSummary()
 
-		return importMarkdownFile(path)
+		if cfg := charmClient.Config(); noSync || im.created+im.updated == 0 || cfg == nil || !cfg.AutoSync {
+			return nil
+		}
+		fmt.Println("Syncing...")
+		if err := client.Sync(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: sync failed, run 'memo sync now' to retry: %v\n", err)
+		}
+		return nil
 	},
 }
 
-func importJSON(path string) error {
+// importer writes imported notes, detecting ones that already exist by ID
+// or by content hash.
+type importer struct {
+	client   *charm.Client
+	mode     string
+	existing map[uuid.UUID]bool
+	hashes   map[string]bool
+
+	created, updated, skipped, conflicts, failed int
+}
+
+func newImporter(client *charm.Client, mode string) (*importer, error) {
+	notes, err := client.ListNotes(&charm.NoteFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list existing notes: %w", err)
+	}
+
+	im := &importer{
+		client:   client,
+		mode:     mode,
+		existing: make(map[uuid.UUID]bool, len(notes)),
+		hashes:   make(map[string]bool, len(notes)),
+	}
+	for _, n := range notes {
+		im.existing[n.ID] = true
+		im.hashes[n.ContentHash()] = true
+	}
+	return im, nil
+}
+
+// add imports one note according to the importer's mode.
+func (im *importer) add(note *models.Note, tags []string, attachments []*models.Attachment) {
+	if im.existing[note.ID] {
+		switch im.mode {
+		case importUpdate:
+			if err := im.update(note, tags, attachments); err != nil {
+				fmt.Printf("Warning: failed to update %q: %v\n", note.Title, err)
+				im.failed++
+				return
+			}
+			im.updated++
+		case importSkip:
+			im.skipped++
+		default:
+			fmt.Printf("Warning: %q already exists (%s)\n", note.Title, note.ID.String()[:8])
+			im.conflicts++
+		}
+		return
+	}
+
+	hash := note.ContentHash()
+	if im.hashes[hash] {
+		im.skipped++
+		return
+	}
+
+	// Note and attachments are written together so a failed import leaves no partial note
+	if err := im.client.CreateNoteWithAttachments(note, tags, attachments); err != nil {
+		fmt.Printf("Warning: failed to import %q: %v\n", note.Title, err)
+		im.failed++
+		return
+	}
+	im.existing[note.ID] = true
+	im.hashes[hash] = true
+	im.created++
+}
+
+// update overwrites an existing note and its imported attachments.
+func (im *importer) update(note *models.Note, tags []string, attachments []*models.Attachment) error {
+	if err := im.client.UpdateNote(note, tags); err != nil {
+		return err
+	}
+	for _, att := range attachments {
+		if err := im.client.CreateAttachment(att); err != nil {
+			return fmt.Errorf("attachment %s: %w", att.Filename, err)
+		}
+	}
+	im.hashes[note.ContentHash()] = true
+	return nil
+}
+
+func (im *importer) printSummary() {
+	fmt.Println(ui.Success(fmt.Sprintf("Imported %d notes", im.created+im.updated)))
+	fmt.Printf("  created: %d  updated: %d  skipped: %d  conflicts: %d  failed: %d\n",
+		im.created, im.updated, im.skipped, im.conflicts, im.failed)
+	if im.conflicts > 0 {
+		fmt.Println("Re-run with --skip-existing or --update-existing to resolve conflicts.")
+	}
+}
+
+func (im *importer) fromJSON(path string) error {
 	data, err := os.ReadFile(path) //nolint:gosec // User-specified file path is expected CLI behavior
 	if err != nil {
 		return err
 	}
+	return im.fromExportData(data, filepath.Dir(path))
+}
 
+// fromExportData imports a JSON export, resolving sidecar attachment paths against baseDir.
+func (im *importer) fromExportData(data []byte, baseDir string) error {
 	var export ExportData
 	if err := json.Unmarshal(data, &export); err != nil {
 		return err
 	}
 
-	count := 0
 	for _, en := range export.Notes {
 		note := models.NewNote(en.Title, en.Content)
 		// Try to preserve original ID if valid
@@ -64,33 +217,75 @@ func importJSON(path string) error {
 		note.CreatedAt = en.CreatedAt
 		note.UpdatedAt = en.UpdatedAt
 
-		if err := charmClient.CreateNote(note, en.Tags); err != nil {
-			fmt.Printf("Warning: failed to import %q: %v\n", en.Title, err)
-			continue
-		}
-
+		attachments := make([]*models.Attachment, 0, len(en.Attachments))
 		for _, att := range en.Attachments {
-			data, _ := base64.StdEncoding.DecodeString(att.Data)
+			data, err := attachmentData(att, baseDir)
+			if err != nil {
+				fmt.Printf("Warning: skipping attachment %s of %q: %v\n", att.Filename, en.Title, err)
+				continue
+			}
 			attachment := models.NewAttachment(note.ID, att.Filename, att.MimeType, data)
 			if id, err := uuid.Parse(att.ID); err == nil {
 				attachment.ID = id
 			}
-			if err := charmClient.CreateAttachment(attachment); err != nil {
-				fmt.Printf("Warning: failed to create attachment %q: %v\n", att.Filename, err)
-			}
+			attachments = append(attachments, attachment)
 		}
 
-		count++
+		im.add(note, en.Tags, attachments)
 	}
-
-	fmt.Println(ui.Success(fmt.Sprintf("Imported %d notes", count)))
 	return nil
 }
 
-func importMarkdownDir(dir string) error {
-	count := 0
+// attachmentData returns an exported attachment's bytes, reading sidecar
+// files relative to the export file's directory.
+func attachmentData(att ExportAttachment, baseDir string) ([]byte, error) {
+	if att.Path == "" {
+		return base64.StdEncoding.DecodeString(att.Data)
+	}
+	path := filepath.FromSlash(att.Path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return os.ReadFile(path) //nolint:gosec // Path comes from the user's own export file
+}
+
+func (im *importer) fromOrg(path string) error {
+	f, err := os.Open(path) //nolint:gosec // User-specified file path is expected CLI behavior
+	if err != nil {
+		return err
+	}
+	entries, err := org.Parse(f)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if e.Title == "" || e.Content == "" {
+			fmt.Printf("Warning: skipping %q: note title and content cannot be empty\n", e.Title)
+			im.failed++
+			continue
+		}
+
+		note := models.NewNote(e.Title, e.Content)
+		if id, err := uuid.Parse(e.ID); err == nil {
+			note.ID = id
+		}
+		if !e.CreatedAt.IsZero() {
+			note.CreatedAt = e.CreatedAt
+			note.UpdatedAt = e.CreatedAt
+		}
+		if !e.UpdatedAt.IsZero() {
+			note.UpdatedAt = e.UpdatedAt
+		}
 
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		im.add(note, e.Tags, nil)
+	}
+	return nil
+}
+
+func (im *importer) fromMarkdownDir(dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -98,48 +293,40 @@ func importMarkdownDir(dir string) error {
 			return nil
 		}
 
-		if err := importMarkdownFile(path); err != nil {
+		if err := im.fromMarkdownFile(path); err != nil {
 			fmt.Printf("Warning: failed to import %s: %v\n", path, err)
-			return nil
+			im.failed++
 		}
-		count++
 		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-
-	fmt.Println(ui.Success(fmt.Sprintf("Imported %d notes", count)))
-	return nil
 }
 
-func importMarkdownFile(path string) error {
+func (im *importer) fromMarkdownFile(path string) error {
 	data, err := os.ReadFile(path) //nolint:gosec // User-specified file path is expected CLI behavior
 	if err != nil {
 		return err
 	}
 
 	content := string(data)
-	var title string
-	var tags []string
+	var frontmatter struct {
+		ID      string    `yaml:"id"`
+		Title   string    `yaml:"title"`
+		Tags    []string  `yaml:"tags"`
+		Created time.Time `yaml:"created"`
+		Updated time.Time `yaml:"updated"`
+	}
 
 	// Try to parse frontmatter
 	if strings.HasPrefix(content, "---\n") {
 		parts := strings.SplitN(content, "---\n", 3)
 		if len(parts) >= 3 {
-			var frontmatter struct {
-				Title string   `yaml:"title"`
-				Tags  []string `yaml:"tags"`
-			}
 			if err := yaml.Unmarshal([]byte(parts[1]), &frontmatter); err == nil {
-				title = frontmatter.Title
-				tags = frontmatter.Tags
 				content = parts[2]
 			}
 		}
 	}
 
+	title := frontmatter.Title
 	if title == "" {
 		title = strings.TrimSuffix(filepath.Base(path), ".md")
 	}
@@ -150,13 +337,25 @@ func importMarkdownFile(path string) error {
 	}
 
 	note := models.NewNote(title, content)
-	if err := charmClient.CreateNote(note, tags); err != nil {
-		return err
+	// Notes exported with `memo export --format md` keep their ID and timestamps
+	if id, err := uuid.Parse(frontmatter.ID); err == nil {
+		note.ID = id
+	}
+	if !frontmatter.Created.IsZero() {
+		note.CreatedAt = frontmatter.Created
+	}
+	if !frontmatter.Updated.IsZero() {
+		note.UpdatedAt = frontmatter.Updated
 	}
 
+	im.add(note, frontmatter.Tags, nil)
 	return nil
 }
 
 func init() {
+	importCmd.Flags().Bool("skip-existing", false, "skip notes whose ID already exists")
+	importCmd.Flags().Bool("update-existing", false, "overwrite notes whose ID already exists")
+	importCmd.Flags().Bool("no-sync", false, "don't sync after importing")
+	importCmd.MarkFlagsMutuallyExclusive("skip-existing", "update-existing")
 	rootCmd.AddCommand(importCmd)
 }
@@ -0,0 +1,94 @@
+// ABOUTME: Ingest-email command for turning an RFC 822 message on stdin into a note.
+// ABOUTME: Meant for .forward and procmail rules; the subject becomes the title and attachments are kept.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/mail"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var ingestEmailCmd = &cobra.Command{
+	Use:   "ingest-email",
+	Short: "Create a note from an email on stdin",
+	Long: `Read an email message (RFC 822) from stdin and save it as a note: the
+subject is the title and the text body is the content (an HTML-only body
+is converted to plain text). Attachments are saved with the note;
+attachments over max_attachment_size are skipped with a warning.
+
+Notes are tagged "email" unless --tags says otherwise. To capture mail
+sent to a dedicated address, pipe it in from a .forward file:
+
+  "|memo ingest-email --quiet"
+
+or a procmail rule:
+
+  :0
+  * ^To:.*notes@example.com
+  | memo ingest-email --tags email,inbox --quiet`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagsFlag, _ := cmd.Flags().GetString("tags")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+
+		in, err := mail.Parse(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to parse email: %w", err)
+		}
+
+		title, content := in.Subject, in.Text
+		if title == "" {
+			title, _, _ = strings.Cut(content, "\n")
+		}
+		if content == "" {
+			content = in.Subject
+		}
+		content = autoEnrich(content)
+
+		tags := collectTags(tagsFlag, false)
+		note := models.NewNote(title, content)
+		if err := charmClient.CreateNote(note, tags); err != nil {
+			return fmt.Errorf("failed to create note: %w", err)
+		}
+
+		saved := 0
+		for _, a := range in.Attachments {
+			att := models.NewAttachment(note.ID, filepath.Base(a.Filename), a.MimeType, a.Data)
+			if err := charmClient.CreateAttachment(att); err != nil {
+				if errors.Is(err, charm.ErrAttachmentTooLarge) {
+					fmt.Fprintf(os.Stderr, "warning: skipped attachment %v\n", err)
+					continue
+				}
+				return fmt.Errorf("failed to save attachment %s: %w", a.Filename, err)
+			}
+			saved++
+		}
+
+		setHookNote(note, tags)
+		if quiet {
+			fmt.Println(note.ID)
+			return nil
+		}
+		msg := fmt.Sprintf("Created note %s from email", note.ID.String()[:6])
+		if saved > 0 {
+			msg += fmt.Sprintf(" with %s", ui.Plural(saved, "attachment"))
+		}
+		fmt.Println(ui.Success(msg))
+		return nil
+	},
+}
+
+func init() {
+	ingestEmailCmd.Flags().String("tags", "email", "comma-separated tags for the note")
+	ingestEmailCmd.Flags().BoolP("quiet", "q", false, "print only the new note's ID")
+	rootCmd.AddCommand(ingestEmailCmd)
+}
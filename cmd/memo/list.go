@@ -8,51 +8,229 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/lastlist"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ordering"
 	"github.com/harper/memo/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 const defaultGlobalLimit = 10
 
+// List sort orders for --sort.
+const (
+	sortUpdated = "updated"
+	sortManual  = "manual"
+)
+
+const (
+	previewWidth      = 80  // Characters shown in a --preview line
+	previewFetchChars = 400 // Content fetched to find a note's first line
+)
+
+// listView controls how listed notes are printed and records their order.
+type listView struct {
+	preview bool
+	query   string   // Search query to center previews on
+	ids     []string // Notes printed so far, numbered from 1
+}
+
+// contentLimit returns how much note content a listing needs to fetch.
+func (v *listView) contentLimit() int {
+	switch {
+	case !v.preview:
+		return charm.NoContent
+	case v.query != "":
+		return 0 // The match may be anywhere in the note
+	default:
+		return previewFetchChars
+	}
+}
+
+// print writes a numbered list item, with a preview line when enabled.
+func (v *listView) print(nt *charm.NoteWithTags) {
+	v.ids = append(v.ids, nt.Note.ID.String())
+	fmt.Print(ui.FormatNumberedNoteListItem(len(v.ids), nt.Note, tagsToModels(nt.Tags), nt.Tasks))
+	if v.preview {
+		fmt.Print(ui.FormatNotePreview(ui.Snippet(nt.Note.Content, v.query, previewWidth)))
+	}
+}
+
+// save records the printed notes for positional references.
+func (v *listView) save() {
+	if len(v.ids) > 0 {
+		_ = lastlist.Save(charm.LastListPath(), v.ids) // Best-effort
+	}
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List notes",
-	Long:  `List all notes, optionally filtered by tag or search query. By default shows directory-specific notes first, then global notes.`,
+	Long: `List all notes, optionally filtered by tag or search query. By default shows directory-specific notes first, then global notes. With --preview, each note shows its first line, or the text around the match when searching.
+
+Notes with task checkboxes show their progress, e.g. [3/7].
+
+Starred notes are marked with ★; --starred lists only them. --status
+lists notes in the read-later queue with that status (see 'memo queue').
+
+--branch lists notes whose recorded context is that git branch (see
+'memo config set record_context true'); --tag, --search, and --here narrow
+it further.
+
+--sort manual lists notes in the order set with 'memo order', followed by
+notes that were never ordered, most recently updated first. Like --starred,
+--status, and --branch, it prints one list honoring the other filters.
+
+Notes are numbered, and until the next listing the number works in place of an ID: 'memo show 3'.
+
+--format alfred or --format raycast prints the notes as script-filter JSON for launcher extensions instead: one flat list honoring --tag, --search, --untagged, --here, --starred, --status, --branch, and --limit, with each item's arg set to the full note ID.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tagFlag, _ := cmd.Flags().GetString("tag")
 		searchFlag, _ := cmd.Flags().GetString("search")
 		limitFlag, _ := cmd.Flags().GetInt("limit")
 		hereFlag, _ := cmd.Flags().GetBool("here")
+		untaggedFlag, _ := cmd.Flags().GetBool("untagged")
+		previewFlag, _ := cmd.Flags().GetBool("preview")
+		formatFlag, _ := cmd.Flags().GetString("format")
+		branchFlag, _ := cmd.Flags().GetString("branch")
+		sortFlag, _ := cmd.Flags().GetString("sort")
+		starredFlag, _ := cmd.Flags().GetBool("starred")
+		statusFlag, _ := cmd.Flags().GetString("status")
+		view := &listView{preview: previewFlag, query: searchFlag}
+
+		if cfg := charmClient.Config(); cfg != nil && cfg.DefaultLimit > 0 && !cmd.Flags().Changed("limit") {
+			limitFlag = cfg.DefaultLimit
+		}
+
+		if !models.ValidStatus(statusFlag) {
+			return fmt.Errorf("invalid --status %q: use unread, reading, or done", statusFlag)
+		}
+		if sortFlag != sortUpdated && sortFlag != sortManual {
+			return fmt.Errorf("invalid --sort %q: use %s or %s", sortFlag, sortUpdated, sortManual)
+		}
+
+		// Launchers rerun the list on every keystroke, so these don't
+		// replace the numbered positions of the last listing
+		if formatFlag != "text" {
+			filter, err := flatFilter(tagFlag, searchFlag, branchFlag, statusFlag, untaggedFlag, hereFlag, starredFlag)
+			if err != nil {
+				return err
+			}
+			filter.Limit = limitFlag
+			return listLauncher(formatFlag, filter)
+		}
+
+		// Remember what was shown so later commands can take its position
+		defer view.save()
+
+		// Flat mode - starred notes, a queue status, a git branch, or the
+		// manual order, narrowed by the other filters
+		if starredFlag || statusFlag != "" || branchFlag != "" || sortFlag == sortManual {
+			filter, err := flatFilter(tagFlag, searchFlag, branchFlag, statusFlag, untaggedFlag, hereFlag, starredFlag)
+			if err != nil {
+				return err
+			}
+			filter.Limit = limitFlag
+			return charmClient.ReadSession(func() error {
+				return listFlat(filter, sortFlag == sortManual, view)
+			})
+		}
 
 		// Search mode - bypass sectioned output
 		if searchFlag != "" {
-			return listSearch(searchFlag, limitFlag)
+			return charmClient.ReadSession(func() error {
+				return listSearch(searchFlag, limitFlag, view)
+			})
 		}
 
 		// Tag filter mode - bypass sectioned output
 		if tagFlag != "" {
-			return listByTag(tagFlag, limitFlag)
+			return charmClient.ReadSession(func() error {
+				return listByTag(tagFlag, limitFlag, view)
+			})
+		}
+
+		// Untagged mode - notes still waiting for a tag
+		if untaggedFlag {
+			return charmClient.ReadSession(func() error {
+				return listUntagged(limitFlag, view)
+			})
 		}
 
 		// Here mode - only show pwd-tagged notes
 		if hereFlag {
-			return listHere(limitFlag)
+			return charmClient.ReadSession(func() error {
+				return listHere(limitFlag, view)
+			})
 		}
 
 		// Default: sectioned output (pwd + global)
-		return listSectioned(limitFlag)
+		return listSectioned(limitFlag, view)
 	},
 }
 
-func listSearch(query string, limit int) error {
+// flatFilter builds the filter for listings that print one flat list.
+func flatFilter(tagName, query, branch, status string, untagged, here, starred bool) (*charm.NoteFilter, error) {
+	filter := &charm.NoteFilter{Search: query, Untagged: untagged, Branch: branch, Status: status, Starred: starred}
+	if tagName != "" {
+		filter.Tag = &tagName
+	}
+	if here {
+		pwd, err := os.Getwd()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current directory: %w", err)
+		}
+		filter.DirTag = &pwd
+	}
+	return filter, nil
+}
+
+// listFlat prints the notes matching filter as one list, in the manual
+// order set with 'memo order' when manual is set.
+func listFlat(filter *charm.NoteFilter, manual bool, view *listView) error {
+	filter.ContentLimit = view.contentLimit()
+	limit := filter.Limit
+	if manual {
+		filter.Limit = 0 // Ranked notes may be anywhere in the default order
+	}
+	notes, err := charmClient.ListNotesWithTags(filter)
+	if err != nil {
+		return fmt.Errorf("failed to list notes: %w", err)
+	}
+
+	if manual {
+		ranks, err := charmClient.NoteRanks()
+		if err != nil {
+			return fmt.Errorf("failed to read manual order: %w", err)
+		}
+		ordering.SortBy(notes, ranks, func(nt *charm.NoteWithTags) uuid.UUID { return nt.Note.ID })
+		if limit > 0 && len(notes) > limit {
+			notes = notes[:limit]
+		}
+	}
+
+	if len(notes) == 0 {
+		fmt.Println("No notes found.")
+		return nil
+	}
+
+	for _, nt := range notes {
+		view.print(nt)
+	}
+	return nil
+}
+
+func listSearch(query string, limit int, view *listView) error {
 	filter := &charm.NoteFilter{
-		Search: query,
-		Limit:  limit,
+		Search:       query,
+		Limit:        limit,
+		ContentLimit: view.contentLimit(),
 	}
-	notes, err := charmClient.ListNotes(filter)
+	notes, err := charmClient.ListNotesWithTags(filter)
 	if err != nil {
 		return fmt.Errorf("search failed: %w", err)
 	}
@@ -62,19 +240,19 @@ func listSearch(query string, limit int) error {
 		return nil
 	}
 
-	for _, note := range notes {
-		tags, _ := charmClient.GetNoteTags(note.ID)
-		fmt.Print(ui.FormatNoteListItem(note, tagsToModels(tags)))
+	for _, nt := range notes {
+		view.print(nt)
 	}
 	return nil
 }
 
-func listByTag(tagName string, limit int) error {
+func listByTag(tagName string, limit int, view *listView) error {
 	filter := &charm.NoteFilter{
-		Tag:   &tagName,
-		Limit: limit,
+		Tag:          &tagName,
+		Limit:        limit,
+		ContentLimit: view.contentLimit(),
 	}
-	notes, err := charmClient.ListNotes(filter)
+	notes, err := charmClient.ListNotesWithTags(filter)
 	if err != nil {
 		return fmt.Errorf("failed to list notes: %w", err)
 	}
@@ -84,24 +262,46 @@ func listByTag(tagName string, limit int) error {
 		return nil
 	}
 
-	for _, note := range notes {
-		tags, _ := charmClient.GetNoteTags(note.ID)
-		fmt.Print(ui.FormatNoteListItem(note, tagsToModels(tags)))
+	for _, nt := range notes {
+		view.print(nt)
+	}
+	return nil
+}
+
+func listUntagged(limit int, view *listView) error {
+	filter := &charm.NoteFilter{
+		Untagged:     true,
+		Limit:        limit,
+		ContentLimit: view.contentLimit(),
+	}
+	notes, err := charmClient.ListNotesWithTags(filter)
+	if err != nil {
+		return fmt.Errorf("failed to list notes: %w", err)
+	}
+
+	if len(notes) == 0 {
+		fmt.Println("No untagged notes.")
+		return nil
+	}
+
+	for _, nt := range notes {
+		view.print(nt)
 	}
 	return nil
 }
 
-func listHere(limit int) error {
+func listHere(limit int, view *listView) error {
 	pwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
 	filter := &charm.NoteFilter{
-		DirTag: &pwd,
-		Limit:  limit,
+		DirTag:       &pwd,
+		Limit:        limit,
+		ContentLimit: view.contentLimit(),
 	}
-	notes, err := charmClient.ListNotes(filter)
+	notes, err := charmClient.ListNotesWithTags(filter)
 	if err != nil {
 		return fmt.Errorf("failed to list notes: %w", err)
 	}
@@ -112,104 +312,146 @@ func listHere(limit int) error {
 	}
 
 	fmt.Print(ui.FormatDirSectionHeader(pwd))
-	for _, note := range notes {
-		tags, _ := charmClient.GetNoteTags(note.ID)
-		fmt.Print(ui.FormatNoteListItem(note, tagsToModels(tags)))
+	for _, nt := range notes {
+		view.print(nt)
 	}
 	return nil
 }
 
-//nolint:funlen,nestif // Complex flow for sectioned listing
-func listSectioned(limit int) error {
+// listSectioned prints directory notes then global notes, offering to show
+// the rest of the global notes. The prompt runs outside the read session so
+// the database isn't held open while waiting for input.
+func listSectioned(limit int, view *listView) error {
 	pwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current directory: %w", err)
 	}
 
+	var totalGlobal int
+	err = charmClient.ReadSession(func() error {
+		var err error
+		totalGlobal, err = printSections(pwd, limit, view)
+		return err
+	})
+	if err != nil || totalGlobal <= defaultGlobalLimit {
+		return err
+	}
+
+	fmt.Print(ui.FormatShowMorePrompt(totalGlobal - defaultGlobalLimit))
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		// EOF or input error - just don't show more
+		return nil //nolint:nilerr // Intentional: silently exit on stdin issues
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response != "y" && response != "yes" {
+		return nil
+	}
+
+	return charmClient.ReadSession(func() error {
+		// Fetch only the notes we haven't shown yet
+		restFilter := &charm.NoteFilter{
+			Global:       true,
+			Offset:       defaultGlobalLimit,
+			ContentLimit: view.contentLimit(),
+		}
+		rest, err := charmClient.ListNotesWithTags(restFilter)
+		if err != nil {
+			return fmt.Errorf("failed to list remaining notes: %w", err)
+		}
+
+		fmt.Println()
+		for _, nt := range rest {
+			view.print(nt)
+		}
+		return nil
+	})
+}
+
+// printSections prints the directory and global sections and returns the
+// total number of global notes, or 0 if nothing was printed.
+func printSections(pwd string, limit int, view *listView) (int, error) {
 	// Get directory-specific notes
 	dirFilter := &charm.NoteFilter{
-		DirTag: &pwd,
-		Limit:  limit,
+		DirTag:       &pwd,
+		Limit:        limit,
+		ContentLimit: view.contentLimit(),
 	}
-	dirNotes, err := charmClient.ListNotes(dirFilter)
+	dirNotes, err := charmClient.ListNotesWithTags(dirFilter)
 	if err != nil {
-		return fmt.Errorf("failed to list directory notes: %w", err)
+		return 0, fmt.Errorf("failed to list directory notes: %w", err)
 	}
 
 	// Get global notes (no dir: tag)
 	globalFilter := &charm.NoteFilter{
-		Global: true,
-		Limit:  defaultGlobalLimit,
+		Global:       true,
+		Limit:        defaultGlobalLimit,
+		ContentLimit: view.contentLimit(),
 	}
-	globalNotes, err := charmClient.ListNotes(globalFilter)
+	globalNotes, err := charmClient.ListNotesWithTags(globalFilter)
 	if err != nil {
-		return fmt.Errorf("failed to list global notes: %w", err)
+		return 0, fmt.Errorf("failed to list global notes: %w", err)
 	}
 
 	// Get total count for "show more" logic
 	totalGlobal, err := charmClient.CountGlobalNotes()
 	if err != nil {
-		return fmt.Errorf("failed to count global notes: %w", err)
+		return 0, fmt.Errorf("failed to count global notes: %w", err)
 	}
 
 	// Handle empty case
 	if len(dirNotes) == 0 && len(globalNotes) == 0 {
 		fmt.Println("No notes found.")
-		return nil
+		return 0, nil
 	}
 
 	// Print directory section if there are notes
 	if len(dirNotes) > 0 {
 		fmt.Print(ui.FormatDirSectionHeader(pwd))
-		for _, note := range dirNotes {
-			tags, _ := charmClient.GetNoteTags(note.ID)
-			fmt.Print(ui.FormatNoteListItem(note, tagsToModels(tags)))
+		for _, nt := range dirNotes {
+			view.print(nt)
 		}
 	}
 
 	// Print global section
-	if len(globalNotes) > 0 {
-		fmt.Print(ui.FormatGlobalSectionHeader())
-		for _, note := range globalNotes {
-			tags, _ := charmClient.GetNoteTags(note.ID)
-			fmt.Print(ui.FormatNoteListItem(note, tagsToModels(tags)))
-		}
-
-		// Show more prompt if there are more global notes
-		remaining := totalGlobal - len(globalNotes)
-		if remaining > 0 {
-			fmt.Print(ui.FormatShowMorePrompt(remaining))
+	if len(globalNotes) == 0 {
+		return 0, nil
+	}
+	fmt.Print(ui.FormatGlobalSectionHeader())
+	for _, nt := range globalNotes {
+		view.print(nt)
+	}
+	return totalGlobal, nil
+}
 
-			reader := bufio.NewReader(os.Stdin)
-			response, err := reader.ReadString('\n')
-			if err != nil {
-				// EOF or input error - just don't show more
-				return nil //nolint:nilerr // Intentional: silently exit on stdin issues
-			}
+// listLauncher prints the notes matching filter as launcher JSON.
+func listLauncher(format string, filter *charm.NoteFilter) error {
+	if format != ui.LauncherAlfred && format != ui.LauncherRaycast {
+		return fmt.Errorf("unknown format %q (use text, alfred, or raycast)", format)
+	}
+	if filter.Search == "" {
+		filter.ContentLimit = previewFetchChars
+	}
+	notes, err := charmClient.ListNotesWithTags(filter)
+	if err != nil {
+		return fmt.Errorf("failed to list notes: %w", err)
+	}
 
-			response = strings.TrimSpace(strings.ToLower(response))
-			if response == "y" || response == "yes" {
-				// Fetch remaining notes
-				allGlobalFilter := &charm.NoteFilter{
-					Global: true,
-					Limit:  totalGlobal,
-				}
-				allGlobal, err := charmClient.ListNotes(allGlobalFilter)
-				if err != nil {
-					return fmt.Errorf("failed to list remaining notes: %w", err)
-				}
-
-				// Print only the ones we haven't shown yet
-				fmt.Println()
-				for i := defaultGlobalLimit; i < len(allGlobal); i++ {
-					note := allGlobal[i]
-					tags, _ := charmClient.GetNoteTags(note.ID)
-					fmt.Print(ui.FormatNoteListItem(note, tagsToModels(tags)))
-				}
-			}
+	items := make([]ui.LauncherNote, len(notes))
+	for i, nt := range notes {
+		items[i] = ui.LauncherNote{
+			Note:    nt.Note,
+			Tags:    nt.Tags,
+			Tasks:   nt.Tasks,
+			Snippet: ui.Snippet(nt.Note.Content, filter.Search, previewWidth),
 		}
 	}
-
+	data, err := ui.FormatLauncherJSON(format, items, time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
 	return nil
 }
 
@@ -227,5 +469,14 @@ func init() {
 	listCmd.Flags().StringP("search", "s", "", "search query")
 	listCmd.Flags().IntP("limit", "n", 20, "number of results")
 	listCmd.Flags().Bool("here", false, "show only notes tagged with current directory")
+	listCmd.Flags().Bool("untagged", false, "show only notes without tags (dir: tags don't count)")
+	listCmd.Flags().String("branch", "", "show only notes recorded on this git branch (see record_context)")
+	listCmd.Flags().Bool("starred", false, "show only starred notes")
+	listCmd.Flags().String("status", "", "show only queued notes with this status: unread, reading, or done")
+	listCmd.Flags().String("sort", sortUpdated, "sort order: updated or manual (see 'memo order')")
+	listCmd.MarkFlagsMutuallyExclusive("tag", "untagged")
+	listCmd.MarkFlagsMutuallyExclusive("branch", "untagged")
+	listCmd.Flags().BoolP("preview", "p", false, "show the first line, or the search match, under each note")
+	listCmd.Flags().String("format", "text", "output format: text, alfred, or raycast")
 	rootCmd.AddCommand(listCmd)
 }
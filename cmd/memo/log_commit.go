@@ -0,0 +1,111 @@
+// ABOUTME: Log-commit command for recording git commits in a project note.
+// ABOUTME: Appends one line per commit to the repository's "Commits: <repo>" note.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/githook"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var logCommitCmd = &cobra.Command{
+	Use:   "log-commit [rev]",
+	Short: "Record a git commit in the repository's commit log note",
+	Long: `Append a commit (default: HEAD) to a note as one line with its date,
+short hash, subject, and author. Commits already in the note are skipped.
+
+By default the note is "Commits: <repository>", tagged "commits" and with
+the repository's root directory, and is created on first use. --note
+records into another note instead.
+
+'memo hook install-git --log-commits' runs this after every commit.
+
+Examples:
+  memo log-commit
+  memo log-commit HEAD~1 --note abc123`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noteFlag, _ := cmd.Flags().GetString("note")
+
+		rev := "HEAD"
+		if len(args) == 1 {
+			rev = args[0]
+		}
+
+		pwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		root, err := githook.TopLevel(pwd)
+		if err != nil {
+			return fmt.Errorf("not in a git repository: %w", err)
+		}
+		commit, err := githook.ReadCommit(root, rev)
+		if err != nil {
+			return err
+		}
+
+		var note *models.Note
+		var tags []string
+		if noteFlag != "" {
+			if note, tags, err = getNote(noteFlag); err != nil {
+				return fmt.Errorf("note not found: %w", err)
+			}
+		} else if note, tags, err = commitLogNote(root); err != nil {
+			return err
+		}
+
+		line := commit.Line()
+		if note == nil {
+			note = models.NewNote("Commits: "+filepath.Base(root), line+"\n")
+			tags = []string{commitLogTag, models.NormalizeTag("dir:" + root)}
+			if err := charmClient.CreateNote(note, tags); err != nil {
+				return fmt.Errorf("failed to create note: %w", err)
+			}
+		} else {
+			if strings.Contains(note.Content, "`"+commit.ShortHash()+"`") {
+				return nil
+			}
+			if err := vault.CheckEdit(note.Title, note.Content); err != nil {
+				return err
+			}
+			note.Content = strings.TrimRight(note.Content, "\n") + "\n" + line + "\n"
+			note.Touch()
+			if err := charmClient.UpdateNote(note, tags); err != nil {
+				return fmt.Errorf("failed to update note: %w", err)
+			}
+		}
+
+		setHookNote(note, tags)
+		fmt.Println(ui.Success(fmt.Sprintf("Logged %s to %s", commit.ShortHash(), note.Title)))
+		return nil
+	},
+}
+
+// commitLogNote finds the commit log note for the repository at root, or
+// returns nil if it hasn't been created yet.
+func commitLogNote(root string) (*models.Note, []string, error) {
+	tag := commitLogTag
+	notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{Tag: &tag, DirTag: &root, Limit: 1, ContentLimit: charm.NoContent})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(notes) == 0 {
+		return nil, nil, nil //nolint:nilnil // No log note yet is a valid outcome
+	}
+	return charmClient.GetNoteByID(notes[0].Note.ID)
+}
+
+func init() {
+	logCommitCmd.Flags().String("note", "", "record into this note instead of the repository's log")
+	rootCmd.AddCommand(logCommitCmd)
+}
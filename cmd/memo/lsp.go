@@ -0,0 +1,39 @@
+// ABOUTME: LSP command to start the language server for editor plugins.
+// ABOUTME: Runs on stdio so Neovim, VS Code, and other LSP clients can link notes.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/lsp"
+	"github.com/spf13/cobra"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Start a language server for note links",
+	Long: `Start a minimal Language Server Protocol server on stdio, for editor
+plugins working with markdown that links notes:
+
+  - completion of note titles after [[
+  - hover previews of [[wiki]] and memo:// links
+  - go-to-definition opening the linked note
+
+Go-to-definition writes the note to ` + "`<state dir>/lsp/<id>.md`" + `; saving
+that file updates the note.
+
+Neovim example:
+  vim.lsp.start({ name = "memo", cmd = { "memo", "lsp" } })`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		server := lsp.NewServer(charmClient, filepath.Join(charm.StateDir(), "lsp"))
+		return server.Serve(os.Stdin, os.Stdout)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lspCmd)
+}
@@ -0,0 +1,118 @@
+// ABOUTME: Mail command for emailing a note as rendered HTML with a markdown alternative.
+// ABOUTME: Sends through the configured SMTP server or sendmail, with the note's attachments.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/mail"
+	"github.com/harper/memo/internal/mdhtml"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var mailCmd = &cobra.Command{
+	Use:   "mail [id-prefix]",
+	Short: "Email a note",
+	Long: `Email a note to one or more recipients. The message has the rendered
+note as HTML, the markdown as a plain-text alternative, and the note's
+attachments (skip them with --no-attachments).
+
+Mail goes through the SMTP server in smtp_addr (with smtp_user and
+smtp_password), or is piped to sendmail when smtp_addr isn't set. The
+sender is mail_from, or $USER@hostname.
+
+Examples:
+  memo mail abc123 --to alex@example.com
+  memo mail abc123 --to alex@example.com --to sam@example.com --subject "Notes from today"
+  memo config set smtp_addr smtp.example.com:587`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetStringSlice("to")
+		subject, _ := cmd.Flags().GetString("subject")
+		noAttachments, _ := cmd.Flags().GetBool("no-attachments")
+
+		if len(to) == 0 {
+			return fmt.Errorf("at least one --to address is required")
+		}
+		note, _, err := noteArg(cmd, args)
+		if err != nil {
+			return err
+		}
+		if subject == "" {
+			subject = note.Title
+		}
+
+		html, err := mdhtml.RenderDocument(note.Title, "# "+note.Title+"\n\n"+note.Content)
+		if err != nil {
+			return fmt.Errorf("failed to render note: %w", err)
+		}
+		cfg := charmClient.Config()
+		if cfg == nil {
+			cfg = charm.DefaultConfig()
+		}
+		msg := &mail.Message{
+			From:    mailFrom(cfg),
+			To:      to,
+			Subject: subject,
+			Text:    note.Content,
+			HTML:    html,
+		}
+
+		if !noAttachments {
+			atts, err := charmClient.ListAttachmentsByNote(note.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list attachments: %w", err)
+			}
+			for _, att := range atts {
+				if att.Missing {
+					return fmt.Errorf("attachment %s: data not on this device", att.Filename)
+				}
+				msg.Attachments = append(msg.Attachments, mail.Attachment{Filename: att.Filename, MimeType: att.MimeType, Data: att.Data})
+			}
+		}
+
+		var sender mail.Sender = &mail.Sendmail{}
+		if cfg.SMTPAddr != "" {
+			sender = &mail.SMTP{Addr: cfg.SMTPAddr, Username: cfg.SMTPUser, Password: cfg.SMTPPassword}
+		}
+		if err := sender.Send(msg); err != nil {
+			return fmt.Errorf("failed to send mail: %w", err)
+		}
+
+		sent := fmt.Sprintf("Mailed note %s to %s", note.ID.String()[:6], strings.Join(to, ", "))
+		if n := len(msg.Attachments); n > 0 {
+			sent += fmt.Sprintf(" with %s", ui.Plural(n, "attachment"))
+		}
+		fmt.Println(ui.Success(sent))
+		return nil
+	},
+}
+
+// mailFrom returns the configured sender, or $USER@hostname.
+func mailFrom(cfg *charm.Config) string {
+	if cfg.MailFrom != "" {
+		return cfg.MailFrom
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	user := os.Getenv("USER")
+	if user == "" {
+		user = "memo"
+	}
+	return user + "@" + host
+}
+
+func init() {
+	mailCmd.Flags().StringSlice("to", nil, "recipient address (repeatable or comma-separated)")
+	mailCmd.Flags().String("subject", "", "subject line (default: the note title)")
+	mailCmd.Flags().Bool("no-attachments", false, "don't attach the note's files")
+	mailCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	rootCmd.AddCommand(mailCmd)
+}
@@ -0,0 +1,117 @@
+// ABOUTME: Note command for converting between notes and attachments.
+// ABOUTME: Turns a text or markdown attachment into a standalone note linked with its parent.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Convert between notes and attachments",
+}
+
+var noteFromAttachmentCmd = &cobra.Command{
+	Use:   "from-attachment <attachment-id-prefix>",
+	Short: "Turn a text or markdown attachment into a linked note",
+	Long: `Create a note from a text or markdown attachment and link the two notes:
+the attachment's note gets a link to the new note, which links back. The
+attachment is then deleted, unless --keep is given.
+
+The title defaults to the attachment's filename without its extension.
+'memo attach import-note' does the reverse trip for a file on disk.
+
+Examples:
+  memo note from-attachment 4f2a9c
+  memo note from-attachment 4f2a9c --title "Meeting transcript" --keep`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		titleFlag, _ := cmd.Flags().GetString("title")
+		tagsFlag, _ := cmd.Flags().GetString("tags")
+		keep, _ := cmd.Flags().GetBool("keep")
+
+		att, err := charmClient.GetAttachmentByPrefix(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get attachment: %w", err)
+		}
+		if !isText(att.Data) {
+			return fmt.Errorf("%s isn't a text file (%s)", att.Filename, att.MimeType)
+		}
+		parent, parentTags, err := charmClient.GetNoteByID(att.NoteID)
+		if err != nil {
+			return fmt.Errorf("failed to get the attachment's note: %w", err)
+		}
+
+		title := titleFlag
+		if title == "" {
+			title = strings.TrimSuffix(att.Filename, filepath.Ext(att.Filename))
+		}
+		sub, err := createSubNote(parent, parentTags, title, string(att.Data), collectTags(tagsFlag, false))
+		if err != nil {
+			return err
+		}
+
+		if !keep {
+			if err := charmClient.DeleteAttachment(att.ID); err != nil {
+				return fmt.Errorf("created note %s but failed to delete the attachment: %w", sub.ID.String()[:6], err)
+			}
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Created note %s from %s", sub.ID.String()[:6], att.Filename)))
+		return nil
+	},
+}
+
+// createSubNote creates a note with content and links it with parent both
+// ways: a link to it appended to the parent, and one back to the parent at
+// its end.
+func createSubNote(parent *models.Note, parentTags []string, title, content string, tags []string) (*models.Note, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("note content cannot be empty")
+	}
+	if err := vault.CheckEdit(parent.Title, parent.Content); err != nil {
+		return nil, err
+	}
+
+	sub := models.NewNote(title, content+"\n\nPart of "+memoLink(parent)+"\n")
+	if err := charmClient.CreateNote(sub, tags); err != nil {
+		return nil, fmt.Errorf("failed to create note: %w", err)
+	}
+
+	parent.Content = strings.TrimRight(parent.Content, "\n") + "\n\n- " + memoLink(sub) + "\n"
+	parent.Touch()
+	if err := charmClient.UpdateNote(parent, parentTags); err != nil {
+		return nil, fmt.Errorf("created note %s but failed to link it from %s: %w", sub.ID.String()[:6], parent.Title, err)
+	}
+	setHookNote(sub, tags)
+	return sub, nil
+}
+
+// memoLink formats a markdown link to a note.
+func memoLink(note *models.Note) string {
+	return fmt.Sprintf("[%s](memo://%s)", note.Title, note.ID.String()[:8])
+}
+
+// isText reports whether attachment data can become note content: UTF-8
+// without NUL bytes, whatever its MIME type says.
+func isText(data []byte) bool {
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) < 0
+}
+
+func init() {
+	noteFromAttachmentCmd.Flags().String("title", "", "note title (default: the filename without its extension)")
+	noteFromAttachmentCmd.Flags().String("tags", "", "comma-separated tags for the new note")
+	noteFromAttachmentCmd.Flags().Bool("keep", false, "keep the attachment after creating the note")
+	noteCmd.AddCommand(noteFromAttachmentCmd)
+	rootCmd.AddCommand(noteCmd)
+}
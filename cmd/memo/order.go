@@ -0,0 +1,112 @@
+// ABOUTME: Order command for arranging notes in a manual sort order.
+// ABOUTME: Places notes before or after others for 'memo list --sort manual'.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/ordering"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var orderCmd = &cobra.Command{
+	Use:   "order",
+	Short: "Arrange notes in a manual order for list --sort manual",
+	Long: `Keep curated lists, like a reading list or a runbook, in the order you
+choose. 'memo list --sort manual' shows ordered notes first, in this order,
+then the rest by last update.
+
+There is one order across all notes, so filtering it (say with --tag
+reading) keeps the relative order of the notes shown.
+
+Examples:
+  memo order set abc123 --top
+  memo order set def456 --after abc123
+  memo order set 3 --before 1        # Positions from the last listing work too
+  memo list --tag reading --sort manual
+  memo order rm def456`,
+}
+
+var orderSetCmd = &cobra.Command{
+	Use:   "set <id>",
+	Short: "Move a note in the manual order",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		before, _ := cmd.Flags().GetString("before")
+		after, _ := cmd.Flags().GetString("after")
+		top, _ := cmd.Flags().GetBool("top")
+		bottom, _ := cmd.Flags().GetBool("bottom")
+
+		note, _, err := getNote(args[0])
+		if err != nil {
+			return fmt.Errorf("note not found: %w", err)
+		}
+
+		var anchor uuid.UUID
+		var where string
+		switch {
+		case before != "" || after != "":
+			ref := before + after
+			other, _, err := getNote(ref)
+			if err != nil {
+				return fmt.Errorf("note %s not found: %w", ref, err)
+			}
+			if other.ID == note.ID {
+				return fmt.Errorf("can't order a note relative to itself")
+			}
+			anchor = other.ID
+			where = "before " + other.Title
+			if after != "" {
+				where = "after " + other.Title
+			}
+		case top:
+			where = "to the top"
+		case bottom:
+			where = "to the bottom"
+		}
+
+		ranks, err := charmClient.NoteRanks()
+		if err != nil {
+			return fmt.Errorf("failed to read manual order: %w", err)
+		}
+		updates := ordering.Move(ranks, note.ID, anchor, after != "" || bottom)
+		if err := charmClient.SetNoteRanks(updates); err != nil {
+			return fmt.Errorf("failed to save manual order: %w", err)
+		}
+
+		fmt.Println(ui.Success(fmt.Sprintf("Moved %s %s", note.Title, where)))
+		return nil
+	},
+}
+
+var orderRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Remove a note from the manual order",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, _, err := getNote(args[0])
+		if err != nil {
+			return fmt.Errorf("note not found: %w", err)
+		}
+		if err := charmClient.ClearNoteRank(note.ID); err != nil {
+			return fmt.Errorf("failed to update manual order: %w", err)
+		}
+		fmt.Println(ui.Success("Removed " + note.Title + " from the manual order"))
+		return nil
+	},
+}
+
+func init() {
+	orderSetCmd.Flags().String("before", "", "place the note just before this note")
+	orderSetCmd.Flags().String("after", "", "place the note just after this note")
+	orderSetCmd.Flags().Bool("top", false, "place the note first")
+	orderSetCmd.Flags().Bool("bottom", false, "place the note last")
+	orderSetCmd.MarkFlagsMutuallyExclusive("before", "after", "top", "bottom")
+	orderSetCmd.MarkFlagsOneRequired("before", "after", "top", "bottom")
+	orderCmd.AddCommand(orderSetCmd)
+	orderCmd.AddCommand(orderRmCmd)
+	rootCmd.AddCommand(orderCmd)
+}
@@ -0,0 +1,192 @@
+// ABOUTME: Pick command and --pick flag for choosing a note interactively.
+// ABOUTME: Runs a fuzzy finder over note titles and tags on the terminal.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// errPickCanceled is returned when the picker is closed without a choice.
+var errPickCanceled = errors.New("no note picked")
+
+var pickCmd = &cobra.Command{
+	Use:   "pick",
+	Short: "Choose a note with a fuzzy finder and print its ID",
+	Long: `Open a fuzzy finder over note titles and tags and print the chosen
+note's ID. The finder draws on stderr, so the ID can be captured:
+
+  memo edit $(memo pick)
+  memo attach add $(memo pick --tag work) diagram.png
+
+Type to filter, use the arrow keys (or Ctrl-N/Ctrl-P) to move, Enter to
+choose, and Esc to cancel. show, edit, and rm also accept --pick in place
+of an ID.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagFlag, _ := cmd.Flags().GetString("tag")
+
+		var filter *charm.NoteFilter
+		if tagFlag != "" {
+			filter = &charm.NoteFilter{Tag: &tagFlag}
+		}
+		note, _, err := pickNote(filter)
+		if err != nil {
+			return err
+		}
+		fmt.Println(note.ID)
+		return nil
+	},
+}
+
+// pickNote lists notes matching filter and lets the user choose one.
+func pickNote(filter *charm.NoteFilter) (*models.Note, []string, error) {
+	if filter == nil {
+		filter = &charm.NoteFilter{}
+	}
+	filter.ContentLimit = charm.NoContent
+
+	notes, err := charmClient.ListNotesWithTags(filter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	if len(notes) == 0 {
+		return nil, nil, fmt.Errorf("no notes to pick from")
+	}
+
+	p := tea.NewProgram(newPicker(notes), tea.WithOutput(os.Stderr), tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		return nil, nil, fmt.Errorf("picker failed: %w", err)
+	}
+	chosen := final.(*picker).chosen
+	if chosen == nil {
+		return nil, nil, errPickCanceled
+	}
+
+	// Listing skipped content; fetch the full note
+	note, tags, err := charmClient.GetNoteByID(chosen.Note.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get note: %w", err)
+	}
+	return note, tags, nil
+}
+
+// picker is the bubbletea model for the fuzzy finder.
+type picker struct {
+	notes   []*charm.NoteWithTags
+	labels  []string
+	matches []int // Indexes into notes, best match first
+	query   []rune
+	cursor  int
+	height  int
+	chosen  *charm.NoteWithTags
+}
+
+func newPicker(notes []*charm.NoteWithTags) *picker {
+	p := &picker{notes: notes, height: 20}
+	for _, n := range notes {
+		label := n.Note.Title
+		if len(n.Tags) > 0 {
+			label += "  #" + strings.Join(n.Tags, " #")
+		}
+		p.labels = append(p.labels, label)
+	}
+	p.filter()
+	return p
+}
+
+// filter recomputes matches for the current query.
+func (p *picker) filter() {
+	type scored struct{ i, score int }
+	var found []scored
+	for i, label := range p.labels {
+		if s, ok := ui.FuzzyScore(string(p.query), label); ok {
+			found = append(found, scored{i, s})
+		}
+	}
+	// Stable keeps the listing's recency order among equal scores
+	sort.SliceStable(found, func(a, b int) bool { return found[a].score > found[b].score })
+
+	p.matches = p.matches[:0]
+	for _, f := range found {
+		p.matches = append(p.matches, f.i)
+	}
+	p.cursor = 0
+}
+
+func (p *picker) Init() tea.Cmd { return nil }
+
+func (p *picker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.height = max(1, msg.Height-2)
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return p, tea.Quit
+		case tea.KeyEnter:
+			if len(p.matches) > 0 {
+				p.chosen = p.notes[p.matches[p.cursor]]
+			}
+			return p, tea.Quit
+		case tea.KeyUp, tea.KeyCtrlP:
+			if p.cursor > 0 {
+				p.cursor--
+			}
+		case tea.KeyDown, tea.KeyCtrlN:
+			if p.cursor < len(p.matches)-1 {
+				p.cursor++
+			}
+		case tea.KeyBackspace:
+			if len(p.query) > 0 {
+				p.query = p.query[:len(p.query)-1]
+				p.filter()
+			}
+		case tea.KeySpace:
+			p.query = append(p.query, ' ')
+			p.filter()
+		case tea.KeyRunes:
+			p.query = append(p.query, msg.Runes...)
+			p.filter()
+		}
+	}
+	return p, nil
+}
+
+func (p *picker) View() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "> %s\n", string(p.query))
+
+	// Scroll so the cursor stays visible
+	start := 0
+	if p.cursor >= p.height {
+		start = p.cursor - p.height + 1
+	}
+	end := min(len(p.matches), start+p.height)
+	for i := start; i < end; i++ {
+		n := p.notes[p.matches[i]]
+		marker := "  "
+		if i == p.cursor {
+			marker = "▸ "
+		}
+		fmt.Fprintf(&sb, "%s%s  %s\n", marker, n.Note.ID.String()[:6], p.labels[p.matches[i]])
+	}
+	fmt.Fprintf(&sb, "  %d/%d", len(p.matches), len(p.notes))
+	return sb.String()
+}
+
+func init() {
+	pickCmd.Flags().String("tag", "", "only offer notes with this tag")
+	rootCmd.AddCommand(pickCmd)
+}
@@ -0,0 +1,168 @@
+// ABOUTME: External subcommand plugins discovered on PATH.
+// ABOUTME: `memo foo` runs a `memo-foo` binary, like git and kubectl.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/spf13/cobra"
+)
+
+// pluginPrefix is the executable name prefix for plugins.
+const pluginPrefix = "memo-"
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage external subcommands",
+	Long: `External subcommands are executables named memo-<name> on your PATH.
+Running 'memo <name> [args]' runs 'memo-<name> [args]' with these
+environment variables set, for the store --db and --config select when
+given before <name>:
+
+  MEMO_CONFIG_DIR   memo's config directory
+  MEMO_DB_NAME      Charm KV database name
+  MEMO_DB_PATH      Charm KV database file (when resolvable)
+  MEMO_CHARM_HOST   Charm server host
+  MEMO_BIN          path to the memo binary`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List plugins found on PATH",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugins := findPlugins()
+		if len(plugins) == 0 {
+			fmt.Println("No plugins found.")
+			return nil
+		}
+		for _, p := range plugins {
+			fmt.Printf("  %-16s %s\n", strings.TrimPrefix(filepath.Base(p), pluginPrefix), p)
+		}
+		return nil
+	},
+}
+
+// findPlugins returns the first memo-* executable for each name on PATH.
+func findPlugins() []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if !strings.HasPrefix(name, pluginPrefix) || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+				continue
+			}
+			seen[name] = true
+			result = append(result, path)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// runPlugin dispatches args to a memo-<name> plugin when the first
+// argument after any --db and --config flags is not a built-in command.
+// Those flags are applied like for built-in commands and not passed on.
+// It reports whether a plugin handled the invocation.
+func runPlugin(args []string) (bool, error) {
+	flags, args, ok := storeFlagArgs(args)
+	if !ok || len(args) == 0 {
+		return false, nil
+	}
+	if found, _, err := rootCmd.Find(args); err == nil && found != rootCmd {
+		return false, nil
+	}
+	path, err := exec.LookPath(pluginPrefix + args[0])
+	if err != nil {
+		return false, nil //nolint:nilerr // Not a plugin; let cobra report the unknown command
+	}
+	for name, value := range flags {
+		if err := rootCmd.PersistentFlags().Set(name, value); err != nil {
+			return true, err
+		}
+	}
+	if err := applyStoreFlags(rootCmd); err != nil {
+		return true, err
+	}
+
+	plugin := exec.Command(path, args[1:]...) //nolint:gosec // Running user-installed plugins is the point
+	plugin.Stdin = os.Stdin
+	plugin.Stdout = os.Stdout
+	plugin.Stderr = os.Stderr
+	plugin.Env = append(os.Environ(), pluginEnv()...)
+	return true, plugin.Run()
+}
+
+// storeFlagArgs splits the --db and --config flags leading args off the
+// rest. ok is false when any other flag comes first: that invocation is
+// for cobra to handle.
+func storeFlagArgs(args []string) (flags map[string]string, rest []string, ok bool) {
+	flags = make(map[string]string)
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(args[0], "--"), "=")
+		if !strings.HasPrefix(args[0], "--") || (name != "db" && name != "config") {
+			return nil, nil, false
+		}
+		if !hasValue {
+			if len(args) < 2 {
+				return nil, nil, false
+			}
+			value, args = args[1], args[1:]
+		}
+		flags[name] = value
+		args = args[1:]
+	}
+	return flags, args, true
+}
+
+// pluginEnv returns the environment passed to plugins.
+func pluginEnv() []string {
+	env := []string{
+		"MEMO_CONFIG_DIR=" + charm.ConfigDir(),
+		"MEMO_DB_NAME=" + charm.DatabaseName(),
+	}
+	if self, err := os.Executable(); err == nil {
+		env = append(env, "MEMO_BIN="+self)
+	}
+	if cfg, err := charm.LoadConfig(); err == nil && cfg.CharmHost != "" {
+		env = append(env, "MEMO_CHARM_HOST="+cfg.CharmHost)
+	}
+	if client, err := charm.NewClient(); err == nil {
+		if dbPath, err := client.DBPath(); err == nil {
+			env = append(env, "MEMO_DB_PATH="+dbPath)
+		}
+	}
+	return env
+}
+
+// pluginExitCode extracts a plugin's exit status from its error, reporting
+// errors that kept the plugin from running at all.
+func pluginExitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	fmt.Fprintf(os.Stderr, "Error: failed to run plugin: %v\n", err)
+	return 1
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
@@ -0,0 +1,202 @@
+// ABOUTME: Queue command for a read-later workflow over notes.
+// ABOUTME: Moves notes through unread, reading, and done, and opens the next unread note.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Keep a read-later queue of notes",
+	Long: `Queue notes to read later, like saved articles. A queued note is unread,
+then reading once 'memo queue next' opens it, then done. Notes are read in
+the order they were queued.
+
+Statuses sync with the note and don't change its last-updated time;
+'memo list --status unread' filters by them too.
+
+Examples:
+  memo queue add abc123
+  memo queue add --tag clipped      # Everything tagged by a clipper
+  memo queue next
+  memo queue done
+  memo queue list`,
+}
+
+var queueAddCmd = &cobra.Command{
+	Use:   "add [id...]",
+	Short: "Add notes to the back of the queue as unread",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notes, err := noteArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		added := 0
+		for _, nt := range notes {
+			if nt.Note.Status == models.StatusUnread || nt.Note.Status == models.StatusReading {
+				continue
+			}
+			if err := charmClient.SetStatus(nt.Note.ID, models.StatusUnread); err != nil {
+				return fmt.Errorf("failed to queue %s: %w", nt.Note.ID.String()[:6], err)
+			}
+			added++
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Queued %s", ui.Plural(added, "note"))))
+		return nil
+	},
+}
+
+var queueNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Open the next unread note and mark it reading",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		unread, err := queuedNotes(models.StatusUnread)
+		if err != nil {
+			return err
+		}
+		if len(unread) == 0 {
+			fmt.Println("Nothing left to read.")
+			return nil
+		}
+
+		note, tags, err := charmClient.GetNoteByID(unread[0].Note.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get note: %w", err)
+		}
+		if err := charmClient.SetStatus(note.ID, models.StatusReading); err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+		note.Status = models.StatusReading
+
+		fmt.Print(ui.FormatNoteHeader(note, tagsToModelsList(tags)))
+		content, _ := ui.FormatNoteContent(note.Content)
+		fmt.Print(content)
+		if len(unread) > 1 {
+			fmt.Print(ui.FormatNotePreview(fmt.Sprintf("%d more unread · 'memo queue done' when finished", len(unread)-1)))
+		}
+		return nil
+	},
+}
+
+var queueDoneCmd = &cobra.Command{
+	Use:   "done [id]",
+	Short: "Mark a note done (default: the one you're reading)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var note *models.Note
+		if len(args) == 1 {
+			n, _, err := getNote(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to get note: %w", err)
+			}
+			note = n
+		} else {
+			reading, err := queuedNotes(models.StatusReading)
+			if err != nil {
+				return err
+			}
+			switch len(reading) {
+			case 0:
+				return fmt.Errorf("no note is being read: pass an ID")
+			case 1:
+				note = reading[0].Note
+			default:
+				return fmt.Errorf("%d notes are being read: pass an ID", len(reading))
+			}
+		}
+
+		if err := charmClient.SetStatus(note.ID, models.StatusDone); err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+		fmt.Println(ui.Success("Done with " + note.Title))
+		return nil
+	},
+}
+
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the queue: notes being read, then unread ones in order",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+
+		statuses := []string{models.StatusReading, models.StatusUnread}
+		if all {
+			statuses = append(statuses, models.StatusDone)
+		}
+
+		view := &listView{}
+		defer view.save()
+		return charmClient.ReadSession(func() error {
+			for _, status := range statuses {
+				notes, err := queuedNotes(status)
+				if err != nil {
+					return err
+				}
+				if len(notes) == 0 {
+					continue
+				}
+				fmt.Printf("%s (%d)\n", status, len(notes))
+				for _, nt := range notes {
+					view.print(nt)
+				}
+			}
+			if len(view.ids) == 0 {
+				fmt.Println("The queue is empty.")
+			}
+			return nil
+		})
+	},
+}
+
+var queueRmCmd = &cobra.Command{
+	Use:   "rm [id...]",
+	Short: "Take notes out of the queue",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		notes, err := noteArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+		for _, nt := range notes {
+			if err := charmClient.SetStatus(nt.Note.ID, ""); err != nil {
+				return fmt.Errorf("failed to update %s: %w", nt.Note.ID.String()[:6], err)
+			}
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Removed %s from the queue", ui.Plural(len(notes), "note"))))
+		return nil
+	},
+}
+
+// queuedNotes returns the notes with a queue status in the order they were
+// queued.
+func queuedNotes(status string) ([]*charm.NoteWithTags, error) {
+	notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{Status: status, ContentLimit: charm.NoContent})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list queue: %w", err)
+	}
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].Note.QueuedAt.Before(notes[j].Note.QueuedAt) })
+	return notes, nil
+}
+
+func init() {
+	queueAddCmd.Flags().String("tag", "", "queue every note with this tag")
+	queueAddCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	queueRmCmd.Flags().String("tag", "", "take every note with this tag out of the queue")
+	queueRmCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	queueListCmd.Flags().Bool("all", false, "include notes already read")
+	queueCmd.AddCommand(queueAddCmd)
+	queueCmd.AddCommand(queueNextCmd)
+	queueCmd.AddCommand(queueDoneCmd)
+	queueCmd.AddCommand(queueListCmd)
+	queueCmd.AddCommand(queueRmCmd)
+	rootCmd.AddCommand(queueCmd)
+}
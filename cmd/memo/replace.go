@@ -0,0 +1,169 @@
+// ABOUTME: Replace command for sed-style substitutions in note content.
+// ABOUTME: Previews a word diff of each change and applies them in one batch after confirmation.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/subst"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+// diffContextLines is how many unchanged lines surround each change in
+// substitution previews.
+const diffContextLines = 1
+
+var replaceCmd = &cobra.Command{
+	Use:   "replace [id-prefix...] <s/old/new/flags>",
+	Short: "Substitute text in notes, sed style",
+	Long: `Apply a sed-style substitution to note content. The expression is
+s/old/new/ with optional flags g (every match, not just the first) and i
+(ignore case). old is a regular expression; in new, & is the whole match
+and \1-\9 are groups. Any punctuation can replace '/', e.g. s|/a|/b|.
+
+--regex and --with give the pattern and replacement separately (Go
+syntax, $1 for groups); every match is replaced and all arguments are
+ID prefixes.
+
+Select notes by ID prefix, with --tag, or with --all (narrowed by --tag).
+Each change is previewed as a word diff and applied together after one
+confirmation, then synced once.
+
+Examples:
+  memo replace abc123 's/teh/the/g'
+  memo replace abc123 --regex 'v(\d+)' --with 'version $1'
+  memo replace --all --tag work 's/old-host/new-host/g' --dry-run`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		force, _ := cmd.Flags().GetBool("force")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		sub, prefixes, err := substitutionArgs(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		var notes []*charm.NoteWithTags
+		if all {
+			if len(prefixes) > 0 {
+				return fmt.Errorf("pass ID prefixes or --all, not both")
+			}
+			var filter *charm.NoteFilter
+			if tag, _ := cmd.Flags().GetString("tag"); tag != "" {
+				filter = &charm.NoteFilter{Tag: &tag}
+			}
+			notes, err = charmClient.ListNotesWithTags(filter)
+			if err != nil {
+				return fmt.Errorf("failed to list notes: %w", err)
+			}
+		} else if notes, err = noteArgs(cmd, prefixes); err != nil {
+			return err
+		}
+
+		changed, total := substituteNotes(notes, sub)
+		if len(changed) == 0 {
+			fmt.Println("No matches.")
+			return nil
+		}
+		summary := fmt.Sprintf("%s in %s", ui.Plural(total, "replacement"), ui.Plural(len(changed), "note"))
+		if dryRun {
+			fmt.Println(summary + " (dry run, nothing changed)")
+			return nil
+		}
+
+		if !force {
+			ok, err := confirm(fmt.Sprintf("Apply %s?", summary))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Canceled.")
+				return nil
+			}
+		}
+
+		if err := applyNoteChanges(changed); err != nil {
+			return err
+		}
+		fmt.Println(ui.Success("Applied " + summary))
+		return nil
+	},
+}
+
+// substitutionArgs splits the arguments into the substitution and the ID
+// prefixes: the last argument is the s/old/new/ expression unless --regex
+// is given.
+func substitutionArgs(cmd *cobra.Command, args []string) (*subst.Sub, []string, error) {
+	if cmd.Flags().Changed("regex") {
+		pattern, _ := cmd.Flags().GetString("regex")
+		with, _ := cmd.Flags().GetString("with")
+		sub, err := subst.New(pattern, with, true)
+		return sub, args, err
+	}
+	if cmd.Flags().Changed("with") {
+		return nil, nil, fmt.Errorf("--with needs --regex")
+	}
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("a substitution like 's/old/new/' (or --regex and --with) is required")
+	}
+	sub, err := subst.Parse(args[len(args)-1])
+	return sub, args[:len(args)-1], err
+}
+
+// noteChange is a note whose content a batch edit rewrites.
+type noteChange struct {
+	note    *charm.NoteWithTags
+	content string
+}
+
+// substituteNotes applies sub to each note, printing a preview of every
+// change, and returns the changed notes and the total replacements.
+func substituteNotes(notes []*charm.NoteWithTags, sub *subst.Sub) ([]noteChange, int) {
+	var changed []noteChange
+	total := 0
+	for _, nt := range notes {
+		if vault.IsSealed(nt.Note.Content) {
+			continue // Rewriting ciphertext would corrupt it
+		}
+		content, n := sub.Apply(nt.Note.Content)
+		if n == 0 || content == nt.Note.Content {
+			continue
+		}
+		total += n
+		changed = append(changed, noteChange{note: nt, content: content})
+
+		fmt.Println(ui.FormatDiffHeader(noteDiffName(nt.Note), ui.Plural(n, "replacement")))
+		fmt.Println(ui.FormatWordDiffContext(ui.WordDiff(nt.Note.Content, content), diffContextLines))
+	}
+	return changed, total
+}
+
+// applyNoteChanges saves rewritten notes in a single batch.
+func applyNoteChanges(changes []noteChange) error {
+	batch := make([]*charm.NoteWithTags, len(changes))
+	for i, ch := range changes {
+		ch.note.Note.Content = ch.content
+		ch.note.Note.Touch()
+		batch[i] = ch.note
+	}
+	if err := charmClient.UpdateNotes(batch); err != nil {
+		return fmt.Errorf("failed to update notes: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	replaceCmd.Flags().String("regex", "", "pattern to replace (Go regexp syntax)")
+	replaceCmd.Flags().String("with", "", "replacement for --regex ($1 for groups)")
+	replaceCmd.Flags().Bool("all", false, "apply to every note (narrow with --tag)")
+	replaceCmd.Flags().String("tag", "", "apply to notes with this tag")
+	replaceCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	replaceCmd.Flags().Bool("dry-run", false, "preview the changes without saving")
+	replaceCmd.Flags().BoolP("force", "f", false, "skip confirmation")
+	rootCmd.AddCommand(replaceCmd)
+}
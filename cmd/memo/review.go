@@ -0,0 +1,212 @@
+// ABOUTME: Review command for taking code review notes on a PR or branch.
+// ABOUTME: Starts a structured review note, adds file/line comments, and exports them as markdown.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/githook"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/review"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Take code review notes on a pull request or branch",
+	Long: `Keep review comments in a note while reading a pull request.
+
+'memo review start' creates a note titled "Review: owner/repo#123" (or the
+branch name), tagged "review", with Summary and Files sections, and makes
+it the current review. 'memo review add-file' adds comments under a
+heading per file, anchored to a line or range with --line. Write the
+summary with 'memo edit'. 'memo review export' prints the summary and
+comments as markdown to post on the pull request.
+
+Examples:
+  memo review start https://github.com/harperreed/memo/pull/42
+  memo review add-file internal/sync.go --line 42 "Retry on 503?"
+  memo review add-file README.md "Mention the new flag"
+  memo review export | gh pr review 42 --comment --body-file -`,
+}
+
+var reviewStartCmd = &cobra.Command{
+	Use:   "start <pr-url-or-branch>",
+	Short: "Start (or resume) a review note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagsFlag, _ := cmd.Flags().GetString("tags")
+		target := args[0]
+		title := review.Title(target)
+
+		// Starting the same review again resumes its note
+		tag := review.Tag
+		notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{Tag: &tag, ContentLimit: charm.NoContent})
+		if err != nil {
+			return fmt.Errorf("failed to list reviews: %w", err)
+		}
+		var note *models.Note
+		for _, nt := range notes {
+			if nt.Note.Title == title {
+				note = nt.Note
+				break
+			}
+		}
+
+		verb := "Resumed"
+		if note == nil {
+			verb = "Started"
+			note = models.NewNote(title, review.Template(target, time.Now()))
+			tags := append([]string{review.Tag}, collectTags(tagsFlag, false)...)
+			if err := charmClient.CreateNote(note, tags); err != nil {
+				return fmt.Errorf("failed to create note: %w", err)
+			}
+			setHookNote(note, tags)
+		}
+
+		if err := (&review.State{NoteID: note.ID.String()}).Save(charm.ReviewStatePath()); err != nil {
+			return fmt.Errorf("failed to save review state: %w", err)
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("%s %s (%s)", verb, title, note.ID.String()[:6])))
+		return nil
+	},
+}
+
+var reviewAddFileCmd = &cobra.Command{
+	Use:   "add-file <path> <comment>",
+	Short: "Add a comment on a file to the current review",
+	Long: `Add a comment on a file to the current review note, under the file's
+heading. --line anchors it to a line (42) or range (42-50). Paths of files
+in the current git repository are recorded relative to its root.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lineFlag, _ := cmd.Flags().GetString("line")
+		noteFlag, _ := cmd.Flags().GetString("note")
+
+		if lineFlag != "" && !review.ValidLine(lineFlag) {
+			return fmt.Errorf("invalid --line %q: use a line number or a range like 42-50", lineFlag)
+		}
+		text := strings.TrimSpace(strings.Join(args[1:], " "))
+		if text == "" {
+			return fmt.Errorf("comment cannot be empty")
+		}
+
+		note, tags, err := reviewNote(noteFlag)
+		if err != nil {
+			return err
+		}
+		if err := vault.CheckEdit(note.Title, note.Content); err != nil {
+			return err
+		}
+		note.Content = review.AddComment(note.Content, review.Comment{Path: reviewPath(args[0]), Line: lineFlag, Text: text})
+		note.Touch()
+		if err := charmClient.UpdateNote(note, tags); err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+
+		setHookNote(note, tags)
+		fmt.Println(ui.Success("Added comment to " + note.Title))
+		return nil
+	},
+}
+
+var reviewExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the review as markdown for the pull request",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noteFlag, _ := cmd.Flags().GetString("note")
+		output, _ := cmd.Flags().GetString("output")
+
+		note, _, err := reviewNote(noteFlag)
+		if err != nil {
+			return err
+		}
+		markdown := review.Export(note.Content)
+		if markdown == "" {
+			return fmt.Errorf("%s has no summary or comments yet", note.Title)
+		}
+
+		if output == "" || output == "-" {
+			fmt.Print(markdown)
+			return nil
+		}
+		if err := os.WriteFile(output, []byte(markdown), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output, err)
+		}
+		fmt.Fprintln(os.Stderr, ui.Success("Wrote "+output))
+		return nil
+	},
+}
+
+// reviewNote loads the review note named by ref, or the current review.
+func reviewNote(ref string) (*models.Note, []string, error) {
+	if ref != "" {
+		note, tags, err := getNote(ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("note not found: %w", err)
+		}
+		return note, tags, nil
+	}
+
+	state, err := review.Load(charm.ReviewStatePath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read review state: %w", err)
+	}
+	if state == nil {
+		return nil, nil, fmt.Errorf("no review started: run 'memo review start <pr-url-or-branch>' or pass --note")
+	}
+	id, err := uuid.Parse(state.NoteID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid review state: %w", err)
+	}
+	note, tags, err := charmClient.GetNoteByID(id)
+	if errors.Is(err, charm.ErrNoteNotFound) {
+		return nil, nil, fmt.Errorf("the current review note was deleted: start another with 'memo review start'")
+	}
+	return note, tags, err
+}
+
+// reviewPath returns path relative to the root of the git repository it's
+// in, or as given when it isn't in one.
+func reviewPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	root, err := githook.TopLevel(filepath.Dir(abs))
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	// Compare resolved paths, since git reports the root with symlinks resolved
+	if resolved, err := filepath.EvalSymlinks(filepath.Dir(abs)); err == nil {
+		abs = filepath.Join(resolved, filepath.Base(abs))
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func init() {
+	reviewStartCmd.Flags().String("tags", "", "comma-separated tags to add")
+	reviewAddFileCmd.Flags().String("line", "", "line number or range the comment is about, e.g. 42 or 42-50")
+	reviewAddFileCmd.Flags().String("note", "", "review note to add to (default: the current review)")
+	reviewExportCmd.Flags().String("note", "", "review note to export (default: the current review)")
+	reviewExportCmd.Flags().StringP("output", "o", "", "write to this file instead of stdout")
+	reviewCmd.AddCommand(reviewStartCmd)
+	reviewCmd.AddCommand(reviewAddFileCmd)
+	reviewCmd.AddCommand(reviewExportCmd)
+	rootCmd.AddCommand(reviewCmd)
+}
@@ -9,49 +9,107 @@ import (
 	"os"
 	"strings"
 
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/charm"
 	"github.com/harper/memo/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var rmCmd = &cobra.Command{
-	Use:   "rm <id-prefix>",
-	Short: "Remove a note",
-	Long:  `Delete a note and all its attachments.`,
-	Args:  cobra.ExactArgs(1),
+	Use:   "rm [id-prefix...]",
+	Short: "Remove notes",
+	Long: `Delete notes and all their attachments.
+
+Pass several ID prefixes, or --tag to delete every note with a tag. All
+notes are deleted together after a single confirmation, and synced once.
+With --quiet nothing is printed on success. Uploads made with 'memo share'
+stay up: rm lists them, and 'memo share --revoke <url>' takes them down.
+
+Examples:
+  memo rm abc123
+  memo rm abc123 def456 --force
+  memo rm --tag scratch`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		prefix := args[0]
 		force, _ := cmd.Flags().GetBool("force")
+		quiet, _ := cmd.Flags().GetBool("quiet")
 
-		note, _, err := charmClient.GetNoteByPrefix(prefix)
+		notes, err := noteArgs(cmd, args)
 		if err != nil {
-			return fmt.Errorf("failed to get note: %w", err)
+			return err
 		}
 
 		if !force {
-			fmt.Printf("Delete note %q (%s)? [y/N] ", note.Title, note.ID.String()[:6])
-			reader := bufio.NewReader(os.Stdin)
-			response, err := reader.ReadString('\n')
+			var prompt string
+			if len(notes) == 1 {
+				prompt = fmt.Sprintf("Delete note %q (%s)?", notes[0].Note.Title, notes[0].Note.ID.String()[:6])
+			} else {
+				for _, nt := range notes {
+					fmt.Printf("  %s  %s\n", nt.Note.ID.String()[:6], nt.Note.Title)
+				}
+				prompt = fmt.Sprintf("Delete these %d notes?", len(notes))
+			}
+			ok, err := confirm(prompt)
 			if err != nil {
-				return fmt.Errorf("failed to read confirmation: %w", err)
+				return err
 			}
-			response = strings.TrimSpace(strings.ToLower(response))
-			if response != "y" && response != "yes" {
+			if !ok {
 				fmt.Println("Canceled.")
 				return nil
 			}
 		}
 
-		// DeleteNote handles cascade deletion of attachments
-		if err := charmClient.DeleteNote(note.ID); err != nil {
-			return fmt.Errorf("failed to delete note: %w", err)
+		ids := make([]uuid.UUID, len(notes))
+		var live []*charm.Share
+		for i, nt := range notes {
+			ids[i] = nt.Note.ID
+			shares, _ := charmClient.ListShares(nt.Note.ID) // Best-effort: only used for the warning
+			for _, s := range shares {
+				if !s.Revoked() {
+					live = append(live, s)
+				}
+			}
+		}
+		// DeleteNotes handles cascade deletion of attachments
+		if err := charmClient.DeleteNotes(ids); err != nil {
+			return fmt.Errorf("failed to delete notes: %w", err)
 		}
 
-		fmt.Println(ui.Success(fmt.Sprintf("Deleted note %s", note.ID.String()[:6])))
+		if len(notes) == 1 {
+			setHookNote(notes[0].Note, notes[0].Tags)
+		}
+		switch {
+		case quiet:
+			// Scripts asked for no output
+		case len(notes) == 1:
+			fmt.Println(ui.Success(fmt.Sprintf("Deleted note %s", notes[0].Note.ID.String()[:6])))
+		default:
+			fmt.Println(ui.Success(fmt.Sprintf("Deleted %d notes", len(notes))))
+		}
+		// Uploads outlive the note; their records are kept for --revoke
+		for _, s := range live {
+			color.Yellow("  ⚠ Still shared at %s: run 'memo share --revoke %s' to take it down", s.URL, s.URL)
+		}
 		return nil
 	},
 }
 
+// confirm asks a yes/no question on stdin, defaulting to no.
+func confirm(prompt string) (bool, error) {
+	fmt.Printf("%s [y/N] ", prompt)
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read confirmation: %w", err)
+	}
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
+
 func init() {
+	rmCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	rmCmd.Flags().String("tag", "", "delete every note with this tag")
 	rmCmd.Flags().BoolP("force", "f", false, "skip confirmation")
+	rmCmd.Flags().BoolP("quiet", "q", false, "print nothing on success")
 	rootCmd.AddCommand(rmCmd)
 }
@@ -0,0 +1,111 @@
+// ABOUTME: Rollup command for weekly and monthly summary notes.
+// ABOUTME: Links all notes created in a period, grouped by tag.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/rollup"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var rollupCmd = &cobra.Command{
+	Use:   "rollup",
+	Short: "Create a weekly or monthly rollup note",
+	Long: `Generate a summary note linking every note created in a period,
+grouped by tag. The note is tagged rollup:<period> (e.g. rollup:2024-w32)
+and is updated in place if it already exists.
+
+Use --previous to roll up the last completed period, which suits a
+scheduler such as cron:
+
+  # Every Monday at 08:00, roll up last week
+  0 8 * * 1 memo rollup --previous
+  # On the 1st of each month, roll up last month
+  0 8 1 * * memo rollup --month --previous`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		monthFlag, _ := cmd.Flags().GetBool("month")
+		dateFlag, _ := cmd.Flags().GetString("date")
+		previousFlag, _ := cmd.Flags().GetBool("previous")
+
+		at := time.Now()
+		if dateFlag != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", dateFlag, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --date (want YYYY-MM-DD): %w", err)
+			}
+			at = parsed
+		}
+
+		period := rollup.WeekOf(at)
+		if monthFlag {
+			period = rollup.MonthOf(at)
+		}
+		if previousFlag {
+			period = previousPeriod(period, monthFlag)
+		}
+
+		notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{})
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+
+		entries := make([]rollup.Entry, 0, len(notes))
+		for _, nt := range notes {
+			entries = append(entries, rollup.Entry{Note: nt.Note, Tags: nt.Tags})
+		}
+		selected := rollup.Select(period, entries)
+		content := rollup.Render(period, selected)
+
+		// Update the existing rollup for this period rather than duplicating it
+		periodTag := period.Tag()
+		existing, err := charmClient.ListNotes(&charm.NoteFilter{Tag: &periodTag, Limit: 1})
+		if err != nil {
+			return fmt.Errorf("failed to look up existing rollup: %w", err)
+		}
+		if len(existing) > 0 {
+			note := existing[0]
+			if err := vault.CheckEdit(note.Title, note.Content); err != nil {
+				return err
+			}
+			tags, _ := charmClient.GetNoteTags(note.ID)
+			note.Content = content
+			note.Touch()
+			if err := charmClient.UpdateNote(note, tags); err != nil {
+				return fmt.Errorf("failed to update rollup: %w", err)
+			}
+			fmt.Println(ui.Success(fmt.Sprintf("Updated %s (%d notes) %s", period.Title(), len(selected), note.ID.String()[:6])))
+			return nil
+		}
+
+		note := models.NewNote(period.Title(), content)
+		if err := charmClient.CreateNote(note, []string{"rollup", periodTag}); err != nil {
+			return fmt.Errorf("failed to create rollup: %w", err)
+		}
+
+		fmt.Println(ui.Success(fmt.Sprintf("Created %s (%d notes) %s", period.Title(), len(selected), note.ID.String()[:6])))
+		return nil
+	},
+}
+
+// previousPeriod returns the period immediately before p.
+func previousPeriod(p rollup.Period, month bool) rollup.Period {
+	before := p.Start.Add(-time.Nanosecond)
+	if month {
+		return rollup.MonthOf(before)
+	}
+	return rollup.WeekOf(before)
+}
+
+func init() {
+	rollupCmd.Flags().Bool("month", false, "roll up a calendar month instead of an ISO week")
+	rollupCmd.Flags().String("date", "", "any date within the period (YYYY-MM-DD, default: today)")
+	rollupCmd.Flags().Bool("previous", false, "roll up the period before the one containing --date")
+	rootCmd.AddCommand(rollupCmd)
+}
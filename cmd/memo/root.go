@@ -5,8 +5,12 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/usage"
 	"github.com/spf13/cobra"
 )
 
@@ -23,15 +27,31 @@ const banner = `
 
 var (
 	charmClient *charm.Client
+
+	// exitCode, when set by a command that succeeded, becomes memo's exit
+	// status after post hooks run (memo run passes on its command's status)
+	exitCode int
 )
 
 var rootCmd = &cobra.Command{
 	Use:   "memo",
 	Short: "A CLI notes tool with markdown support",
-	Long:  banner + `memo is a command-line notes tool that stores markdown notes with tags and attachments using Charm KV.`,
+	Long: banner + `memo is a command-line notes tool that stores markdown notes with tags and attachments using Charm KV.
+
+Hooks: executables named pre-<command> or post-<command> in
+~/.config/memo/hooks run around each command (e.g. post-add, post-edit,
+post-sync). They receive a JSON payload on stdin with the command, args,
+and the affected note; a failing pre hook aborts the command.
+
+Separate stores: --db (or $MEMO_DB_NAME) selects another database, e.g.
+"work", that syncs on its own; --config (or $MEMO_CONFIG_DIR) selects
+another config directory.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		// Skip client init for version command
-		if cmd.Name() == "version" {
+		if err := applyStoreFlags(cmd); err != nil {
+			return err
+		}
+
+		if skipsSetup(cmd) {
 			return nil
 		}
 
@@ -40,14 +60,89 @@ var rootCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to initialize charm client: %w", err)
 		}
-		return nil
+		charmClient.SetActor(cliActor(cmd))
+
+		if cfg, err := charm.LoadConfig(); err == nil {
+			ui.SetRenderOptions(ui.RenderOptions{Style: cfg.Theme, Width: cfg.RenderWidth, CodeTheme: cfg.CodeTheme})
+		}
+
+		if charmClient.UsageMetricsEnabled() {
+			_ = usage.RecordCommand(charm.UsagePath(), commandName(cmd)) // Best-effort, local only
+		}
+
+		// A failing pre hook aborts the command
+		return runHook("pre", cmd, args)
 	},
 	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
-		// Client is global and managed by charm package
+		// Client is global and managed by charm package. Commands that
+		// skipped the pre hook skip the post hook too
+		if !skipsSetup(cmd) {
+			runPostHook(cmd, args)
+		}
+		maintainIfDue(cmd)
+		recordSessionActivity(cmd)
+		refreshStatusCache(cmd)
 		return nil
 	},
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	if handled, err := runPlugin(os.Args[1:]); handled {
+		if err != nil {
+			os.Exit(pluginExitCode(err))
+		}
+		return nil
+	}
+	if err := rootCmd.Execute(); err != nil {
+		return err
+	}
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+	return nil
+}
+
+// skipsSetup reports whether cmd runs without the client and hooks: version
+// and config commands (config must stay usable even when the config file
+// is broken), status, which reads its cache and opens the database only
+// when needed, and the session shell hooks, which run at every prompt.
+func skipsSetup(cmd *cobra.Command) bool {
+	return cmd.Name() == "version" || cmd == statusCmd || cmd == sessionLogCmd || cmd == sessionHookCmd || (cmd.HasParent() && cmd.Parent() == configCmd)
+}
+
+// commandName returns a command's path without the leading "memo".
+func commandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// cliActor identifies a command in the audit log.
+func cliActor(cmd *cobra.Command) charm.Actor {
+	return charm.Actor{Source: charm.SourceCLI, Command: commandName(cmd)}
+}
+
+// applyStoreFlags exports --db and --config as environment variables so
+// the charm package, hooks, and plugins all see the same store.
+func applyStoreFlags(cmd *cobra.Command) error {
+	if db, _ := cmd.Flags().GetString("db"); db != "" {
+		if err := charm.ValidateDBName(db); err != nil {
+			return err
+		}
+		if err := os.Setenv(charm.DBNameEnv, db); err != nil {
+			return err
+		}
+	} else if err := charm.ValidateDBName(charm.DatabaseName()); err != nil {
+		return fmt.Errorf("%s: %w", charm.DBNameEnv, err)
+	}
+
+	if dir, _ := cmd.Flags().GetString("config"); dir != "" {
+		if err := os.Setenv(charm.ConfigDirEnv, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().String("db", "", "database name for a separate note store (env "+charm.DBNameEnv+")")
+	rootCmd.PersistentFlags().String("config", "", "config directory (env "+charm.ConfigDirEnv+")")
 }
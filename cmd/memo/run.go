@@ -0,0 +1,135 @@
+// ABOUTME: Run command for capturing a command's output into a note.
+// ABOUTME: Tees output to the terminal and records command, exit status, and duration.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/capture"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <command> [args...]",
+	Short: "Run a command and save its output as a note",
+	Long: `Run a command, showing its output as usual, then save the command, exit
+status, duration, and output as a new note tagged with the current
+directory - a debugging diary without copy and paste.
+
+A single argument runs through the shell (sh -c, or cmd /C on Windows), so
+pipes and quoting work; several arguments run the program directly. memo
+exits with the command's exit status.
+
+Output beyond 256 KB keeps its start and end. Terminal colors are removed
+and progress lines keep their final state.
+
+Examples:
+  memo run "make test"
+  memo run --title "flaky CI repro" --tags ci go test -count=10 ./...`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var c *exec.Cmd
+		switch {
+		case len(args) > 1:
+			c = exec.Command(args[0], args[1:]...) //nolint:gosec // Running the user's command is the point
+		case runtime.GOOS == "windows":
+			c = exec.Command("cmd", "/C", args[0]) //nolint:gosec // Running the user's command is the point
+		default:
+			c = exec.Command("sh", "-c", args[0]) //nolint:gosec // Running the user's command is the point
+		}
+		buf := capture.NewBuffer(capture.DefaultLimit)
+		c.Stdin = os.Stdin
+		c.Stdout = io.MultiWriter(os.Stdout, buf)
+		c.Stderr = io.MultiWriter(os.Stderr, buf)
+
+		// Ctrl-C reaches the command too; memo stays up to save the note
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt)
+		defer signal.Stop(signals)
+
+		pwd, _ := os.Getwd()
+		rec := &capture.Record{Command: shellJoin(args), Dir: pwd, Started: time.Now()}
+		err := c.Run()
+		rec.Duration = time.Since(rec.Started)
+
+		var exitErr *exec.ExitError
+		switch {
+		case errors.As(err, &exitErr):
+			code := exitErr.ExitCode()
+			rec.ExitCode = &code
+		case err != nil:
+			return fmt.Errorf("failed to run %s: %w", args[0], err)
+		default:
+			code := 0
+			rec.ExitCode = &code
+		}
+		rec.Output, _ = buf.Output()
+
+		title, _ := cmd.Flags().GetString("title")
+		if title == "" {
+			title = "$ " + ui.Snippet(rec.Command, "", 60)
+		}
+		if err := saveCapture(cmd, title, rec); err != nil {
+			return err
+		}
+		if *rec.ExitCode != 0 {
+			exitCode = *rec.ExitCode
+			if exitCode < 0 {
+				exitCode = 1 // Killed by a signal
+			}
+		}
+		return nil
+	},
+}
+
+// saveCapture stores a captured run as a note tagged with the current
+// directory and any --tags, and reports the new note on stderr so stdout
+// carries only the captured output.
+func saveCapture(cmd *cobra.Command, title string, rec *capture.Record) error {
+	tagsFlag, _ := cmd.Flags().GetString("tags")
+	tags := collectTags(tagsFlag, true)
+
+	note := models.NewNote(title, rec.Markdown())
+	if err := charmClient.CreateNote(note, tags); err != nil {
+		return fmt.Errorf("failed to create note: %w", err)
+	}
+	setHookNote(note, tags)
+	fmt.Fprintln(os.Stderr, ui.Success(fmt.Sprintf("Saved output to note %s", note.ID.String()[:6])))
+	return nil
+}
+
+// shellJoin shows args as one command line, quoting arguments with spaces.
+func shellJoin(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	line := ""
+	for i, a := range args {
+		if i > 0 {
+			line += " "
+		}
+		if a == "" || strings.ContainsAny(a, " \t\"'$`\\|&;<>()*?") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		line += a
+	}
+	return line
+}
+
+func init() {
+	runCmd.Flags().SetInterspersed(false) // Flags after the command belong to it
+	runCmd.Flags().String("title", "", "note title (default: the command)")
+	runCmd.Flags().String("tags", "", "comma-separated tags to add")
+	rootCmd.AddCommand(runCmd)
+}
@@ -0,0 +1,119 @@
+// ABOUTME: Serve command for running memo's web UI over HTTP.
+// ABOUTME: Binds localhost by default and guards every page with a per-run access token.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/harper/memo/internal/webui"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a web UI for reading and adding notes",
+	Long: `Run a small web server with --ui: a browser front end to list, search,
+and read notes (with rendered markdown) and to add new ones.
+
+The server binds 127.0.0.1:8080. To use it from a phone on your LAN, bind
+all interfaces with --addr 0.0.0.0:8080. Traffic is plain HTTP, so only do
+this on networks you trust.
+
+Every page needs an access token. A new one is generated each run and
+printed as part of the URL; opening that link once stores it in a cookie.
+--token sets a fixed token so bookmarks survive restarts, and --no-token
+turns the check off (only sensible on 127.0.0.1).
+
+Examples:
+  memo serve --ui
+  memo serve --ui --addr 0.0.0.0:8080 --token "$(cat ~/.memo-token)"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		withUI, _ := cmd.Flags().GetBool("ui")
+		addr, _ := cmd.Flags().GetString("addr")
+		token, _ := cmd.Flags().GetString("token")
+		noToken, _ := cmd.Flags().GetBool("no-token")
+
+		if !withUI {
+			return fmt.Errorf("nothing to serve: pass --ui for the web interface")
+		}
+		switch {
+		case noToken && token != "":
+			return fmt.Errorf("--token and --no-token can't be combined")
+		case !noToken && token == "":
+			b := make([]byte, 16)
+			if _, err := rand.Read(b); err != nil {
+				return fmt.Errorf("failed to generate token: %w", err)
+			}
+			token = hex.EncodeToString(b)
+		}
+
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		srv := &http.Server{
+			Handler:           webui.NewServer(charmClient, token),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		fmt.Println("Serving memo (Ctrl-C to stop):")
+		for _, url := range serveURLs(ln.Addr().(*net.TCPAddr), token) {
+			fmt.Println("  " + url)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = srv.Shutdown(shutdownCtx)
+		}()
+
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	},
+}
+
+// serveURLs lists the URLs the server can be opened at: the bound address,
+// or each interface's IPv4 address when bound to all interfaces.
+func serveURLs(addr *net.TCPAddr, token string) []string {
+	suffix := "/"
+	if token != "" {
+		suffix += "?token=" + token
+	}
+	if !addr.IP.IsUnspecified() {
+		return []string{fmt.Sprintf("http://%s%s", addr, suffix)}
+	}
+
+	urls := []string{fmt.Sprintf("http://127.0.0.1:%d%s", addr.Port, suffix)}
+	ifaceAddrs, _ := net.InterfaceAddrs()
+	for _, a := range ifaceAddrs {
+		if ipnet, ok := a.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+			urls = append(urls, fmt.Sprintf("http://%s:%d%s", ipnet.IP, addr.Port, suffix))
+		}
+	}
+	return urls
+}
+
+func init() {
+	serveCmd.Flags().Bool("ui", false, "serve the web interface")
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "address to listen on")
+	serveCmd.Flags().String("token", "", "access token (default: random each run)")
+	serveCmd.Flags().Bool("no-token", false, "don't require an access token")
+	rootCmd.AddCommand(serveCmd)
+}
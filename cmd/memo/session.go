@@ -0,0 +1,310 @@
+// ABOUTME: Session command for an automatic engineering logbook.
+// ABOUTME: Logs shell commands and note changes during a session to a per-day journal note.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/session"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Journal terminal work to a per-day session note",
+	Long: `Keep an automatic logbook while you work. Between 'memo session start'
+and 'memo session stop', timestamped entries are added to a note titled
+"Session YYYY-MM-DD" (tagged "session"): commands you run, once the shell
+hook is installed, and notes you add or edit.
+
+Commands are spooled locally, so the hook never waits on the database;
+they are written to the journal when the session stops and whenever a
+memo command changes notes.
+
+Examples:
+  eval "$(memo session hook zsh)"   # In ~/.zshrc
+  memo session start
+  memo session stop`,
+}
+
+var sessionStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start logging a session",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		state, err := session.Load(charm.SessionStatePath())
+		if err != nil {
+			return fmt.Errorf("failed to read session state: %w", err)
+		}
+		if state != nil {
+			return fmt.Errorf("a session is already running (started %s)", ui.FormatRelativeTime(state.StartedAt, time.Now()))
+		}
+
+		pwd, _ := os.Getwd()
+		state = &session.State{StartedAt: time.Now(), Dir: pwd}
+		if err := state.Save(charm.SessionStatePath()); err != nil {
+			return fmt.Errorf("failed to save session state: %w", err)
+		}
+		entry := session.Entry{Time: state.StartedAt, Kind: session.KindStart, Text: "Session started in " + pwd, Dir: pwd}
+		if err := session.Append(charm.SessionSpoolPath(), entry); err != nil {
+			return fmt.Errorf("failed to log session start: %w", err)
+		}
+		if err := flushSession(); err != nil {
+			return err
+		}
+
+		fmt.Println(ui.Success("Session started"))
+		fmt.Println(`Log shell commands with: eval "$(memo session hook bash|zsh|fish)"`)
+		return nil
+	},
+}
+
+var sessionStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the session and write its remaining entries",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		state, err := session.Load(charm.SessionStatePath())
+		if err != nil {
+			return fmt.Errorf("failed to read session state: %w", err)
+		}
+		if state == nil {
+			return fmt.Errorf("no session is running")
+		}
+
+		now := time.Now()
+		length := now.Sub(state.StartedAt).Round(time.Minute)
+		entry := session.Entry{Time: now, Kind: session.KindStop, Text: "Session stopped after " + length.String()}
+		if err := session.Append(charm.SessionSpoolPath(), entry); err != nil {
+			return fmt.Errorf("failed to log session stop: %w", err)
+		}
+		if err := os.Remove(charm.SessionStatePath()); err != nil {
+			return fmt.Errorf("failed to end session: %w", err)
+		}
+		if err := flushSession(); err != nil {
+			return err
+		}
+		fmt.Println(ui.Success("Session stopped after " + length.String()))
+		return nil
+	},
+}
+
+var sessionStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether a session is running",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		state, err := session.Load(charm.SessionStatePath())
+		if err != nil {
+			return fmt.Errorf("failed to read session state: %w", err)
+		}
+		if state == nil {
+			fmt.Println("No session running.")
+			return nil
+		}
+		fmt.Printf("Session running since %s (%s) in %s\n",
+			state.StartedAt.Format("15:04"), ui.FormatRelativeTime(state.StartedAt, time.Now()), state.Dir)
+		if session.Pending(charm.SessionSpoolPath()) {
+			fmt.Println("Some entries are waiting to be written to the journal.")
+		}
+		return nil
+	},
+}
+
+var sessionLogCmd = &cobra.Command{
+	Use:   "log [--exit N] [--dir DIR] -- <command line>",
+	Short: "Record a command in the running session (used by the shell hook)",
+	Long: `Record a command line in the running session. The shell hook calls this
+after each command; it only appends to a local spool and does nothing when
+no session is running.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exit, _ := cmd.Flags().GetInt("exit")
+		dir, _ := cmd.Flags().GetString("dir")
+
+		line := strings.TrimSpace(strings.Join(args, " "))
+		if line == "" || strings.HasPrefix(line, "memo session") {
+			return nil
+		}
+		if state, err := session.Load(charm.SessionStatePath()); err != nil || state == nil {
+			return err
+		}
+		return session.Append(charm.SessionSpoolPath(), session.Entry{
+			Time: time.Now(), Kind: session.KindCommand, Text: line, Dir: dir, Exit: exit,
+		})
+	},
+}
+
+var sessionHookCmd = &cobra.Command{
+	Use:       "hook <bash|zsh|fish>",
+	Short:     "Print the shell hook that logs commands during a session",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script, err := sessionHook(args[0], charm.SessionStatePath())
+		if err != nil {
+			return err
+		}
+		fmt.Print(script)
+		return nil
+	},
+}
+
+// sessionHook returns the shell snippet that logs each command while the
+// session state file exists. It checks the file itself so that outside a
+// session the prompt never starts memo.
+func sessionHook(shell, statePath string) (string, error) {
+	quoted := "'" + strings.ReplaceAll(statePath, "'", `'\''`) + "'"
+	switch shell {
+	case "bash":
+		return `__memo_session_log() {
+  local code=$? num line
+  read -r num line <<< "$(HISTTIMEFORMAT= history 1)"
+  if [ -f ` + quoted + ` ] && [ -n "$line" ] && [ "$num" != "$__memo_session_last" ]; then
+    (memo session log --exit "$code" --dir "$PWD" -- "$line" >/dev/null 2>&1 &)
+  fi
+  __memo_session_last=$num
+  return $code
+}
+PROMPT_COMMAND="__memo_session_log${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+`, nil
+	case "zsh":
+		return `__memo_session_preexec() { __memo_session_cmd=$1 }
+__memo_session_precmd() {
+  local code=$?
+  if [[ -n $__memo_session_cmd && -f ` + quoted + ` ]]; then
+    (memo session log --exit $code --dir "$PWD" -- "$__memo_session_cmd" &>/dev/null &)
+  fi
+  __memo_session_cmd=
+}
+autoload -Uz add-zsh-hook
+add-zsh-hook preexec __memo_session_preexec
+add-zsh-hook precmd __memo_session_precmd
+`, nil
+	case "fish":
+		return `function __memo_session_log --on-event fish_postexec
+    set -l code $status
+    if test -f ` + quoted + `; and test -n "$argv[1]"
+        memo session log --exit $code --dir "$PWD" -- "$argv[1]" >/dev/null 2>&1 &
+        disown 2>/dev/null
+    end
+end
+`, nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q (use bash, zsh, or fish)", shell)
+	}
+}
+
+// recordSessionActivity notes the note a command added or edited in the
+// running session, then writes spooled entries if the command already
+// changed the database (so read-only commands stay read-only).
+func recordSessionActivity(cmd *cobra.Command) {
+	if charmClient == nil || (cmd.HasParent() && cmd.Parent() == sessionCmd) {
+		return
+	}
+	if state, err := session.Load(charm.SessionStatePath()); err == nil && state != nil && hookNote != nil {
+		verb := "Edited"
+		switch {
+		case cmd == rmCmd:
+			verb = "Deleted"
+		case hookNote.CreatedAt.Equal(hookNote.UpdatedAt):
+			verb = "Added"
+		}
+		_ = session.Append(charm.SessionSpoolPath(), session.Entry{
+			Time: time.Now(),
+			Kind: session.KindNote,
+			Text: fmt.Sprintf("%s note [%s](memo://%s)", verb, hookNote.Title, hookNote.ID[:8]),
+		})
+	}
+	if charmClient.Changed() && session.Pending(charm.SessionSpoolPath()) {
+		if err := flushSession(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+}
+
+// flushSession appends spooled entries to each day's journal note,
+// creating notes as needed. Entries that can't be written go back to the
+// spool.
+func flushSession() error {
+	if charmClient == nil {
+		var err error
+		if charmClient, err = charm.GetClient(); err != nil {
+			return fmt.Errorf("failed to initialize charm client: %w", err)
+		}
+	}
+	entries, err := session.Drain(charm.SessionSpoolPath())
+	if err != nil {
+		return fmt.Errorf("failed to read session entries: %w", err)
+	}
+
+	byDay := make(map[string][]session.Entry)
+	for _, e := range entries {
+		byDay[e.Day()] = append(byDay[e.Day()], e)
+	}
+	days := make([]string, 0, len(byDay))
+	for d := range byDay {
+		days = append(days, d)
+	}
+	sort.Strings(days)
+
+	for i, day := range days {
+		if err := appendJournal(byDay[day]); err != nil {
+			for _, d := range days[i:] {
+				_ = session.Append(charm.SessionSpoolPath(), byDay[d]...)
+			}
+			return fmt.Errorf("failed to write session journal: %w", err)
+		}
+	}
+	return nil
+}
+
+// appendJournal adds entries from one day to that day's journal note.
+func appendJournal(entries []session.Entry) error {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	title := session.Title(entries[0].Time.Local())
+	lines := session.Format(entries)
+
+	tag := session.Tag
+	notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{Tag: &tag, ContentLimit: charm.NoContent})
+	if err != nil {
+		return err
+	}
+	for _, nt := range notes {
+		if nt.Note.Title != title {
+			continue
+		}
+		note, tags, err := charmClient.GetNoteByID(nt.Note.ID)
+		if err != nil {
+			return err
+		}
+		if err := vault.CheckEdit(note.Title, note.Content); err != nil {
+			return err
+		}
+		note.Content = strings.TrimRight(note.Content, "\n") + "\n" + lines
+		note.Touch()
+		return charmClient.UpdateNote(note, tags)
+	}
+
+	return charmClient.CreateNote(models.NewNote(title, lines), []string{session.Tag})
+}
+
+func init() {
+	sessionLogCmd.Flags().Int("exit", 0, "the command's exit status")
+	sessionLogCmd.Flags().String("dir", "", "the directory the command ran in")
+	sessionCmd.AddCommand(sessionStartCmd)
+	sessionCmd.AddCommand(sessionStopCmd)
+	sessionCmd.AddCommand(sessionStatusCmd)
+	sessionCmd.AddCommand(sessionLogCmd)
+	sessionCmd.AddCommand(sessionHookCmd)
+	rootCmd.AddCommand(sessionCmd)
+}
@@ -0,0 +1,234 @@
+// ABOUTME: Share command for publishing a note to a paste service and revoking it later.
+// ABOUTME: Uploads raw markdown or rendered HTML to a GitHub Gist or 0x0.st-style host.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/mdhtml"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/paste"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share [id-prefix]",
+	Short: "Publish a note to a paste service",
+	Long: `Upload a note to a paste service and print its URL.
+
+  --to gist    a secret GitHub Gist (--public for a public one), using the
+               gist_token setting or $GITHUB_TOKEN
+  --to paste   a 0x0.st-compatible file host (paste_url, default
+               https://0x0.st), which returns a deletion token
+
+The note's title is added as a heading and the markdown uploaded as is;
+--rendered uploads a standalone HTML page instead.
+
+Every upload is recorded with the note (and synced), so it can be listed
+with --list and taken down with --revoke <url> or --revoke all. Deleting
+the note leaves its uploads up; --revoke <url> without a note takes one
+down afterwards.
+
+Examples:
+  memo share abc123 --to gist
+  memo share abc123 --to paste --rendered
+  memo share abc123 --list
+  memo share abc123 --revoke all
+  memo share --revoke https://0x0.st/abc.md`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString("to")
+		list, _ := cmd.Flags().GetBool("list")
+		revoke, _ := cmd.Flags().GetString("revoke")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		pick, _ := cmd.Flags().GetBool("pick")
+
+		// A share outlives its note, so a single one can be revoked by URL alone
+		if len(args) == 0 && !pick && revoke != "" && revoke != "all" {
+			shares, err := charmClient.ListAllShares()
+			if err != nil {
+				return fmt.Errorf("failed to list shares: %w", err)
+			}
+			return revokeShares(shares, revoke, "")
+		}
+
+		note, _, err := noteArg(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case list:
+			return listShares(note)
+		case revoke != "":
+			shares, err := charmClient.ListShares(note.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list shares: %w", err)
+			}
+			return revokeShares(shares, revoke, " of note "+note.ID.String()[:6])
+		case to == "":
+			return fmt.Errorf("--to gist or --to paste is required")
+		}
+
+		svc, err := pasteService(to)
+		if err != nil {
+			return err
+		}
+		if public, _ := cmd.Flags().GetBool("public"); public {
+			gist, ok := svc.(*paste.Gist)
+			if !ok {
+				return fmt.Errorf("--public only applies to gists")
+			}
+			gist.Public = true
+		}
+
+		rendered, _ := cmd.Flags().GetBool("rendered")
+		filename, content, format, err := shareContent(note, rendered)
+		if err != nil {
+			return err
+		}
+
+		upload, err := svc.Upload(context.Background(), filename, content)
+		if err != nil {
+			return fmt.Errorf("failed to upload to %s: %w", to, err)
+		}
+		share := &charm.Share{
+			NoteID:    note.ID,
+			Service:   to,
+			ID:        upload.ID,
+			URL:       upload.URL,
+			Token:     upload.Token,
+			Format:    format,
+			CreatedAt: time.Now(),
+		}
+		if err := charmClient.SaveShare(share); err != nil {
+			// The upload exists; make sure the user can still find it
+			fmt.Println(upload.URL)
+			return fmt.Errorf("uploaded, but failed to record the share: %w", err)
+		}
+
+		if quiet {
+			fmt.Println(upload.URL)
+			return nil
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Shared note %s: %s", note.ID.String()[:6], upload.URL)))
+		return nil
+	},
+}
+
+// pasteService returns the uploader for a --to name, configured from the
+// config file.
+func pasteService(name string) (paste.Service, error) {
+	cfg := charmClient.Config()
+	if cfg == nil {
+		cfg = charm.DefaultConfig()
+	}
+	switch name {
+	case paste.ServiceGist:
+		token := cfg.GistToken
+		if token == "" {
+			token = os.Getenv("GITHUB_TOKEN")
+		}
+		return paste.NewGist(token), nil
+	case paste.ServicePaste:
+		return paste.NewHost(cfg.PasteURL), nil
+	default:
+		return nil, fmt.Errorf("unknown service %q (use gist or paste)", name)
+	}
+}
+
+// shareContent returns the filename, content, and format uploaded for a
+// note.
+func shareContent(note *models.Note, rendered bool) (string, string, string, error) {
+	name := sanitizeFilename(note.Title)
+	if !rendered {
+		return name + ".md", "# " + note.Title + "\n\n" + note.Content, "markdown", nil
+	}
+	page, err := mdhtml.RenderDocument(note.Title, "# "+note.Title+"\n\n"+note.Content)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to render note: %w", err)
+	}
+	return name + ".html", page, "html", nil
+}
+
+func listShares(note *models.Note) error {
+	shares, err := charmClient.ListShares(note.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list shares: %w", err)
+	}
+	if len(shares) == 0 {
+		fmt.Println("Not shared.")
+		return nil
+	}
+	now := time.Now()
+	for _, s := range shares {
+		state := "shared " + ui.FormatRelativeTime(s.CreatedAt, now)
+		if s.Revoked() {
+			state = "revoked " + ui.FormatRelativeTime(*s.RevokedAt, now)
+		}
+		fmt.Printf("%-5s %-8s %s  %s\n", s.Service, s.Format, s.URL, state)
+	}
+	return nil
+}
+
+// revokeShares takes down the share among shares with the given URL or
+// ID, or every live one with "all", and marks them revoked. of describes
+// where the shares came from for the no-match error.
+func revokeShares(shares []*charm.Share, which, of string) error {
+	var targets []*charm.Share
+	for _, s := range shares {
+		if s.Revoked() {
+			continue
+		}
+		if which == "all" || s.URL == which || s.ID == which {
+			targets = append(targets, s)
+		}
+	}
+	if len(targets) == 0 {
+		if which == "all" {
+			fmt.Println("No live shares.")
+			return nil
+		}
+		return fmt.Errorf("no live share%s matches %q", of, which)
+	}
+
+	var failed []string
+	for _, s := range targets {
+		svc, err := pasteService(s.Service)
+		if err == nil {
+			err = svc.Revoke(context.Background(), &paste.Upload{ID: s.ID, URL: s.URL, Token: s.Token})
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", s.URL, err))
+			continue
+		}
+		now := time.Now()
+		s.RevokedAt = &now
+		if err := charmClient.SaveShare(s); err != nil {
+			return fmt.Errorf("revoked %s, but failed to record it: %w", s.URL, err)
+		}
+		fmt.Println(ui.Success("Revoked " + s.URL))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to revoke:\n  %s", strings.Join(failed, "\n  "))
+	}
+	return nil
+}
+
+func init() {
+	shareCmd.Flags().String("to", "", "service to upload to (gist|paste)")
+	shareCmd.Flags().Bool("rendered", false, "upload rendered HTML instead of markdown")
+	shareCmd.Flags().Bool("public", false, "make the gist public")
+	shareCmd.Flags().Bool("list", false, "list the note's shares")
+	shareCmd.Flags().String("revoke", "", "take down a share by URL or ID, or 'all'")
+	shareCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	shareCmd.Flags().BoolP("quiet", "q", false, "print only the URL")
+	rootCmd.AddCommand(shareCmd)
+}
@@ -1,31 +1,123 @@
 // ABOUTME: Show command for displaying a single note.
-// ABOUTME: Renders markdown content with glamour.
+// ABOUTME: Renders markdown content with glamour, or emits raw, JSON, or single-field output.
 
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/mdsection"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/synchealth"
 	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
 	"github.com/spf13/cobra"
 )
 
 var showCmd = &cobra.Command{
-	Use:   "show <id-prefix>",
+	Use:   "show [id-prefix]",
 	Short: "Show a note",
-	Long:  `Display a note's full content with rendered markdown.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Display a note's full content with rendered markdown.
+
+For scripts and editors, --raw prints the markdown content unrendered,
+--json prints the note as JSON (same fields as 'memo export'), and
+--field prints a single field: id, title, content, tags (one per line),
+created, or updated.
+
+--section limits the output to one markdown heading and everything under
+it, up to the next heading of the same or a higher level. It combines with
+the other output modes.
+
+--stats adds a footer with relative created/updated times, word count,
+attachment total size, and whether the note has synced since its last
+change.
+
+--width and --style override the render_width and theme settings for one
+note; --style takes a glamour style name or a JSON style file.
+
+Examples:
+  memo show abc123
+  memo show abc123 --raw > note.md
+  memo show abc123 --json | jq .tags
+  memo show abc123 --field title
+  memo show abc123 --section "Action Items"
+  memo show abc123 --width 120 --style dracula
+  memo show abc123 --stats`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		prefix := args[0]
+		rawFlag, _ := cmd.Flags().GetBool("raw")
+		jsonFlag, _ := cmd.Flags().GetBool("json")
+		fieldFlag, _ := cmd.Flags().GetString("field")
+		sectionFlag, _ := cmd.Flags().GetString("section")
+		statsFlag, _ := cmd.Flags().GetBool("stats")
 
-		note, tags, err := charmClient.GetNoteByPrefix(prefix)
+		opts := ui.CurrentRenderOptions()
+		if cmd.Flags().Changed("width") {
+			opts.Width, _ = cmd.Flags().GetInt("width")
+		}
+		if cmd.Flags().Changed("style") {
+			opts.Style, _ = cmd.Flags().GetString("style")
+		}
+		ui.SetRenderOptions(opts)
+
+		note, tags, err := noteArg(cmd, args)
 		if err != nil {
-			return fmt.Errorf("failed to get note: %w", err)
+			return err
+		}
+
+		// Vault notes only show their ciphertext to scripts
+		if vault.IsSealed(note.Content) && !rawFlag && !jsonFlag && fieldFlag == "" {
+			fmt.Print(ui.FormatNoteHeader(note, tagsToModelsList(tags)))
+			fmt.Printf("Locked in the vault: run 'memo vault show %s' to read it.\n", note.ID.String()[:6])
+			return nil
+		}
+
+		if sectionFlag != "" {
+			section, err := mdsection.Extract(note.Content, sectionFlag)
+			if err != nil {
+				return sectionError(note.Content, sectionFlag, err)
+			}
+			note.Content = section
+		}
+
+		switch {
+		case rawFlag:
+			fmt.Print(note.Content)
+			if !strings.HasSuffix(note.Content, "\n") {
+				fmt.Println()
+			}
+			return nil
+		case fieldFlag != "":
+			return printNoteField(note, tags, fieldFlag)
 		}
 
 		attachments, _ := charmClient.ListAttachmentsByNote(note.ID)
 
+		if jsonFlag {
+			en := ExportNote{
+				ID:        note.ID.String(),
+				Title:     note.Title,
+				Content:   note.Content,
+				Tags:      tags,
+				CreatedAt: note.CreatedAt,
+				UpdatedAt: note.UpdatedAt,
+			}
+			for _, a := range attachments {
+				en.Attachments = append(en.Attachments, ExportAttachment{ID: a.ID.String(), Filename: a.Filename, MimeType: a.MimeType})
+			}
+			data, err := json.MarshalIndent(en, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
 		// Print header
 		fmt.Print(ui.FormatNoteHeader(note, tagsToModelsList(tags)))
 
@@ -41,15 +133,89 @@ var showCmd = &cobra.Command{
 					ID:       a.ID.String(),
 					Filename: a.Filename,
 					MimeType: a.MimeType,
+					Missing:  a.Missing,
 				})
 			}
 			fmt.Print(ui.FormatAttachmentList(attInfos))
 		}
 
+		if statsFlag {
+			stats := ui.NoteStats{
+				CreatedAt:   note.CreatedAt,
+				UpdatedAt:   note.UpdatedAt,
+				Words:       len(strings.Fields(note.Content)),
+				Attachments: len(attachments),
+				SyncStatus:  noteSyncStatus(note),
+			}
+			for _, a := range attachments {
+				stats.AttachmentBytes += int64(len(a.Data))
+			}
+			fmt.Print(ui.FormatNoteFooter(stats))
+		}
+
 		return nil
 	},
 }
 
+// printNoteField prints one field of a note, unformatted.
+func printNoteField(note *models.Note, tags []string, field string) error {
+	switch strings.ToLower(field) {
+	case "id":
+		fmt.Println(note.ID)
+	case "title":
+		fmt.Println(note.Title)
+	case "content":
+		fmt.Print(note.Content)
+		if !strings.HasSuffix(note.Content, "\n") {
+			fmt.Println()
+		}
+	case "tags":
+		for _, t := range tags {
+			fmt.Println(t)
+		}
+	case "created":
+		fmt.Println(note.CreatedAt.Format(time.RFC3339))
+	case "updated":
+		fmt.Println(note.UpdatedAt.Format(time.RFC3339))
+	default:
+		return fmt.Errorf("unknown field %q (want id, title, content, tags, created, or updated)", field)
+	}
+	return nil
+}
+
+// noteSyncStatus reports whether a note's latest change has been synced,
+// judged by the last successful sync.
+func noteSyncStatus(note *models.Note) string {
+	state, err := synchealth.Load(charm.SyncStatePath())
+	switch {
+	case err != nil:
+		return "unknown"
+	case state.LastSuccessAt.IsZero():
+		return "never synced"
+	case note.UpdatedAt.After(state.LastSuccessAt):
+		return "pending"
+	default:
+		return "synced"
+	}
+}
+
+// sectionError explains a failed --section lookup, listing the headings
+// the note does have.
+func sectionError(content, name string, err error) error {
+	if !errors.Is(err, mdsection.ErrNotFound) {
+		return err
+	}
+	headings := mdsection.Headings(content)
+	if len(headings) == 0 {
+		return fmt.Errorf("section %q not found: note has no headings", name)
+	}
+	names := make([]string, len(headings))
+	for i, h := range headings {
+		names[i] = h.Text
+	}
+	return fmt.Errorf("section %q not found (headings: %s)", name, strings.Join(names, ", "))
+}
+
 // tagsToModelsList converts string tags to model tags.
 func tagsToModelsList(tags []string) []*models.Tag {
 	result := make([]*models.Tag, len(tags))
@@ -60,5 +226,14 @@ func tagsToModelsList(tags []string) []*models.Tag {
 }
 
 func init() {
+	showCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	showCmd.Flags().Bool("raw", false, "print the markdown content without rendering")
+	showCmd.Flags().Bool("json", false, "print the note as JSON")
+	showCmd.Flags().String("field", "", "print a single field: id, title, content, tags, created, updated")
+	showCmd.Flags().String("section", "", "only show the named markdown heading and its subsections")
+	showCmd.Flags().Bool("stats", false, "add a footer with times, word count, attachment size, and sync status")
+	showCmd.Flags().Int("width", 0, "wrap rendered markdown at this column (default: render_width or 80)")
+	showCmd.Flags().String("style", "", "glamour style name or JSON style file (default: theme)")
+	showCmd.MarkFlagsMutuallyExclusive("raw", "json", "field")
 	rootCmd.AddCommand(showCmd)
 }
@@ -0,0 +1,107 @@
+// ABOUTME: Snip command for extracting fenced code blocks from a note.
+// ABOUTME: Prints one block (or all) to stdout or a file, filtered by language.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/harper/memo/internal/mdsection"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var snipCmd = &cobra.Command{
+	Use:   "snip [id-prefix]",
+	Short: "Extract code blocks from a note",
+	Long: `Print a fenced code block from a note, so notes can double as a snippet
+manager. Only the code is printed, without fences.
+
+--lang keeps blocks in that language (the first word after the fence) and
+--index picks the Nth remaining block, starting at 1 (default: the first).
+--all prints every matching block instead, separated by blank lines.
+--list shows the blocks with their numbers, languages, and first lines.
+
+Examples:
+  memo snip roadmap | pbcopy
+  memo snip abc123 --lang go --index 2
+  memo snip abc123 --lang sql -o query.sql
+  memo snip abc123 --list`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lang, _ := cmd.Flags().GetString("lang")
+		index, _ := cmd.Flags().GetInt("index")
+		all, _ := cmd.Flags().GetBool("all")
+		list, _ := cmd.Flags().GetBool("list")
+		output, _ := cmd.Flags().GetString("output")
+
+		note, _, err := noteArg(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		var blocks []mdsection.CodeBlock
+		for _, b := range mdsection.CodeBlocks(note.Content) {
+			if lang == "" || strings.EqualFold(b.Lang, lang) {
+				blocks = append(blocks, b)
+			}
+		}
+		if len(blocks) == 0 {
+			if lang != "" {
+				return fmt.Errorf("no %s code blocks in note %s", lang, note.ID.String()[:6])
+			}
+			return fmt.Errorf("no code blocks in note %s", note.ID.String()[:6])
+		}
+
+		if list {
+			for i, b := range blocks {
+				fmt.Printf("%2d  %-10s line %-4d %s\n", i+1, orDash(b.Lang), b.Line, ui.Snippet(b.Code, "", 50))
+			}
+			return nil
+		}
+
+		var code string
+		switch {
+		case all:
+			parts := make([]string, len(blocks))
+			for i, b := range blocks {
+				parts[i] = b.Code
+			}
+			code = strings.Join(parts, "\n")
+		case index < 1 || index > len(blocks):
+			return fmt.Errorf("--index %d out of range: note has %s", index, ui.Plural(len(blocks), "matching block"))
+		default:
+			code = blocks[index-1].Code
+		}
+
+		if output == "" || output == "-" {
+			fmt.Print(code)
+			return nil
+		}
+		if err := os.WriteFile(output, []byte(code), 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output, err)
+		}
+		fmt.Fprintln(os.Stderr, ui.Success("Wrote "+output))
+		return nil
+	},
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	snipCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	snipCmd.Flags().String("lang", "", "only blocks in this language")
+	snipCmd.Flags().Int("index", 1, "which matching block to print, starting at 1")
+	snipCmd.Flags().Bool("all", false, "print every matching block")
+	snipCmd.Flags().Bool("list", false, "list the blocks instead of printing one")
+	snipCmd.Flags().StringP("output", "o", "", "write to this file instead of stdout")
+	snipCmd.MarkFlagsMutuallyExclusive("index", "all", "list")
+	rootCmd.AddCommand(snipCmd)
+}
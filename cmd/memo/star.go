@@ -0,0 +1,62 @@
+// ABOUTME: Star and unstar commands for marking favorite notes.
+// ABOUTME: Stars sync with the note and are listed with 'memo list --starred'.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var starCmd = &cobra.Command{
+	Use:   "star [id]",
+	Short: "Star a note for quick access",
+	Long: `Star the notes you open every day. Starred notes are marked with ★ in
+listings, and 'memo list --starred' shows just them. Starring doesn't
+change a note's last-updated time.
+
+Examples:
+  memo star abc123
+  memo star --pick
+  memo list --starred
+  memo unstar abc123`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setStarred(cmd, args, true)
+	},
+}
+
+var unstarCmd = &cobra.Command{
+	Use:   "unstar [id]",
+	Short: "Remove a note's star",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setStarred(cmd, args, false)
+	},
+}
+
+func setStarred(cmd *cobra.Command, args []string, starred bool) error {
+	note, _, err := noteArg(cmd, args)
+	if err != nil {
+		return err
+	}
+	if err := charmClient.SetStarred(note.ID, starred); err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+
+	if starred {
+		fmt.Println(ui.Success("Starred " + note.Title))
+	} else {
+		fmt.Println(ui.Success("Unstarred " + note.Title))
+	}
+	return nil
+}
+
+func init() {
+	starCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	unstarCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	rootCmd.AddCommand(starCmd)
+	rootCmd.AddCommand(unstarCmd)
+}
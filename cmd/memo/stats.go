@@ -0,0 +1,93 @@
+// ABOUTME: Stats command for collection and local usage statistics.
+// ABOUTME: Shows note/tag counts, or opt-in usage metrics with --usage.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show note statistics",
+	Long: `Show counts for your note collection.
+
+With --usage, show local usage metrics (commands run and sync timings).
+Metrics are opt-in via "usage_metrics": true in the config file and are
+only ever written to a local file - nothing is reported over the network.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		usageFlag, _ := cmd.Flags().GetBool("usage")
+		if usageFlag {
+			return showUsageStats()
+		}
+
+		notes, err := charmClient.ListNotes(&charm.NoteFilter{})
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+		tags, err := charmClient.ListAllTags()
+		if err != nil {
+			return fmt.Errorf("failed to list tags: %w", err)
+		}
+		globalCount, err := charmClient.CountGlobalNotes()
+		if err != nil {
+			return fmt.Errorf("failed to count global notes: %w", err)
+		}
+
+		fmt.Println("Note Statistics")
+		fmt.Println(strings.Repeat("-", 40))
+		fmt.Printf("Notes:     %d\n", len(notes))
+		fmt.Printf("Global:    %d\n", globalCount)
+		fmt.Printf("Directory: %d\n", len(notes)-globalCount)
+		fmt.Printf("Tags:      %d\n", len(tags))
+		return nil
+	},
+}
+
+func showUsageStats() error {
+	if !charmClient.UsageMetricsEnabled() {
+		fmt.Printf("Usage metrics are %s.\n", color.YellowString("disabled"))
+		fmt.Printf("Set \"usage_metrics\": true in %s to start recording locally.\n", charm.ConfigPath())
+		return nil
+	}
+
+	m, err := usage.Load(charm.UsagePath())
+	if err != nil {
+		return fmt.Errorf("failed to load usage metrics: %w", err)
+	}
+
+	fmt.Println("Usage Statistics")
+	fmt.Println(strings.Repeat("-", 40))
+	fmt.Printf("File:      %s\n", charm.UsagePath())
+	fmt.Printf("Since:     %s\n", m.Since.Format("2006-01-02"))
+
+	fmt.Println("\nCommands:")
+	top := m.TopCommands()
+	if len(top) == 0 {
+		fmt.Println("  (none recorded)")
+	}
+	for _, c := range top {
+		fmt.Printf("  %-20s %d\n", c.Name, c.Count)
+	}
+
+	fmt.Println("\nSync:")
+	fmt.Printf("  Runs:      %d (%d failed)\n", m.Sync.Count, m.Sync.Failures)
+	if m.Sync.Count > 0 {
+		fmt.Printf("  Average:   %v\n", m.Sync.Average().Round(time.Millisecond))
+		fmt.Printf("  Longest:   %v\n", m.Sync.Longest.Round(time.Millisecond))
+		fmt.Printf("  Last:      %v at %s\n", m.Sync.Last.Round(time.Millisecond), m.Sync.LastRunAt.Format("2006-01-02 15:04"))
+	}
+	return nil
+}
+
+func init() {
+	statsCmd.Flags().Bool("usage", false, "show local usage metrics")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,130 @@
+// ABOUTME: Status command for a quick overview and prompt/status-line integration.
+// ABOUTME: --short reads cached counts only, so tmux and starship can call it on every redraw.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/statuscache"
+	"github.com/harper/memo/internal/synchealth"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show note counts for this directory and unsynced changes",
+	Long: `Show how many notes are tagged with the current directory, how many
+changed since the last successful sync, and when that sync was.
+
+--short prints one line for status bars, like "memo: 3 here · 2 unsynced".
+It reads counts cached by the last memo command that changed notes and
+never opens the database, so it's cheap enough to run on every prompt.
+Running memo status (without --short) refreshes the cache.
+
+Examples:
+  # tmux
+  set -g status-right '#(cd #{pane_current_path} && memo status --short)'
+
+  # starship.toml
+  [custom.memo]
+  command = "memo status --short"
+  when = true`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		short, _ := cmd.Flags().GetBool("short")
+		pwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		dir := dirKey(pwd)
+
+		var lastSync time.Time
+		if state, err := synchealth.Load(charm.SyncStatePath()); err == nil {
+			lastSync = state.LastSuccessAt
+		}
+
+		cache, _ := statuscache.Load(charm.StatusCachePath())
+		if short && cache != nil {
+			fmt.Println(cache.Short(dir, lastSync))
+			return nil
+		}
+
+		// No cache yet, or a full status: count from the database
+		if charmClient == nil {
+			if charmClient, err = charm.GetClient(); err != nil {
+				return fmt.Errorf("failed to initialize charm client: %w", err)
+			}
+		}
+		cache, err = buildStatusCache()
+		if err != nil {
+			return err
+		}
+		if err := cache.Save(charm.StatusCachePath()); err != nil {
+			return fmt.Errorf("failed to save status cache: %w", err)
+		}
+
+		if short {
+			fmt.Println(cache.Short(dir, lastSync))
+			return nil
+		}
+		fmt.Printf("Notes:     %d\n", cache.Total)
+		fmt.Printf("Here:      %d\n", cache.Here(dir))
+		fmt.Printf("Unsynced:  %d\n", cache.Unsynced(lastSync))
+		if lastSync.IsZero() {
+			fmt.Println("Last sync: never")
+		} else {
+			fmt.Printf("Last sync: %s\n", ui.FormatRelativeTime(lastSync, time.Now()))
+		}
+		return nil
+	},
+}
+
+// dirKey normalizes a directory the way dir: tags are stored.
+func dirKey(dir string) string {
+	return strings.TrimPrefix(models.NormalizeTag("dir:"+dir), "dir:")
+}
+
+// buildStatusCache counts notes per directory and collects update times.
+func buildStatusCache() (*statuscache.Cache, error) {
+	notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{ContentLimit: charm.NoContent})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+	entries := make([]statuscache.Entry, len(notes))
+	for i, nt := range notes {
+		entries[i].UpdatedAt = nt.Note.UpdatedAt
+		for _, t := range nt.Tags {
+			if strings.HasPrefix(strings.ToLower(t), "dir:") {
+				entries[i].Dirs = append(entries[i].Dirs, dirKey(t[len("dir:"):]))
+			}
+		}
+	}
+	return statuscache.Build(entries, time.Now()), nil
+}
+
+// refreshStatusCache rebuilds the status cache after a command changed
+// the database. It only runs once memo status has created the cache.
+func refreshStatusCache(cmd *cobra.Command) {
+	if charmClient == nil || !charmClient.Changed() || cmd == statusCmd {
+		return
+	}
+	path := charm.StatusCachePath()
+	if !statuscache.Exists(path) {
+		return
+	}
+	if cache, err := buildStatusCache(); err == nil {
+		_ = cache.Save(path) // Best-effort; memo status rebuilds it
+	}
+}
+
+func init() {
+	statusCmd.Flags().Bool("short", false, "print one cached line for status bars and prompts")
+	rootCmd.AddCommand(statusCmd)
+}
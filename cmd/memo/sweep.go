@@ -0,0 +1,90 @@
+// ABOUTME: Sweep command for literal find-and-replace across all notes.
+// ABOUTME: Reports matches with context and applies every change in one batch.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/subst"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var sweepCmd = &cobra.Command{
+	Use:   "sweep --find <text> --replace <text>",
+	Short: "Find and replace text across all notes",
+	Long: `Replace every occurrence of a string in every note, e.g. after a
+hostname or project name changes. Unlike 'memo replace', the text is
+matched literally, with no regexp or sed syntax to escape.
+
+Matching notes are listed with a word diff of the surrounding lines. After
+confirmation all changes are written in a single batch, so they sync
+together: immediately with auto_sync, otherwise on the next sync.
+
+Examples:
+  memo sweep --find old-hostname --replace new-hostname --dry-run
+  memo sweep --find "Project X" --replace "Project Y" --tag work`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		find, _ := cmd.Flags().GetString("find")
+		replace, _ := cmd.Flags().GetString("replace")
+		tagFlag, _ := cmd.Flags().GetString("tag")
+		force, _ := cmd.Flags().GetBool("force")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		sub, err := subst.Literal(find, replace)
+		if err != nil {
+			return err
+		}
+
+		filter := &charm.NoteFilter{Search: find}
+		if tagFlag != "" {
+			filter.Tag = &tagFlag
+		}
+		notes, err := charmClient.ListNotesWithTags(filter)
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+
+		changed, total := substituteNotes(notes, sub)
+		if len(changed) == 0 {
+			fmt.Printf("No notes contain %q.\n", find)
+			return nil
+		}
+		summary := fmt.Sprintf("%s in %s", ui.Plural(total, "replacement"), ui.Plural(len(changed), "note"))
+		if dryRun {
+			fmt.Println(summary + " (dry run, nothing changed)")
+			return nil
+		}
+
+		if !force {
+			ok, err := confirm(fmt.Sprintf("Replace %q with %q: %s?", find, replace, summary))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Canceled.")
+				return nil
+			}
+		}
+
+		if err := applyNoteChanges(changed); err != nil {
+			return err
+		}
+		fmt.Println(ui.Success("Applied " + summary))
+		return nil
+	},
+}
+
+func init() {
+	sweepCmd.Flags().String("find", "", "text to find (matched literally)")
+	sweepCmd.Flags().String("replace", "", "replacement text")
+	sweepCmd.Flags().String("tag", "", "only sweep notes with this tag")
+	sweepCmd.Flags().Bool("dry-run", false, "report matches without changing anything")
+	sweepCmd.Flags().BoolP("force", "f", false, "skip confirmation")
+	_ = sweepCmd.MarkFlagRequired("find")
+	_ = sweepCmd.MarkFlagRequired("replace")
+	rootCmd.AddCommand(sweepCmd)
+}
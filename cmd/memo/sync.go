@@ -1,5 +1,5 @@
 // ABOUTME: Sync subcommand for Charm cloud integration.
-// ABOUTME: Provides link, unlink, status, and wipe commands for Charm sync.
+// ABOUTME: Provides link, unlink, status, log, and wipe commands for Charm sync.
 
 package main
 
@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	charmkv "github.com/charmbracelet/charm/kv"
 	"github.com/fatih/color"
 	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/synchealth"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +27,9 @@ Data syncs automatically after each change.
 
 Commands:
   status  - Show sync configuration and connection status
+  now     - Sync immediately
+  log     - Show recent sync attempts
+  compact - Drop orphaned keys and shrink the local store
   link    - Connect this device to Charm cloud
   unlink  - Disconnect from Charm cloud
   repair  - Repair database corruption issues
@@ -33,6 +38,8 @@ Commands:
 
 Examples:
   memo sync status
+  memo sync now
+  memo sync log
   memo sync link
   memo sync link --host charm.example.com
   memo sync repair
@@ -42,7 +49,7 @@ Examples:
 var syncStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show sync status",
-	Long:  `Display Charm sync configuration and connection status.`,
+	Long:  `Display Charm sync configuration, connection status, and recent sync failures.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := charm.LoadConfig()
 		if err != nil {
@@ -84,6 +91,120 @@ var syncStatusCmd = &cobra.Command{
 			fmt.Printf("Status:    %s\n", color.RedString("client not initialized"))
 		}
 
+		printSyncHealth()
+		return nil
+	},
+}
+
+// printSyncHealth shows the last sync outcome, highlighting repeated failures.
+func printSyncHealth() {
+	state, err := synchealth.Load(charm.SyncStatePath())
+	if err != nil {
+		return
+	}
+	if !state.LastSuccessAt.IsZero() {
+		fmt.Printf("Last sync: %s\n", state.LastSuccessAt.Format("2006-01-02 15:04:05"))
+	}
+	if state.ConsecutiveFailures == 0 {
+		return
+	}
+
+	msg := fmt.Sprintf("%d failed in a row, last at %s", state.ConsecutiveFailures, state.LastFailureAt.Format("2006-01-02 15:04:05"))
+	if state.Failing() {
+		fmt.Printf("Failures:  %s\n", color.RedString(msg))
+		fmt.Printf("Error:     %s\n", state.LastError)
+		fmt.Println("\nSync keeps failing. Check your connection or run 'memo sync repair'.")
+		return
+	}
+	fmt.Printf("Failures:  %s\n", color.YellowString(msg))
+}
+
+var syncNowCmd = &cobra.Command{
+	Use:   "now",
+	Short: "Sync with Charm cloud now",
+	Long:  `Push local changes and pull remote changes immediately.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println("Syncing...")
+		if err := charmClient.Sync(); err != nil {
+			return fmt.Errorf("sync failed: %w", err)
+		}
+		color.Green("✓ Synced")
+		return nil
+	},
+}
+
+var syncLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show recent sync attempts",
+	Long: `Show recent sync attempts, newest last: when each ran, what triggered
+it (manual, write, or stale), how long it took, how many tries it needed,
+and the error if it failed. Useful when notes don't show up on another
+device.
+
+Examples:
+  memo sync log
+  memo sync log -n 50
+  memo sync log --failed`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		failed, _ := cmd.Flags().GetBool("failed")
+
+		entries, err := synchealth.ReadLog(charm.SyncLogPath(), 0)
+		if err != nil {
+			return fmt.Errorf("failed to read sync log: %w", err)
+		}
+		if failed {
+			kept := entries[:0]
+			for _, e := range entries {
+				if e.Error != "" {
+					kept = append(kept, e)
+				}
+			}
+			entries = kept
+		}
+		if limit > 0 && len(entries) > limit {
+			entries = entries[len(entries)-limit:]
+		}
+		if len(entries) == 0 {
+			fmt.Println("No sync attempts recorded.")
+			return nil
+		}
+
+		for _, e := range entries {
+			status := color.GreenString("ok")
+			if e.Error != "" {
+				status = color.RedString("failed: %s", e.Error)
+			}
+			fmt.Printf("%s  %-6s  %8s  %dx  %s\n",
+				e.Time.Format("2006-01-02 15:04:05"), e.Trigger, e.Duration.Round(time.Millisecond), e.Attempts, status)
+		}
+		return nil
+	},
+}
+
+var syncCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Drop orphaned keys and shrink the local store",
+	Long: `Delete the attachments, index entries, aliases, ranks, and revoked
+share records of notes that no longer exist, audit log entries older than
+audit_retention, and blob files no attachment uses any more, locally and
+in Charm FS, rebuild the tag and attachment indexes, then checkpoint and
+vacuum the local database, reporting its size before and after. Timer
+intervals are kept, so timesheets still count time logged on deleted
+notes, and so are live shares, so they can still be revoked.
+
+Deleting and editing notes leaves free pages behind in the database
+file; compacting returns them to the filesystem. Orphan removal is
+synced like any other change.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println("Compacting...")
+		result, err := charmClient.Compact()
+		if err != nil {
+			return fmt.Errorf("compact failed: %w", err)
+		}
+		printMaintenance(result)
 		return nil
 	},
 }
@@ -200,7 +321,7 @@ Use --force to attempt repair even if integrity check fails.`,
 		force, _ := cmd.Flags().GetBool("force")
 
 		fmt.Println("Repairing database...")
-		result, err := charmkv.Repair(charm.DBName, force)
+		result, err := charmkv.Repair(charm.DatabaseName(), force)
 		if err != nil {
 			return fmt.Errorf("repair failed: %w", err)
 		}
@@ -261,7 +382,7 @@ Your cloud data is preserved.`,
 
 		fmt.Println("\nResetting local data...")
 
-		if err := charmkv.Reset(charm.DBName); err != nil {
+		if err := charmkv.Reset(charm.DatabaseName()); err != nil {
 			return fmt.Errorf("reset failed: %w", err)
 		}
 
@@ -303,7 +424,7 @@ This deletes BOTH cloud backups and local files.`,
 
 		fmt.Println("\nWiping data...")
 
-		result, err := charmkv.Wipe(charm.DBName)
+		result, err := charmkv.Wipe(charm.DatabaseName())
 		if err != nil {
 			return fmt.Errorf("wipe failed: %w", err)
 		}
@@ -326,8 +447,13 @@ This deletes BOTH cloud backups and local files.`,
 func init() {
 	syncLinkCmd.Flags().String("host", "", "Charm server host (default: cloud.charm.sh)")
 	syncRepairCmd.Flags().Bool("force", false, "Force repair even if integrity check fails")
+	syncLogCmd.Flags().IntP("limit", "n", 20, "number of attempts to show (0 for all)")
+	syncLogCmd.Flags().Bool("failed", false, "only show failed attempts")
 
 	syncCmd.AddCommand(syncStatusCmd)
+	syncCmd.AddCommand(syncNowCmd)
+	syncCmd.AddCommand(syncLogCmd)
+	syncCmd.AddCommand(syncCompactCmd)
 	syncCmd.AddCommand(syncLinkCmd)
 	syncCmd.AddCommand(syncUnlinkCmd)
 	syncCmd.AddCommand(syncRepairCmd)
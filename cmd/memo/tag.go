@@ -1,11 +1,18 @@
 // ABOUTME: Tag command for managing note tags.
-// ABOUTME: Provides add, rm, and list subcommands.
+// ABOUTME: Provides add, rm, delete, list, audit, and normalize subcommands.
 
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/tagaudit"
 	"github.com/harper/memo/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -17,23 +24,57 @@ var tagCmd = &cobra.Command{
 }
 
 var tagAddCmd = &cobra.Command{
-	Use:   "add <id-prefix> <tag>",
-	Short: "Add a tag to a note",
-	Args:  cobra.ExactArgs(2),
+	Use:   "add <id-prefix>... <tag>",
+	Short: "Add a tag to notes",
+	Long: `Add a tag to one or more notes. The last argument is the tag.
+
+Pass several ID prefixes, or --tag to select every note that already has
+another tag. All notes are updated together and synced once; selecting by
+--tag asks for confirmation first.
+
+Examples:
+  memo tag add abc123 work
+  memo tag add abc123 def456 urgent
+  memo tag add --tag meeting work`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		prefix := args[0]
-		tagName := args[1]
+		prefixes, tagName := args[:len(args)-1], args[len(args)-1]
+		tagFilter, _ := cmd.Flags().GetString("tag")
+		force, _ := cmd.Flags().GetBool("force")
 
-		note, _, err := charmClient.GetNoteByPrefix(prefix)
+		notes, err := noteArgs(cmd, prefixes)
 		if err != nil {
-			return fmt.Errorf("failed to get note: %w", err)
+			return err
+		}
+
+		if tagFilter != "" && !force {
+			ok, err := confirm(fmt.Sprintf("Add tag %q to %d notes?", tagName, len(notes)))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Canceled.")
+				return nil
+			}
 		}
 
-		if err := charmClient.AddTagToNote(note.ID, tagName); err != nil {
+		ids := make([]uuid.UUID, len(notes))
+		for i, nt := range notes {
+			ids[i] = nt.Note.ID
+		}
+		changed, err := charmClient.AddTagToNotes(ids, tagName)
+		if err != nil {
 			return fmt.Errorf("failed to add tag: %w", err)
 		}
 
-		fmt.Println(ui.Success(fmt.Sprintf("Added tag %q to note %s", tagName, note.ID.String()[:6])))
+		if len(notes) == 1 {
+			if updated, tags, err := charmClient.GetNoteByID(notes[0].Note.ID); err == nil {
+				setHookNote(updated, tags)
+			}
+			fmt.Println(ui.Success(fmt.Sprintf("Added tag %q to note %s", tagName, notes[0].Note.ID.String()[:6])))
+			return nil
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Added tag %q to %d notes (%d already had it)", tagName, changed, len(notes)-changed)))
 		return nil
 	},
 }
@@ -46,7 +87,7 @@ var tagRmCmd = &cobra.Command{
 		prefix := args[0]
 		tagName := args[1]
 
-		note, _, err := charmClient.GetNoteByPrefix(prefix)
+		note, _, err := getNote(prefix)
 		if err != nil {
 			return fmt.Errorf("failed to get note: %w", err)
 		}
@@ -55,11 +96,59 @@ var tagRmCmd = &cobra.Command{
 			return fmt.Errorf("failed to remove tag: %w", err)
 		}
 
+		if updated, tags, err := charmClient.GetNoteByID(note.ID); err == nil {
+			setHookNote(updated, tags)
+		}
 		fmt.Println(ui.Success(fmt.Sprintf("Removed tag %q from note %s", tagName, note.ID.String()[:6])))
 		return nil
 	},
 }
 
+var tagDeleteCmd = &cobra.Command{
+	Use:   "delete <tag>",
+	Short: "Delete a tag from every note",
+	Long: `Remove a tag from every note that has it. The notes themselves are
+kept. Shows how many notes are affected and asks for confirmation first.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagName := models.NormalizeTag(args[0])
+		force, _ := cmd.Flags().GetBool("force")
+
+		notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{Tag: &tagName, ContentLimit: charm.NoContent})
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+		if len(notes) == 0 {
+			return fmt.Errorf("no notes tagged %q", tagName)
+		}
+
+		if !force {
+			const shown = 5
+			for _, nt := range notes[:min(shown, len(notes))] {
+				fmt.Printf("  %s  %s\n", nt.Note.ID.String()[:6], nt.Note.Title)
+			}
+			if len(notes) > shown {
+				fmt.Printf("  ... and %d more\n", len(notes)-shown)
+			}
+			ok, err := confirm(fmt.Sprintf("Remove tag %q from %d notes?", tagName, len(notes)))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				fmt.Println("Canceled.")
+				return nil
+			}
+		}
+
+		changed, err := charmClient.DeleteTag(tagName)
+		if err != nil {
+			return fmt.Errorf("failed to delete tag: %w", err)
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Deleted tag %q from %d notes", tagName, changed)))
+		return nil
+	},
+}
+
 var tagListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all tags",
@@ -86,9 +175,174 @@ var tagListCmd = &cobra.Command{
 	},
 }
 
+var tagAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Find near-duplicate and single-use tags",
+	Long: `Report tags that look like the same tag (work, Work, wrok,
+follow-up vs followup) and tags used on only one note. Tags exist only on
+notes, so there are never unused tags to report.
+
+With --interactive, walk through the report: merge or rename each group
+of similar tags, and rename or delete each single-use tag. Changes are
+applied to every affected note and synced.
+
+Directory tags (dir:...) are skipped.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		interactive, _ := cmd.Flags().GetBool("interactive")
+
+		notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{ContentLimit: charm.NoContent})
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+		counts := make(map[string]int)
+		for _, nt := range notes {
+			for _, tag := range nt.Tags {
+				if !strings.HasPrefix(strings.ToLower(tag), "dir:") {
+					counts[tag]++
+				}
+			}
+		}
+
+		report := tagaudit.Audit(counts)
+		if len(report.Duplicates) == 0 && len(report.Singletons) == 0 {
+			fmt.Println(ui.Success("No tag problems found"))
+			return nil
+		}
+
+		if !interactive {
+			printTagAudit(report)
+			return nil
+		}
+		return runTagAudit(report, bufio.NewReader(os.Stdin))
+	},
+}
+
+var tagNormalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Rewrite stored tags in normalized form",
+	Long: `Rewrite every note's tags in normalized form and merge tags that
+normalize to the same name. Tags are normalized to Unicode NFC, trimmed,
+lowercased, with a leading '#' dropped and whitespace collapsed, so Work,
+work, and #work are one tag.
+
+New tags are always normalized; run this once to fix notes written by
+older versions of memo, including on other synced devices.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changed, err := charmClient.NormalizeStoredTags()
+		if err != nil {
+			return fmt.Errorf("failed to normalize tags: %w", err)
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Normalized tags on %d notes", changed)))
+		return nil
+	},
+}
+
+// printTagAudit prints an audit report.
+func printTagAudit(report *tagaudit.Report) {
+	if len(report.Duplicates) > 0 {
+		fmt.Println("Similar tags:")
+		for _, group := range report.Duplicates {
+			fmt.Printf("  %s\n", formatTagGroup(group))
+		}
+	}
+	if len(report.Singletons) > 0 {
+		if len(report.Duplicates) > 0 {
+			fmt.Println()
+		}
+		fmt.Println("Used on one note:")
+		for _, t := range report.Singletons {
+			fmt.Printf("  %s\n", t.Name)
+		}
+	}
+	fmt.Println("\nRun 'memo tag audit --interactive' to merge, rename, or delete.")
+}
+
+// formatTagGroup formats tags with their counts, e.g. "work (5), wrok (1)".
+func formatTagGroup(group []tagaudit.Tag) string {
+	parts := make([]string, len(group))
+	for i, t := range group {
+		parts[i] = fmt.Sprintf("%s (%d)", t.Name, t.Count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runTagAudit prompts for an action on each finding until the report is
+// done or the user quits.
+func runTagAudit(report *tagaudit.Report, in *bufio.Reader) error {
+	for _, group := range report.Duplicates {
+		fmt.Printf("\nSimilar: %s\n", formatTagGroup(group))
+		switch ask(in, fmt.Sprintf("[m]erge into %q, [r]ename all, [s]kip, [q]uit: ", group[0].Name)) {
+		case "m":
+			if err := mergeTags(group, group[0].Name); err != nil {
+				return err
+			}
+		case "r":
+			if name := ask(in, "New name: "); name != "" {
+				if err := mergeTags(group, name); err != nil {
+					return err
+				}
+			}
+		case "q":
+			return nil
+		}
+	}
+
+	for _, t := range report.Singletons {
+		fmt.Printf("\nUsed once: %s\n", t.Name)
+		switch ask(in, "[r]ename, [d]elete, [s]kip, [q]uit: ") {
+		case "r":
+			if name := ask(in, "New name: "); name != "" {
+				if err := mergeTags([]tagaudit.Tag{t}, name); err != nil {
+					return err
+				}
+			}
+		case "d":
+			if _, err := charmClient.DeleteTag(t.Name); err != nil {
+				return fmt.Errorf("failed to delete tag: %w", err)
+			}
+			fmt.Println(ui.Success(fmt.Sprintf("Deleted tag %q", t.Name)))
+		case "q":
+			return nil
+		}
+	}
+	return nil
+}
+
+// mergeTags renames every tag in group to target.
+func mergeTags(group []tagaudit.Tag, target string) error {
+	for _, t := range group {
+		if _, err := charmClient.RenameTag(t.Name, target); err != nil {
+			return fmt.Errorf("failed to rename tag %q: %w", t.Name, err)
+		}
+	}
+	fmt.Println(ui.Success(fmt.Sprintf("Merged %d tags into %q", len(group), models.NormalizeTag(target))))
+	return nil
+}
+
+// ask prints a prompt and returns the trimmed answer, lowercased when it
+// is a single letter. EOF reads as an empty answer.
+func ask(in *bufio.Reader, prompt string) string {
+	fmt.Print(prompt)
+	line, _ := in.ReadString('\n')
+	line = strings.TrimSpace(line)
+	if len(line) == 1 {
+		line = strings.ToLower(line)
+	}
+	return line
+}
+
 func init() {
+	tagAddCmd.Flags().String("tag", "", "also tag every note that has this tag")
+	tagAddCmd.Flags().BoolP("force", "f", false, "skip confirmation")
+	tagDeleteCmd.Flags().BoolP("force", "f", false, "skip confirmation")
+	tagAuditCmd.Flags().BoolP("interactive", "i", false, "merge, rename, or delete tags from the report")
 	tagCmd.AddCommand(tagAddCmd)
 	tagCmd.AddCommand(tagRmCmd)
+	tagCmd.AddCommand(tagDeleteCmd)
 	tagCmd.AddCommand(tagListCmd)
+	tagCmd.AddCommand(tagAuditCmd)
+	tagCmd.AddCommand(tagNormalizeCmd)
 	rootCmd.AddCommand(tagCmd)
 }
@@ -0,0 +1,169 @@
+// ABOUTME: Timer command for tracking time spent on notes.
+// ABOUTME: Starts and stops work intervals and reports weekly or monthly time sheets.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/rollup"
+	"github.com/harper/memo/internal/timesheet"
+	"github.com/harper/memo/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var timerCmd = &cobra.Command{
+	Use:   "timer",
+	Short: "Track time spent on notes",
+	Long: `Time work against notes, turning project notes into lightweight time
+sheets. One timer runs at a time; starting another stops it. Intervals sync
+with your notes, so a timer started on one machine can be stopped on
+another.
+
+Examples:
+  memo timer start roadmap
+  memo timer status
+  memo timer stop
+  memo timer report --week
+  memo timer report --month --previous`,
+}
+
+var timerStartCmd = &cobra.Command{
+	Use:   "start [id-prefix]",
+	Short: "Start timing work on a note",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, _, err := noteArg(cmd, args)
+		if err != nil {
+			return err
+		}
+		_, stopped, err := charmClient.StartTimer(note.ID)
+		if err != nil {
+			return fmt.Errorf("failed to start timer: %w", err)
+		}
+		if stopped != nil {
+			fmt.Println(ui.Success("Stopped " + timerSummary(stopped)))
+		}
+		fmt.Println(ui.Success(fmt.Sprintf("Timing %s (%s)", note.Title, note.ID.String()[:6])))
+		return nil
+	},
+}
+
+var timerStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running timer",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stopped, err := charmClient.StopTimer()
+		if errors.Is(err, charm.ErrNoTimer) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stop timer: %w", err)
+		}
+		fmt.Println(ui.Success("Stopped " + timerSummary(stopped)))
+		return nil
+	},
+}
+
+var timerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the running timer",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		running, err := charmClient.RunningTimer()
+		if err != nil {
+			return fmt.Errorf("failed to read timer: %w", err)
+		}
+		if running == nil {
+			fmt.Println("No timer running.")
+			return nil
+		}
+		fmt.Printf("Timing %s since %s\n", timerSummary(running), running.Start.Format("15:04"))
+		return nil
+	},
+}
+
+var timerReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show time spent per note for a week or month",
+	Long: `Show a time sheet: time per note and day for an ISO week (the default,
+or --week), or per note for a calendar month with --month. A running
+timer counts up to now.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		monthFlag, _ := cmd.Flags().GetBool("month")
+		dateFlag, _ := cmd.Flags().GetString("date")
+		previousFlag, _ := cmd.Flags().GetBool("previous")
+
+		at := time.Now()
+		if dateFlag != "" {
+			parsed, err := time.ParseInLocation("2006-01-02", dateFlag, time.Local)
+			if err != nil {
+				return fmt.Errorf("invalid --date (want YYYY-MM-DD): %w", err)
+			}
+			at = parsed
+		}
+		period := rollup.WeekOf(at)
+		if monthFlag {
+			period = rollup.MonthOf(at)
+		}
+		if previousFlag {
+			period = previousPeriod(period, monthFlag)
+		}
+
+		stored, err := charmClient.ListIntervals()
+		if err != nil {
+			return fmt.Errorf("failed to list timers: %w", err)
+		}
+		intervals := make([]timesheet.Interval, len(stored))
+		for i, iv := range stored {
+			intervals[i] = timesheet.Interval{NoteID: iv.NoteID.String(), Start: iv.Start}
+			if iv.End != nil {
+				intervals[i].End = *iv.End
+			}
+		}
+
+		notes, err := charmClient.ListNotesWithTags(&charm.NoteFilter{ContentLimit: charm.NoContent})
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %w", err)
+		}
+		titles := make(map[string]string, len(notes))
+		for _, nt := range notes {
+			titles[nt.Note.ID.String()] = nt.Note.Title
+		}
+
+		fmt.Print(timesheet.Summarize(period, intervals, time.Now()).Format(titles))
+		return nil
+	},
+}
+
+// timerSummary describes an interval as its note and elapsed time.
+func timerSummary(iv *charm.Interval) string {
+	end := time.Now()
+	if iv.End != nil {
+		end = *iv.End
+	}
+	title := iv.NoteID.String()[:6]
+	if note, _, err := charmClient.GetNoteByID(iv.NoteID); err == nil {
+		title = note.Title
+	}
+	return fmt.Sprintf("%s (%s)", title, timesheet.FormatHours(end.Sub(iv.Start)))
+}
+
+func init() {
+	timerStartCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	timerReportCmd.Flags().Bool("week", false, "report an ISO week (the default)")
+	timerReportCmd.Flags().Bool("month", false, "report a calendar month")
+	timerReportCmd.Flags().String("date", "", "any date within the period (YYYY-MM-DD, default: today)")
+	timerReportCmd.Flags().Bool("previous", false, "report the period before the one containing --date")
+	timerReportCmd.MarkFlagsMutuallyExclusive("week", "month")
+	timerCmd.AddCommand(timerStartCmd)
+	timerCmd.AddCommand(timerStopCmd)
+	timerCmd.AddCommand(timerStatusCmd)
+	timerCmd.AddCommand(timerReportCmd)
+	rootCmd.AddCommand(timerCmd)
+}
@@ -0,0 +1,115 @@
+// ABOUTME: URI command for opening memo:// links from other apps.
+// ABOUTME: Provides handle, which opens the linked note, and install, which registers memo with the OS.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/harper/memo/internal/links"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/urischeme"
+	"github.com/harper/memo/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var uriCmd = &cobra.Command{
+	Use:   "uri",
+	Short: "Open memo:// links from other apps",
+	Long: `Handle memo:// links, so clicking one in a browser, VS Code, or a chat
+app opens the note.
+
+Links look like memo://note/<id-prefix-or-alias> (memo://<id> works too).
+Run 'memo uri install' once to register memo as the handler; links then
+open in a terminal running 'memo uri handle'.
+
+Examples:
+  memo uri install
+  memo uri handle memo://note/abc123
+  memo uri handle --view memo://note/roadmap`,
+}
+
+var uriHandleCmd = &cobra.Command{
+	Use:   "handle <uri>",
+	Short: "Open the note a memo:// link points to",
+	Long: `Open the note a memo:// link points to in $EDITOR, saving any changes
+like 'memo edit'. With --view, show the rendered note instead and wait for
+Enter, so the terminal window opened for the link stays readable.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		view, _ := cmd.Flags().GetBool("view")
+
+		ref, ok := links.MemoRef(args[0])
+		if !ok || ref == "" {
+			return fmt.Errorf("not a memo:// note link: %s", args[0])
+		}
+		note, tags, err := getNote(ref)
+		if err != nil {
+			return fmt.Errorf("failed to get note: %w", err)
+		}
+
+		if vault.IsSealed(note.Content) {
+			return fmt.Errorf("%s is in the vault: run 'memo vault show %s' to read it", note.Title, note.ID.String()[:6])
+		}
+		if view {
+			fmt.Print(ui.FormatNoteHeader(note, tagsToModelsList(tags)))
+			content, _ := ui.FormatNoteContent(note.Content)
+			fmt.Print(content)
+			fmt.Print("\nPress Enter to close.")
+			_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+			return nil
+		}
+
+		newContent, err := openEditor(note.Content)
+		if err != nil {
+			return fmt.Errorf("failed to open editor: %w", err)
+		}
+		if newContent == note.Content {
+			return nil
+		}
+		note.Content = autoEnrich(newContent)
+		note.Touch()
+		if err := charmClient.UpdateNote(note, tags); err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+		setHookNote(note, tags)
+		fmt.Println(ui.Success(fmt.Sprintf("Updated note %s", note.ID.String()[:6])))
+		return nil
+	},
+}
+
+var uriInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Register memo as the handler for memo:// links",
+	Long: `Register this memo binary as the current user's handler for memo://
+links: a .desktop entry set with xdg-mime on Linux, a small app in
+~/Applications on macOS (links open in Terminal), or HKCU registry keys on
+Windows. Run it again after moving the binary.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exe, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("failed to find the memo binary: %w", err)
+		}
+		if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+			exe = resolved
+		}
+
+		where, err := urischeme.Install(exe)
+		if err != nil {
+			return fmt.Errorf("failed to register memo:// handler: %w", err)
+		}
+		fmt.Println(ui.Success("Registered memo:// links (" + where + ")"))
+		return nil
+	},
+}
+
+func init() {
+	uriHandleCmd.Flags().Bool("view", false, "show the rendered note instead of editing it")
+	uriCmd.AddCommand(uriHandleCmd)
+	uriCmd.AddCommand(uriInstallCmd)
+	rootCmd.AddCommand(uriCmd)
+}
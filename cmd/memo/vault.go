@@ -0,0 +1,213 @@
+// ABOUTME: Vault command for encrypting single notes with a passphrase.
+// ABOUTME: Seals a note's content so it stores and syncs as ciphertext, and decrypts it on demand.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/fatih/color"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var vaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Encrypt notes with a passphrase",
+	Long: `Lock notes holding secrets, like recovery codes, behind a passphrase.
+
+'memo vault add' encrypts a note's content (scrypt and NaCl secretbox) and
+stores the ciphertext in its place, so from then on the database, sync,
+and exports only see ciphertext. The title and tags stay readable:
+listings show the title without a preview. 'memo vault show' decrypts the
+note for reading, and 'memo vault rm' decrypts it for good. Commands that
+change or add to a note's content refuse vault notes until then.
+
+Sealing doesn't reach back: the plain text the note held before stays in
+the store's sync history, on the Charm server and devices that already
+synced it, and in earlier backups and exports.
+
+There is no way to recover a forgotten passphrase. The passphrase is read
+from the terminal, or from the first line of stdin when it isn't one.
+
+Examples:
+  memo vault add abc123
+  memo vault show abc123
+  memo vault rm abc123`,
+}
+
+var vaultAddCmd = &cobra.Command{
+	Use:   "add [id]",
+	Short: "Encrypt a note's content with a passphrase",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, tags, err := noteArg(cmd, args)
+		if err != nil {
+			return err
+		}
+		if vault.IsSealed(note.Content) {
+			return fmt.Errorf("%s is already in the vault", note.Title)
+		}
+
+		passphrase, err := readPassphrase("Passphrase: ")
+		if err != nil {
+			return err
+		}
+		if stdinIsTerminal() {
+			again, err := readPassphrase("Repeat passphrase: ")
+			if err != nil {
+				return err
+			}
+			if string(again) != string(passphrase) {
+				return fmt.Errorf("passphrases don't match")
+			}
+		}
+
+		sealed, err := vault.Seal(note.Content, passphrase)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt note: %w", err)
+		}
+		note.Content = sealed
+		note.Touch()
+		if err := charmClient.UpdateNote(note, tags); err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+
+		fmt.Println(ui.Success("Locked " + note.Title + " in the vault"))
+		color.Yellow("  ⚠ Earlier versions keep the plain text in the sync history and in past backups and exports")
+		return nil
+	},
+}
+
+var vaultShowCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Decrypt and display a vault note",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rawFlag, _ := cmd.Flags().GetBool("raw")
+
+		note, tags, err := vaultNoteArg(cmd, args)
+		if err != nil {
+			return err
+		}
+		content, err := openVaultNote(note)
+		if err != nil {
+			return err
+		}
+
+		if rawFlag {
+			fmt.Print(content)
+			if !strings.HasSuffix(content, "\n") {
+				fmt.Println()
+			}
+			return nil
+		}
+		fmt.Print(ui.FormatNoteHeader(note, tagsToModelsList(tags)))
+		rendered, _ := ui.FormatNoteContent(content)
+		fmt.Print(rendered)
+		return nil
+	},
+}
+
+var vaultRmCmd = &cobra.Command{
+	Use:   "rm [id]",
+	Short: "Decrypt a vault note and store it as plain text again",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, tags, err := vaultNoteArg(cmd, args)
+		if err != nil {
+			return err
+		}
+		content, err := openVaultNote(note)
+		if err != nil {
+			return err
+		}
+
+		note.Content = content
+		note.Touch()
+		if err := charmClient.UpdateNote(note, tags); err != nil {
+			return fmt.Errorf("failed to update note: %w", err)
+		}
+		fmt.Println(ui.Success("Took " + note.Title + " out of the vault"))
+		return nil
+	},
+}
+
+// vaultNoteArg loads the note named by args, which must be in the vault.
+func vaultNoteArg(cmd *cobra.Command, args []string) (*models.Note, []string, error) {
+	note, tags, err := noteArg(cmd, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !vault.IsSealed(note.Content) {
+		return nil, nil, fmt.Errorf("%s isn't in the vault", note.Title)
+	}
+	return note, tags, nil
+}
+
+// openVaultNote asks for the passphrase and decrypts a vault note's content.
+func openVaultNote(note *models.Note) (string, error) {
+	passphrase, err := readPassphrase("Passphrase for " + note.Title + ": ")
+	if err != nil {
+		return "", err
+	}
+	content, err := vault.Open(note.Content, passphrase)
+	if errors.Is(err, vault.ErrWrongPassphrase) {
+		return "", fmt.Errorf("wrong passphrase")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt note: %w", err)
+	}
+	return content, nil
+}
+
+// stdinReader is shared so consecutive passphrase reads from a pipe don't
+// lose buffered input.
+var stdinReader = bufio.NewReader(os.Stdin)
+
+func stdinIsTerminal() bool {
+	return term.IsTerminal(os.Stdin.Fd())
+}
+
+// readPassphrase prompts on stderr and reads a passphrase without echo, or
+// reads a line of stdin when it isn't a terminal.
+func readPassphrase(prompt string) ([]byte, error) {
+	var passphrase []byte
+	if stdinIsTerminal() {
+		fmt.Fprint(os.Stderr, prompt)
+		p, err := term.ReadPassword(os.Stdin.Fd())
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		passphrase = p
+	} else {
+		line, err := stdinReader.ReadString('\n')
+		if err != nil && line == "" {
+			return nil, fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		passphrase = []byte(strings.TrimRight(line, "\r\n"))
+	}
+	if len(passphrase) == 0 {
+		return nil, vault.ErrEmptyPassphrase
+	}
+	return passphrase, nil
+}
+
+func init() {
+	vaultAddCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	vaultShowCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	vaultShowCmd.Flags().Bool("raw", false, "print the decrypted markdown without rendering")
+	vaultRmCmd.Flags().Bool("pick", false, "choose the note with a fuzzy finder")
+	vaultCmd.AddCommand(vaultAddCmd)
+	vaultCmd.AddCommand(vaultShowCmd)
+	vaultCmd.AddCommand(vaultRmCmd)
+	rootCmd.AddCommand(vaultCmd)
+}
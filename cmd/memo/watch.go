@@ -0,0 +1,195 @@
+// ABOUTME: Watch command for live-importing markdown files from a directory.
+// ABOUTME: Imports new or changed files as notes on filesystem events, or by polling on network filesystems.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/fssync"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch <dir>",
+	Short: "Import markdown files from a directory as they change",
+	Long: `Watch a directory and import .md files as notes whenever they appear
+or change, until interrupted. Useful for folders filled by Syncthing,
+Dropbox, or a phone app.
+
+Changes are picked up from OS file events. Files are imported once they
+have gone --settle without another change, so half-written files are
+not imported. On network filesystems (NFS, SMB, sshfs) OS events miss
+changes made on other machines; use --poll there to scan the directory
+every --interval instead. Polling is also used when OS events are
+unavailable.
+
+Watching only reads files: notes are never written to the directory and
+deleting a file does not delete its note. The mapping is shared with
+'memo fs sync'.
+
+Examples:
+  memo watch ~/Sync/notes
+  memo watch /mnt/nas/inbox --poll --interval 10s`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		poll, _ := cmd.Flags().GetBool("poll")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		settle, _ := cmd.Flags().GetDuration("settle")
+		noSync, _ := cmd.Flags().GetBool("no-sync")
+
+		if info, err := os.Stat(args[0]); err != nil || !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", args[0])
+		}
+		if interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
+		// Imports skip per-note sync; each batch is synced once
+		client, err := charm.NewClient(charm.WithAutoSync(false), charm.WithActor(cliActor(cmd)))
+		if err != nil {
+			return err
+		}
+		cfg := charmClient.Config()
+		autoSync := !noSync && cfg != nil && cfg.AutoSync
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		syncer := &fssync.Syncer{Repo: client, Dir: args[0], MinAge: settle}
+		reported := make(map[string]bool)
+		pass := func() error {
+			res, err := syncer.Import()
+			if err != nil {
+				return fmt.Errorf("watch failed: %w", err)
+			}
+			reportWatchPass(res, reported)
+
+			if autoSync && res.Changed() {
+				if err := client.Sync(); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: sync failed: %v\n", err)
+				}
+			}
+			return nil
+		}
+
+		var watcher *fsnotify.Watcher
+		if !poll {
+			watcher, err = fsnotify.NewWatcher()
+			if err == nil {
+				defer func() { _ = watcher.Close() }()
+				err = watcher.Add(args[0])
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: can't watch for file events (%v); polling every %s\n", err, interval)
+				poll = true
+			}
+		}
+
+		fmt.Printf("Watching %s (Ctrl-C to stop)\n", args[0])
+		if err := pass(); err != nil {
+			return err
+		}
+		if poll {
+			return pollDir(ctx, interval, pass)
+		}
+		return watchEvents(ctx, watcher, settle, pass)
+	},
+}
+
+// pollDir runs pass every interval until ctx is done.
+func pollDir(ctx context.Context, interval time.Duration, pass func() error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := pass(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchEvents runs pass once markdown files have stopped changing for
+// settle, until ctx is done. The first pass runs after settle regardless,
+// for files the initial pass skipped as too new; so does a pass after the
+// event queue overflows, since changes may have been missed.
+func watchEvents(ctx context.Context, w *fsnotify.Watcher, settle time.Duration, pass func() error) error {
+	due := time.After(settle)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if (ev.Has(fsnotify.Create) || ev.Has(fsnotify.Write)) && isWatchedFile(ev.Name) {
+				due = time.After(settle)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				due = time.After(settle)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		case <-due:
+			due = nil
+			if err := pass(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// isWatchedFile reports whether an event's file is one Import reads.
+func isWatchedFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasSuffix(name, ".md") && !strings.HasPrefix(name, ".")
+}
+
+// reportWatchPass prints a pass's changes and any errors not already reported.
+func reportWatchPass(res *fssync.Result, reported map[string]bool) {
+	if res.Imported > 0 || res.Updated > 0 {
+		fmt.Printf("%s  imported: %d  updated: %d\n", time.Now().Format("15:04:05"), res.Imported, res.Updated)
+	}
+
+	current := make(map[string]bool, len(res.Errors))
+	for _, err := range res.Errors {
+		msg := err.Error()
+		current[msg] = true
+		if !reported[msg] {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		}
+	}
+	for msg := range reported {
+		delete(reported, msg)
+	}
+	for msg := range current {
+		reported[msg] = true
+	}
+}
+
+func init() {
+	watchCmd.Flags().Bool("poll", false, "scan the directory every --interval instead of using OS file events")
+	watchCmd.Flags().Duration("interval", 2*time.Second, "how often to scan the directory when polling")
+	watchCmd.Flags().Duration("settle", time.Second, "wait for files to go this long without changing before importing")
+	watchCmd.Flags().Bool("no-sync", false, "don't sync to Charm after importing")
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,151 @@
+// ABOUTME: Content-addressed file store for attachment data.
+// ABOUTME: Blobs are stored by SHA-256 under a two-level directory fan-out.
+
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when a blob is not present locally.
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps blobs as files under Dir.
+type Store struct {
+	Dir string
+
+	// Shared is an older store that Get falls back to for blobs missing
+	// from Dir. It is never written to or swept.
+	Shared string
+}
+
+// New creates a store rooted at dir.
+func New(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+// Hash returns the content address of data.
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Path returns the file path for a blob hash.
+func (s *Store) Path(hash string) string {
+	return blobPath(s.Dir, hash)
+}
+
+func blobPath(dir, hash string) string {
+	if len(hash) < 2 {
+		return filepath.Join(dir, hash)
+	}
+	return filepath.Join(dir, hash[:2], hash)
+}
+
+// Put stores data and returns its hash. Storing existing content only
+// refreshes its modification time, so a sweep running alongside treats the
+// blob as new rather than as one nothing has referred to for a while.
+func (s *Store) Put(data []byte) (string, error) {
+	hash := Hash(data)
+	path := s.Path(hash)
+	if _, err := os.Stat(path); err == nil {
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			return "", err
+		}
+		return hash, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return "", err
+	}
+	// Write to a temp file and rename so a crash never leaves a partial blob
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return "", err
+	}
+	return hash, nil
+}
+
+// Get reads a blob, verifying its content against the hash.
+func (s *Store) Get(hash string) ([]byte, error) {
+	data, err := os.ReadFile(s.Path(hash))
+	if os.IsNotExist(err) && s.Shared != "" {
+		data, err = os.ReadFile(blobPath(s.Shared, hash))
+	}
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if Hash(data) != hash {
+		return nil, fmt.Errorf("blob %s is corrupt", hash)
+	}
+	return data, nil
+}
+
+// Sweep removes blobs whose hash isn't in keep and returns how many were
+// removed. Files written after cutoff are left alone so a blob stored just
+// before its reference is written survives; so are temp files, which
+// belong to writes still in progress until they pass the cutoff too.
+// Only files in the fan-out directories are considered; anything else
+// under Dir isn't a blob of this store.
+func (s *Store) Sweep(keep map[string]bool, cutoff time.Time) (int, error) {
+	removed := 0
+	root := filepath.Clean(s.Dir)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil // No blobs stored yet
+			}
+			return err
+		}
+		if d.IsDir() {
+			if path != root && filepath.Dir(path) != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Dir(filepath.Dir(path)) != root {
+			return nil
+		}
+		name := d.Name()
+		if keep[name] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		if !strings.HasPrefix(name, ".tmp-") {
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
@@ -0,0 +1,149 @@
+// ABOUTME: Records command output for saving as a note.
+// ABOUTME: Bounds captured output to its head and tail, cleans terminal escapes, and formats markdown.
+
+package capture
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLimit is how much output a note keeps by default.
+const DefaultLimit = 256 * 1024
+
+// Buffer keeps the first quarter and the last three quarters of its limit
+// of everything written to it, so long logs keep both how they started
+// and how they ended. It is safe for concurrent writes, so stdout and
+// stderr can share one.
+type Buffer struct {
+	mu      sync.Mutex
+	limit   int
+	head    []byte
+	tail    []byte
+	written int64
+}
+
+// NewBuffer creates a buffer keeping at most limit bytes.
+func NewBuffer(limit int) *Buffer {
+	return &Buffer{limit: limit}
+}
+
+// Write records p. It never fails.
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.written += int64(len(p))
+
+	rest := p
+	if room := b.limit/4 - len(b.head); room > 0 {
+		n := min(room, len(rest))
+		b.head = append(b.head, rest[:n]...)
+		rest = rest[n:]
+	}
+	b.tail = append(b.tail, rest...)
+	// Trim in batches so each write doesn't copy the whole tail
+	if keep := b.limit - b.limit/4; len(b.tail) > 2*keep {
+		b.tail = append([]byte(nil), b.tail[len(b.tail)-keep:]...)
+	}
+	return len(p), nil
+}
+
+// Output returns the kept output and how many bytes were dropped from the
+// middle.
+func (b *Buffer) Output() (string, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tail := b.tail
+	if keep := b.limit - b.limit/4; len(tail) > keep {
+		tail = tail[len(tail)-keep:]
+	}
+	omitted := b.written - int64(len(b.head)) - int64(len(tail))
+	if omitted == 0 {
+		return string(b.head) + string(tail), 0
+	}
+	return string(b.head) + fmt.Sprintf("\n… %d bytes omitted …\n", omitted) + string(tail), omitted
+}
+
+// Record is a captured run.
+type Record struct {
+	Command  string // Empty for output piped in
+	Dir      string
+	Started  time.Time
+	Duration time.Duration
+	ExitCode *int // Nil when unknown
+	Output   string
+}
+
+// ansiEscape matches CSI and OSC terminal escape sequences.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// Clean makes terminal output readable as text: escape sequences are
+// removed, and lines redrawn with carriage returns (like progress bars)
+// keep only their final state.
+func Clean(output string) string {
+	output = ansiEscape.ReplaceAllString(output, "")
+	output = strings.ReplaceAll(output, "\r\n", "\n")
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		if j := strings.LastIndex(strings.TrimRight(line, "\r"), "\r"); j >= 0 {
+			line = line[j+1:]
+		}
+		lines[i] = strings.TrimRight(line, "\r")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Markdown formats the record as note content: the command, a summary of
+// how it ran, and the cleaned output in a code block.
+func (r *Record) Markdown() string {
+	var b strings.Builder
+	if r.Command != "" {
+		fmt.Fprintf(&b, "`$ %s`\n\n", strings.ReplaceAll(r.Command, "`", "'"))
+	}
+	if r.ExitCode != nil {
+		fmt.Fprintf(&b, "- Exit status: %d\n", *r.ExitCode)
+	}
+	fmt.Fprintf(&b, "- Duration: %s\n", FormatDuration(r.Duration))
+	fmt.Fprintf(&b, "- Started: %s\n", r.Started.Format("2006-01-02 15:04:05"))
+	if r.Dir != "" {
+		fmt.Fprintf(&b, "- Directory: %s\n", r.Dir)
+	}
+
+	output := strings.TrimRight(Clean(r.Output), "\n")
+	if output == "" {
+		b.WriteString("\n(no output)\n")
+		return b.String()
+	}
+	fence := strings.Repeat("`", max(3, longestRun(output, '`')+1))
+	fmt.Fprintf(&b, "\n%stext\n%s\n%s\n", fence, output, fence)
+	return b.String()
+}
+
+// FormatDuration rounds a duration for display: milliseconds under a
+// second, tenths of a second under a minute, whole seconds after that.
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
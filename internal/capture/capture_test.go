@@ -0,0 +1,58 @@
+// ABOUTME: Tests for output capture: the bounded buffer, cleanup, and note formatting.
+// ABOUTME: Uses fixed times and small limits so results are exact.
+
+package capture
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuffer(t *testing.T) {
+	b := NewBuffer(8) // Keeps 2 head bytes and 6 tail bytes
+	_, _ = b.Write([]byte("ab"))
+	if out, omitted := b.Output(); out != "ab" || omitted != 0 {
+		t.Fatalf("Output = %q, %d", out, omitted)
+	}
+	for _, s := range []string{"cdef", "ghijkl", "mnopqrstuvwxyz"} {
+		_, _ = b.Write([]byte(s))
+	}
+	out, omitted := b.Output()
+	if omitted != 18 || out != "ab\n… 18 bytes omitted …\nuvwxyz" {
+		t.Errorf("Output = %q, %d", out, omitted)
+	}
+}
+
+func TestClean(t *testing.T) {
+	in := "\x1b[32mok\x1b[0m  pkg\r\n10%\r50%\r100%\ndone\x1b]0;title\x07\n"
+	if got, want := Clean(in), "ok  pkg\n100%\ndone\n"; got != want {
+		t.Errorf("Clean = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	code := 2
+	r := &Record{
+		Command:  "make test",
+		Dir:      "/src/memo",
+		Started:  time.Date(2026, 3, 4, 9, 30, 0, 0, time.UTC),
+		Duration: 3210 * time.Millisecond,
+		ExitCode: &code,
+		Output:   "FAIL ```x```\n",
+	}
+	want := "`$ make test`\n\n" +
+		"- Exit status: 2\n" +
+		"- Duration: 3.2s\n" +
+		"- Started: 2026-03-04 09:30:00\n" +
+		"- Directory: /src/memo\n" +
+		"\n````text\nFAIL ```x```\n````\n"
+	if got := r.Markdown(); got != want {
+		t.Errorf("Markdown =\n%s\nwant\n%s", got, want)
+	}
+
+	piped := &Record{Started: r.Started, Duration: 40 * time.Millisecond}
+	if got := piped.Markdown(); strings.Contains(got, "Exit status") || !strings.Contains(got, "(no output)") {
+		t.Errorf("piped Markdown = %q", got)
+	}
+}
@@ -0,0 +1,160 @@
+// ABOUTME: User-assigned aliases that name notes
+// ABOUTME: Stored as alias:<name> keys holding the note ID, so they sync with notes
+
+package charm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/charm/kv"
+	"github.com/google/uuid"
+)
+
+// AliasPrefix is the key prefix for aliases.
+const AliasPrefix = "alias:"
+
+var (
+	ErrInvalidAlias  = errors.New("alias must start with a letter and contain only a-z, 0-9, '-' and '_' (at most 64 characters)")
+	ErrHexAlias      = errors.New("alias must not look like a note ID prefix (use a non-hex letter)")
+	ErrAliasExists   = errors.New("alias already names another note")
+	ErrAliasNotFound = errors.New("alias not found")
+)
+
+var aliasPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+var hexPattern = regexp.MustCompile(`^[0-9a-f-]+$`)
+
+// Alias is a name for a note.
+type Alias struct {
+	Name   string
+	NoteID uuid.UUID
+}
+
+// NormalizeAlias lowercases and trims an alias name.
+func NormalizeAlias(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ValidateAlias checks that a normalized alias is well formed and can't be
+// mistaken for a note ID prefix.
+func ValidateAlias(name string) error {
+	if !aliasPattern.MatchString(name) {
+		return ErrInvalidAlias
+	}
+	if hexPattern.MatchString(name) {
+		return ErrHexAlias
+	}
+	return nil
+}
+
+// aliasKey returns the key for an alias.
+func aliasKey(name string) []byte {
+	return []byte(AliasPrefix + name)
+}
+
+// SetAlias names a note. Setting an alias that already names the same note
+// is a no-op; one that names a different note fails with ErrAliasExists.
+func (c *Client) SetAlias(name string, noteID uuid.UUID) error {
+	name = NormalizeAlias(name)
+	if err := ValidateAlias(name); err != nil {
+		return err
+	}
+	return c.Do(func(k *kv.KV) error {
+		if _, err := k.Get(noteKey(noteID)); err != nil {
+			if errors.Is(err, kv.ErrMissingKey) {
+				return ErrNoteNotFound
+			}
+			return err
+		}
+		existing, err := k.Get(aliasKey(name))
+		switch {
+		case err == nil && string(existing) == noteID.String():
+			return nil
+		case err == nil:
+			return fmt.Errorf("%w: %s", ErrAliasExists, name)
+		case !errors.Is(err, kv.ErrMissingKey):
+			return err
+		}
+		return k.Set(aliasKey(name), []byte(noteID.String()))
+	})
+}
+
+// RemoveAlias deletes an alias. The note it named is unchanged.
+func (c *Client) RemoveAlias(name string) error {
+	name = NormalizeAlias(name)
+	return c.Do(func(k *kv.KV) error {
+		if _, err := k.Get(aliasKey(name)); err != nil {
+			if errors.Is(err, kv.ErrMissingKey) {
+				return fmt.Errorf("%w: %s", ErrAliasNotFound, name)
+			}
+			return err
+		}
+		return k.Delete(aliasKey(name))
+	})
+}
+
+// ListAliases returns all aliases sorted by name.
+func (c *Client) ListAliases() ([]Alias, error) {
+	var aliases []Alias
+	err := c.DoReadOnly(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, []byte(AliasPrefix)) {
+				continue
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			id, err := uuid.Parse(string(val))
+			if err != nil {
+				continue // Skip invalid data
+			}
+			aliases = append(aliases, Alias{Name: string(key[len(AliasPrefix):]), NoteID: id})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(aliases, func(i, j int) bool { return aliases[i].Name < aliases[j].Name })
+	return aliases, nil
+}
+
+// resolveAlias returns the note an alias names, if ref is one.
+func (c *Client) resolveAlias(ref string) (uuid.UUID, bool) {
+	name := NormalizeAlias(ref)
+	if ValidateAlias(name) != nil {
+		return uuid.UUID{}, false
+	}
+	val, err := c.Get(aliasKey(name))
+	if err != nil {
+		return uuid.UUID{}, false
+	}
+	id, err := uuid.Parse(string(val))
+	return id, err == nil
+}
+
+// deleteAliasesFor removes the aliases naming any of noteIDs.
+func deleteAliasesFor(k *kv.KV, keys [][]byte, noteIDs map[string]bool) error {
+	for _, key := range keys {
+		if !bytes.HasPrefix(key, []byte(AliasPrefix)) {
+			continue
+		}
+		val, err := k.Get(key)
+		if err != nil || !noteIDs[string(val)] {
+			continue
+		}
+		if err := k.Delete(key); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// ABOUTME: Tests for note alias validation
+// ABOUTME: Validates allowed names and rejection of names that look like ID prefixes
+
+package charm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAlias(t *testing.T) {
+	cases := map[string]error{
+		"roadmap":       nil,
+		"q3-plan_v2":    nil,
+		"Roadmap":       ErrInvalidAlias, // Callers normalize first
+		"2024-plan":     ErrInvalidAlias,
+		"has space":     ErrInvalidAlias,
+		"":              ErrInvalidAlias,
+		"abcdef":        ErrHexAlias,
+		"deadbeef-cafe": ErrHexAlias,
+	}
+	for name, want := range cases {
+		if got := ValidateAlias(name); !errors.Is(got, want) {
+			t.Errorf("ValidateAlias(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if NormalizeAlias("  Roadmap ") != "roadmap" {
+		t.Error("expected NormalizeAlias to trim and lowercase")
+	}
+}
@@ -1,5 +1,5 @@
 // ABOUTME: Attachment operations using Charm KV storage
-// ABOUTME: Uses type-prefixed keys (attachment:uuid) with base64 blob data
+// ABOUTME: Uses type-prefixed keys (attachment:uuid) with base64 data or a blob reference
 
 package charm
 
@@ -9,10 +9,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/charmbracelet/charm/kv"
 	"github.com/google/uuid"
+	"github.com/harper/memo/internal/compress"
 	"github.com/harper/memo/internal/models"
 )
 
@@ -21,17 +23,42 @@ const (
 	AttachmentPrefix = "attachment:"
 )
 
+// DefaultMaxAttachmentSize is the default attachment size limit (10 MiB).
+const DefaultMaxAttachmentSize = 10 << 20
+
 var (
 	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentTooLarge = errors.New("attachment too large")
 )
 
+// CheckAttachmentSize returns ErrAttachmentTooLarge if size exceeds limit.
+// A limit of 0 or less means no limit.
+func CheckAttachmentSize(size, limit int64) error {
+	if limit <= 0 || size <= limit {
+		return nil
+	}
+	return fmt.Errorf("%w: %.1f MiB exceeds max_attachment_size of %.1f MiB",
+		ErrAttachmentTooLarge, float64(size)/(1<<20), float64(limit)/(1<<20))
+}
+
+// AttachmentSize describes an attachment's stored size without its data.
+type AttachmentSize struct {
+	ID       uuid.UUID
+	NoteID   uuid.UUID
+	Filename string
+	Size     int64
+}
+
 // AttachmentData represents an attachment stored in charm KV.
 type AttachmentData struct {
 	ID        string `json:"id"`
 	NoteID    string `json:"note_id"`
 	Filename  string `json:"filename"`
 	MimeType  string `json:"mime_type"`
-	Data      string `json:"data"` // base64-encoded
+	Data      string `json:"data"`             // base64-encoded, maybe compressed; empty when stored as a blob
+	Blob      string `json:"blob,omitempty"`   // SHA-256 of the data in the blob store
+	Remote    bool   `json:"remote,omitempty"` // Blob lives in Charm FS, not just locally
+	Size      int64  `json:"size,omitempty"`   // Data size in bytes, set for blobs and compressed data
 	CreatedAt int64  `json:"created_at"`
 }
 
@@ -45,7 +72,7 @@ func (a *AttachmentData) ToModel() (*models.Attachment, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse note ID: %w", err)
 	}
-	data, err := base64.StdEncoding.DecodeString(a.Data)
+	data, err := compress.ExpandBytes(a.Data)
 	if err != nil {
 		return nil, fmt.Errorf("decode attachment data: %w", err)
 	}
@@ -59,6 +86,22 @@ func (a *AttachmentData) ToModel() (*models.Attachment, error) {
 	}, nil
 }
 
+// Attachment storage modes for the attachment_storage config setting.
+const (
+	StorageKV      = "kv"      // Attachment data inline in the KV value (default)
+	StorageBlobs   = "blobs"   // Attachment data in the local blob store, metadata in KV
+	StorageCharmFS = "charmfs" // Large attachment data in Charm FS, metadata in KV
+)
+
+// checkStorage rejects anything but a known attachment storage mode.
+func checkStorage(mode string) error {
+	switch mode {
+	case StorageKV, StorageBlobs, StorageCharmFS:
+		return nil
+	}
+	return fmt.Errorf("unknown attachment storage %q (want %s, %s, or %s)", mode, StorageKV, StorageBlobs, StorageCharmFS)
+}
+
 // FromAttachmentModel creates AttachmentData from a models.Attachment.
 func FromAttachmentModel(att *models.Attachment) *AttachmentData {
 	return &AttachmentData{
@@ -76,14 +119,105 @@ func attachmentKey(id uuid.UUID) []byte {
 	return []byte(AttachmentPrefix + id.String())
 }
 
+// encodeAttachment converts an attachment for storage, rejecting data over
+// max_attachment_size with ErrAttachmentTooLarge, and moving its data out
+// of KV according to the attachment_storage setting.
+func (c *Client) encodeAttachment(att *models.Attachment) (*AttachmentData, error) {
+	if err := CheckAttachmentSize(int64(len(att.Data)), c.maxAttachment); err != nil {
+		return nil, fmt.Errorf("%s: %w", att.Filename, err)
+	}
+	mode := c.attachmentStorage
+	if mode == StorageCharmFS && len(att.Data) < CharmFSMinSize {
+		mode = StorageKV
+	}
+	ad := FromAttachmentModel(&models.Attachment{
+		ID: att.ID, NoteID: att.NoteID, Filename: att.Filename, MimeType: att.MimeType, CreatedAt: att.CreatedAt,
+	})
+	if err := c.storeData(ad, att.Data, mode); err != nil {
+		return nil, err
+	}
+	return ad, nil
+}
+
+// storeData sets ad's data fields for the given storage mode, writing data
+// to the blob store or Charm FS as needed.
+func (c *Client) storeData(ad *AttachmentData, data []byte, mode string) error {
+	ad.Data, ad.Blob, ad.Remote, ad.Size = "", "", false, 0
+	var err error
+	switch mode {
+	case StorageBlobs:
+		if ad.Blob, err = c.blobs.Put(data); err != nil {
+			return fmt.Errorf("store blob: %w", err)
+		}
+		ad.Size = int64(len(data))
+	case StorageCharmFS:
+		if ad.Blob, err = c.putCharmFS(data); err != nil {
+			return err
+		}
+		ad.Remote, ad.Size = true, int64(len(data))
+	default:
+		if !c.compress {
+			ad.Data = base64.StdEncoding.EncodeToString(data)
+			break
+		}
+		ad.Data = compress.Bytes(data)
+		if compress.IsCompressed(ad.Data) {
+			ad.Size = int64(len(data))
+		}
+	}
+	return nil
+}
+
+// storageOf returns the storage mode an attachment's data is currently in.
+func storageOf(ad *AttachmentData) string {
+	switch {
+	case ad.Blob == "":
+		return StorageKV
+	case ad.Remote:
+		return StorageCharmFS
+	default:
+		return StorageBlobs
+	}
+}
+
+// decodeAttachment converts stored attachment data to a model, reading
+// blob-backed data from the blob store or Charm FS.
+func (c *Client) decodeAttachment(ad *AttachmentData) (*models.Attachment, error) {
+	att, err := ad.ToModel()
+	if err != nil || ad.Blob == "" {
+		return att, err
+	}
+	if ad.Remote {
+		att.Data, err = c.getCharmFS(ad.Blob)
+	} else {
+		att.Data, err = c.blobs.Get(ad.Blob)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("attachment %s: %w", ad.Filename, err)
+	}
+	return att, nil
+}
+
 // CreateAttachment creates a new attachment.
 func (c *Client) CreateAttachment(att *models.Attachment) error {
-	data := FromAttachmentModel(att)
+	data, err := c.encodeAttachment(att)
+	if err != nil {
+		return err
+	}
 	encoded, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("marshal attachment: %w", err)
 	}
-	return c.Set(attachmentKey(att.ID), encoded)
+	return c.Do(func(k *kv.KV) error {
+		if err := k.Set(attachmentKey(att.ID), encoded); err != nil {
+			return err
+		}
+		if err := k.Set(attachmentIndexKey(data.NoteID, data.ID), []byte(data.ID)); err != nil {
+			return err
+		}
+		c.audit(k, AuditAttach, data.Filename, data.NoteID)
+		return nil
+	})
 }
 
 // GetAttachmentByID retrieves an attachment by its UUID.
@@ -101,7 +235,7 @@ func (c *Client) GetAttachmentByID(id uuid.UUID) (*models.Attachment, error) {
 		return nil, fmt.Errorf("unmarshal attachment: %w", err)
 	}
 
-	return attData.ToModel()
+	return c.decodeAttachment(&attData)
 }
 
 // GetAttachmentByPrefix finds an attachment by ID prefix (minimum 6 chars).
@@ -148,10 +282,12 @@ func (c *Client) GetAttachmentByPrefix(prefix string) (*models.Attachment, error
 		return nil, fmt.Errorf("%w: %d matches", ErrAmbiguousPrefix, len(matches))
 	}
 
-	return matches[0].ToModel()
+	return c.decodeAttachment(matches[0])
 }
 
-// ListAttachmentsByNote returns all attachments for a note.
+// ListAttachmentsByNote returns all attachments for a note. Attachments
+// whose blob is neither on this device nor downloadable are returned with
+// Missing set and no data.
 func (c *Client) ListAttachmentsByNote(noteID uuid.UUID) ([]*models.Attachment, error) {
 	var attachments []*models.Attachment
 	prefix := []byte(AttachmentPrefix)
@@ -163,6 +299,15 @@ func (c *Client) ListAttachmentsByNote(noteID uuid.UUID) ([]*models.Attachment,
 			return err
 		}
 
+		// With indexes built, only this note's attachments are read
+		if indexed(k) {
+			ids := indexedIDs(keys, AttachmentIndexPrefix+noteIDStr+":")
+			keys = make([][]byte, len(ids))
+			for i, id := range ids {
+				keys[i] = []byte(AttachmentPrefix + id)
+			}
+		}
+
 		for _, key := range keys {
 			if !bytes.HasPrefix(key, prefix) {
 				continue
@@ -178,13 +323,23 @@ func (c *Client) ListAttachmentsByNote(noteID uuid.UUID) ([]*models.Attachment,
 				continue // Skip invalid data
 			}
 
-			if ad.NoteID == noteIDStr {
-				att, err := ad.ToModel()
-				if err != nil {
-					continue // Skip invalid attachments
+			if ad.NoteID != noteIDStr {
+				continue
+			}
+			att, err := ad.ToModel()
+			if err != nil {
+				continue // Skip invalid attachments
+			}
+			if ad.Blob != "" {
+				// List blob-backed attachments even when their data can't
+				// be read here, so callers can say what's missing
+				if full, err := c.decodeAttachment(&ad); err == nil {
+					att = full
+				} else {
+					att.Missing = true
 				}
-				attachments = append(attachments, att)
 			}
+			attachments = append(attachments, att)
 		}
 		return nil
 	})
@@ -192,31 +347,206 @@ func (c *Client) ListAttachmentsByNote(noteID uuid.UUID) ([]*models.Attachment,
 	return attachments, err
 }
 
-// DeleteAttachment deletes an attachment by ID.
+// DeleteAttachment deletes an attachment by ID, and its Charm FS file
+// when no other attachment shares it.
 func (c *Client) DeleteAttachment(id uuid.UUID) error {
-	if err := c.Delete(attachmentKey(id)); err != nil {
-		if errors.Is(err, kv.ErrMissingKey) {
-			return ErrAttachmentNotFound
+	var remote string
+	err := c.Do(func(k *kv.KV) error {
+		val, err := k.Get(attachmentKey(id))
+		if err != nil {
+			if errors.Is(err, kv.ErrMissingKey) {
+				return ErrAttachmentNotFound
+			}
+			return err
+		}
+		if err := k.Delete(attachmentKey(id)); err != nil {
+			return err
+		}
+
+		var ad AttachmentData
+		if err := json.Unmarshal(val, &ad); err != nil {
+			return nil // Nothing to unindex for unreadable data
+		}
+		if ad.Remote {
+			remote = ad.Blob
+		}
+		if err := k.Delete(attachmentIndexKey(ad.NoteID, ad.ID)); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+			return err
 		}
+		c.audit(k, AuditDetach, ad.Filename, ad.NoteID)
+		return nil
+	})
+	if err != nil {
 		return err
 	}
+	if remote != "" {
+		c.releaseCharmFS([]string{remote})
+	}
 	return nil
 }
 
-// deleteAttachmentsByNote deletes all attachments for a note (cascade delete).
-func (c *Client) deleteAttachmentsByNote(noteID uuid.UUID) error {
-	attachments, err := c.ListAttachmentsByNote(noteID)
+// LargestAttachments returns up to n attachments ordered by size descending
+// (all of them if n <= 0). Sizes are computed without decoding the data.
+func (c *Client) LargestAttachments(n int) ([]AttachmentSize, error) {
+	var result []AttachmentSize
+	prefix := []byte(AttachmentPrefix)
+
+	err := c.DoReadOnly(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, prefix) {
+				continue
+			}
+
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+
+			var ad AttachmentData
+			if err := json.Unmarshal(val, &ad); err != nil {
+				continue // Skip invalid data
+			}
+			id, err := uuid.Parse(ad.ID)
+			if err != nil {
+				continue
+			}
+			noteID, _ := uuid.Parse(ad.NoteID)
+			size := ad.Size
+			if size == 0 {
+				size = int64(base64.StdEncoding.DecodedLen(len(ad.Data)))
+			}
+			result = append(result, AttachmentSize{
+				ID:       id,
+				NoteID:   noteID,
+				Filename: ad.Filename,
+				Size:     size,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Size > result[j].Size })
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result, nil
+}
+
+// MigrateResult reports what MigrateAttachments did.
+type MigrateResult struct {
+	Moved   int
+	Missing []string // Filenames of attachments whose data isn't on this device
+}
+
+// MigrateAttachments moves attachment data into the given storage mode.
+// Attachments smaller than minSize are left where they are unless moving
+// to StorageKV. Attachments whose data can't be read here, like blobs
+// added on another device, are skipped and listed in the result. Charm FS
+// files moved away from are removed once nothing refers to them; local
+// blobs are kept until a compact sweeps the ones nothing refers to. If
+// storing one attachment fails, the ones already moved are still
+// recorded before the error is returned.
+func (c *Client) MigrateAttachments(mode string, minSize int64) (*MigrateResult, error) {
+	if err := checkStorage(mode); err != nil {
+		return nil, err
+	}
+	// Read the records in one session, move the data with no database
+	// open so slow uploads and downloads don't hold the write lock, then
+	// write the records back in a short session
+	type move struct {
+		key  []byte
+		old  []byte
+		blob string // Charm FS blob the attachment used before the move
+		ad   AttachmentData
+	}
+	var moves []*move
+	err := c.DoReadOnly(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, []byte(AttachmentPrefix)) {
+				continue
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			m := &move{key: key, old: val}
+			if err := json.Unmarshal(val, &m.ad); err != nil {
+				continue // Skip invalid data
+			}
+			if storageOf(&m.ad) != mode {
+				moves = append(moves, m)
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, att := range attachments {
-		if err := c.Delete(attachmentKey(att.ID)); err != nil {
-			// Ignore not found errors during cascade
-			if !errors.Is(err, kv.ErrMissingKey) {
+	result := &MigrateResult{}
+	var storeErr error
+	pending := moves[:0]
+	for _, m := range moves {
+		att, err := c.decodeAttachment(&m.ad)
+		if err != nil {
+			result.Missing = append(result.Missing, m.ad.Filename)
+			continue
+		}
+		if mode != StorageKV && int64(len(att.Data)) < minSize {
+			continue
+		}
+		blob := ""
+		if m.ad.Remote {
+			blob = m.ad.Blob
+		}
+		if err := c.storeData(&m.ad, att.Data, mode); err != nil {
+			storeErr = fmt.Errorf("%s: %w", m.ad.Filename, err)
+			break
+		}
+		m.blob = blob
+		pending = append(pending, m)
+	}
+
+	var released []string
+	err = c.Do(func(k *kv.KV) error {
+		for _, m := range pending {
+			// Leave attachments changed or deleted since they were read
+			if val, err := k.Get(m.key); err != nil || !bytes.Equal(val, m.old) {
+				continue
+			}
+			encoded, err := json.Marshal(&m.ad)
+			if err != nil {
 				return err
 			}
+			if err := k.Set(m.key, encoded); err != nil {
+				return err
+			}
+			result.Moved++
+			if m.blob != "" {
+				released = append(released, m.blob)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	c.releaseCharmFS(released)
+	if storeErr != nil {
+		return result, storeErr
+	}
+	c.attachmentStorage = mode
+	return result, nil
 }
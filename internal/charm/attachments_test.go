@@ -0,0 +1,26 @@
+// ABOUTME: Tests for attachment encoding
+// ABOUTME: Validates that the client enforces max_attachment_size before storing any data
+
+package charm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/models"
+)
+
+func TestEncodeAttachmentSizeLimit(t *testing.T) {
+	att := models.NewAttachment(uuid.New(), "scan.pdf", "application/pdf", make([]byte, 2<<20))
+
+	c := &Client{maxAttachment: 1 << 20}
+	if _, err := c.encodeAttachment(att); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Errorf("encodeAttachment over the limit = %v, want ErrAttachmentTooLarge", err)
+	}
+
+	c.SetMaxAttachmentSize(0)
+	if _, err := c.encodeAttachment(att); err != nil {
+		t.Errorf("encodeAttachment with no limit = %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+// ABOUTME: Append-only audit log of note changes
+// ABOUTME: Stored as audit:<note-id>:<time>:<device> keys written in the same session as the change they record, pruned by compact
+
+package charm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/charm/kv"
+	"github.com/google/uuid"
+)
+
+// AuditPrefix is the key prefix for audit log entries.
+const AuditPrefix = "audit:"
+
+// Actor sources.
+const (
+	SourceCLI     = "cli"
+	SourceMCP     = "mcp"
+	SourceLibrary = "library"
+)
+
+// Audit actions.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditTag    = "tag"
+	AuditStar   = "star"
+	AuditUnstar = "unstar"
+	AuditStatus = "status"
+	AuditAttach = "attach"
+	AuditDetach = "detach"
+	AuditDelete = "delete"
+)
+
+// Actor is what changes notes through a Client: a CLI command, an MCP tool
+// called by a named client, or a program using the Go library.
+type Actor struct {
+	Source  string `json:"source"`
+	Command string `json:"command,omitempty"` // CLI command path or MCP tool name
+	Client  string `json:"client,omitempty"`  // MCP client name
+}
+
+// String formats the actor as "cli: edit" or "mcp: update_note (Claude)".
+func (a Actor) String() string {
+	s := a.Source
+	if a.Command != "" {
+		s += ": " + a.Command
+	}
+	if a.Client != "" {
+		s += " (" + a.Client + ")"
+	}
+	return s
+}
+
+// AuditEntry records one change to a note. Entries sync with the notes,
+// so Device tells changes made here from ones synced from elsewhere.
+type AuditEntry struct {
+	NoteID string    `json:"note_id"`
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`
+	Detail string    `json:"detail,omitempty"` // Status, tag, or attachment name
+	Actor
+	Device string `json:"device"`
+	Host   string `json:"host,omitempty"`
+}
+
+// WithActor sets the actor recorded for the client's changes.
+func WithActor(a Actor) Option {
+	return func(c *Client) {
+		c.actor = a
+	}
+}
+
+// SetActor sets the actor recorded for the client's changes from now on.
+func (c *Client) SetActor(a Actor) {
+	c.actor = a
+}
+
+// DeviceID returns this device's ID, a UUID created on first use and kept
+// in the data directory. It identifies the device in audit entries.
+func DeviceID() (string, error) {
+	path := filepath.Join(DataDir(), "device-id")
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // Path is under memo's data dir
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	id := uuid.New().String()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(id+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func auditKey(e *AuditEntry) []byte {
+	device := e.Device
+	if len(device) > 8 {
+		device = device[:8]
+	}
+	return []byte(fmt.Sprintf("%s%s:%d:%s", AuditPrefix, e.NoteID, e.Time.UnixNano(), device))
+}
+
+// audit appends an entry for each note to the log in the open session k.
+// Keys are unique per device and instant, so entries are never rewritten.
+// The change being recorded is already written, so a failed entry is
+// reported as a warning rather than failing it.
+func (c *Client) audit(k *kv.KV, action, detail string, noteIDs ...string) {
+	now := time.Now()
+	for _, id := range noteIDs {
+		e := &AuditEntry{NoteID: id, Time: now, Action: action, Detail: detail, Actor: c.actor, Device: c.device, Host: c.host}
+		data, err := json.Marshal(e)
+		if err == nil {
+			err = k.Set(auditKey(e), data)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: audit log: %v\n", err)
+			return
+		}
+	}
+}
+
+// auditTime returns the time recorded in an audit key, without reading
+// the entry.
+func auditTime(key []byte) (time.Time, bool) {
+	parts := strings.Split(strings.TrimPrefix(string(key), AuditPrefix), ":")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
+
+// pruneAudit deletes audit entries older than the audit_retention setting
+// and returns how many were deleted. A retention of 0 keeps them all.
+func (c *Client) pruneAudit() (int, error) {
+	if c.auditRetention <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().Add(-c.auditRetention)
+	pruned := 0
+	err := c.Do(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, []byte(AuditPrefix)) {
+				continue
+			}
+			if at, ok := auditTime(key); !ok || !at.Before(cutoff) {
+				continue
+			}
+			if err := k.Delete(key); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+				return err
+			}
+			pruned++
+		}
+		return nil
+	})
+	return pruned, err
+}
+
+// AuditLog returns the audit entries of the notes whose IDs start with
+// prefix (every note when it is empty), oldest first. Entries outlive their
+// notes, so deleted notes can be looked up by ID prefix.
+func (c *Client) AuditLog(prefix string) ([]*AuditEntry, error) {
+	var entries []*AuditEntry
+	err := c.DoReadOnly(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		want := []byte(AuditPrefix + strings.ToLower(prefix))
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, want) {
+				continue
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			var e AuditEntry
+			if err := json.Unmarshal(val, &e); err != nil {
+				continue // Skip invalid data
+			}
+			entries = append(entries, &e)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
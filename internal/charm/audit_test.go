@@ -0,0 +1,77 @@
+// ABOUTME: Tests for the audit log
+// ABOUTME: Validates actor formatting, entry keys and their times, tag change details, and the persistent device ID
+
+package charm
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestActorString(t *testing.T) {
+	cases := []struct {
+		actor Actor
+		want  string
+	}{
+		{Actor{Source: SourceCLI, Command: "edit"}, "cli: edit"},
+		{Actor{Source: SourceMCP, Command: "update_note", Client: "claude-desktop"}, "mcp: update_note (claude-desktop)"},
+		{Actor{Source: SourceLibrary}, "library"},
+	}
+	for _, tc := range cases {
+		if got := tc.actor.String(); got != tc.want {
+			t.Errorf("String() = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestAuditKey(t *testing.T) {
+	at := time.Unix(1700000000, 42)
+	e := &AuditEntry{NoteID: "0b6f3a1e-0000-4000-8000-000000000000", Time: at, Device: "d3adbeef-1111-4111-8111-111111111111"}
+
+	want := "audit:0b6f3a1e-0000-4000-8000-000000000000:1700000000000000042:d3adbeef"
+	if got := string(auditKey(e)); got != want {
+		t.Errorf("auditKey = %q, want %q", got, want)
+	}
+
+	if got, ok := auditTime(auditKey(e)); !ok || !got.Equal(at) {
+		t.Errorf("auditTime = %v, %v; want %v", got, ok, at)
+	}
+	if _, ok := auditTime([]byte(AuditPrefix + "garbage")); ok {
+		t.Error("auditTime parsed a malformed key")
+	}
+
+	other := *e
+	other.Device = "c0ffee00-2222-4222-8222-222222222222"
+	if string(auditKey(&other)) == string(auditKey(e)) {
+		t.Error("entries from two devices at the same instant share a key")
+	}
+}
+
+func TestTagChange(t *testing.T) {
+	if got := tagChange("work", ""); got != "-work" {
+		t.Errorf("removal = %q, want -work", got)
+	}
+	if got := tagChange("wrk", "work"); got != "wrk→work" {
+		t.Errorf("rename = %q, want wrk→work", got)
+	}
+}
+
+func TestDeviceIDPersists(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	first, err := DeviceID()
+	if err != nil {
+		t.Fatalf("DeviceID: %v", err)
+	}
+	if len(first) != 36 || strings.Count(first, "-") != 4 {
+		t.Errorf("DeviceID = %q, want a UUID", first)
+	}
+	second, err := DeviceID()
+	if err != nil {
+		t.Fatalf("DeviceID: %v", err)
+	}
+	if second != first {
+		t.Errorf("DeviceID changed between calls: %q then %q", first, second)
+	}
+}
@@ -0,0 +1,161 @@
+// ABOUTME: Charm FS storage for large attachment data
+// ABOUTME: Stores encrypted blobs by hash in Charm FS, cached in the local blob store
+
+package charm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"time"
+
+	charmfs "github.com/charmbracelet/charm/fs"
+	"github.com/harper/memo/internal/blobstore"
+)
+
+// CharmFSMinSize is the smallest attachment stored in Charm FS when
+// attachment_storage is "charmfs"; smaller ones stay inline in KV.
+const CharmFSMinSize = 256 << 10
+
+// charmFSRoot is the Charm FS directory holding attachment blobs, in a
+// directory per database. Blobs uploaded before databases had their own
+// sit in it directly; they are still read but never removed, since any
+// database may use them.
+const charmFSRoot = "memo/attachments"
+
+// charmFSDir returns the Charm FS directory of the client's database.
+func (c *Client) charmFSDir() string {
+	return path.Join(charmFSRoot, c.dbName)
+}
+
+// charmFS returns the Charm FS handle, connecting on first use.
+func (c *Client) charmFS() (*charmfs.FS, error) {
+	if c.cfs != nil {
+		return c.cfs, nil
+	}
+	cfs, err := charmfs.NewFS()
+	if err != nil {
+		return nil, fmt.Errorf("connect to charm fs: %w", err)
+	}
+	c.cfs = cfs
+	return cfs, nil
+}
+
+// putCharmFS uploads data to Charm FS under its hash and keeps a local copy
+// so this device never downloads its own attachments.
+func (c *Client) putCharmFS(data []byte) (string, error) {
+	hash, err := c.blobs.Put(data)
+	if err != nil {
+		return "", fmt.Errorf("cache blob: %w", err)
+	}
+	cfs, err := c.charmFS()
+	if err != nil {
+		return "", err
+	}
+	if err := cfs.WriteFile(path.Join(c.charmFSDir(), hash), newMemFile(hash, data)); err != nil {
+		return "", fmt.Errorf("upload to charm fs: %w", err)
+	}
+	return hash, nil
+}
+
+// getCharmFS returns the data for hash from the local cache, downloading
+// it from Charm FS on first access.
+func (c *Client) getCharmFS(hash string) ([]byte, error) {
+	data, err := c.blobs.Get(hash)
+	if err == nil || !errors.Is(err, blobstore.ErrNotFound) {
+		return data, err
+	}
+
+	cfs, err := c.charmFS()
+	if err != nil {
+		return nil, err
+	}
+	data, err = cfs.ReadFile(path.Join(c.charmFSDir(), hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		data, err = cfs.ReadFile(path.Join(charmFSRoot, hash))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("download from charm fs: %w", err)
+	}
+	if blobstore.Hash(data) != hash {
+		return nil, fmt.Errorf("charm fs blob %s is corrupt", hash)
+	}
+	_, _ = c.blobs.Put(data) // Best-effort cache
+	return data, nil
+}
+
+// releaseCharmFS removes the Charm FS files of hashes that no attachment
+// of the client's database refers to any more. It runs after the session
+// that dropped the references, whose sync brought in attachments other
+// devices made from the same data. Without autosync those may not have
+// arrived yet, so the files are left for compact. Failures are left for
+// compact too.
+func (c *Client) releaseCharmFS(hashes []string) {
+	if len(hashes) == 0 || !c.autoSync {
+		return
+	}
+	refs, _, err := c.blobRefs()
+	if err != nil {
+		return
+	}
+	cfs, err := c.charmFS()
+	if err != nil {
+		return
+	}
+	for _, hash := range hashes {
+		if !refs[hash] {
+			_ = cfs.Remove(path.Join(c.charmFSDir(), hash))
+		}
+	}
+}
+
+// sweepCharmFS removes Charm FS attachment files of the client's database
+// that no attachment in refs uses and that were uploaded before cutoff,
+// and returns how many were removed.
+func (c *Client) sweepCharmFS(refs map[string]bool, cutoff time.Time) (int, error) {
+	cfs, err := c.charmFS()
+	if err != nil {
+		return 0, err
+	}
+	entries, err := cfs.ReadDir(c.charmFSDir())
+	if err != nil {
+		return 0, fmt.Errorf("list charm fs: %w", err)
+	}
+	removed := 0
+	for _, e := range entries {
+		if e.IsDir() || refs[e.Name()] {
+			continue
+		}
+		if info, err := e.Info(); err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := cfs.Remove(path.Join(c.charmFSDir(), e.Name())); err != nil {
+			return removed, fmt.Errorf("remove %s from charm fs: %w", e.Name(), err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// memFile adapts a byte slice to the fs.File that Charm FS uploads from.
+type memFile struct {
+	*bytes.Reader
+	name string
+	size int64
+}
+
+func newMemFile(name string, data []byte) *memFile {
+	return &memFile{Reader: bytes.NewReader(data), name: name, size: int64(len(data))}
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f, nil }
+func (f *memFile) Close() error               { return nil }
+
+func (f *memFile) Name() string       { return f.name }
+func (f *memFile) Size() int64        { return f.size }
+func (f *memFile) Mode() fs.FileMode  { return 0600 }
+func (f *memFile) ModTime() time.Time { return time.Time{} }
+func (f *memFile) IsDir() bool        { return false }
+func (f *memFile) Sys() any           { return nil }
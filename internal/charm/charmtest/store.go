@@ -0,0 +1,270 @@
+// ABOUTME: In-memory implementation of charm.Repository for unit tests
+// ABOUTME: Mirrors Charm KV semantics for prefixes, filters, and cascade deletes
+
+package charmtest
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+)
+
+// Store is an in-memory repository safe for concurrent use.
+type Store struct {
+	mu          sync.Mutex
+	notes       map[uuid.UUID]*charm.NoteData
+	attachments map[uuid.UUID]*models.Attachment
+}
+
+var _ charm.Repository = (*Store)(nil)
+
+// NewStore creates an empty in-memory store.
+func NewStore() *Store {
+	return &Store{
+		notes:       make(map[uuid.UUID]*charm.NoteData),
+		attachments: make(map[uuid.UUID]*models.Attachment),
+	}
+}
+
+// CreateNote stores a new note.
+func (s *Store) CreateNote(note *models.Note, tags []string) error {
+	tags, err := models.NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notes[note.ID] = charm.FromModel(note, tags)
+	return nil
+}
+
+// GetNoteByID retrieves a note by its UUID.
+func (s *Store) GetNoteByID(id uuid.UUID) (*models.Note, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nd, ok := s.notes[id]
+	if !ok {
+		return nil, nil, charm.ErrNoteNotFound
+	}
+	note, err := nd.ToModel()
+	return note, nd.Tags, err
+}
+
+// GetNoteByPrefix finds a note by ID prefix (minimum 6 chars).
+func (s *Store) GetNoteByPrefix(prefix string) (*models.Note, []string, error) {
+	if len(prefix) < 6 {
+		return nil, nil, charm.ErrPrefixTooShort
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matches []*charm.NoteData
+	for id, nd := range s.notes {
+		if strings.HasPrefix(id.String(), prefix) {
+			matches = append(matches, nd)
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return nil, nil, charm.ErrNoteNotFound
+	case len(matches) > 1:
+		return nil, nil, charm.ErrAmbiguousPrefix
+	}
+	note, err := matches[0].ToModel()
+	return note, matches[0].Tags, err
+}
+
+// ListNotes returns notes matching the filter, sorted by updated_at desc.
+func (s *Store) ListNotes(filter *charm.NoteFilter) ([]*models.Note, error) {
+	listed, err := s.ListNotesWithTags(filter)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Note, len(listed))
+	for i, nt := range listed {
+		result[i] = nt.Note
+	}
+	return result, nil
+}
+
+// ListNotesWithTags returns notes matching the filter with their tags,
+// sorted by updated_at desc.
+func (s *Store) ListNotesWithTags(filter *charm.NoteFilter) ([]*charm.NoteWithTags, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []*charm.NoteData
+	for _, nd := range s.notes {
+		if filter == nil || filter.Match(nd) {
+			matched = append(matched, nd)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return matched[i].UpdatedAt > matched[j].UpdatedAt
+	})
+	matched = filter.Page(matched)
+
+	result := make([]*charm.NoteWithTags, 0, len(matched))
+	for _, nd := range matched {
+		note, err := nd.ToModel()
+		if err != nil {
+			continue
+		}
+		tasks := models.CountTasks(note.Content)
+		if filter != nil {
+			note.Content = charm.TruncateContent(note.Content, filter.ContentLimit)
+		}
+		result = append(result, &charm.NoteWithTags{Note: note, Tags: append([]string(nil), nd.Tags...), Tasks: tasks})
+	}
+	return result, nil
+}
+
+// UpdateNote replaces an existing note.
+func (s *Store) UpdateNote(note *models.Note, tags []string) error {
+	tags, err := models.NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.notes[note.ID]
+	if !ok {
+		return charm.ErrNoteNotFound
+	}
+	data := charm.FromModel(note, tags)
+	charm.KeepManaged(data, old)
+	s.notes[note.ID] = data
+	return nil
+}
+
+// DeleteNote deletes a note and its attachments.
+func (s *Store) DeleteNote(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.notes[id]; !ok {
+		return charm.ErrNoteNotFound
+	}
+	for attID, att := range s.attachments {
+		if att.NoteID == id {
+			delete(s.attachments, attID)
+		}
+	}
+	delete(s.notes, id)
+	return nil
+}
+
+// GetNoteTags returns the tags for a note.
+func (s *Store) GetNoteTags(id uuid.UUID) ([]string, error) {
+	_, tags, err := s.GetNoteByID(id)
+	return tags, err
+}
+
+// AddTagToNote adds a normalized tag to a note if not already present.
+func (s *Store) AddTagToNote(noteID uuid.UUID, tagName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nd, ok := s.notes[noteID]
+	if !ok {
+		return charm.ErrNoteNotFound
+	}
+	normalized := models.NormalizeTag(tagName)
+	if err := models.ValidateTag(normalized); err != nil {
+		return err
+	}
+	for _, t := range nd.Tags {
+		if models.NormalizeTag(t) == normalized {
+			return nil
+		}
+	}
+	nd.Tags = append(nd.Tags, normalized)
+	return nil
+}
+
+// RemoveTagFromNote removes a tag from a note.
+func (s *Store) RemoveTagFromNote(noteID uuid.UUID, tagName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	nd, ok := s.notes[noteID]
+	if !ok {
+		return charm.ErrNoteNotFound
+	}
+	normalized := models.NormalizeTag(tagName)
+	tags := make([]string, 0, len(nd.Tags))
+	for _, t := range nd.Tags {
+		if models.NormalizeTag(t) != normalized {
+			tags = append(tags, t)
+		}
+	}
+	nd.Tags = tags
+	return nil
+}
+
+// CreateAttachment stores a new attachment.
+func (s *Store) CreateAttachment(att *models.Attachment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attachments[att.ID] = att
+	return nil
+}
+
+// GetAttachmentByID retrieves an attachment by its UUID.
+func (s *Store) GetAttachmentByID(id uuid.UUID) (*models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	att, ok := s.attachments[id]
+	if !ok {
+		return nil, charm.ErrAttachmentNotFound
+	}
+	return att, nil
+}
+
+// GetAttachmentByPrefix finds an attachment by ID prefix (minimum 6 chars).
+func (s *Store) GetAttachmentByPrefix(prefix string) (*models.Attachment, error) {
+	if len(prefix) < 6 {
+		return nil, charm.ErrPrefixTooShort
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matches []*models.Attachment
+	for id, att := range s.attachments {
+		if strings.HasPrefix(id.String(), prefix) {
+			matches = append(matches, att)
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return nil, charm.ErrAttachmentNotFound
+	case len(matches) > 1:
+		return nil, charm.ErrAmbiguousPrefix
+	}
+	return matches[0], nil
+}
+
+// ListAttachmentsByNote returns all attachments for a note.
+func (s *Store) ListAttachmentsByNote(noteID uuid.UUID) ([]*models.Attachment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*models.Attachment
+	for _, att := range s.attachments {
+		if att.NoteID == noteID {
+			result = append(result, att)
+		}
+	}
+	return result, nil
+}
+
+// DeleteAttachment deletes an attachment by ID.
+func (s *Store) DeleteAttachment(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.attachments[id]; !ok {
+		return charm.ErrAttachmentNotFound
+	}
+	delete(s.attachments, id)
+	return nil
+}
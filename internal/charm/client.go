@@ -4,27 +4,79 @@
 package charm
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"time"
 
 	"github.com/charmbracelet/charm/client"
+	charmfs "github.com/charmbracelet/charm/fs"
 	"github.com/charmbracelet/charm/kv"
 	charmproto "github.com/charmbracelet/charm/proto"
+	"github.com/harper/memo/internal/blobstore"
+	"github.com/harper/memo/internal/synchealth"
+	"github.com/harper/memo/internal/usage"
+	"github.com/harper/memo/internal/webhooks"
 )
 
 const (
 	// DBName is the name of the charm kv database for memo.
 	DBName = "memo"
+
+	// DBNameEnv selects another database, for a separate note store.
+	DBNameEnv = "MEMO_DB_NAME"
+	// ConfigDirEnv overrides the configuration directory.
+	ConfigDirEnv = "MEMO_CONFIG_DIR"
 )
 
+// ErrInvalidDBName is returned for database names that aren't safe as file names.
+var ErrInvalidDBName = errors.New("database name may only contain letters, digits, '-' and '_'")
+
+var dbNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// DatabaseName returns the charm kv database to use: $MEMO_DB_NAME if set,
+// otherwise DBName.
+func DatabaseName() string {
+	if name := os.Getenv(DBNameEnv); name != "" {
+		return name
+	}
+	return DBName
+}
+
+// ValidateDBName checks that name can be used as a database name.
+func ValidateDBName(name string) error {
+	if !dbNamePattern.MatchString(name) {
+		return ErrInvalidDBName
+	}
+	return nil
+}
+
 // Client holds configuration for KV operations.
 // Unlike the previous implementation, it does NOT hold a persistent connection.
 // Each operation opens the database, performs the operation, and closes it.
 type Client struct {
-	dbName         string
-	autoSync       bool
-	staleThreshold time.Duration
+	dbName           string
+	autoSync         bool
+	staleThreshold   time.Duration
+	maintainInterval time.Duration
+	usageMetrics     bool
+	maxAttachment    int64
+	webhooks         []*webhooks.Hook
+	sender           *webhooks.Sender
+
+	compress          bool // Compress large note content and inline attachment data
+	attachmentStorage string
+	blobs             *blobstore.Store
+	cfs               *charmfs.FS // Connected on first use
+
+	session *kv.KV // Shared read-only handle while a ReadSession is open
+	changed bool   // Set once this process writes or syncs the database
+
+	actor          Actor  // Recorded in the audit log for each change
+	device         string // Device ID recorded in audit entries
+	host           string // Hostname recorded in audit entries
+	auditRetention time.Duration
 }
 
 // Option configures a Client.
@@ -59,23 +111,68 @@ func NewClient(opts ...Option) (*Client, error) {
 	}
 
 	c := &Client{
-		dbName:         DBName,
-		autoSync:       cfg.AutoSync,
-		staleThreshold: cfg.StaleThreshold,
+		dbName:           DatabaseName(),
+		autoSync:         cfg.AutoSync,
+		staleThreshold:   time.Duration(cfg.StaleThreshold),
+		maintainInterval: time.Duration(cfg.MaintainInterval),
+		usageMetrics:     cfg.UsageMetrics,
+		maxAttachment:    cfg.MaxAttachmentSize,
+		webhooks:         cfg.Webhooks,
+		sender:           webhooks.NewSender(),
+
+		compress:          cfg.Compression,
+		attachmentStorage: cfg.AttachmentStorage,
+
+		actor:          Actor{Source: SourceLibrary},
+		auditRetention: time.Duration(cfg.AuditRetention),
 	}
+	// Resolved up front so audited writes do no file IO mid-session; entries
+	// are still written, without a device, if the data dir is unusable
+	c.device, _ = DeviceID()
+	c.host, _ = os.Hostname()
 	for _, opt := range opts {
 		opt(c)
 	}
+	// After the options, so a database chosen with WithDBName gets its own blobs
+	c.blobs = &blobstore.Store{Dir: blobsDir(c.dbName), Shared: SharedBlobsDir()}
 	return c, nil
 }
 
-// Get retrieves a value by key (read-only, no lock contention).
-func (c *Client) Get(key []byte) ([]byte, error) {
+// ReadSession runs fn with one read-only database handle shared by every
+// read the client makes until fn returns, instead of opening the database
+// per call. fn should only read: writes open their own connection
+// alongside the session handle. Sessions nest, but a Client with an open
+// session must not be used concurrently.
+func (c *Client) ReadSession(fn func() error) error {
+	if c.session != nil {
+		return fn()
+	}
 	if err := c.SyncIfStale(); err != nil {
-		return nil, err
+		return err
 	}
+	return kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
+		c.session = k
+		defer func() { c.session = nil }()
+		return fn()
+	})
+}
+
+// readOnly runs fn against the session handle if one is open, otherwise
+// against a fresh read-only connection.
+func (c *Client) readOnly(fn func(k *kv.KV) error) error {
+	if c.session != nil {
+		return fn(c.session)
+	}
+	if err := c.SyncIfStale(); err != nil {
+		return err
+	}
+	return kv.DoReadOnly(c.dbName, fn)
+}
+
+// Get retrieves a value by key (read-only, no lock contention).
+func (c *Client) Get(key []byte) ([]byte, error) {
 	var val []byte
-	err := kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
+	err := c.readOnly(func(k *kv.KV) error {
 		var err error
 		val, err = k.Get(key)
 		return err
@@ -89,8 +186,9 @@ func (c *Client) Set(key, value []byte) error {
 		if err := k.Set(key, value); err != nil {
 			return err
 		}
+		c.changed = true
 		if c.autoSync {
-			return k.Sync()
+			return c.syncKV(k, synchealth.TriggerWrite)
 		}
 		return nil
 	})
@@ -102,8 +200,9 @@ func (c *Client) Delete(key []byte) error {
 		if err := k.Delete(key); err != nil {
 			return err
 		}
+		c.changed = true
 		if c.autoSync {
-			return k.Sync()
+			return c.syncKV(k, synchealth.TriggerWrite)
 		}
 		return nil
 	})
@@ -111,11 +210,8 @@ func (c *Client) Delete(key []byte) error {
 
 // Keys returns all keys in the database.
 func (c *Client) Keys() ([][]byte, error) {
-	if err := c.SyncIfStale(); err != nil {
-		return nil, err
-	}
 	var keys [][]byte
-	err := kv.DoReadOnly(c.dbName, func(k *kv.KV) error {
+	err := c.readOnly(func(k *kv.KV) error {
 		var err error
 		keys, err = k.Keys()
 		return err
@@ -126,10 +222,7 @@ func (c *Client) Keys() ([][]byte, error) {
 // DoReadOnly executes a function with read-only database access.
 // Use this for batch read operations that need multiple Gets.
 func (c *Client) DoReadOnly(fn func(k *kv.KV) error) error {
-	if err := c.SyncIfStale(); err != nil {
-		return err
-	}
-	return kv.DoReadOnly(c.dbName, fn)
+	return c.readOnly(fn)
 }
 
 // Do executes a function with write access to the database.
@@ -139,8 +232,9 @@ func (c *Client) Do(fn func(k *kv.KV) error) error {
 		if err := fn(k); err != nil {
 			return err
 		}
+		c.changed = true
 		if c.autoSync {
-			return k.Sync()
+			return c.syncKV(k, synchealth.TriggerWrite)
 		}
 		return nil
 	})
@@ -148,9 +242,84 @@ func (c *Client) Do(fn func(k *kv.KV) error) error {
 
 // Sync triggers a manual sync with the charm server.
 func (c *Client) Sync() error {
-	return kv.Do(c.dbName, func(k *kv.KV) error {
+	return c.sync(synchealth.TriggerManual)
+}
+
+// sync opens the database and syncs it, logging the given trigger.
+func (c *Client) sync(trigger string) error {
+	err := kv.Do(c.dbName, func(k *kv.KV) error {
+		return c.syncKV(k, trigger)
+	})
+	if err == nil {
+		c.changed = true
+		c.notify(webhooks.EventSyncCompleted, nil)
+	}
+	return err
+}
+
+// syncKV syncs an open KV store, retrying transient failures. Auto-sync
+// after a write tries once: it runs while the write handle is held, so
+// backing off there would block every other writer; a failed write sync is
+// picked up by the next manual or stale sync. The outcome is recorded for
+// 'memo sync status' and 'memo sync log', and the duration when usage
+// metrics are enabled.
+func (c *Client) syncKV(k *kv.KV, trigger string) error {
+	retry := synchealth.DefaultRetry()
+	if trigger == synchealth.TriggerWrite {
+		retry.Attempts = 1
+	}
+	start := time.Now()
+	attempts := 0
+	err := retry.Do(func() error {
+		attempts++
 		return k.Sync()
 	})
+
+	// Best-effort: a full state dir must not fail the sync itself
+	_ = synchealth.Record(SyncStatePath(), err)
+	entry := synchealth.LogEntry{Time: start, Trigger: trigger, Duration: time.Since(start), Attempts: attempts}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	_ = synchealth.AppendLog(SyncLogPath(), entry)
+	if c.usageMetrics {
+		_ = usage.RecordSync(UsagePath(), time.Since(start), err) // Best-effort
+	}
+	return err
+}
+
+// UsageMetricsEnabled reports whether local usage metrics are being recorded.
+func (c *Client) UsageMetricsEnabled() bool {
+	return c.usageMetrics
+}
+
+// MaxAttachmentSize returns the attachment size limit in bytes (0 = unlimited).
+func (c *Client) MaxAttachmentSize() int64 {
+	return c.maxAttachment
+}
+
+// SetMaxAttachmentSize sets the limit CreateAttachment and
+// CreateNoteWithAttachments enforce from now on (0 = unlimited), in place
+// of max_attachment_size.
+func (c *Client) SetMaxAttachmentSize(n int64) {
+	c.maxAttachment = n
+}
+
+// Compression reports whether large note content and attachment data are stored compressed.
+func (c *Client) Compression() bool {
+	return c.compress
+}
+
+// notify fires webhooks for event. Delivery failures are reported but never
+// fail the write that triggered them. The sender is shared by every notify,
+// so a hook that just failed isn't retried for each note of a batch.
+func (c *Client) notify(event string, data any) {
+	if len(c.webhooks) == 0 {
+		return
+	}
+	if err := c.sender.Send(c.webhooks, event, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 }
 
 // LastSyncTime returns the timestamp of the last sync operation.
@@ -176,18 +345,25 @@ func (c *Client) IsStale() bool {
 	return isStale
 }
 
+// Changed reports whether this client has written to or synced the
+// database, so callers know when derived caches need refreshing.
+func (c *Client) Changed() bool {
+	return c.changed
+}
+
 // SyncIfStale syncs with the charm server if data is stale.
 func (c *Client) SyncIfStale() error {
 	if !c.IsStale() {
 		return nil
 	}
 	fmt.Fprintf(os.Stderr, "Data stale (last sync > %v ago), syncing...\n", c.staleThreshold)
-	return c.Sync()
+	return c.sync(synchealth.TriggerStale)
 }
 
 // Reset clears all data (nuclear option).
 func (c *Client) Reset() error {
 	return kv.Do(c.dbName, func(k *kv.KV) error {
+		c.changed = true
 		return k.Reset()
 	})
 }
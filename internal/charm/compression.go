@@ -0,0 +1,112 @@
+// ABOUTME: Applies the compression setting to notes and attachments already stored
+// ABOUTME: Rewrites note content and inline attachment data compressed, or expanded when compression is off
+
+package charm
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/charmbracelet/charm/kv"
+	"github.com/harper/memo/internal/compress"
+)
+
+// Recompress rewrites stored notes and inline attachment data to match the
+// compression setting, compressing them when it is on and expanding them
+// when it is off. New writes follow the setting on their own; this brings
+// older records along. It returns how many notes and attachments changed.
+func (c *Client) Recompress() (notes, attachments int, err error) {
+	type rewrite struct {
+		key, old, new []byte
+		note          bool
+	}
+	var rewrites []*rewrite
+
+	// Encode with no write session open, then write in a short one
+	err = c.DoReadOnly(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			isNote := bytes.HasPrefix(key, []byte(NotePrefix))
+			if !isNote && !bytes.HasPrefix(key, []byte(AttachmentPrefix)) {
+				continue
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			encoded, err := c.recompressed(val, isNote)
+			if err != nil || encoded == nil {
+				continue // Leave unreadable data and records already in shape
+			}
+			rewrites = append(rewrites, &rewrite{key: key, old: val, new: encoded, note: isNote})
+		}
+		return nil
+	})
+	if err != nil || len(rewrites) == 0 {
+		return 0, 0, err
+	}
+
+	err = c.Do(func(k *kv.KV) error {
+		for _, r := range rewrites {
+			// Leave records changed or deleted since they were read
+			if val, err := k.Get(r.key); err != nil || !bytes.Equal(val, r.old) {
+				continue
+			}
+			if err := k.Set(r.key, r.new); err != nil {
+				return err
+			}
+			if r.note {
+				notes++
+			} else {
+				attachments++
+			}
+		}
+		return nil
+	})
+	return notes, attachments, err
+}
+
+// recompressed returns a stored note or attachment re-encoded under the
+// compression setting, or nil when it already matches.
+func (c *Client) recompressed(val []byte, isNote bool) ([]byte, error) {
+	if isNote {
+		var stored struct {
+			Content string `json:"content"`
+		}
+		if err := json.Unmarshal(val, &stored); err != nil {
+			return nil, err
+		}
+		var nd NoteData
+		if err := json.Unmarshal(val, &nd); err != nil {
+			return nil, err
+		}
+		want := c.compress && compress.IsCompressed(compress.String(nd.Content))
+		if compress.IsCompressed(stored.Content) == want {
+			return nil, nil
+		}
+		return c.marshalNote(&nd)
+	}
+
+	var ad AttachmentData
+	if err := json.Unmarshal(val, &ad); err != nil {
+		return nil, err
+	}
+	if storageOf(&ad) != StorageKV {
+		return nil, nil // Data lives outside KV
+	}
+	att, err := ad.ToModel()
+	if err != nil {
+		return nil, err
+	}
+	was := compress.IsCompressed(ad.Data)
+	if err := c.storeData(&ad, att.Data, StorageKV); err != nil {
+		return nil, err
+	}
+	if compress.IsCompressed(ad.Data) == was {
+		return nil, nil
+	}
+	return json.Marshal(&ad)
+}
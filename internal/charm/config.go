@@ -5,11 +5,16 @@ package charm
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/charm/kv"
+	"github.com/harper/memo/internal/webhooks"
 )
 
 // Config holds charm sync configuration.
@@ -21,20 +26,115 @@ type Config struct {
 	AutoSync bool `json:"auto_sync"`
 
 	// StaleThreshold is the duration after which data is considered stale
-	StaleThreshold time.Duration `json:"stale_threshold,omitempty"`
+	StaleThreshold Duration `json:"stale_threshold,omitempty"`
+
+	// MaintainInterval runs `memo db maintain` automatically when this much
+	// time has passed since the last run, e.g. "168h" (0 disables)
+	MaintainInterval Duration `json:"maintain_interval,omitempty"`
+
+	// AuditRetention is how long `memo sync compact` keeps audit log
+	// entries, e.g. "8760h" (default: one year, 0 keeps them forever). Not
+	// omitempty so 0 survives a save.
+	AuditRetention Duration `json:"audit_retention"`
+
+	// UsageMetrics records command counts and sync durations to a local
+	// file for `memo stats --usage` (default: false, never sent anywhere)
+	UsageMetrics bool `json:"usage_metrics,omitempty"`
+
+	// MaxAttachmentSize rejects larger attachments unless forced, in bytes
+	// (default: 10 MiB, 0 disables). Not omitempty so 0 survives a save.
+	MaxAttachmentSize int64 `json:"max_attachment_size"`
+
+	// AttachmentStorage is where attachment data lives: "kv" (default),
+	// "blobs" for local-only files under BlobsDir, or "charmfs" to upload
+	// large attachments to Charm FS. Switch with `memo attach migrate`.
+	AttachmentStorage string `json:"attachment_storage,omitempty"`
+
+	// Compression stores note content and inline attachment data of 1 KiB
+	// or more zstd-compressed (default: false). Every device syncing the
+	// notes needs a memo that reads it. Apply to stored notes with
+	// `memo db compress`.
+	Compression bool `json:"compression,omitempty"`
+
+	// Editor overrides $EDITOR for composing and editing notes
+	Editor string `json:"editor,omitempty"`
+
+	// DefaultLimit is the default number of results for `memo list` (default: 20)
+	DefaultLimit int `json:"default_limit,omitempty"`
+
+	// Theme is the glamour style used to render markdown: a standard style
+	// name or the path to a JSON style file (default: auto)
+	Theme string `json:"theme,omitempty"`
+
+	// RenderWidth is the column markdown is word-wrapped at (default: 80)
+	RenderWidth int `json:"render_width,omitempty"`
+
+	// CodeTheme is the chroma theme for code blocks, e.g. "monokai"
+	// (default: the markdown style's own colors)
+	CodeTheme string `json:"code_theme,omitempty"`
+
+	// AutoEnrich turns bare URLs into titled markdown links when notes are
+	// added or edited, as `memo enrich` does (default: false)
+	AutoEnrich bool `json:"auto_enrich,omitempty"`
+
+	// RecordContext stores the hostname, git branch, and commit with notes
+	// added using --here, for `memo list --branch` (default: false)
+	RecordContext bool `json:"record_context,omitempty"`
+
+	// GistToken is the GitHub token `memo share --to gist` uploads with
+	// (default: $GITHUB_TOKEN)
+	GistToken string `json:"gist_token,omitempty"`
+
+	// PasteURL is the 0x0.st-compatible host for `memo share --to paste`
+	// (default: https://0x0.st)
+	PasteURL string `json:"paste_url,omitempty"`
+
+	// MailFrom is the sender address for `memo mail` (default: $USER@hostname)
+	MailFrom string `json:"mail_from,omitempty"`
+
+	// SMTPAddr is the host:port `memo mail` delivers through; when empty,
+	// messages are piped to sendmail instead
+	SMTPAddr     string `json:"smtp_addr,omitempty"`
+	SMTPUser     string `json:"smtp_user,omitempty"`
+	SMTPPassword string `json:"smtp_password,omitempty"`
+
+	// ExportProfiles are named export presets run via `memo export --profile`
+	ExportProfiles map[string]*ExportProfile `json:"export_profiles,omitempty"`
+
+	// Webhooks receive signed POSTs on note create/update/delete and sync completion
+	Webhooks []*webhooks.Hook `json:"webhooks,omitempty"`
+}
+
+// ExportProfile holds preset options for a recurring export.
+type ExportProfile struct {
+	Format           string `json:"format,omitempty"`
+	Output           string `json:"output,omitempty"`
+	Tag              string `json:"tag,omitempty"`
+	Search           string `json:"search,omitempty"`
+	ScrubDirTags     bool   `json:"scrub_dir_tags,omitempty"`    // Drop dir: tags so local paths don't leak
+	FilenameTemplate string `json:"filename_template,omitempty"` // Markdown filename template, e.g. "{{.Date}}-{{.Title}}"
 }
 
+// DefaultAuditRetention is how long audit log entries are kept by default.
+const DefaultAuditRetention = 365 * 24 * time.Hour
+
 // DefaultConfig returns a Config with sensible defaults.
 func DefaultConfig() *Config {
 	return &Config{
-		CharmHost:      "charm.2389.dev",
-		AutoSync:       true,
-		StaleThreshold: kv.DefaultStaleThreshold,
+		CharmHost:         "charm.2389.dev",
+		AutoSync:          true,
+		StaleThreshold:    Duration(kv.DefaultStaleThreshold),
+		MaxAttachmentSize: DefaultMaxAttachmentSize,
+		AuditRetention:    Duration(DefaultAuditRetention),
 	}
 }
 
-// ConfigDir returns the configuration directory path.
+// ConfigDir returns the configuration directory path: $MEMO_CONFIG_DIR if
+// set, otherwise XDG_CONFIG_HOME/memo.
 func ConfigDir() string {
+	if dir := os.Getenv(ConfigDirEnv); dir != "" {
+		return dir
+	}
 	configHome := os.Getenv("XDG_CONFIG_HOME")
 	if configHome == "" {
 		home, _ := os.UserHomeDir()
@@ -43,6 +143,96 @@ func ConfigDir() string {
 	return filepath.Join(configHome, "memo")
 }
 
+// StateDir returns the directory for local state files (XDG_STATE_HOME/memo).
+// Databases other than the default keep their state in a subdirectory so
+// sync health and list positions don't mix between stores.
+func StateDir() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, _ := os.UserHomeDir()
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	if db := DatabaseName(); db != DBName {
+		return filepath.Join(stateHome, "memo", db)
+	}
+	return filepath.Join(stateHome, "memo")
+}
+
+// DataDir returns the directory for local data files (XDG_DATA_HOME/memo).
+func DataDir() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, _ := os.UserHomeDir()
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "memo")
+}
+
+// BlobsDir returns the directory of the content-addressed attachment store.
+// Each database has its own, so compacting one never sweeps blobs another
+// still references.
+func BlobsDir() string {
+	return blobsDir(DatabaseName())
+}
+
+func blobsDir(db string) string {
+	return filepath.Join(SharedBlobsDir(), db)
+}
+
+// SharedBlobsDir returns the blob directory every database used before
+// each got its own. Blobs are still read from it but never swept.
+func SharedBlobsDir() string {
+	return filepath.Join(DataDir(), "blobs")
+}
+
+// UsagePath returns the path to the local usage metrics file.
+func UsagePath() string {
+	return filepath.Join(StateDir(), "usage.json")
+}
+
+// SyncStatePath returns the path to the file tracking recent sync failures.
+func SyncStatePath() string {
+	return filepath.Join(StateDir(), "sync-state.json")
+}
+
+// SyncLogPath returns the path to the log of recent sync attempts.
+func SyncLogPath() string {
+	return filepath.Join(StateDir(), "sync-log.jsonl")
+}
+
+// LastListPath returns the path to the record of the last note listing.
+func LastListPath() string {
+	return filepath.Join(StateDir(), "last-list.json")
+}
+
+// LinkTitlesPath returns the path to the cache of fetched page titles.
+func LinkTitlesPath() string {
+	return filepath.Join(StateDir(), "link-titles.json")
+}
+
+// StatusCachePath returns the path to the cached note counts for
+// `memo status --short`.
+func StatusCachePath() string {
+	return filepath.Join(StateDir(), "status.json")
+}
+
+// SessionStatePath returns the path to the running session's state.
+func SessionStatePath() string {
+	return filepath.Join(StateDir(), "session.json")
+}
+
+// SessionSpoolPath returns the path to session entries not yet written to
+// their journal note.
+func SessionSpoolPath() string {
+	return filepath.Join(StateDir(), "session.jsonl")
+}
+
+// ReviewStatePath returns the path to the state naming the current review
+// note.
+func ReviewStatePath() string {
+	return filepath.Join(StateDir(), "review.json")
+}
+
 // ConfigPath returns the path to the config file.
 func ConfigPath() string {
 	return filepath.Join(ConfigDir(), "charm.json")
@@ -82,6 +272,245 @@ func SaveConfig(cfg *Config) error {
 	return os.WriteFile(ConfigPath(), data, 0600)
 }
 
+// ConfigKey describes a scalar setting managed by `memo config`.
+type ConfigKey struct {
+	Name string
+	// Help is the one-line description shown by `memo config --help`;
+	// further lines continue it.
+	Help string
+	// Secret keys are masked by `memo config list` and `set`; `get`
+	// prints them.
+	Secret bool
+}
+
+// ConfigKeys lists the scalar settings managed by `memo config`, in the
+// order `memo config list` and the help show them.
+var ConfigKeys = []ConfigKey{
+	{"charm_host", "Charm server host", false},
+	{"auto_sync", "Sync after every write (true/false)", false},
+	{"stale_threshold", "Sync before reads when older than this (e.g. 1h, 0 disables)", false},
+	{"maintain_interval", "Run 'memo db maintain' automatically (e.g. 168h, 0 disables)", false},
+	{"audit_retention", "Audit log kept by 'memo sync compact' (e.g. 8760h, 0 keeps all)", false},
+	{"usage_metrics", "Record local usage metrics (true/false)", false},
+	{"max_attachment_size", "Largest attachment without --force (e.g. 10MB, 0 disables)", false},
+	{"attachment_storage", "Where new attachment data goes: kv, blobs, or charmfs (default kv)", false},
+	{"compression", "Store large content zstd-compressed (true/false)", false},
+	{"editor", "Editor command, overrides $EDITOR", false},
+	{"default_limit", "Default result count for 'memo list'", false},
+	{"theme", "Markdown style: auto, dark, light, notty, dracula, ...\nor the path to a glamour JSON style file", false},
+	{"render_width", "Column to wrap rendered markdown at (default 80)", false},
+	{"code_theme", "Chroma theme for code blocks, e.g. monokai", false},
+	{"auto_enrich", "Link bare URLs with their page titles on add/edit (true/false)", false},
+	{"record_context", "Store hostname, git branch, and commit with notes (true/false)", false},
+	{"gist_token", "GitHub token for 'memo share --to gist' (default $GITHUB_TOKEN)", true},
+	{"paste_url", "File host for 'memo share --to paste' (default https://0x0.st)", false},
+	{"mail_from", "Sender for 'memo mail' (default $USER@hostname)", false},
+	{"smtp_addr", "SMTP server host:port for 'memo mail' (default: use sendmail)", false},
+	{"smtp_user", "SMTP username", false},
+	{"smtp_password", "SMTP password", true},
+}
+
+// SecretConfigKey reports whether the setting named key is a secret.
+func SecretConfigKey(key string) bool {
+	for _, k := range ConfigKeys {
+		if k.Name == key {
+			return k.Secret
+		}
+	}
+	return false
+}
+
+// Get returns the string form of a config setting.
+func (c *Config) Get(key string) (string, error) {
+	switch key {
+	case "charm_host":
+		return c.CharmHost, nil
+	case "auto_sync":
+		return strconv.FormatBool(c.AutoSync), nil
+	case "stale_threshold":
+		return c.StaleThreshold.String(), nil
+	case "maintain_interval":
+		return c.MaintainInterval.String(), nil
+	case "audit_retention":
+		return c.AuditRetention.String(), nil
+	case "usage_metrics":
+		return strconv.FormatBool(c.UsageMetrics), nil
+	case "max_attachment_size":
+		return strconv.FormatInt(c.MaxAttachmentSize, 10), nil
+	case "attachment_storage":
+		return c.AttachmentStorage, nil
+	case "compression":
+		return strconv.FormatBool(c.Compression), nil
+	case "editor":
+		return c.Editor, nil
+	case "default_limit":
+		return strconv.Itoa(c.DefaultLimit), nil
+	case "theme":
+		return c.Theme, nil
+	case "render_width":
+		return strconv.Itoa(c.RenderWidth), nil
+	case "code_theme":
+		return c.CodeTheme, nil
+	case "auto_enrich":
+		return strconv.FormatBool(c.AutoEnrich), nil
+	case "record_context":
+		return strconv.FormatBool(c.RecordContext), nil
+	case "gist_token":
+		return c.GistToken, nil
+	case "paste_url":
+		return c.PasteURL, nil
+	case "mail_from":
+		return c.MailFrom, nil
+	case "smtp_addr":
+		return c.SMTPAddr, nil
+	case "smtp_user":
+		return c.SMTPUser, nil
+	case "smtp_password":
+		return c.SMTPPassword, nil
+	default:
+		return "", fmt.Errorf("unknown config key %q", key)
+	}
+}
+
+// Set parses value and assigns it to a config setting.
+func (c *Config) Set(key, value string) error {
+	var err error
+	switch key {
+	case "charm_host":
+		c.CharmHost = value
+	case "auto_sync":
+		c.AutoSync, err = strconv.ParseBool(value)
+	case "stale_threshold":
+		c.StaleThreshold, err = ParseDuration(value)
+	case "maintain_interval":
+		c.MaintainInterval, err = ParseDuration(value)
+	case "audit_retention":
+		c.AuditRetention, err = ParseDuration(value)
+	case "usage_metrics":
+		c.UsageMetrics, err = strconv.ParseBool(value)
+	case "max_attachment_size":
+		c.MaxAttachmentSize, err = ParseSize(value)
+	case "attachment_storage":
+		if err = checkStorage(value); err == nil {
+			c.AttachmentStorage = value
+		}
+	case "compression":
+		c.Compression, err = strconv.ParseBool(value)
+	case "editor":
+		c.Editor = value
+	case "default_limit":
+		c.DefaultLimit, err = strconv.Atoi(value)
+		if err == nil && c.DefaultLimit < 0 {
+			err = errors.New("must not be negative")
+		}
+	case "theme":
+		c.Theme = value
+	case "render_width":
+		c.RenderWidth, err = strconv.Atoi(value)
+		if err == nil && c.RenderWidth < 0 {
+			err = errors.New("must not be negative")
+		}
+	case "code_theme":
+		c.CodeTheme = value
+	case "auto_enrich":
+		c.AutoEnrich, err = strconv.ParseBool(value)
+	case "record_context":
+		c.RecordContext, err = strconv.ParseBool(value)
+	case "gist_token":
+		c.GistToken = value
+	case "paste_url":
+		c.PasteURL = value
+	case "mail_from":
+		c.MailFrom = value
+	case "smtp_addr":
+		c.SMTPAddr = value
+	case "smtp_user":
+		c.SMTPUser = value
+	case "smtp_password":
+		c.SMTPPassword = value
+	default:
+		return fmt.Errorf("unknown config key %q", key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return nil
+}
+
+// Duration is a time.Duration stored in the config file as a string like
+// "168h", so the file can be edited by hand. Numbers of nanoseconds, as
+// older versions wrote, are read too.
+type Duration time.Duration
+
+// ParseDuration parses a duration string such as "30m" or "168h".
+func ParseDuration(value string) (Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	return Duration(d), err
+}
+
+// String formats the duration like time.Duration does.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalJSON writes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads a duration string or a number of nanoseconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("duration must be a string like \"168h\": %s", data)
+		}
+		*d = Duration(n)
+		return nil
+	}
+	parsed, err := ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// ParseSize parses a byte count with an optional KB, MB, or GB suffix
+// (binary multiples, case-insensitive), e.g. "512KB" or "25MB".
+func ParseSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	return n * multiplier, nil
+}
+
+// ExportProfile returns the named export profile from the config.
+func (c *Config) ExportProfile(name string) (*ExportProfile, error) {
+	profile, ok := c.ExportProfiles[name]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("export profile %q not found in %s", name, ConfigPath())
+	}
+	return profile, nil
+}
+
 // ConfigExists returns true if a config file exists.
 func ConfigExists() bool {
 	_, err := os.Stat(ConfigPath())
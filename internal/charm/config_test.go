@@ -0,0 +1,144 @@
+// ABOUTME: Tests for charm configuration helpers
+// ABOUTME: Validates export profile lookup, get/set, and durations in the config file
+
+package charm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportProfileLookup(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ExportProfiles = map[string]*ExportProfile{
+		"weekly-site": {Format: "md", Output: "site/notes", Tag: "publish", ScrubDirTags: true},
+	}
+
+	profile, err := cfg.ExportProfile("weekly-site")
+	if err != nil {
+		t.Fatalf("expected profile, got error: %v", err)
+	}
+	if profile.Format != "md" || profile.Tag != "publish" || !profile.ScrubDirTags {
+		t.Errorf("unexpected profile: %+v", profile)
+	}
+
+	if _, err := cfg.ExportProfile("missing"); err == nil {
+		t.Error("expected error for missing profile")
+	}
+}
+
+func TestConfigGetSet(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if err := cfg.Set("auto_sync", "false"); err != nil {
+		t.Fatalf("set auto_sync: %v", err)
+	}
+	if err := cfg.Set("stale_threshold", "30m"); err != nil {
+		t.Fatalf("set stale_threshold: %v", err)
+	}
+	if err := cfg.Set("default_limit", "50"); err != nil {
+		t.Fatalf("set default_limit: %v", err)
+	}
+	if err := cfg.Set("max_attachment_size", "25MB"); err != nil {
+		t.Fatalf("set max_attachment_size: %v", err)
+	}
+	if err := cfg.Set("attachment_storage", "blobs"); err != nil {
+		t.Fatalf("set attachment_storage: %v", err)
+	}
+	if err := cfg.Set("render_width", "100"); err != nil {
+		t.Fatalf("set render_width: %v", err)
+	}
+	if err := cfg.Set("auto_enrich", "true"); err != nil {
+		t.Fatalf("set auto_enrich: %v", err)
+	}
+
+	for key, want := range map[string]string{
+		"auto_sync":           "false",
+		"stale_threshold":     "30m0s",
+		"default_limit":       "50",
+		"max_attachment_size": "26214400",
+		"attachment_storage":  "blobs",
+		"render_width":        "100",
+		"auto_enrich":         "true",
+	} {
+		got, err := cfg.Get(key)
+		if err != nil || got != want {
+			t.Errorf("Get(%q) = %q, %v; want %q", key, got, err, want)
+		}
+	}
+}
+
+func TestConfigKeysGettable(t *testing.T) {
+	cfg := DefaultConfig()
+	for _, key := range ConfigKeys {
+		if key.Help == "" {
+			t.Errorf("%s has no help", key.Name)
+		}
+		if _, err := cfg.Get(key.Name); err != nil {
+			t.Errorf("Get(%q): %v", key.Name, err)
+		}
+	}
+}
+
+func TestConfigSetInvalid(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if err := cfg.Set("auto_sync", "maybe"); err == nil {
+		t.Error("expected error for invalid bool")
+	}
+	if err := cfg.Set("default_limit", "-1"); err == nil {
+		t.Error("expected error for negative limit")
+	}
+	if err := cfg.Set("render_width", "-80"); err == nil {
+		t.Error("expected error for negative width")
+	}
+	if err := cfg.Set("max_attachment_size", "lots"); err == nil {
+		t.Error("expected error for invalid size")
+	}
+	if err := cfg.Set("attachment_storage", "s3"); err == nil {
+		t.Error("expected error for unknown attachment storage")
+	}
+	if err := cfg.Set("nope", "x"); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
+
+func TestConfigDurationJSON(t *testing.T) {
+	var cfg Config
+	if err := json.Unmarshal([]byte(`{"maintain_interval": "168h", "stale_threshold": 1800000000000}`), &cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if time.Duration(cfg.MaintainInterval) != 168*time.Hour {
+		t.Errorf("maintain_interval = %v, want 168h", cfg.MaintainInterval)
+	}
+	if time.Duration(cfg.StaleThreshold) != 30*time.Minute {
+		t.Errorf("stale_threshold = %v, want 30m (nanoseconds from older versions)", cfg.StaleThreshold)
+	}
+
+	data, err := json.Marshal(&cfg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"maintain_interval":"168h0m0s"`) {
+		t.Errorf("marshaled %s, want maintain_interval as a string", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"maintain_interval": "weekly"}`), &cfg); err == nil {
+		t.Error("expected error for invalid duration")
+	}
+}
+
+func TestSecretConfigKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"gist_token":    true,
+		"smtp_password": true,
+		"smtp_user":     false,
+		"nope":          false,
+	} {
+		if got := SecretConfigKey(key); got != want {
+			t.Errorf("SecretConfigKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
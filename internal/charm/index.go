@@ -0,0 +1,153 @@
+// ABOUTME: Secondary index keys for tag and attachment lookups
+// ABOUTME: Maintained on write so filtered reads decode only matching values
+
+package charm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"github.com/charmbracelet/charm/kv"
+	"github.com/harper/memo/internal/models"
+)
+
+const (
+	// TagIndexPrefix keys (tagidx:<tag>:<note-id>) list the notes carrying a tag.
+	TagIndexPrefix = "tagidx:"
+	// AttachmentIndexPrefix keys (noteatt:<note-id>:<att-id>) list a note's attachments.
+	AttachmentIndexPrefix = "noteatt:"
+
+	// indexMarkerKey is set once the indexes have been built for existing
+	// data. Until then, reads fall back to full scans.
+	indexMarkerKey = "meta:indexed"
+)
+
+// idLen is the length of a UUID string, which ends every index key.
+const idLen = 36
+
+// tagIndexKey returns the index key linking a tag to a note.
+func tagIndexKey(tag, noteID string) []byte {
+	return []byte(TagIndexPrefix + models.NormalizeTag(tag) + ":" + noteID)
+}
+
+// attachmentIndexKey returns the index key linking a note to an attachment.
+func attachmentIndexKey(noteID, attID string) []byte {
+	return []byte(AttachmentIndexPrefix + noteID + ":" + attID)
+}
+
+// setTagIndex adds index keys for each of a note's tags.
+func setTagIndex(k *kv.KV, noteID string, tags []string) error {
+	for _, tag := range tags {
+		if err := k.Set(tagIndexKey(tag, noteID), []byte(noteID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteTagIndex removes index keys for each of a note's tags.
+func deleteTagIndex(k *kv.KV, noteID string, tags []string) error {
+	for _, tag := range tags {
+		if err := k.Delete(tagIndexKey(tag, noteID)); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexed reports whether the indexes cover all existing data.
+func indexed(k *kv.KV) bool {
+	_, err := k.Get([]byte(indexMarkerKey))
+	return err == nil
+}
+
+// indexedIDs returns the trailing IDs of index keys that are exactly prefix
+// followed by an ID, so tag "a" does not match keys for tag "a:b".
+func indexedIDs(keys [][]byte, prefix string) []string {
+	var ids []string
+	for _, key := range keys {
+		if len(key) == len(prefix)+idLen && bytes.HasPrefix(key, []byte(prefix)) {
+			ids = append(ids, string(key[len(prefix):]))
+		}
+	}
+	return ids
+}
+
+// indexedTag returns the tag a filter can be answered from, if any.
+func indexedTag(filter *NoteFilter) (string, bool) {
+	switch {
+	case filter == nil:
+		return "", false
+	case filter.Tag != nil:
+		return *filter.Tag, true
+	case filter.DirTag != nil:
+		return "dir:" + *filter.DirTag, true
+	}
+	return "", false
+}
+
+// Reindex rebuilds the tag and attachment indexes from the stored notes and
+// attachments and returns the number of index keys written. Run it after
+// syncing data written by memo versions that did not maintain indexes.
+func (c *Client) Reindex() (int, error) {
+	written := 0
+	err := c.Do(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if bytes.HasPrefix(key, []byte(TagIndexPrefix)) || bytes.HasPrefix(key, []byte(AttachmentIndexPrefix)) {
+				if err := k.Delete(key); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+					return err
+				}
+			}
+		}
+
+		for _, key := range keys {
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			switch {
+			case bytes.HasPrefix(key, []byte(NotePrefix)):
+				var nd NoteData
+				if err := json.Unmarshal(val, &nd); err != nil {
+					continue // Skip invalid data
+				}
+				if err := setTagIndex(k, nd.ID, nd.Tags); err != nil {
+					return err
+				}
+				written += len(nd.Tags)
+			case bytes.HasPrefix(key, []byte(AttachmentPrefix)):
+				var ad AttachmentData
+				if err := json.Unmarshal(val, &ad); err != nil {
+					continue // Skip invalid data
+				}
+				if err := k.Set(attachmentIndexKey(ad.NoteID, ad.ID), []byte(ad.ID)); err != nil {
+					return err
+				}
+				written++
+			}
+		}
+
+		return k.Set([]byte(indexMarkerKey), []byte("1"))
+	})
+	return written, err
+}
+
+// noteKeysForTag returns the keys of notes indexed under tag, or false if
+// the indexes have not been built.
+func noteKeysForTag(k *kv.KV, keys [][]byte, tag string) ([][]byte, bool) {
+	if !indexed(k) {
+		return nil, false
+	}
+	ids := indexedIDs(keys, TagIndexPrefix+models.NormalizeTag(tag)+":")
+	result := make([][]byte, len(ids))
+	for i, id := range ids {
+		result[i] = []byte(NotePrefix + id)
+	}
+	return result, true
+}
@@ -0,0 +1,45 @@
+// ABOUTME: Tests for secondary index key handling
+// ABOUTME: Validates exact-prefix ID extraction and which filters use the tag index
+
+package charm
+
+import "testing"
+
+func TestIndexedIDsMatchesExactTag(t *testing.T) {
+	id1 := "11111111-1111-1111-1111-111111111111"
+	id2 := "22222222-2222-2222-2222-222222222222"
+	keys := [][]byte{
+		tagIndexKey("Work", id1),
+		tagIndexKey("work:urgent", id2),
+		tagIndexKey("dir:/src", id2),
+		[]byte(NotePrefix + id1),
+	}
+
+	ids := indexedIDs(keys, TagIndexPrefix+"work:")
+	if len(ids) != 1 || ids[0] != id1 {
+		t.Errorf("expected only %s for tag work, got %v", id1, ids)
+	}
+	if ids := indexedIDs(keys, TagIndexPrefix+"dir:/src:"); len(ids) != 1 || ids[0] != id2 {
+		t.Errorf("expected %s for dir tag, got %v", id2, ids)
+	}
+}
+
+func TestIndexedTag(t *testing.T) {
+	tag, dir := "work", "/src"
+	cases := []struct {
+		filter *NoteFilter
+		want   string
+		ok     bool
+	}{
+		{nil, "", false},
+		{&NoteFilter{Search: "x"}, "", false},
+		{&NoteFilter{Tag: &tag}, "work", true},
+		{&NoteFilter{DirTag: &dir}, "dir:/src", true},
+	}
+	for _, tc := range cases {
+		got, ok := indexedTag(tc.filter)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("indexedTag(%+v) = %q, %v; want %q, %v", tc.filter, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+// ABOUTME: Database maintenance for the SQLite-backed Charm KV store
+// ABOUTME: Runs integrity check, WAL checkpoint, and vacuum on a plain connection, reporting space reclaimed
+
+package charm
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/charm/client"
+	"github.com/charmbracelet/charm/kv"
+	_ "modernc.org/sqlite" // SQLite driver, as used by kv
+)
+
+// MaintenanceResult reports the outcome of a maintenance run.
+type MaintenanceResult struct {
+	IntegrityOK  bool  // PRAGMA integrity_check passed
+	Checkpointed bool  // WAL checkpointed into the database and truncated
+	Vacuumed     bool  // Database rebuilt without free pages
+	Warning      error // Step skipped because another process was using the database
+	Orphans      int   // Keys dropped because their note or attachment is gone (Compact only)
+	Blobs        int   // Local blob files removed because no attachment uses them (Compact only)
+	Remote       int   // Charm FS attachment files removed because no attachment uses them (Compact only)
+	RemoteErr    error // Why Charm FS couldn't be swept, e.g. when offline (Compact only)
+	Audit        int   // Audit entries past audit_retention deleted (Compact only)
+	Indexed      int   // Index keys rebuilt (Compact only)
+	SizeBefore   int64 // DB + WAL bytes before maintenance
+	SizeAfter    int64 // DB + WAL bytes after maintenance
+}
+
+// Reclaimed returns the number of bytes freed (never negative).
+func (r *MaintenanceResult) Reclaimed() int64 {
+	if r.SizeAfter >= r.SizeBefore {
+		return 0
+	}
+	return r.SizeBefore - r.SizeAfter
+}
+
+// DBPath returns the on-disk path of the KV database file.
+func (c *Client) DBPath() (string, error) {
+	cc, err := client.NewClientWithDefaults()
+	if err != nil {
+		return "", err
+	}
+	dataDir, err := cc.DataPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "kv", c.dbName+".db"), nil
+}
+
+// Maintain runs PRAGMA integrity_check, WAL checkpointing, and VACUUM on
+// an ordinary connection, so other processes can keep the database open
+// meanwhile. Unlike kv.Repair it never touches the WAL index (-shm) file.
+func (c *Client) Maintain() (*MaintenanceResult, error) {
+	dbPath, err := c.DBPath()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	result := &MaintenanceResult{SizeBefore: storeSize(dbPath)}
+	err = maintainDB(dbPath, result)
+	result.SizeAfter = storeSize(dbPath)
+	if err != nil {
+		return result, err
+	}
+
+	_ = os.MkdirAll(StateDir(), 0750)
+	_ = os.WriteFile(lastMaintenancePath(), []byte(time.Now().Format(time.RFC3339)), 0600)
+	return result, nil
+}
+
+// maintainDB checks, checkpoints, and vacuums the database at dbPath,
+// recording each step in result. A failed integrity check is an error;
+// steps blocked by another connection are skipped with a warning.
+func maintainDB(dbPath string, result *MaintenanceResult) error {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	db.SetMaxOpenConns(1) // PRAGMAs apply per connection
+	if _, err := db.Exec("PRAGMA busy_timeout=5000"); err != nil {
+		return err
+	}
+
+	rows, err := db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("integrity check: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("integrity check failed: %s; run 'memo sync repair'", strings.Join(problems, "; "))
+	}
+	result.IntegrityOK = true
+
+	var busy, logFrames, checkpointed int
+	if err := db.QueryRow("PRAGMA wal_checkpoint(TRUNCATE)").Scan(&busy, &logFrames, &checkpointed); err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+	if busy != 0 {
+		result.Warning = errors.New("checkpoint incomplete: another process is reading the database")
+	} else {
+		result.Checkpointed = true
+	}
+
+	if _, err := db.Exec("VACUUM"); err != nil {
+		if result.Warning == nil {
+			result.Warning = fmt.Errorf("vacuum skipped: %w", err)
+		}
+		return nil
+	}
+	result.Vacuumed = true
+	return nil
+}
+
+// Compact drops orphaned keys, audit entries past their retention, and
+// unreferenced blobs, locally and in Charm FS, rebuilds the indexes, then
+// runs Maintain. SizeBefore is measured before any keys are dropped.
+func (c *Client) Compact() (*MaintenanceResult, error) {
+	dbPath, err := c.DBPath()
+	if err != nil {
+		return nil, err
+	}
+	before := storeSize(dbPath)
+
+	orphans, err := c.dropOrphans()
+	if err != nil {
+		return nil, fmt.Errorf("drop orphaned keys: %w", err)
+	}
+	pruned, err := c.pruneAudit()
+	if err != nil {
+		return nil, fmt.Errorf("prune audit log: %w", err)
+	}
+	refs, remote, err := c.blobRefs()
+	if err != nil {
+		return nil, fmt.Errorf("sweep blobs: %w", err)
+	}
+	cutoff := time.Now().Add(-blobGracePeriod)
+	blobs, err := c.blobs.Sweep(refs, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("sweep blobs: %w", err)
+	}
+	var removed int
+	var remoteErr error
+	if remote || c.attachmentStorage == StorageCharmFS {
+		removed, remoteErr = c.sweepCharmFS(refs, cutoff)
+	}
+	indexed, err := c.Reindex()
+	if err != nil {
+		return nil, fmt.Errorf("rebuild indexes: %w", err)
+	}
+
+	result, err := c.Maintain()
+	if result != nil {
+		result.Orphans = orphans
+		result.Blobs = blobs
+		result.Audit = pruned
+		result.Remote = removed
+		result.RemoteErr = remoteErr
+		result.Indexed = indexed
+		result.SizeBefore = before
+	}
+	return result, err
+}
+
+// dropOrphans deletes keys that belong to a note or attachment that no
+// longer exists: attachments, tag and attachment index entries, aliases,
+// manual ranks, and revoked share records. They are left behind by
+// interrupted deletes, by syncs that crossed a delete, and by deletes,
+// which keep a note's timers and shares. Timer intervals are never
+// dropped: timesheets still report hours logged on deleted notes. Live
+// shares are kept so 'memo share --revoke' can still take them down.
+func (c *Client) dropOrphans() (int, error) {
+	dropped := 0
+	err := c.Do(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+
+		notes := make(map[string]bool)
+		attachments := make(map[string]bool)
+		for _, key := range keys {
+			switch s := string(key); {
+			case strings.HasPrefix(s, NotePrefix):
+				notes[strings.TrimPrefix(s, NotePrefix)] = true
+			case strings.HasPrefix(s, AttachmentPrefix):
+				attachments[strings.TrimPrefix(s, AttachmentPrefix)] = true
+			}
+		}
+
+		for _, key := range keys {
+			noteID, attID, ok := keyOwner(string(key))
+			if !ok {
+				noteID, ok = valueOwner(k, key)
+			}
+			if !ok || (notes[noteID] && (attID == "" || attachments[attID])) {
+				continue
+			}
+			if bytes.HasPrefix(key, []byte(SharePrefix)) {
+				if val, err := k.Get(key); err == nil && liveShare(val) {
+					continue
+				}
+			}
+			if err := k.Delete(key); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+				return err
+			}
+			dropped++
+		}
+		return nil
+	})
+	return dropped, err
+}
+
+// keyOwner returns the note a key belongs to when the key names it, and
+// the attachment too for attachment index keys. Timer keys report no owner:
+// logged time outlives its note.
+func keyOwner(key string) (noteID, attID string, ok bool) {
+	switch {
+	case strings.HasPrefix(key, TagIndexPrefix):
+		// Tags may contain ':', so the note ID is found from the end
+		if len(key) < len(TagIndexPrefix)+idLen {
+			return "", "", false
+		}
+		return key[len(key)-idLen:], "", true
+	case strings.HasPrefix(key, AttachmentIndexPrefix):
+		noteID, attID, ok = strings.Cut(strings.TrimPrefix(key, AttachmentIndexPrefix), ":")
+		return noteID, attID, ok
+	case strings.HasPrefix(key, OrderPrefix):
+		return strings.TrimPrefix(key, OrderPrefix), "", true
+	case strings.HasPrefix(key, SharePrefix):
+		noteID, _, ok = strings.Cut(strings.TrimPrefix(key, SharePrefix), ":")
+		return noteID, "", ok
+	}
+	return "", "", false
+}
+
+// valueOwner returns the note an attachment or alias belongs to, read from
+// its value. Unreadable values report no owner, leaving the key for repair
+// to inspect.
+func valueOwner(k *kv.KV, key []byte) (string, bool) {
+	isAttachment := bytes.HasPrefix(key, []byte(AttachmentPrefix))
+	if !isAttachment && !bytes.HasPrefix(key, []byte(AliasPrefix)) {
+		return "", false
+	}
+	val, err := k.Get(key)
+	if err != nil {
+		return "", false
+	}
+	if !isAttachment {
+		return string(val), true
+	}
+	var ad AttachmentData
+	if err := json.Unmarshal(val, &ad); err != nil {
+		return "", false
+	}
+	return ad.NoteID, true
+}
+
+// blobGracePeriod is how long a blob file is kept before a sweep may
+// remove it, covering attachments whose blob is written before their key.
+const blobGracePeriod = time.Hour
+
+// blobRefs returns the set of blob hashes attachments refer to, and
+// whether any of them lives in Charm FS. Blobs cached from Charm FS count
+// as referenced while their attachment exists. An unreadable attachment
+// fails it, since its blob can't be told apart from garbage.
+func (c *Client) blobRefs() (map[string]bool, bool, error) {
+	refs := make(map[string]bool)
+	remote := false
+	err := c.DoReadOnly(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, []byte(AttachmentPrefix)) {
+				continue
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", key, err)
+			}
+			var ad AttachmentData
+			if err := json.Unmarshal(val, &ad); err != nil {
+				return fmt.Errorf("%s is unreadable: %w", key, err)
+			}
+			if ad.Blob != "" {
+				refs[ad.Blob] = true
+				remote = remote || ad.Remote
+			}
+		}
+		return nil
+	})
+	return refs, remote, err
+}
+
+// MaintenanceDue reports whether the configured interval has elapsed since the last run.
+func (c *Client) MaintenanceDue() bool {
+	if c.maintainInterval <= 0 {
+		return false
+	}
+	data, err := os.ReadFile(lastMaintenancePath())
+	if err != nil {
+		return true
+	}
+	last, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data)))
+	if err != nil {
+		return true
+	}
+	return time.Since(last) >= c.maintainInterval
+}
+
+// lastMaintenancePath returns the path of the last maintenance timestamp file.
+func lastMaintenancePath() string {
+	return filepath.Join(StateDir(), "last_maintenance")
+}
+
+// storeSize returns the combined size of the database and its WAL file.
+func storeSize(dbPath string) int64 {
+	var total int64
+	for _, p := range []string{dbPath, dbPath + "-wal"} {
+		if info, err := os.Stat(p); err == nil {
+			total += info.Size()
+		}
+	}
+	return total
+}
@@ -0,0 +1,129 @@
+// ABOUTME: Tests for database maintenance
+// ABOUTME: Validates that maintenance checkpoints and vacuums a database another connection holds open, finds the note orphaned keys belong to, keeps live shares, and sweeps only its own blobs and Charm FS files
+
+package charm
+
+import (
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMaintainDBWithLiveConnection(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "memo.db")
+	live, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = live.Close() }()
+	live.SetMaxOpenConns(1)
+	for _, stmt := range []string{
+		"PRAGMA journal_mode=WAL",
+		"CREATE TABLE kv (k TEXT PRIMARY KEY, v BLOB)",
+		"INSERT INTO kv VALUES ('a', randomblob(65536)), ('b', randomblob(65536))",
+		"DELETE FROM kv WHERE k = 'a'",
+	} {
+		if _, err := live.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	result := &MaintenanceResult{}
+	if err := maintainDB(dbPath, result); err != nil {
+		t.Fatalf("maintainDB: %v", err)
+	}
+	if !result.IntegrityOK || !result.Checkpointed || !result.Vacuumed || result.Warning != nil {
+		t.Errorf("result = %+v, want every step done", result)
+	}
+	if _, err := os.Stat(dbPath + "-shm"); err != nil {
+		t.Errorf("WAL index of the open database was removed: %v", err)
+	}
+
+	var n int
+	if err := live.QueryRow("SELECT count(*) FROM kv").Scan(&n); err != nil || n != 1 {
+		t.Errorf("live connection reads %d rows (%v) after maintenance, want 1", n, err)
+	}
+}
+
+func TestKeyOwner(t *testing.T) {
+	const note = "0b6f3a1e-0000-4000-8000-000000000000"
+	const att = "d3adbeef-1111-4111-8111-111111111111"
+	cases := []struct {
+		key         string
+		noteID, att string
+		ok          bool
+	}{
+		{TagIndexPrefix + "work:" + note, note, "", true},
+		{TagIndexPrefix + "dir:/home/me/src:" + note, note, "", true},
+		{AttachmentIndexPrefix + note + ":" + att, note, att, true},
+		{OrderPrefix + note, note, "", true},
+		{TimerPrefix + note + ":1700000000000000042", "", "", false}, // Kept for timesheets
+		{SharePrefix + note + ":a1b2c3d4e5f6", note, "", true},
+		{TagIndexPrefix + "short", "", "", false},
+		{NotePrefix + note, "", "", false},
+		{AliasPrefix + "roadmap", "", "", false},
+		{AuditPrefix + note + ":1700000000000000042:d3adbeef", "", "", false},
+	}
+	for _, tc := range cases {
+		noteID, attID, ok := keyOwner(tc.key)
+		if noteID != tc.noteID || attID != tc.att || ok != tc.ok {
+			t.Errorf("keyOwner(%q) = %q, %q, %v; want %q, %q, %v", tc.key, noteID, attID, ok, tc.noteID, tc.att, tc.ok)
+		}
+	}
+}
+
+func TestLiveShare(t *testing.T) {
+	live, _ := json.Marshal(&Share{URL: "https://0x0.st/abc.md", Token: "t"})
+	now := time.Now()
+	revoked, _ := json.Marshal(&Share{URL: "https://0x0.st/abc.md", RevokedAt: &now})
+	if !liveShare(live) {
+		t.Error("liveShare = false for an upload that is still up; compact would drop its deletion token")
+	}
+	if liveShare(revoked) || liveShare([]byte("{")) {
+		t.Error("liveShare = true for a revoked or unreadable record")
+	}
+}
+
+func TestBlobSweepKeepsOtherDatabases(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv(ConfigDirEnv, t.TempDir())
+
+	personal, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	work, err := NewClient(WithDBName("work"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if personal.blobs.Dir == work.blobs.Dir {
+		t.Fatalf("both databases store blobs in %s", work.blobs.Dir)
+	}
+
+	hash, err := personal.blobs.Put([]byte("attachment only the default database references"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// What Compact does for a store that references no blobs, past the grace period
+	if _, err := work.blobs.Sweep(nil, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("Sweep: %v", err)
+	}
+	if _, err := personal.blobs.Get(hash); err != nil {
+		t.Errorf("compacting another database removed a blob: %v", err)
+	}
+}
+
+func TestCharmFSDirPerDatabase(t *testing.T) {
+	personal := &Client{dbName: DBName}
+	work := &Client{dbName: "work"}
+	if personal.charmFSDir() == work.charmFSDir() {
+		t.Errorf("both databases keep Charm FS files in %s", work.charmFSDir())
+	}
+	if path.Dir(work.charmFSDir()) != charmFSRoot {
+		t.Errorf("charmFSDir() = %s, want a directory in %s", work.charmFSDir(), charmFSRoot)
+	}
+}
@@ -14,7 +14,9 @@ import (
 
 	"github.com/charmbracelet/charm/kv"
 	"github.com/google/uuid"
+	"github.com/harper/memo/internal/compress"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/webhooks"
 )
 
 const (
@@ -26,6 +28,7 @@ var (
 	ErrPrefixTooShort  = errors.New("prefix must be at least 6 characters")
 	ErrAmbiguousPrefix = errors.New("prefix matches multiple notes")
 	ErrNoteNotFound    = errors.New("note not found")
+	ErrInvalidStatus   = errors.New("status must be unread, reading, or done")
 )
 
 // NoteData represents a note stored in charm KV.
@@ -36,6 +39,18 @@ type NoteData struct {
 	Tags      []string `json:"tags,omitempty"`
 	CreatedAt int64    `json:"created_at"`
 	UpdatedAt int64    `json:"updated_at"`
+
+	Context  *ContextData `json:"context,omitempty"`
+	Starred  bool         `json:"starred,omitempty"`
+	Status   string       `json:"status,omitempty"`
+	QueuedAt int64        `json:"queued_at,omitempty"`
+}
+
+// ContextData is the stored form of a note's models.Context.
+type ContextData struct {
+	Host   string `json:"host,omitempty"`
+	Branch string `json:"branch,omitempty"`
+	Commit string `json:"commit,omitempty"`
 }
 
 // ToModel converts NoteData to a models.Note.
@@ -44,25 +59,68 @@ func (n *NoteData) ToModel() (*models.Note, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse note ID: %w", err)
 	}
-	return &models.Note{
+	note := &models.Note{
 		ID:        id,
 		Title:     n.Title,
 		Content:   n.Content,
 		CreatedAt: time.Unix(n.CreatedAt, 0),
 		UpdatedAt: time.Unix(n.UpdatedAt, 0),
-	}, nil
+		Starred:   n.Starred,
+		Status:    n.Status,
+	}
+	if n.QueuedAt != 0 {
+		note.QueuedAt = time.Unix(n.QueuedAt, 0)
+	}
+	if n.Context != nil {
+		note.Context = &models.Context{Host: n.Context.Host, Branch: n.Context.Branch, Commit: n.Context.Commit}
+	}
+	return note, nil
+}
+
+// UnmarshalJSON reads stored note data, expanding compressed content.
+func (n *NoteData) UnmarshalJSON(data []byte) error {
+	type plain NoteData // Without this method
+	if err := json.Unmarshal(data, (*plain)(n)); err != nil {
+		return err
+	}
+	content, err := compress.ExpandString(n.Content)
+	if err != nil {
+		return fmt.Errorf("note %s: %w", n.ID, err)
+	}
+	n.Content = content
+	return nil
+}
+
+// marshalNote encodes note data for storage, compressing its content when
+// compression is enabled.
+func (c *Client) marshalNote(nd *NoteData) ([]byte, error) {
+	if !c.compress {
+		return json.Marshal(nd)
+	}
+	stored := *nd
+	stored.Content = compress.String(nd.Content)
+	return json.Marshal(&stored)
 }
 
 // FromModel creates NoteData from a models.Note with tags.
 func FromModel(note *models.Note, tags []string) *NoteData {
-	return &NoteData{
+	data := &NoteData{
 		ID:        note.ID.String(),
 		Title:     note.Title,
 		Content:   note.Content,
 		Tags:      tags,
 		CreatedAt: note.CreatedAt.Unix(),
 		UpdatedAt: note.UpdatedAt.Unix(),
+		Starred:   note.Starred,
+		Status:    note.Status,
+	}
+	if !note.QueuedAt.IsZero() {
+		data.QueuedAt = note.QueuedAt.Unix()
+	}
+	if note.Context != nil {
+		data.Context = &ContextData{Host: note.Context.Host, Branch: note.Context.Branch, Commit: note.Context.Commit}
 	}
+	return data
 }
 
 // noteKey returns the key for a note.
@@ -72,12 +130,78 @@ func noteKey(id uuid.UUID) []byte {
 
 // CreateNote creates a new note.
 func (c *Client) CreateNote(note *models.Note, tags []string) error {
+	tags, err := models.NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
 	data := FromModel(note, tags)
-	encoded, err := json.Marshal(data)
+	encoded, err := c.marshalNote(data)
 	if err != nil {
 		return fmt.Errorf("marshal note: %w", err)
 	}
-	return c.Set(noteKey(note.ID), encoded)
+	err = c.Do(func(k *kv.KV) error {
+		if err := k.Set(noteKey(note.ID), encoded); err != nil {
+			return err
+		}
+		if err := setTagIndex(k, data.ID, tags); err != nil {
+			return err
+		}
+		c.audit(k, AuditCreate, "", data.ID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.notify(webhooks.EventNoteCreated, data)
+	return nil
+}
+
+// CreateNoteWithAttachments creates a note and its attachments in a single KV session.
+// Everything is encoded up front and the note is written last, so an interrupted
+// write never leaves a visible note with missing attachments.
+func (c *Client) CreateNoteWithAttachments(note *models.Note, tags []string, attachments []*models.Attachment) error {
+	tags, err := models.NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	data := FromModel(note, tags)
+	encodedNote, err := c.marshalNote(data)
+	if err != nil {
+		return fmt.Errorf("marshal note: %w", err)
+	}
+
+	encodedAtts := make([][]byte, len(attachments))
+	for i, att := range attachments {
+		ad, err := c.encodeAttachment(att)
+		if err != nil {
+			return err
+		}
+		encodedAtts[i], err = json.Marshal(ad)
+		if err != nil {
+			return fmt.Errorf("marshal attachment: %w", err)
+		}
+	}
+
+	err = c.Do(func(k *kv.KV) error {
+		for i, att := range attachments {
+			if err := k.Set(attachmentKey(att.ID), encodedAtts[i]); err != nil {
+				return fmt.Errorf("set attachment: %w", err)
+			}
+			if err := k.Set(attachmentIndexKey(data.ID, att.ID.String()), []byte(att.ID.String())); err != nil {
+				return fmt.Errorf("set attachment index: %w", err)
+			}
+		}
+		if err := setTagIndex(k, data.ID, tags); err != nil {
+			return fmt.Errorf("set tag index: %w", err)
+		}
+		c.audit(k, AuditCreate, "", data.ID)
+		return k.Set(noteKey(note.ID), encodedNote)
+	})
+	if err != nil {
+		return err
+	}
+	c.notify(webhooks.EventNoteCreated, data)
+	return nil
 }
 
 // GetNoteByID retrieves a note by its UUID.
@@ -102,8 +226,11 @@ func (c *Client) GetNoteByID(id uuid.UUID) (*models.Note, []string, error) {
 	return note, noteData.Tags, nil
 }
 
-// GetNoteByPrefix finds a note by ID prefix (minimum 6 chars).
+// GetNoteByPrefix finds a note by alias or ID prefix (minimum 6 chars).
 func (c *Client) GetNoteByPrefix(prefix string) (*models.Note, []string, error) {
+	if id, ok := c.resolveAlias(prefix); ok {
+		return c.GetNoteByID(id)
+	}
 	if len(prefix) < 6 {
 		return nil, nil, ErrPrefixTooShort
 	}
@@ -155,17 +282,65 @@ func (c *Client) GetNoteByPrefix(prefix string) (*models.Note, []string, error)
 
 // NoteFilter defines criteria for filtering notes.
 type NoteFilter struct {
-	Tag    *string // Filter by tag name
-	DirTag *string // Filter by dir: tag
-	Global bool    // Only notes without dir: tags
-	Limit  int     // Max results (0 = unlimited)
-	Search string  // FTS search term (simple contains for now)
+	Tag    *string   // Filter by tag name
+	DirTag *string   // Filter by dir: tag
+	Global bool      // Only notes without dir: tags
+	Limit  int       // Max results (0 = unlimited)
+	Offset int       // Skip this many matches first, for paging
+	Search string    // FTS search term (simple contains for now)
+	Since  time.Time // Only notes created at or after this time (zero = no bound)
+	Until  time.Time // Only notes created before this time (zero = no bound)
+
+	// Untagged keeps only notes with no tags other than dir: tags.
+	Untagged bool
+
+	// Branch keeps only notes whose recorded context is this git branch.
+	Branch string
+
+	// Starred keeps only starred notes.
+	Starred bool
+
+	// Status keeps only notes with this read-later queue status.
+	Status string
+
+	// ContentLimit caps each returned note's content at this many
+	// characters (0 = full content, NoContent = none). Search still
+	// matches against the full content.
+	ContentLimit int
+}
+
+// NoContent as a NoteFilter.ContentLimit lists notes without their content.
+const NoContent = -1
+
+// NoteWithTags pairs a note with its tags.
+type NoteWithTags struct {
+	Note *models.Note
+	Tags []string
+
+	// Tasks counts the note's checkboxes, taken from the full content even
+	// when the listing truncates it.
+	Tasks models.TaskCount
 }
 
 // ListNotes returns notes matching the filter, sorted by updated_at desc.
 func (c *Client) ListNotes(filter *NoteFilter) ([]*models.Note, error) {
+	listed, err := c.ListNotesWithTags(filter)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Note, len(listed))
+	for i, nt := range listed {
+		result[i] = nt.Note
+	}
+	return result, nil
+}
+
+// ListNotesWithTags returns notes matching the filter with their tags,
+// sorted by updated_at desc, in a single pass over the store.
+func (c *Client) ListNotesWithTags(filter *NoteFilter) ([]*NoteWithTags, error) {
 	prefix := []byte(NotePrefix)
 	var notes []*NoteData
+	tasks := make(map[string]models.TaskCount) // Keyed by note ID; only notes with tasks
 
 	err := c.DoReadOnly(func(k *kv.KV) error {
 		keys, err := k.Keys()
@@ -173,6 +348,13 @@ func (c *Client) ListNotes(filter *NoteFilter) ([]*models.Note, error) {
 			return err
 		}
 
+		// Tag and directory filters only need to read the indexed notes
+		if tag, ok := indexedTag(filter); ok {
+			if tagged, ok := noteKeysForTag(k, keys, tag); ok {
+				keys = tagged
+			}
+		}
+
 		for _, key := range keys {
 			if !bytes.HasPrefix(key, prefix) {
 				continue
@@ -193,6 +375,15 @@ func (c *Client) ListNotes(filter *NoteFilter) ([]*models.Note, error) {
 				continue
 			}
 
+			// Count tasks while the full content is at hand
+			if tc := models.CountTasks(nd.Content); tc.Total > 0 {
+				tasks[nd.ID] = tc
+			}
+
+			// Drop content the caller doesn't need before holding on to it
+			if filter != nil {
+				nd.Content = TruncateContent(nd.Content, filter.ContentLimit)
+			}
 			notes = append(notes, &nd)
 		}
 		return nil
@@ -206,25 +397,68 @@ func (c *Client) ListNotes(filter *NoteFilter) ([]*models.Note, error) {
 		return notes[i].UpdatedAt > notes[j].UpdatedAt
 	})
 
-	// Apply limit
-	limit := filter.Limit
-	if limit > 0 && len(notes) > limit {
-		notes = notes[:limit]
-	}
+	// Apply offset and limit
+	notes = paginate(notes, filter)
 
 	// Convert to models
-	result := make([]*models.Note, 0, len(notes))
+	result := make([]*NoteWithTags, 0, len(notes))
 	for _, nd := range notes {
 		note, err := nd.ToModel()
 		if err != nil {
 			continue // Skip invalid notes
 		}
-		result = append(result, note)
+		result = append(result, &NoteWithTags{Note: note, Tags: nd.Tags, Tasks: tasks[nd.ID]})
 	}
 
 	return result, nil
 }
 
+// Page applies the filter's offset and limit to matches sorted by updated_at desc.
+func (f *NoteFilter) Page(notes []*NoteData) []*NoteData {
+	return paginate(notes, f)
+}
+
+// Match reports whether the note data satisfies the filter criteria.
+func (f *NoteFilter) Match(nd *NoteData) bool {
+	return matchesFilter(nd, f)
+}
+
+// TruncateContent applies a NoteFilter.ContentLimit to content. A shortened
+// result is copied so it doesn't keep the full content in memory.
+func TruncateContent(content string, limit int) string {
+	switch {
+	case limit == 0:
+		return content
+	case limit < 0:
+		return ""
+	}
+	n := 0
+	for i := range content {
+		if n == limit {
+			return strings.Clone(content[:i])
+		}
+		n++
+	}
+	return content
+}
+
+// paginate drops the filter's offset and truncates to its limit.
+func paginate(notes []*NoteData, filter *NoteFilter) []*NoteData {
+	if filter == nil {
+		return notes
+	}
+	if filter.Offset > 0 {
+		if filter.Offset >= len(notes) {
+			return nil
+		}
+		notes = notes[filter.Offset:]
+	}
+	if filter.Limit > 0 && len(notes) > filter.Limit {
+		notes = notes[:filter.Limit]
+	}
+	return notes
+}
+
 // matchesFilter checks if a note matches the filter criteria.
 func matchesFilter(nd *NoteData, filter *NoteFilter) bool {
 	if filter == nil {
@@ -240,7 +474,7 @@ func matchesFilter(nd *NoteData, filter *NoteFilter) bool {
 
 	// Dir tag filter
 	if filter.DirTag != nil {
-		dirTag := strings.ToLower("dir:" + *filter.DirTag)
+		dirTag := "dir:" + *filter.DirTag
 		if !hasTag(nd.Tags, dirTag) {
 			return false
 		}
@@ -255,6 +489,37 @@ func matchesFilter(nd *NoteData, filter *NoteFilter) bool {
 		}
 	}
 
+	// Untagged filter (dir: tags record location, not a category)
+	if filter.Untagged {
+		for _, tag := range nd.Tags {
+			if !strings.HasPrefix(strings.ToLower(tag), "dir:") {
+				return false
+			}
+		}
+	}
+
+	if filter.Starred && !nd.Starred {
+		return false
+	}
+	if filter.Status != "" && nd.Status != filter.Status {
+		return false
+	}
+
+	// Git branch the note was written on
+	if filter.Branch != "" {
+		if nd.Context == nil || nd.Context.Branch != filter.Branch {
+			return false
+		}
+	}
+
+	// Created-at range filter
+	if !filter.Since.IsZero() && nd.CreatedAt < filter.Since.Unix() {
+		return false
+	}
+	if !filter.Until.IsZero() && nd.CreatedAt >= filter.Until.Unix() {
+		return false
+	}
+
 	// Search filter (simple contains for now)
 	if filter.Search != "" {
 		searchLower := strings.ToLower(filter.Search)
@@ -270,9 +535,9 @@ func matchesFilter(nd *NoteData, filter *NoteFilter) bool {
 
 // hasTag checks if a tag exists in the list (case-insensitive).
 func hasTag(tags []string, name string) bool {
-	nameLower := strings.ToLower(name)
+	normalized := models.NormalizeTag(name)
 	for _, t := range tags {
-		if strings.ToLower(t) == nameLower {
+		if models.NormalizeTag(t) == normalized {
 			return true
 		}
 	}
@@ -281,34 +546,264 @@ func hasTag(tags []string, name string) bool {
 
 // UpdateNote updates an existing note.
 func (c *Client) UpdateNote(note *models.Note, tags []string) error {
-	// Check if note exists first
-	_, _, err := c.GetNoteByID(note.ID)
+	return c.UpdateNotes([]*NoteWithTags{{Note: note, Tags: tags}})
+}
+
+// UpdateNotes updates existing notes in a single KV session, so a batch is
+// synced once. If any note is missing nothing is written.
+func (c *Client) UpdateNotes(notes []*NoteWithTags) error {
+	data := make([]*NoteData, len(notes))
+	encoded := make([][]byte, len(notes))
+	for i, nt := range notes {
+		tags, err := models.NormalizeTags(nt.Tags)
+		if err != nil {
+			return err
+		}
+		data[i] = FromModel(nt.Note, tags)
+		if encoded[i], err = c.marshalNote(data[i]); err != nil {
+			return fmt.Errorf("marshal note: %w", err)
+		}
+	}
+
+	err := c.Do(func(k *kv.KV) error {
+		oldTags := make([][]string, len(notes))
+		for i, nt := range notes {
+			val, err := k.Get(noteKey(nt.Note.ID))
+			if err != nil {
+				if errors.Is(err, kv.ErrMissingKey) {
+					return fmt.Errorf("%w: %s", ErrNoteNotFound, nt.Note.ID)
+				}
+				return err
+			}
+			var old NoteData
+			_ = json.Unmarshal(val, &old) // Tag index cleanup is best-effort for corrupt notes
+			oldTags[i] = old.Tags
+
+			if KeepManaged(data[i], &old) {
+				if encoded[i], err = c.marshalNote(data[i]); err != nil {
+					return fmt.Errorf("marshal note: %w", err)
+				}
+			}
+		}
+
+		for i, nt := range notes {
+			if err := deleteTagIndex(k, data[i].ID, oldTags[i]); err != nil {
+				return err
+			}
+			if err := k.Set(noteKey(nt.Note.ID), encoded[i]); err != nil {
+				return err
+			}
+			if err := setTagIndex(k, data[i].ID, data[i].Tags); err != nil {
+				return err
+			}
+			c.audit(k, AuditUpdate, "", data[i].ID)
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	for _, d := range data {
+		c.notify(webhooks.EventNoteUpdated, d)
+	}
+	return nil
+}
 
-	data := FromModel(note, tags)
-	encoded, err := json.Marshal(data)
+// KeepManaged copies the fields an update doesn't own from the stored note
+// old into nd, and reports whether nd changed. Context is recorded at
+// creation, and stars and queue status change only through SetStarred and
+// SetStatus, so callers that don't carry them (imports, the public API)
+// can't erase them.
+func KeepManaged(nd, old *NoteData) bool {
+	changed := false
+	if nd.Context == nil && old.Context != nil {
+		nd.Context = old.Context
+		changed = true
+	}
+	if nd.Starred != old.Starred || nd.Status != old.Status || nd.QueuedAt != old.QueuedAt {
+		nd.Starred, nd.Status, nd.QueuedAt = old.Starred, old.Status, old.QueuedAt
+		changed = true
+	}
+	return changed
+}
+
+// SetStarred stars or unstars a note. It leaves UpdatedAt alone, since
+// starring doesn't change the note itself.
+func (c *Client) SetStarred(id uuid.UUID, starred bool) error {
+	action := AuditStar
+	if !starred {
+		action = AuditUnstar
+	}
+	return c.patchNote(id, action, "", func(nd *NoteData) bool {
+		if nd.Starred == starred {
+			return false
+		}
+		nd.Starred = starred
+		return true
+	})
+}
+
+// SetStatus moves a note in the read-later queue: models.StatusUnread,
+// StatusReading, or StatusDone, or "" to take it out. A note entering the
+// queue (from no status or done) joins the back of it. UpdatedAt is left
+// alone.
+func (c *Client) SetStatus(id uuid.UUID, status string) error {
+	if !models.ValidStatus(status) {
+		return fmt.Errorf("%w: %q", ErrInvalidStatus, status)
+	}
+	now := time.Now().Unix()
+	detail := status
+	if detail == "" {
+		detail = "none"
+	}
+	return c.patchNote(id, AuditStatus, detail, func(nd *NoteData) bool {
+		if nd.Status == status {
+			return false
+		}
+		switch {
+		case status == "":
+			nd.QueuedAt = 0
+		case nd.Status == "" || nd.Status == models.StatusDone:
+			if status != models.StatusDone {
+				nd.QueuedAt = now
+			}
+		}
+		nd.Status = status
+		return true
+	})
+}
+
+// patchNote rewrites a stored note in place when change reports a change,
+// without touching UpdatedAt, and records action in the audit log.
+func (c *Client) patchNote(id uuid.UUID, action, detail string, change func(*NoteData) bool) error {
+	var nd NoteData
+	changed := false
+	err := c.Do(func(k *kv.KV) error {
+		val, err := k.Get(noteKey(id))
+		if err != nil {
+			if errors.Is(err, kv.ErrMissingKey) {
+				return fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+			}
+			return err
+		}
+		if err := json.Unmarshal(val, &nd); err != nil {
+			return fmt.Errorf("unmarshal note: %w", err)
+		}
+		if changed = change(&nd); !changed {
+			return nil
+		}
+		encoded, err := c.marshalNote(&nd)
+		if err != nil {
+			return fmt.Errorf("marshal note: %w", err)
+		}
+		if err := k.Set(noteKey(id), encoded); err != nil {
+			return err
+		}
+		c.audit(k, action, detail, nd.ID)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("marshal note: %w", err)
+		return err
 	}
-	return c.Set(noteKey(note.ID), encoded)
+	if changed {
+		c.notify(webhooks.EventNoteUpdated, &nd)
+	}
+	return nil
 }
 
-// DeleteNote deletes a note and its attachments.
+// DeleteNote deletes a note and its attachments in a single KV session.
 func (c *Client) DeleteNote(id uuid.UUID) error {
-	// Delete attachments first (cascade)
-	if err := c.deleteAttachmentsByNote(id); err != nil {
-		return fmt.Errorf("delete attachments: %w", err)
-	}
+	return c.DeleteNotes([]uuid.UUID{id})
+}
 
-	// Delete the note
-	if err := c.Delete(noteKey(id)); err != nil {
-		if errors.Is(err, kv.ErrMissingKey) {
-			return ErrNoteNotFound
+// DeleteNotes deletes notes and their attachments in a single KV session,
+// so a batch is synced once. If any note is missing nothing is deleted.
+func (c *Client) DeleteNotes(ids []uuid.UUID) error {
+	attPrefix := []byte(AttachmentPrefix)
+	noteIDs := make(map[string]bool, len(ids))
+	var remote []string
+
+	err := c.Do(func(k *kv.KV) error {
+		notes := make([]NoteData, len(ids))
+		for i, id := range ids {
+			val, err := k.Get(noteKey(id))
+			if err != nil {
+				if errors.Is(err, kv.ErrMissingKey) {
+					return fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+				}
+				return err
+			}
+			_ = json.Unmarshal(val, &notes[i]) // Tag index cleanup is best-effort for corrupt notes
+			noteIDs[id.String()] = true
+		}
+
+		for i, id := range ids {
+			if err := deleteTagIndex(k, id.String(), notes[i].Tags); err != nil {
+				return fmt.Errorf("delete tag index: %w", err)
+			}
+		}
+
+		keys, err := k.Keys()
+		if err != nil {
+			return err
 		}
+
+		// Delete attachments first (cascade). With indexes built, only the
+		// deleted notes' attachments are read
+		attKeys := keys
+		if indexed(k) {
+			attKeys = nil
+			for _, id := range ids {
+				for _, attID := range indexedIDs(keys, AttachmentIndexPrefix+id.String()+":") {
+					attKeys = append(attKeys, []byte(AttachmentPrefix+attID))
+				}
+			}
+		}
+		for _, key := range attKeys {
+			if !bytes.HasPrefix(key, attPrefix) {
+				continue
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			var ad AttachmentData
+			if err := json.Unmarshal(val, &ad); err != nil || !noteIDs[ad.NoteID] {
+				continue
+			}
+			if err := k.Delete(key); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+				return fmt.Errorf("delete attachments: %w", err)
+			}
+			if err := k.Delete(attachmentIndexKey(ad.NoteID, ad.ID)); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+				return fmt.Errorf("delete attachment index: %w", err)
+			}
+			if ad.Remote {
+				remote = append(remote, ad.Blob)
+			}
+		}
+
+		if err := deleteAliasesFor(k, keys, noteIDs); err != nil {
+			return fmt.Errorf("delete aliases: %w", err)
+		}
+
+		for i, id := range ids {
+			if err := k.Delete(orderKey(id)); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+				return fmt.Errorf("delete sort rank: %w", err)
+			}
+			if err := k.Delete(noteKey(id)); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+				return err
+			}
+			c.audit(k, AuditDelete, notes[i].Title, id.String())
+		}
+		return nil
+	})
+	if err != nil {
 		return err
 	}
+	c.releaseCharmFS(remote)
+	for _, id := range ids {
+		c.notify(webhooks.EventNoteDeleted, map[string]string{"id": id.String()})
+	}
 	return nil
 }
 
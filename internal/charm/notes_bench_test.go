@@ -0,0 +1,172 @@
+// ABOUTME: Benchmarks for note listing, search, and sync against a real Client on a temporary KV
+// ABOUTME: Run with `make bench` to catch index, filter, sort, and sync regressions at 10k/100k notes
+
+package charm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/charm/kv"
+
+	"github.com/harper/memo/internal/models"
+)
+
+var benchSizes = []int{10_000, 100_000}
+
+// syncBenchSize keeps the sync benchmarks from pushing 100k notes to the
+// server on every iteration.
+const syncBenchSize = 10_000
+
+// seededClient returns a client on a fresh KV holding n notes spread over a
+// handful of tags and directories, with varied update times so sorting does
+// real work. Auto-sync and stale syncs are off so only local reads and
+// writes are measured; the indexes are built.
+func seededClient(b *testing.B, n int) *Client {
+	b.Helper()
+	b.Setenv("CHARM_DATA_DIR", b.TempDir())
+	b.Setenv("XDG_DATA_HOME", b.TempDir())
+	b.Setenv(ConfigDirEnv, b.TempDir())
+
+	c, err := NewClient(WithDBName("memo-bench"), WithAutoSync(false))
+	if err != nil {
+		b.Fatal(err)
+	}
+	c.staleThreshold = 0
+	c.maintainInterval = 0
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// One session for the whole seed: a Do per note would time the opens
+	err = c.Do(func(k *kv.KV) error {
+		for i := 0; i < n; i++ {
+			note := models.NewNote(
+				fmt.Sprintf("Note %d", i),
+				fmt.Sprintf("Body of note %d about topic %d with some filler text to search through.", i, i%97),
+			)
+			note.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+			note.UpdatedAt = base.Add(time.Duration((i*7919)%n) * time.Minute)
+			tags := []string{fmt.Sprintf("tag%d", i%20)}
+			if i%3 == 0 {
+				tags = append(tags, fmt.Sprintf("dir:/projects/p%d", i%10))
+			}
+			encoded, err := c.marshalNote(FromModel(note, tags))
+			if err != nil {
+				return err
+			}
+			if err := k.Set(noteKey(note.ID), encoded); err != nil {
+				return err
+			}
+			if err := setTagIndex(k, note.ID.String(), tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatalf("seed %d notes: %v", n, err)
+	}
+	if _, err := c.Reindex(); err != nil {
+		b.Fatalf("reindex: %v", err)
+	}
+	return c
+}
+
+// setIndexed sets or clears the index marker, switching reads between the
+// index keys and full scans.
+func setIndexed(b *testing.B, c *Client, on bool) {
+	b.Helper()
+	err := c.Do(func(k *kv.KV) error {
+		if on {
+			return k.Set([]byte(indexMarkerKey), []byte("1"))
+		}
+		return k.Delete([]byte(indexMarkerKey))
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+// benchmarkList times filter on seeded clients, once answered from the
+// indexes and once from a full scan as for data synced from older versions.
+func benchmarkList(b *testing.B, filter func() *NoteFilter) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("notes=%d", n), func(b *testing.B) {
+			c := seededClient(b, n)
+			for _, on := range []bool{true, false} {
+				name := "indexed"
+				if !on {
+					name = "unindexed"
+				}
+				b.Run(name, func(b *testing.B) {
+					setIndexed(b, c, on)
+					b.ReportAllocs()
+					b.ResetTimer()
+					for i := 0; i < b.N; i++ {
+						if _, err := c.ListNotesWithTags(filter()); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			}
+		})
+	}
+}
+
+func BenchmarkListNotes(b *testing.B) {
+	benchmarkList(b, func() *NoteFilter { return &NoteFilter{Limit: 20} })
+}
+
+func BenchmarkListNotesByTag(b *testing.B) {
+	tag := "tag7"
+	benchmarkList(b, func() *NoteFilter { return &NoteFilter{Tag: &tag, Limit: 20} })
+}
+
+func BenchmarkListNotesByDir(b *testing.B) {
+	dir := "/projects/p3"
+	benchmarkList(b, func() *NoteFilter { return &NoteFilter{DirTag: &dir, Limit: 20} })
+}
+
+func BenchmarkListGlobalNotesPage(b *testing.B) {
+	benchmarkList(b, func() *NoteFilter { return &NoteFilter{Global: true, Offset: 100, Limit: 20} })
+}
+
+func BenchmarkSearch(b *testing.B) {
+	benchmarkList(b, func() *NoteFilter { return &NoteFilter{Search: "topic 42 ", Limit: 20} })
+}
+
+// BenchmarkSyncAfterWrite times what auto-sync does after each write: one
+// changed note pushed and the server's changes pulled.
+func BenchmarkSyncAfterWrite(b *testing.B) {
+	c := seededClient(b, syncBenchSize)
+	if err := c.Sync(); err != nil {
+		b.Fatalf("initial sync: %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := c.CreateNote(models.NewNote(fmt.Sprintf("Synced %d", i), "body"), []string{"sync"}); err != nil {
+			b.Fatal(err)
+		}
+		if err := c.Sync(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSyncApply times a device applying the server's copy of a
+// database it doesn't have yet: the local database is dropped and rebuilt
+// from sync.
+func BenchmarkSyncApply(b *testing.B) {
+	c := seededClient(b, syncBenchSize)
+	if err := c.Sync(); err != nil {
+		b.Fatalf("initial sync: %v", err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := kv.Do(c.dbName, func(k *kv.KV) error { return k.Reset() }); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
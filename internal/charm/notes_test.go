@@ -0,0 +1,204 @@
+// ABOUTME: Tests for note filtering
+// ABOUTME: Validates tag, dir, untagged, branch, starred, status, and created-at range matching, paging, content limits, and compressed storage
+
+package charm
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harper/memo/internal/models"
+)
+
+func TestNoteFilterDateRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	nd := &NoteData{Title: "n", CreatedAt: day(10).Add(12 * time.Hour).Unix()}
+
+	cases := []struct {
+		name   string
+		filter NoteFilter
+		want   bool
+	}{
+		{"no bounds", NoteFilter{}, true},
+		{"since before", NoteFilter{Since: day(10)}, true},
+		{"since after", NoteFilter{Since: day(11)}, false},
+		{"until after", NoteFilter{Until: day(11)}, true},
+		{"until before", NoteFilter{Until: day(10)}, false},
+		{"within range", NoteFilter{Since: day(1), Until: day(31)}, true},
+	}
+	for _, tc := range cases {
+		if got := tc.filter.Match(nd); got != tc.want {
+			t.Errorf("%s: Match = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestNoteFilterTagAndDir(t *testing.T) {
+	nd := &NoteData{Tags: []string{"work", "dir:/home/me/proj"}}
+	tag, dir, other := "Work", "/home/me/proj", "/tmp"
+
+	if !(&NoteFilter{Tag: &tag, DirTag: &dir}).Match(nd) {
+		t.Error("expected tag and dir filter to match")
+	}
+	if (&NoteFilter{DirTag: &other}).Match(nd) {
+		t.Error("expected other dir not to match")
+	}
+}
+
+func TestNoteFilterUntagged(t *testing.T) {
+	filter := &NoteFilter{Untagged: true}
+
+	if !filter.Match(&NoteData{}) {
+		t.Error("expected note without tags to match")
+	}
+	if !filter.Match(&NoteData{Tags: []string{"dir:/home/me/proj"}}) {
+		t.Error("expected note with only a dir tag to match")
+	}
+	if filter.Match(&NoteData{Tags: []string{"dir:/home/me/proj", "work"}}) {
+		t.Error("expected tagged note not to match")
+	}
+}
+
+func TestNoteFilterBranch(t *testing.T) {
+	filter := &NoteFilter{Branch: "feature/sync"}
+
+	if filter.Match(&NoteData{}) {
+		t.Error("expected note without context not to match")
+	}
+	if filter.Match(&NoteData{Context: &ContextData{Branch: "main"}}) {
+		t.Error("expected note on another branch not to match")
+	}
+	if !filter.Match(&NoteData{Context: &ContextData{Host: "laptop", Branch: "feature/sync"}}) {
+		t.Error("expected note on the branch to match")
+	}
+}
+
+func TestNoteFilterStarred(t *testing.T) {
+	filter := &NoteFilter{Starred: true}
+	if filter.Match(&NoteData{}) {
+		t.Error("expected unstarred note not to match")
+	}
+	if !filter.Match(&NoteData{Starred: true}) {
+		t.Error("expected starred note to match")
+	}
+}
+
+func TestKeepManaged(t *testing.T) {
+	old := &NoteData{Context: &ContextData{Branch: "main"}, Starred: true, Status: "unread", QueuedAt: 100}
+
+	nd := &NoteData{Title: "edited"}
+	if !KeepManaged(nd, old) {
+		t.Fatal("expected fields to be copied")
+	}
+	if nd.Context != old.Context || !nd.Starred || nd.Status != "unread" || nd.QueuedAt != 100 || nd.Title != "edited" {
+		t.Errorf("unexpected note %+v", nd)
+	}
+	if KeepManaged(nd, old) {
+		t.Error("expected no change the second time")
+	}
+
+	// An update can't set managed fields
+	nd = &NoteData{Starred: true, Status: "done"}
+	KeepManaged(nd, &NoteData{})
+	if nd.Starred || nd.Status != "" {
+		t.Errorf("update changed managed fields: %+v", nd)
+	}
+}
+
+func TestNoteFilterStatus(t *testing.T) {
+	filter := &NoteFilter{Status: "unread"}
+	if filter.Match(&NoteData{}) || filter.Match(&NoteData{Status: "done"}) {
+		t.Error("expected only unread notes to match")
+	}
+	if !filter.Match(&NoteData{Status: "unread"}) {
+		t.Error("expected unread note to match")
+	}
+}
+
+func TestNoteDataContextRoundTrip(t *testing.T) {
+	note := models.NewNote("Sync bug", "race in pull")
+	note.Context = &models.Context{Host: "laptop", Branch: "feature/sync", Commit: "3f2a9c1"}
+
+	got, err := FromModel(note, nil).ToModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Context == nil || *got.Context != *note.Context {
+		t.Errorf("context = %+v, want %+v", got.Context, note.Context)
+	}
+
+	note.Context = nil
+	if FromModel(note, nil).Context != nil {
+		t.Error("expected no stored context for a note without one")
+	}
+}
+
+func TestNoteFilterPage(t *testing.T) {
+	notes := []*NoteData{{Title: "a"}, {Title: "b"}, {Title: "c"}}
+
+	cases := []struct {
+		name   string
+		filter *NoteFilter
+		want   string
+	}{
+		{"nil filter", nil, "abc"},
+		{"limit", &NoteFilter{Limit: 2}, "ab"},
+		{"offset", &NoteFilter{Offset: 1}, "bc"},
+		{"offset and limit", &NoteFilter{Offset: 1, Limit: 1}, "b"},
+		{"offset past end", &NoteFilter{Offset: 3}, ""},
+	}
+	for _, tc := range cases {
+		got := ""
+		for _, nd := range tc.filter.Page(notes) {
+			got += nd.Title
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTruncateContent(t *testing.T) {
+	cases := []struct {
+		limit int
+		want  string
+	}{
+		{0, "héllo world"},
+		{NoContent, ""},
+		{5, "héllo"},
+		{50, "héllo world"},
+	}
+	for _, tc := range cases {
+		if got := TruncateContent("héllo world", tc.limit); got != tc.want {
+			t.Errorf("TruncateContent(%d) = %q, want %q", tc.limit, got, tc.want)
+		}
+	}
+}
+
+func TestMarshalNoteCompression(t *testing.T) {
+	nd := &NoteData{ID: "n1", Title: "Long", Content: strings.Repeat("the same paragraph again\n", 500)}
+
+	plain, err := (&Client{}).marshalNote(nd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	packed, err := (&Client{compress: true}).marshalNote(nd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packed) >= len(plain) {
+		t.Errorf("compressed note is %d bytes, plain %d", len(packed), len(plain))
+	}
+
+	for name, data := range map[string][]byte{"plain": plain, "compressed": packed} {
+		var got NoteData
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got.Content != nd.Content || got.Title != nd.Title {
+			t.Errorf("%s: round trip changed the note", name)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// ABOUTME: Manual sort order for notes
+// ABOUTME: Stored as order:<note-id> keys holding a fractional rank, so reordering usually writes one key
+
+package charm
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+
+	"github.com/charmbracelet/charm/kv"
+	"github.com/google/uuid"
+)
+
+// OrderPrefix is the key prefix for manual sort ranks.
+const OrderPrefix = "order:"
+
+func orderKey(id uuid.UUID) []byte {
+	return []byte(OrderPrefix + id.String())
+}
+
+// NoteRanks returns the manual sort rank of every ranked note.
+func (c *Client) NoteRanks() (map[uuid.UUID]float64, error) {
+	ranks := make(map[uuid.UUID]float64)
+	err := c.DoReadOnly(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, []byte(OrderPrefix)) {
+				continue
+			}
+			id, err := uuid.Parse(string(key[len(OrderPrefix):]))
+			if err != nil {
+				continue // Skip invalid keys
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			rank, err := strconv.ParseFloat(string(val), 64)
+			if err != nil {
+				continue // Skip invalid data
+			}
+			ranks[id] = rank
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ranks, nil
+}
+
+// SetNoteRanks writes manual sort ranks in a single KV session. Every note
+// must exist.
+func (c *Client) SetNoteRanks(ranks map[uuid.UUID]float64) error {
+	return c.Do(func(k *kv.KV) error {
+		for id := range ranks {
+			if _, err := k.Get(noteKey(id)); err != nil {
+				if errors.Is(err, kv.ErrMissingKey) {
+					return ErrNoteNotFound
+				}
+				return err
+			}
+		}
+		for id, rank := range ranks {
+			if err := k.Set(orderKey(id), []byte(strconv.FormatFloat(rank, 'g', -1, 64))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ClearNoteRank removes a note from the manual order. Clearing an unranked
+// note is a no-op.
+func (c *Client) ClearNoteRank(id uuid.UUID) error {
+	return c.Do(func(k *kv.KV) error {
+		if err := k.Delete(orderKey(id)); err != nil && !errors.Is(err, kv.ErrMissingKey) {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,40 @@
+// ABOUTME: Repository interfaces over the Charm KV client
+// ABOUTME: Lets the MCP server and commands run against a mock store in tests
+
+package charm
+
+import (
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/models"
+)
+
+// NotesRepository is the note and tag storage surface used by memo.
+type NotesRepository interface {
+	CreateNote(note *models.Note, tags []string) error
+	GetNoteByID(id uuid.UUID) (*models.Note, []string, error)
+	GetNoteByPrefix(prefix string) (*models.Note, []string, error)
+	ListNotes(filter *NoteFilter) ([]*models.Note, error)
+	ListNotesWithTags(filter *NoteFilter) ([]*NoteWithTags, error)
+	UpdateNote(note *models.Note, tags []string) error
+	DeleteNote(id uuid.UUID) error
+	GetNoteTags(id uuid.UUID) ([]string, error)
+	AddTagToNote(noteID uuid.UUID, tagName string) error
+	RemoveTagFromNote(noteID uuid.UUID, tagName string) error
+}
+
+// AttachmentsRepository is the attachment storage surface used by memo.
+type AttachmentsRepository interface {
+	CreateAttachment(att *models.Attachment) error
+	GetAttachmentByID(id uuid.UUID) (*models.Attachment, error)
+	GetAttachmentByPrefix(prefix string) (*models.Attachment, error)
+	ListAttachmentsByNote(noteID uuid.UUID) ([]*models.Attachment, error)
+	DeleteAttachment(id uuid.UUID) error
+}
+
+// Repository combines note and attachment storage.
+type Repository interface {
+	NotesRepository
+	AttachmentsRepository
+}
+
+var _ Repository = (*Client)(nil)
@@ -0,0 +1,100 @@
+// ABOUTME: Records of notes published to paste services
+// ABOUTME: Stored as share:<note-id>:<hash> keys so revocation works from any synced machine
+
+package charm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/charm/kv"
+	"github.com/google/uuid"
+)
+
+// SharePrefix is the key prefix for share records.
+const SharePrefix = "share:"
+
+// Share is a copy of a note uploaded with `memo share`.
+type Share struct {
+	NoteID    uuid.UUID  `json:"note_id"`
+	Service   string     `json:"service"`
+	ID        string     `json:"id"`
+	URL       string     `json:"url"`
+	Token     string     `json:"token,omitempty"` // Deletion token, for services that issue one
+	Format    string     `json:"format"`          // "markdown" or "html"
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Revoked reports whether the upload has been taken down.
+func (s *Share) Revoked() bool {
+	return s.RevokedAt != nil
+}
+
+func shareKey(s *Share) []byte {
+	sum := sha256.Sum256([]byte(s.Service + "\n" + s.URL))
+	return []byte(SharePrefix + s.NoteID.String() + ":" + hex.EncodeToString(sum[:6]))
+}
+
+// SaveShare creates or updates a share record.
+func (c *Client) SaveShare(s *Share) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return c.Do(func(k *kv.KV) error {
+		return k.Set(shareKey(s), data)
+	})
+}
+
+// ListShares returns a note's share records, oldest first.
+func (c *Client) ListShares(noteID uuid.UUID) ([]*Share, error) {
+	return c.listShares([]byte(SharePrefix + noteID.String() + ":"))
+}
+
+// ListAllShares returns every share record, oldest first, including those
+// of deleted notes: their uploads stay up until revoked.
+func (c *Client) ListAllShares() ([]*Share, error) {
+	return c.listShares([]byte(SharePrefix))
+}
+
+// liveShare reports whether a stored share record is still up. Records
+// that can't be read can't be revoked either, so they don't count.
+func liveShare(val []byte) bool {
+	var s Share
+	return json.Unmarshal(val, &s) == nil && !s.Revoked()
+}
+
+func (c *Client) listShares(prefix []byte) ([]*Share, error) {
+	var shares []*Share
+	err := c.DoReadOnly(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, prefix) {
+				continue
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			var s Share
+			if err := json.Unmarshal(val, &s); err != nil {
+				continue // Skip invalid data
+			}
+			shares = append(shares, &s)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(shares, func(i, j int) bool { return shares[i].CreatedAt.Before(shares[j].CreatedAt) })
+	return shares, nil
+}
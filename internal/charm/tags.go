@@ -6,12 +6,15 @@ package charm
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"sort"
-	"strings"
 
 	"github.com/charmbracelet/charm/kv"
 	"github.com/google/uuid"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/webhooks"
 )
 
 // TagWithCount represents a tag with its usage count.
@@ -47,7 +50,7 @@ func (c *Client) ListAllTags() ([]*TagWithCount, error) {
 			}
 
 			for _, tag := range nd.Tags {
-				tagCounts[strings.ToLower(tag)]++
+				tagCounts[models.NormalizeTag(tag)]++
 			}
 		}
 		return nil
@@ -81,11 +84,14 @@ func (c *Client) AddTagToNote(noteID uuid.UUID, tagName string) error {
 	}
 
 	// Normalize tag name
-	normalizedTag := strings.ToLower(strings.TrimSpace(tagName))
+	normalizedTag := models.NormalizeTag(tagName)
+	if err := models.ValidateTag(normalizedTag); err != nil {
+		return err
+	}
 
 	// Check if already has tag
 	for _, t := range tags {
-		if strings.ToLower(t) == normalizedTag {
+		if models.NormalizeTag(t) == normalizedTag {
 			return nil // Already has tag
 		}
 	}
@@ -95,6 +101,60 @@ func (c *Client) AddTagToNote(noteID uuid.UUID, tagName string) error {
 	return c.UpdateNote(note, tags)
 }
 
+// AddTagToNotes adds a tag to several notes in a single KV session, so a
+// batch is synced once. It returns the number of notes that did not
+// already have the tag. If any note is missing nothing is changed.
+func (c *Client) AddTagToNotes(noteIDs []uuid.UUID, tagName string) (int, error) {
+	normalizedTag := models.NormalizeTag(tagName)
+	if err := models.ValidateTag(normalizedTag); err != nil {
+		return 0, err
+	}
+	var changed []*NoteData
+
+	err := c.Do(func(k *kv.KV) error {
+		for _, id := range noteIDs {
+			val, err := k.Get(noteKey(id))
+			if err != nil {
+				if errors.Is(err, kv.ErrMissingKey) {
+					return fmt.Errorf("%w: %s", ErrNoteNotFound, id)
+				}
+				return err
+			}
+			var nd NoteData
+			if err := json.Unmarshal(val, &nd); err != nil {
+				return fmt.Errorf("unmarshal note %s: %w", id, err)
+			}
+			if slices.ContainsFunc(nd.Tags, func(t string) bool { return models.NormalizeTag(t) == normalizedTag }) {
+				continue // Already has tag
+			}
+			nd.Tags = append(nd.Tags, normalizedTag)
+			changed = append(changed, &nd)
+		}
+
+		for _, nd := range changed {
+			encoded, err := c.marshalNote(nd)
+			if err != nil {
+				return fmt.Errorf("marshal note: %w", err)
+			}
+			if err := k.Set([]byte(NotePrefix+nd.ID), encoded); err != nil {
+				return err
+			}
+			if err := setTagIndex(k, nd.ID, []string{normalizedTag}); err != nil {
+				return err
+			}
+			c.audit(k, AuditTag, "+"+normalizedTag, nd.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, nd := range changed {
+		c.notify(webhooks.EventNoteUpdated, nd)
+	}
+	return len(changed), nil
+}
+
 // RemoveTagFromNote removes a tag from a note.
 func (c *Client) RemoveTagFromNote(noteID uuid.UUID, tagName string) error {
 	note, tags, err := c.GetNoteByID(noteID)
@@ -103,15 +163,177 @@ func (c *Client) RemoveTagFromNote(noteID uuid.UUID, tagName string) error {
 	}
 
 	// Normalize tag name
-	normalizedTag := strings.ToLower(strings.TrimSpace(tagName))
+	normalizedTag := models.NormalizeTag(tagName)
 
 	// Remove tag
 	newTags := make([]string, 0, len(tags))
 	for _, t := range tags {
-		if strings.ToLower(t) != normalizedTag {
+		if models.NormalizeTag(t) != normalizedTag {
 			newTags = append(newTags, t)
 		}
 	}
 
 	return c.UpdateNote(note, newTags)
 }
+
+// NormalizeStoredTags rewrites every note whose tags are not in normalized
+// form, merging tags that normalize to the same name (Work, work, #work),
+// and returns the number of notes changed. Notes written before tags were
+// normalized, or by older memo versions on other devices, need this once;
+// the tag indexes are rebuilt afterwards.
+func (c *Client) NormalizeStoredTags() (int, error) {
+	prefix := []byte(NotePrefix)
+	var changed []*NoteData
+
+	err := c.Do(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, prefix) {
+				continue
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			var nd NoteData
+			if err := json.Unmarshal(val, &nd); err != nil {
+				continue // Skip invalid data
+			}
+
+			tags := make([]string, 0, len(nd.Tags))
+			for _, t := range nd.Tags {
+				if t = models.NormalizeTag(t); t != "" && !slices.Contains(tags, t) {
+					tags = append(tags, t)
+				}
+			}
+			if slices.Equal(tags, nd.Tags) {
+				continue
+			}
+
+			nd.Tags = tags
+			encoded, err := c.marshalNote(&nd)
+			if err != nil {
+				return fmt.Errorf("marshal note: %w", err)
+			}
+			if err := k.Set(key, encoded); err != nil {
+				return err
+			}
+			c.audit(k, AuditTag, "normalize", nd.ID)
+			changed = append(changed, &nd)
+		}
+		return nil
+	})
+	if err != nil || len(changed) == 0 {
+		return 0, err
+	}
+	for _, nd := range changed {
+		c.notify(webhooks.EventNoteUpdated, nd)
+	}
+
+	// Index keys written under the old tag forms can't be found by name;
+	// rebuild rather than guess them
+	if _, err := c.Reindex(); err != nil {
+		return len(changed), fmt.Errorf("reindex: %w", err)
+	}
+	return len(changed), nil
+}
+
+// RenameTag replaces a tag (matched case-insensitively) with another on
+// every note in a single KV session, and returns the number of notes
+// changed. Renaming onto a tag a note already has merges the two.
+func (c *Client) RenameTag(from, to string) (int, error) {
+	to = models.NormalizeTag(to)
+	if err := models.ValidateTag(to); err != nil {
+		return 0, err
+	}
+	return c.rewriteTag(from, to)
+}
+
+// DeleteTag removes a tag (matched case-insensitively) from every note in
+// a single KV session, and returns the number of notes changed.
+func (c *Client) DeleteTag(name string) (int, error) {
+	return c.rewriteTag(name, "")
+}
+
+// rewriteTag replaces tag from with to on every note carrying it, or
+// removes it when to is empty.
+func (c *Client) rewriteTag(from, to string) (int, error) {
+	from = models.NormalizeTag(from)
+	prefix := []byte(NotePrefix)
+	var changed []*NoteData
+
+	err := c.Do(func(k *kv.KV) error {
+		keys, err := k.Keys()
+		if err != nil {
+			return err
+		}
+		if indexedKeys, ok := noteKeysForTag(k, keys, from); ok {
+			keys = indexedKeys
+		}
+
+		for _, key := range keys {
+			if !bytes.HasPrefix(key, prefix) {
+				continue
+			}
+			val, err := k.Get(key)
+			if err != nil {
+				continue // Skip keys that can't be read
+			}
+			var nd NoteData
+			if err := json.Unmarshal(val, &nd); err != nil {
+				continue // Skip invalid data
+			}
+			if !slices.ContainsFunc(nd.Tags, func(t string) bool { return models.NormalizeTag(t) == from }) {
+				continue
+			}
+
+			oldTags := nd.Tags
+			nd.Tags = make([]string, 0, len(oldTags))
+			for _, t := range oldTags {
+				t = models.NormalizeTag(t)
+				if t == from {
+					t = to
+				}
+				if t != "" && !slices.Contains(nd.Tags, t) {
+					nd.Tags = append(nd.Tags, t)
+				}
+			}
+
+			encoded, err := c.marshalNote(&nd)
+			if err != nil {
+				return fmt.Errorf("marshal note: %w", err)
+			}
+			if err := deleteTagIndex(k, nd.ID, oldTags); err != nil {
+				return err
+			}
+			if err := k.Set(key, encoded); err != nil {
+				return err
+			}
+			if err := setTagIndex(k, nd.ID, nd.Tags); err != nil {
+				return err
+			}
+			c.audit(k, AuditTag, tagChange(from, to), nd.ID)
+			changed = append(changed, &nd)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, nd := range changed {
+		c.notify(webhooks.EventNoteUpdated, nd)
+	}
+	return len(changed), nil
+}
+
+// tagChange describes a tag rewrite for the audit log: "-from" for a
+// removal, "from→to" for a rename.
+func tagChange(from, to string) string {
+	if to == "" {
+		return "-" + from
+	}
+	return from + "→" + to
+}
@@ -0,0 +1,152 @@
+// ABOUTME: Work intervals timed against notes
+// ABOUTME: Stored as timer:<note-id>:<start> keys so a timer started on one machine can stop on another
+
+package charm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/charm/kv"
+	"github.com/google/uuid"
+)
+
+// TimerPrefix is the key prefix for timed intervals.
+const TimerPrefix = "timer:"
+
+var ErrNoTimer = errors.New("no timer is running")
+
+// Interval is a span of work on a note. A running timer has no End.
+type Interval struct {
+	NoteID uuid.UUID  `json:"note_id"`
+	Start  time.Time  `json:"start"`
+	End    *time.Time `json:"end,omitempty"`
+}
+
+// Running reports whether the interval's timer is still going.
+func (iv *Interval) Running() bool {
+	return iv.End == nil
+}
+
+func intervalKey(iv *Interval) []byte {
+	return []byte(fmt.Sprintf("%s%s:%d", TimerPrefix, iv.NoteID, iv.Start.UnixNano()))
+}
+
+// StartTimer starts timing work on a note. A timer already running on any
+// note is stopped first and returned.
+func (c *Client) StartTimer(noteID uuid.UUID) (started, stopped *Interval, err error) {
+	now := time.Now()
+	started = &Interval{NoteID: noteID, Start: now}
+	err = c.Do(func(k *kv.KV) error {
+		running, err := runningInterval(k)
+		if err != nil {
+			return err
+		}
+		if running != nil {
+			running.End = &now
+			if err := setInterval(k, running); err != nil {
+				return err
+			}
+			stopped = running
+		}
+		return setInterval(k, started)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return started, stopped, nil
+}
+
+// StopTimer stops the running timer and returns its interval, or
+// ErrNoTimer when none is running.
+func (c *Client) StopTimer() (*Interval, error) {
+	var stopped *Interval
+	err := c.Do(func(k *kv.KV) error {
+		running, err := runningInterval(k)
+		if err != nil {
+			return err
+		}
+		if running == nil {
+			return ErrNoTimer
+		}
+		now := time.Now()
+		running.End = &now
+		stopped = running
+		return setInterval(k, running)
+	})
+	return stopped, err
+}
+
+// RunningTimer returns the running timer's interval, or nil.
+func (c *Client) RunningTimer() (*Interval, error) {
+	var running *Interval
+	err := c.DoReadOnly(func(k *kv.KV) error {
+		var err error
+		running, err = runningInterval(k)
+		return err
+	})
+	return running, err
+}
+
+// ListIntervals returns every timed interval, oldest first.
+func (c *Client) ListIntervals() ([]*Interval, error) {
+	var intervals []*Interval
+	err := c.DoReadOnly(func(k *kv.KV) error {
+		var err error
+		intervals, err = readIntervals(k)
+		return err
+	})
+	return intervals, err
+}
+
+func setInterval(k *kv.KV, iv *Interval) error {
+	data, err := json.Marshal(iv)
+	if err != nil {
+		return err
+	}
+	return k.Set(intervalKey(iv), data)
+}
+
+func runningInterval(k *kv.KV) (*Interval, error) {
+	intervals, err := readIntervals(k)
+	if err != nil {
+		return nil, err
+	}
+	// Normally at most one; if two machines started timers before syncing,
+	// the latest one counts as running
+	for i := len(intervals) - 1; i >= 0; i-- {
+		if intervals[i].Running() {
+			return intervals[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func readIntervals(k *kv.KV) ([]*Interval, error) {
+	keys, err := k.Keys()
+	if err != nil {
+		return nil, err
+	}
+	prefix := []byte(TimerPrefix)
+	var intervals []*Interval
+	for _, key := range keys {
+		if !bytes.HasPrefix(key, prefix) {
+			continue
+		}
+		val, err := k.Get(key)
+		if err != nil {
+			continue // Skip keys that can't be read
+		}
+		var iv Interval
+		if err := json.Unmarshal(val, &iv); err != nil {
+			continue // Skip invalid data
+		}
+		intervals = append(intervals, &iv)
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+	return intervals, nil
+}
@@ -0,0 +1,87 @@
+// ABOUTME: Transparent zstd compression for note content and attachment data stored in KV.
+// ABOUTME: Compressed values carry a marker prefix, so plain values written before stay readable.
+
+package compress
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Marker starts every compressed value. It begins with a NUL byte, which
+// neither note text nor base64 contains, so plain values never match it.
+const Marker = "\x00zstd:"
+
+// MinSize is the smallest value worth compressing, in bytes.
+const MinSize = 1 << 10
+
+var (
+	encoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	decoder, _ = zstd.NewReader(nil)
+)
+
+// IsCompressed reports whether s was made by String or Bytes with compression.
+func IsCompressed(s string) bool {
+	return strings.HasPrefix(s, Marker)
+}
+
+// String returns s compressed and marked when it is at least MinSize and
+// compressing makes it smaller, otherwise s unchanged.
+func String(s string) string {
+	if len(s) < MinSize || IsCompressed(s) {
+		return s
+	}
+	if packed := pack([]byte(s)); len(packed) < len(s) {
+		return packed
+	}
+	return s
+}
+
+// ExpandString reverses String. Values without the marker are returned as is.
+func ExpandString(s string) (string, error) {
+	if !IsCompressed(s) {
+		return s, nil
+	}
+	data, err := unpack(s)
+	return string(data), err
+}
+
+// Bytes encodes data as base64, compressed and marked when it is at least
+// MinSize and compressing makes it smaller.
+func Bytes(data []byte) string {
+	plain := base64.StdEncoding.EncodeToString(data)
+	if len(data) >= MinSize {
+		if packed := pack(data); len(packed) < len(plain) {
+			return packed
+		}
+	}
+	return plain
+}
+
+// ExpandBytes decodes a value made by Bytes, or plain base64.
+func ExpandBytes(s string) ([]byte, error) {
+	if !IsCompressed(s) {
+		return base64.StdEncoding.DecodeString(s)
+	}
+	return unpack(s)
+}
+
+// pack compresses data into a marked base64 string.
+func pack(data []byte) string {
+	return Marker + base64.StdEncoding.EncodeToString(encoder.EncodeAll(data, nil))
+}
+
+func unpack(s string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, Marker))
+	if err != nil {
+		return nil, fmt.Errorf("decode compressed value: %w", err)
+	}
+	data, err := decoder.DecodeAll(raw, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decompress value: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,94 @@
+// ABOUTME: Tests and benchmarks for stored value compression
+// ABOUTME: Validates round-trips, the size threshold, plain values, and incompressible data
+
+package compress
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func longNote() string {
+	return strings.Repeat("Meeting notes: discussed the roadmap, the sync backlog, and the release.\n", 200)
+}
+
+func TestStringRoundTrip(t *testing.T) {
+	content := longNote()
+	packed := String(content)
+	if !IsCompressed(packed) {
+		t.Fatal("long repetitive content was not compressed")
+	}
+	if len(packed) >= len(content) {
+		t.Errorf("compressed %d bytes to %d", len(content), len(packed))
+	}
+	got, err := ExpandString(packed)
+	if err != nil || got != content {
+		t.Errorf("ExpandString = %d bytes, %v; want the original", len(got), err)
+	}
+}
+
+func TestStringLeavesShortAndPlainValues(t *testing.T) {
+	if got := String("short note"); got != "short note" {
+		t.Errorf("String(short) = %q", got)
+	}
+	got, err := ExpandString("zstd: not compressed, just text")
+	if err != nil || got != "zstd: not compressed, just text" {
+		t.Errorf("ExpandString(plain) = %q, %v", got, err)
+	}
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	random := make([]byte, 8<<10)
+	_, _ = rand.Read(random)
+
+	for name, data := range map[string][]byte{
+		"text":   []byte(longNote()),
+		"random": random,
+		"small":  []byte("tiny"),
+	} {
+		encoded := Bytes(data)
+		if name == "text" && !IsCompressed(encoded) {
+			t.Errorf("%s: not compressed", name)
+		}
+		if name != "text" && encoded != base64.StdEncoding.EncodeToString(data) {
+			t.Errorf("%s: want plain base64 when compression doesn't help", name)
+		}
+		got, err := ExpandBytes(encoded)
+		if err != nil || string(got) != string(data) {
+			t.Errorf("%s: ExpandBytes round trip failed: %v", name, err)
+		}
+	}
+}
+
+func TestExpandCorrupt(t *testing.T) {
+	if _, err := ExpandString(Marker + "!!!"); err == nil {
+		t.Error("expected error for invalid base64")
+	}
+	if _, err := ExpandBytes(Marker + base64.StdEncoding.EncodeToString([]byte("not zstd"))); err == nil {
+		t.Error("expected error for invalid zstd frame")
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	content := longNote()
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = String(content)
+	}
+	b.ReportMetric(float64(len(String(content)))/float64(len(content)), "ratio")
+}
+
+func BenchmarkExpandString(b *testing.B) {
+	content := longNote()
+	packed := String(content)
+	b.SetBytes(int64(len(content)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ExpandString(packed); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// ABOUTME: Per-directory .memo.toml project configuration.
+// ABOUTME: Finds the nearest config up the tree and exposes note defaults.
+
+package dirconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileName is the per-directory config file name.
+const FileName = ".memo.toml"
+
+// Config holds project defaults applied to commands run inside the tree.
+type Config struct {
+	// Here tags new notes with the current directory, as if --here were passed
+	Here bool `toml:"here"`
+
+	// Tags are added to every new note
+	Tags []string `toml:"tags"`
+
+	// Template is the initial content when composing a note in $EDITOR
+	Template string `toml:"template"`
+
+	// Path is the file the config was loaded from
+	Path string `toml:"-"`
+}
+
+// Find walks up from dir and loads the nearest .memo.toml.
+// It returns nil with no error when no config file is found.
+func Find(dir string) (*Config, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path := filepath.Join(dir, FileName)
+		if _, err := os.Stat(path); err == nil {
+			return Load(path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil //nolint:nilnil // No config is a valid outcome
+		}
+		dir = parent
+	}
+}
+
+// Load parses a .memo.toml file.
+func Load(path string) (*Config, error) {
+	var cfg Config
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	cfg.Path = path
+
+	// Template paths are resolved relative to the config file
+	if strings.HasPrefix(cfg.Template, "@") {
+		tmplPath := strings.TrimPrefix(cfg.Template, "@")
+		if !filepath.IsAbs(tmplPath) {
+			tmplPath = filepath.Join(filepath.Dir(path), tmplPath)
+		}
+		data, err := os.ReadFile(tmplPath) //nolint:gosec // Template path comes from the user's project config
+		if err != nil {
+			return nil, fmt.Errorf("read template: %w", err)
+		}
+		cfg.Template = string(data)
+	}
+
+	return &cfg, nil
+}
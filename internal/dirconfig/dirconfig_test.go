@@ -0,0 +1,68 @@
+// ABOUTME: Tests for per-directory .memo.toml discovery.
+// ABOUTME: Validates upward search, parsing, and template files.
+
+package dirconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindWalksUp(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "src", "pkg")
+	if err := os.MkdirAll(nested, 0750); err != nil {
+		t.Fatal(err)
+	}
+	content := "here = true\ntags = [\"project\", \"memo\"]\ntemplate = \"## Context\\n\"\n"
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Find(nested)
+	if err != nil {
+		t.Fatalf("find failed: %v", err)
+	}
+	if cfg == nil {
+		t.Fatal("expected config to be found")
+	}
+	if !cfg.Here {
+		t.Error("expected here = true")
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags[0] != "project" {
+		t.Errorf("unexpected tags: %v", cfg.Tags)
+	}
+	if cfg.Template != "## Context\n" {
+		t.Errorf("unexpected template: %q", cfg.Template)
+	}
+}
+
+func TestFindNone(t *testing.T) {
+	cfg, err := Find(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg != nil {
+		t.Errorf("expected no config, got %+v", cfg)
+	}
+}
+
+func TestLoadTemplateFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "note.md"), []byte("# Template"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, []byte(`template = "@note.md"`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Template != "# Template" {
+		t.Errorf("expected template file content, got %q", cfg.Template)
+	}
+}
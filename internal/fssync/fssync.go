@@ -0,0 +1,526 @@
+// ABOUTME: Two-way sync between notes and a directory of markdown files.
+// ABOUTME: Tracks file hashes and note timestamps in a state file to detect edits on either side.
+
+package fssync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/vault"
+	"gopkg.in/yaml.v3"
+)
+
+// StateFile is the name of the mapping file kept in the synced directory.
+const StateFile = ".memo-sync.json"
+
+// conflictExt marks a file's version saved aside on conflict. It is not .md
+// so the copy is not picked up as a new note on the next sync.
+const conflictExt = ".conflict"
+
+// entry records a file's note and the state of both sides at the last sync.
+type entry struct {
+	ID      string `json:"id"`
+	Hash    string `json:"hash"`    // SHA-256 of the file as last written or read
+	Updated int64  `json:"updated"` // Note UpdatedAt (unix) at the last sync
+}
+
+type state struct {
+	Files map[string]*entry `json:"files"` // Keyed by filename within the directory
+}
+
+// Result counts the changes made by a sync.
+type Result struct {
+	Written   int      // Files written from notes
+	Imported  int      // Notes created from new files
+	Updated   int      // Notes updated from edited files
+	Deleted   int      // Notes deleted because their file was removed
+	Removed   int      // Files removed because their note was deleted
+	Conflicts []string // Files edited on both sides; the note wins and the file is saved aside
+	Errors    []error  // Files that could not be applied; they are retried on the next pass
+}
+
+// Changed reports whether the sync modified any notes.
+func (r *Result) Changed() bool {
+	return r.Imported+r.Updated+r.Deleted > 0
+}
+
+// Syncer keeps Dir and the notes in Repo in step.
+type Syncer struct {
+	Repo charm.NotesRepository
+	Dir  string
+
+	// MinAge skips files modified more recently than this during Import, so
+	// files still being written by another tool are picked up on a later pass.
+	MinAge time.Duration
+}
+
+// frontmatter is the YAML header written to each file, matching `memo export --format md`.
+type frontmatter struct {
+	ID      string    `yaml:"id"`
+	Title   string    `yaml:"title"`
+	Tags    []string  `yaml:"tags"`
+	Created time.Time `yaml:"created"`
+	Updated time.Time `yaml:"updated"`
+}
+
+// Sync reconciles the directory with the notes. Edits and deletions on
+// either side since the last sync are applied to the other; the first sync
+// of a directory only adds, never deletes. A file renamed on disk keeps its
+// note. A file that can't be applied is reported in Result.Errors and the
+// rest are still synced, so the state always records what the pass did.
+func (s *Syncer) Sync() (*Result, error) {
+	if err := os.MkdirAll(s.Dir, 0750); err != nil {
+		return nil, err
+	}
+	st, err := s.loadState()
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.Repo.ListNotes(&charm.NoteFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+	pending := make(map[string]*models.Note, len(notes))
+	for _, n := range notes {
+		pending[n.ID.String()] = n
+	}
+
+	// Before the tracked pass, which would take a renamed file for a deleted one
+	if err := s.followRenames(st); err != nil {
+		return nil, err
+	}
+
+	res := &Result{}
+	for _, name := range sortedKeys(st.Files) {
+		if err := s.syncTracked(st, name, pending, res); err != nil {
+			res.Errors = append(res.Errors, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	untracked, err := s.untrackedFiles(st)
+	if err != nil {
+		_ = s.saveState(st) // Keep what this pass already applied
+		return nil, err
+	}
+	for _, name := range untracked {
+		if err := s.adoptFile(st, name, pending, res); err != nil {
+			res.Errors = append(res.Errors, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	// Notes with no file yet, oldest first so name collisions resolve stably
+	remaining := make([]*models.Note, 0, len(pending))
+	for _, n := range pending {
+		remaining = append(remaining, n)
+	}
+	sort.Slice(remaining, func(i, j int) bool { return remaining[i].CreatedAt.Before(remaining[j].CreatedAt) })
+	for _, n := range remaining {
+		name := s.newFilename(st, n)
+		if err := s.writeNote(st, name, n); err != nil {
+			res.Errors = append(res.Errors, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		res.Written++
+	}
+
+	return res, s.saveState(st)
+}
+
+// Import applies new and edited files to notes without writing files or
+// deleting anything, for folders filled by other tools. A file edit always
+// wins over changes made in memo.
+func (s *Syncer) Import() (*Result, error) {
+	st, err := s.loadState()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.followRenames(st); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.Repo.ListNotes(&charm.NoteFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("list notes: %w", err)
+	}
+	byID := make(map[string]*models.Note, len(notes))
+	for _, n := range notes {
+		byID[n.ID.String()] = n
+	}
+	pending := make(map[string]*models.Note, len(notes))
+	for id, n := range byID {
+		pending[id] = n
+	}
+	for _, e := range st.Files {
+		delete(pending, e.ID)
+	}
+
+	res := &Result{}
+	for _, name := range sortedKeys(st.Files) {
+		data, err := os.ReadFile(filepath.Join(s.Dir, name))
+		if os.IsNotExist(err) || (err == nil && hashBytes(data) == st.Files[name].Hash) || !s.settled(name) {
+			continue
+		}
+		if err == nil {
+			if note := byID[st.Files[name].ID]; note != nil {
+				if err = s.updateNote(st, name, note, data); err == nil {
+					res.Updated++
+				}
+			} else {
+				err = s.importFile(st, name, data, res)
+			}
+		}
+		if err != nil {
+			res.Errors = append(res.Errors, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	untracked, err := s.untrackedFiles(st)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range untracked {
+		if !s.settled(name) {
+			continue
+		}
+		if err := s.adoptFile(st, name, pending, res); err != nil {
+			res.Errors = append(res.Errors, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return res, s.saveState(st)
+}
+
+// settled reports whether a file is old enough to import.
+func (s *Syncer) settled(name string) bool {
+	if s.MinAge <= 0 {
+		return true
+	}
+	info, err := os.Stat(filepath.Join(s.Dir, name))
+	return err == nil && time.Since(info.ModTime()) >= s.MinAge
+}
+
+// syncTracked reconciles one file from the state with its note.
+func (s *Syncer) syncTracked(st *state, name string, pending map[string]*models.Note, res *Result) error {
+	e := st.Files[name]
+	note := pending[e.ID]
+	delete(pending, e.ID)
+
+	data, err := os.ReadFile(filepath.Join(s.Dir, name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	fileExists := err == nil
+	fileChanged := fileExists && hashBytes(data) != e.Hash
+	noteChanged := note != nil && note.UpdatedAt.Unix() != e.Updated
+
+	switch {
+	case note == nil && !fileExists:
+		delete(st.Files, name)
+	case note == nil && fileChanged:
+		// Deleted in memo but edited on disk: keep the edit as a new note
+		return s.importFile(st, name, data, res)
+	case note == nil:
+		if err := os.Remove(filepath.Join(s.Dir, name)); err != nil {
+			return err
+		}
+		delete(st.Files, name)
+		res.Removed++
+	case !fileExists && noteChanged:
+		if err := s.writeNote(st, name, note); err != nil {
+			return err
+		}
+		res.Written++
+	case !fileExists:
+		if err := s.Repo.DeleteNote(note.ID); err != nil && !errors.Is(err, charm.ErrNoteNotFound) {
+			return err
+		}
+		delete(st.Files, name)
+		res.Deleted++
+	case fileChanged && noteChanged:
+		aside := filepath.Join(s.Dir, name+conflictExt)
+		if err := os.WriteFile(aside, data, 0600); err != nil {
+			return err
+		}
+		if err := s.writeNote(st, name, note); err != nil {
+			return err
+		}
+		res.Conflicts = append(res.Conflicts, name)
+	case fileChanged:
+		if err := s.updateNote(st, name, note, data); err != nil {
+			return err
+		}
+		res.Updated++
+	case noteChanged:
+		if err := s.writeNote(st, name, note); err != nil {
+			return err
+		}
+		res.Written++
+	}
+	return nil
+}
+
+// followRenames moves the state entry of each tracked file that is gone to
+// an untracked file carrying the same note ID in its frontmatter, so a file
+// renamed on disk stays linked to its note rather than the note being
+// deleted and the file imported as a new one.
+func (s *Syncer) followRenames(st *state) error {
+	untracked, err := s.untrackedFiles(st)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]string, len(untracked))
+	for _, name := range untracked {
+		data, err := os.ReadFile(filepath.Join(s.Dir, name))
+		if err != nil {
+			continue // Reported when the file is adopted
+		}
+		if fm, _ := parseFile(name, data); fm.ID != "" && byID[fm.ID] == "" {
+			byID[fm.ID] = name
+		}
+	}
+
+	for _, name := range sortedKeys(st.Files) {
+		e := st.Files[name]
+		renamed := byID[e.ID]
+		if renamed == "" || fileExists(filepath.Join(s.Dir, name)) {
+			continue
+		}
+		st.Files[renamed] = e
+		delete(st.Files, name)
+		delete(byID, e.ID)
+	}
+	return nil
+}
+
+// adoptFile starts tracking a new file. A file carrying the ID of an
+// existing note (e.g. from `memo export --format md`) is linked to it;
+// anything else becomes a new note.
+func (s *Syncer) adoptFile(st *state, name string, pending map[string]*models.Note, res *Result) error {
+	data, err := os.ReadFile(filepath.Join(s.Dir, name))
+	if err != nil {
+		return err
+	}
+
+	fm, _ := parseFile(name, data)
+	note, ok := pending[fm.ID]
+	if !ok {
+		return s.importFile(st, name, data, res)
+	}
+	delete(pending, fm.ID)
+
+	tags, err := s.Repo.GetNoteTags(note.ID)
+	if err != nil {
+		return err
+	}
+	if string(data) == string(render(note, tags)) {
+		st.Files[name] = &entry{ID: fm.ID, Hash: hashBytes(data), Updated: note.UpdatedAt.Unix()}
+		return nil
+	}
+	if err := s.updateNote(st, name, note, data); err != nil {
+		return err
+	}
+	res.Updated++
+	return nil
+}
+
+// importFile creates a new note from a file's contents.
+func (s *Syncer) importFile(st *state, name string, data []byte, res *Result) error {
+	fm, content := parseFile(name, data)
+	if strings.TrimSpace(content) == "" {
+		return errors.New("note content cannot be empty")
+	}
+
+	note := models.NewNote(fm.Title, content)
+	if !fm.Created.IsZero() {
+		note.CreatedAt = fm.Created
+	}
+	if err := s.Repo.CreateNote(note, fm.Tags); err != nil {
+		return err
+	}
+	st.Files[name] = &entry{ID: note.ID.String(), Hash: hashBytes(data), Updated: note.UpdatedAt.Unix()}
+	res.Imported++
+	return nil
+}
+
+// updateNote applies a file's title, tags, and content to its note.
+func (s *Syncer) updateNote(st *state, name string, note *models.Note, data []byte) error {
+	fm, content := parseFile(name, data)
+	if strings.TrimSpace(content) == "" {
+		return errors.New("note content cannot be empty")
+	}
+	// parseFile trims the body, so compare against the trimmed note
+	if content != strings.TrimSpace(note.Content) {
+		if err := vault.CheckEdit(note.Title, note.Content); err != nil {
+			return err
+		}
+	}
+
+	note.Title = fm.Title
+	note.Content = content
+	note.Touch()
+	if err := s.Repo.UpdateNote(note, fm.Tags); err != nil {
+		return err
+	}
+	st.Files[name] = &entry{ID: note.ID.String(), Hash: hashBytes(data), Updated: note.UpdatedAt.Unix()}
+	return nil
+}
+
+// writeNote renders a note to its file and records it in the state.
+func (s *Syncer) writeNote(st *state, name string, note *models.Note) error {
+	tags, err := s.Repo.GetNoteTags(note.ID)
+	if err != nil {
+		return err
+	}
+	data := render(note, tags)
+	if err := os.WriteFile(filepath.Join(s.Dir, name), data, 0600); err != nil {
+		return err
+	}
+	st.Files[name] = &entry{ID: note.ID.String(), Hash: hashBytes(data), Updated: note.UpdatedAt.Unix()}
+	return nil
+}
+
+// newFilename picks a filename for a note from its title, appending the
+// short ID if the name is already taken.
+func (s *Syncer) newFilename(st *state, note *models.Note) string {
+	base := sanitizeFilename(note.Title)
+	if base == "" {
+		base = note.ID.String()[:8]
+	}
+	name := base + ".md"
+	if _, taken := st.Files[name]; taken || fileExists(filepath.Join(s.Dir, name)) {
+		name = base + "-" + note.ID.String()[:8] + ".md"
+	}
+	return name
+}
+
+// untrackedFiles lists markdown files in the directory that are not in the state.
+func (s *Syncer) untrackedFiles(st *state) ([]string, error) {
+	dirEntries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, de := range dirEntries {
+		name := de.Name()
+		if de.IsDir() || !strings.HasSuffix(name, ".md") || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if _, tracked := st.Files[name]; !tracked {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (s *Syncer) loadState() (*state, error) {
+	st := &state{Files: make(map[string]*entry)}
+	data, err := os.ReadFile(filepath.Join(s.Dir, StateFile))
+	if os.IsNotExist(err) {
+		return st, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", StateFile, err)
+	}
+	if st.Files == nil {
+		st.Files = make(map[string]*entry)
+	}
+	return st, nil
+}
+
+func (s *Syncer) saveState(st *state) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.Dir, StateFile), data, 0600)
+}
+
+// render formats a note as markdown with YAML frontmatter.
+func render(note *models.Note, tags []string) []byte {
+	if tags == nil {
+		tags = []string{}
+	}
+	header, _ := yaml.Marshal(frontmatter{
+		ID:      note.ID.String(),
+		Title:   note.Title,
+		Tags:    tags,
+		Created: note.CreatedAt,
+		Updated: note.UpdatedAt,
+	})
+
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.Write(header)
+	sb.WriteString("---\n\n")
+	sb.WriteString(note.Content)
+	sb.WriteString("\n")
+	return []byte(sb.String())
+}
+
+// parseFile splits a markdown file into frontmatter and content. Files
+// without a title in their frontmatter are titled after the filename.
+func parseFile(name string, data []byte) (frontmatter, string) {
+	var fm frontmatter
+	content := string(data)
+	if strings.HasPrefix(content, "---\n") {
+		parts := strings.SplitN(content, "---\n", 3)
+		if len(parts) == 3 && yaml.Unmarshal([]byte(parts[1]), &fm) == nil {
+			content = parts[2]
+		}
+	}
+	if fm.Title == "" {
+		fm.Title = strings.TrimSuffix(name, ".md")
+	}
+	if _, err := uuid.Parse(fm.ID); err != nil {
+		fm.ID = ""
+	}
+	return fm, strings.TrimSpace(content)
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func sortedKeys(m map[string]*entry) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sanitizeFilename replaces characters that are unsafe in filenames.
+func sanitizeFilename(name string) string {
+	replacer := strings.NewReplacer(
+		"/", "-", "\\", "-", ":", "-", "*", "-",
+		"?", "-", "\"", "-", "<", "-", ">", "-", "|", "-",
+	)
+	name = strings.TrimSpace(replacer.Replace(name))
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return name
+}
@@ -0,0 +1,35 @@
+// ABOUTME: Benchmarks for markdown directory sync
+// ABOUTME: Measures a no-change pass, the common case for memo watch and fs sync
+
+package fssync
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/harper/memo/internal/charm/charmtest"
+	"github.com/harper/memo/internal/models"
+)
+
+func BenchmarkSyncNoChanges(b *testing.B) {
+	for _, n := range []int{1_000, 10_000} {
+		b.Run(fmt.Sprintf("notes=%d", n), func(b *testing.B) {
+			store := charmtest.NewStore()
+			for i := 0; i < n; i++ {
+				_ = store.CreateNote(models.NewNote(fmt.Sprintf("Note %d", i), fmt.Sprintf("content %d", i)), []string{"bench"})
+			}
+			s := &Syncer{Repo: store, Dir: b.TempDir()}
+			if _, err := s.Sync(); err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.Sync(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
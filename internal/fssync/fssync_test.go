@@ -0,0 +1,248 @@
+// ABOUTME: Tests for two-way markdown directory sync.
+// ABOUTME: Uses the in-memory store to exercise edits, renames, and deletions on both sides.
+
+package fssync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harper/memo/internal/charm/charmtest"
+	"github.com/harper/memo/internal/models"
+)
+
+func newSyncer(t *testing.T) (*Syncer, *charmtest.Store) {
+	t.Helper()
+	store := charmtest.NewStore()
+	return &Syncer{Repo: store, Dir: t.TempDir()}, store
+}
+
+func mustSync(t *testing.T, s *Syncer) *Result {
+	t.Helper()
+	res, err := s.Sync()
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	return res
+}
+
+func TestSyncWritesNotesAndImportsFiles(t *testing.T) {
+	s, store := newSyncer(t)
+	note := models.NewNote("Ideas", "first idea")
+	if err := store.CreateNote(note, []string{"work"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, "Groceries.md"), []byte("milk\neggs\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	res := mustSync(t, s)
+	if res.Written != 1 || res.Imported != 1 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.Dir, "Ideas.md"))
+	if err != nil {
+		t.Fatalf("expected note file: %v", err)
+	}
+	if !strings.Contains(string(data), "first idea") || !strings.Contains(string(data), "- work") {
+		t.Errorf("unexpected file contents:\n%s", data)
+	}
+
+	notes, _ := store.ListNotes(nil)
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 notes, got %d", len(notes))
+	}
+
+	// A second sync with no changes does nothing
+	res = mustSync(t, s)
+	if res.Written+res.Imported+res.Updated+res.Deleted+res.Removed != 0 {
+		t.Errorf("expected no-op sync, got %+v", res)
+	}
+}
+
+func TestSyncAppliesFileEdits(t *testing.T) {
+	s, store := newSyncer(t)
+	note := models.NewNote("Ideas", "first idea")
+	_ = store.CreateNote(note, nil)
+	mustSync(t, s)
+
+	path := filepath.Join(s.Dir, "Ideas.md")
+	data, _ := os.ReadFile(path)
+	edited := strings.Replace(string(data), "first idea", "better idea", 1)
+	if err := os.WriteFile(path, []byte(edited), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	res := mustSync(t, s)
+	if res.Updated != 1 {
+		t.Errorf("expected 1 updated note, got %+v", res)
+	}
+	got, _, _ := store.GetNoteByID(note.ID)
+	if got.Content != "better idea" {
+		t.Errorf("expected note updated from file, got %q", got.Content)
+	}
+}
+
+func TestSyncPropagatesDeletes(t *testing.T) {
+	s, store := newSyncer(t)
+	keep := models.NewNote("Keep", "stays")
+	drop := models.NewNote("Drop", "goes")
+	gone := models.NewNote("Gone", "removed in memo")
+	for _, n := range []*models.Note{keep, drop, gone} {
+		_ = store.CreateNote(n, nil)
+	}
+	mustSync(t, s)
+
+	if err := os.Remove(filepath.Join(s.Dir, "Drop.md")); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.DeleteNote(gone.ID)
+
+	res := mustSync(t, s)
+	if res.Deleted != 1 || res.Removed != 1 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if _, _, err := store.GetNoteByID(drop.ID); err == nil {
+		t.Error("expected note for deleted file to be deleted")
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, "Gone.md")); !os.IsNotExist(err) {
+		t.Error("expected file for deleted note to be removed")
+	}
+}
+
+func TestSyncFollowsRenames(t *testing.T) {
+	s, store := newSyncer(t)
+	note := models.NewNote("Draft", "keep me")
+	_ = store.CreateNote(note, []string{"work"})
+	mustSync(t, s)
+
+	if err := os.Rename(filepath.Join(s.Dir, "Draft.md"), filepath.Join(s.Dir, "Final.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	res := mustSync(t, s)
+	if res.Deleted+res.Imported+res.Written+res.Removed != 0 {
+		t.Errorf("expected the rename to change nothing, got %+v", res)
+	}
+	notes, _ := store.ListNotes(nil)
+	if len(notes) != 1 || notes[0].ID != note.ID {
+		t.Fatalf("expected the renamed file to keep its note, got %d notes", len(notes))
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, "Draft.md")); !os.IsNotExist(err) {
+		t.Error("expected the old filename not to be written back")
+	}
+
+	// Edits to the renamed file reach the note
+	if err := os.WriteFile(filepath.Join(s.Dir, "Final.md"), []byte("---\nid: "+note.ID.String()+"\ntitle: Final\n---\n\nkept"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if res := mustSync(t, s); res.Updated != 1 {
+		t.Errorf("expected the edit to update the note, got %+v", res)
+	}
+	if got, _, _ := store.GetNoteByID(note.ID); got == nil || got.Content != "kept" {
+		t.Errorf("note = %+v, want the renamed file's edit", got)
+	}
+}
+
+func TestSyncConflictKeepsBothVersions(t *testing.T) {
+	s, store := newSyncer(t)
+	note := models.NewNote("Ideas", "original")
+	_ = store.CreateNote(note, nil)
+	mustSync(t, s)
+
+	path := filepath.Join(s.Dir, "Ideas.md")
+	if err := os.WriteFile(path, []byte("edited on disk"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	note.Content = "edited in memo"
+	note.UpdatedAt = note.UpdatedAt.Add(time.Minute)
+	_ = store.UpdateNote(note, nil)
+
+	res := mustSync(t, s)
+	if len(res.Conflicts) != 1 {
+		t.Fatalf("expected a conflict, got %+v", res)
+	}
+	aside, err := os.ReadFile(path + conflictExt)
+	if err != nil || string(aside) != "edited on disk" {
+		t.Errorf("expected disk version saved aside, got %q (%v)", aside, err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "edited in memo") {
+		t.Errorf("expected memo version in file, got:\n%s", data)
+	}
+}
+
+func TestSyncRecordsImportsWhenAFileFails(t *testing.T) {
+	s, store := newSyncer(t)
+	if err := os.WriteFile(filepath.Join(s.Dir, "a.md"), []byte("kept"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, "b.md"), []byte("  \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for pass := 1; pass <= 3; pass++ {
+		res := mustSync(t, s)
+		if len(res.Errors) != 1 || !strings.Contains(res.Errors[0].Error(), "b.md") {
+			t.Errorf("pass %d: expected one error for b.md, got %v", pass, res.Errors)
+		}
+	}
+
+	notes, _ := store.ListNotes(nil)
+	if len(notes) != 1 {
+		t.Errorf("expected a.md imported once, got %d notes", len(notes))
+	}
+}
+
+func TestImportOnlyReadsFiles(t *testing.T) {
+	s, store := newSyncer(t)
+	existing := models.NewNote("Existing", "not written to disk")
+	_ = store.CreateNote(existing, nil)
+
+	path := filepath.Join(s.Dir, "Inbox.md")
+	if err := os.WriteFile(path, []byte("from phone"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, "Empty.md"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.Import()
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if res.Imported != 1 || res.Written != 0 || len(res.Errors) != 1 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if _, err := os.Stat(filepath.Join(s.Dir, "Existing.md")); !os.IsNotExist(err) {
+		t.Error("import should not write notes to disk")
+	}
+
+	if err := os.WriteFile(path, []byte("from phone, edited"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	res, err = s.Import()
+	if err != nil || res.Updated != 1 {
+		t.Fatalf("expected edited file to update its note: %+v (%v)", res, err)
+	}
+}
+
+func TestImportWaitsForFilesToSettle(t *testing.T) {
+	s, _ := newSyncer(t)
+	s.MinAge = time.Hour
+	if err := os.WriteFile(filepath.Join(s.Dir, "Fresh.md"), []byte("still writing"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.Import()
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if res.Imported != 0 {
+		t.Errorf("expected recently modified file to be skipped, got %+v", res)
+	}
+}
@@ -0,0 +1,171 @@
+// ABOUTME: Git hooks that offer notes in commit messages and log commits to notes.
+// ABOUTME: Installs memo-marked hook scripts, edits commit message files, and reads commit details.
+
+package githook
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Marker identifies hook scripts written by Install, so reinstalling may
+// replace them but never a hook the user wrote.
+const Marker = "# Installed by memo hook install-git"
+
+// ErrForeignHook is returned by Install when a hook not written by memo is
+// already in place.
+var ErrForeignHook = errors.New("a hook not installed by memo already exists")
+
+// Script returns a POSIX shell hook that runs memo with args followed by
+// the hook's own arguments. A missing memo or a failing run never blocks git.
+func Script(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return "#!/bin/sh\n" + Marker + "\n" +
+		"command -v memo >/dev/null 2>&1 || exit 0\n" +
+		"memo " + strings.Join(quoted, " ") + ` "$@" || true` + "\n"
+}
+
+// HookPath returns where git looks for the named hook in the repository
+// containing dir, honoring core.hooksPath and worktrees.
+func HookPath(dir, name string) (string, error) {
+	out, err := git(dir, "rev-parse", "--git-path", "hooks/"+name)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(dir, out)
+	}
+	return out, nil
+}
+
+// Install writes script as the hook at path. An existing hook is replaced
+// only if memo wrote it, or if force is set.
+func Install(path, script string, force bool) error {
+	existing, err := os.ReadFile(path) //nolint:gosec // Path comes from git
+	switch {
+	case err == nil && !force && !strings.Contains(string(existing), Marker):
+		return fmt.Errorf("%w: %s", ErrForeignHook, path)
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(script), 0755) //nolint:gosec // Git hooks must be executable
+}
+
+// SkipSource reports whether prepare-commit-msg should leave a message
+// alone: merges, squashes, amends, and messages given with -m or -F are
+// already written, and -m commits may never reach an editor to strip
+// comments.
+func SkipSource(source string) bool {
+	return source != "" && source != "template"
+}
+
+// Insert adds block to a commit message file's content after the message
+// and before git's comment lines, leaving the subject line free.
+func Insert(msg, block string) string {
+	head, tail := msg, ""
+	for i := 0; i < len(msg); {
+		end := strings.IndexByte(msg[i:], '\n')
+		if strings.HasPrefix(msg[i:], "#") {
+			head, tail = msg[:i], msg[i:]
+			break
+		}
+		if end < 0 {
+			break
+		}
+		i += end + 1
+	}
+	if tail != "" && !strings.HasSuffix(tail, "\n") {
+		tail += "\n"
+	}
+	return strings.TrimRight(head, "\n") + "\n\n" + strings.TrimRight(block, "\n") + "\n" + tail
+}
+
+// Comment prefixes each line of text with "# ", so git strips it unless
+// the user uncomments it.
+func Comment(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("# "+line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Commit is a commit recorded by `memo log-commit`.
+type Commit struct {
+	Hash    string
+	Subject string
+	Author  string
+	Time    time.Time
+}
+
+// ReadCommit reads rev's details from the repository containing dir.
+func ReadCommit(dir, rev string) (*Commit, error) {
+	out, err := git(dir, "log", "-1", "--format=%H%x00%s%x00%an%x00%aI", rev, "--")
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Split(out, "\x00")
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("unexpected git log output %q", out)
+	}
+	t, err := time.Parse(time.RFC3339, fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("parse commit time: %w", err)
+	}
+	return &Commit{Hash: fields[0], Subject: fields[1], Author: fields[2], Time: t}, nil
+}
+
+// ShortHash abbreviates the commit hash for display.
+func (c *Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// Line formats the commit as a markdown list item for a log note.
+func (c *Commit) Line() string {
+	return fmt.Sprintf("- %s `%s` %s (%s)", c.Time.Local().Format("2006-01-02 15:04"), c.ShortHash(), c.Subject, c.Author)
+}
+
+// TopLevel returns the root of the working tree containing dir.
+func TopLevel(dir string) (string, error) {
+	return git(dir, "rev-parse", "--show-toplevel")
+}
+
+// git runs a git command in dir and returns its trimmed output.
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// shellQuote quotes s for a POSIX shell when it isn't a plain word.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,@", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,121 @@
+// ABOUTME: Tests for git hook scripts, commit message editing, and commit reading.
+// ABOUTME: Uses a scratch git repository when git is installed.
+
+package githook
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInsert(t *testing.T) {
+	tests := []struct {
+		name, msg, block, want string
+	}{
+		{
+			name:  "empty template",
+			msg:   "\n# Please enter the commit message.\n",
+			block: "# - Sync notes\n",
+			want:  "\n\n# - Sync notes\n# Please enter the commit message.\n",
+		},
+		{
+			name:  "existing message",
+			msg:   "Fix sync\n\n# Please enter the commit message.\n",
+			block: "Retry on 503.",
+			want:  "Fix sync\n\nRetry on 503.\n# Please enter the commit message.\n",
+		},
+		{
+			name:  "no comments",
+			msg:   "Fix sync\n",
+			block: "Retry on 503.\n",
+			want:  "Fix sync\n\nRetry on 503.\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := Insert(tt.msg, tt.block); got != tt.want {
+			t.Errorf("%s: Insert = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestComment(t *testing.T) {
+	if got := Comment("one\n\ntwo\n"); got != "# one\n#\n# two\n" {
+		t.Errorf("Comment = %q", got)
+	}
+}
+
+func TestScript(t *testing.T) {
+	got := Script("hook", "prepare-commit-msg", "--section", "Commit notes")
+	if !strings.Contains(got, Marker) || !strings.Contains(got, `memo hook prepare-commit-msg --section 'Commit notes' "$@" || true`) {
+		t.Errorf("unexpected script:\n%s", got)
+	}
+}
+
+func TestSkipSource(t *testing.T) {
+	for source, want := range map[string]bool{"": false, "template": false, "message": true, "merge": true, "squash": true, "commit": true} {
+		if got := SkipSource(source); got != want {
+			t.Errorf("SkipSource(%q) = %v, want %v", source, got, want)
+		}
+	}
+}
+
+func TestInstall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hooks", "prepare-commit-msg")
+
+	if err := Install(path, Script("log-commit"), false); err != nil {
+		t.Fatal(err)
+	}
+	if err := Install(path, Script("log-commit", "--note", "abc123"), false); err != nil {
+		t.Errorf("reinstalling memo's own hook: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nexit 0\n"), 0700); err != nil { //nolint:gosec // Test hook
+		t.Fatal(err)
+	}
+	if err := Install(path, Script("log-commit"), false); !errors.Is(err, ErrForeignHook) {
+		t.Errorf("expected ErrForeignHook, got %v", err)
+	}
+	if err := Install(path, Script("log-commit"), true); err != nil {
+		t.Errorf("forced install: %v", err)
+	}
+}
+
+func TestReadCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=Ada", "-c", "user.email=ada@example.com", "commit", "-q", "--allow-empty", "-m", "Add retry"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	c, err := ReadCommit(dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Subject != "Add retry" || c.Author != "Ada" || len(c.Hash) != 40 {
+		t.Errorf("unexpected commit %+v", c)
+	}
+	if line := c.Line(); !strings.Contains(line, "`"+c.Hash[:7]+"` Add retry (Ada)") {
+		t.Errorf("Line = %q", line)
+	}
+
+	path, err := HookPath(dir, "post-commit")
+	if err != nil || !strings.HasSuffix(filepath.ToSlash(path), ".git/hooks/post-commit") {
+		t.Errorf("HookPath = %q, %v", path, err)
+	}
+	if _, err := ReadCommit(dir, "nope"); err == nil {
+		t.Error("expected error for unknown revision")
+	}
+}
@@ -0,0 +1,116 @@
+// ABOUTME: Note graph export for visualization tools.
+// ABOUTME: Builds note and tag nodes joined by edges and writes them as Graphviz DOT or JSON.
+
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Node types.
+const (
+	NodeNote = "note"
+	NodeTag  = "tag"
+)
+
+// EdgeTag joins a note to one of its tags.
+const EdgeTag = "tag"
+
+// Entry is a note to place in the graph.
+type Entry struct {
+	ID    string
+	Title string
+	Tags  []string
+}
+
+// Node is a note or tag.
+type Node struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// Edge joins two nodes.
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Type   string `json:"type"`
+}
+
+// Graph is the set of nodes and edges, in a stable order.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Build returns the graph of entries and their tags: one node per note,
+// one per distinct (case-insensitive) tag, and an edge for each tagging.
+func Build(entries []Entry) *Graph {
+	g := &Graph{Nodes: []Node{}, Edges: []Edge{}}
+	tags := make(map[string]bool)
+
+	for _, e := range entries {
+		noteID := NodeNote + ":" + e.ID
+		g.Nodes = append(g.Nodes, Node{ID: noteID, Type: NodeNote, Label: e.Title})
+		seen := make(map[string]bool)
+		for _, tag := range e.Tags {
+			tag = strings.ToLower(tag)
+			if seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags[tag] = true
+			g.Edges = append(g.Edges, Edge{Source: noteID, Target: NodeTag + ":" + tag, Type: EdgeTag})
+		}
+	}
+
+	names := make([]string, 0, len(tags))
+	for tag := range tags {
+		names = append(names, tag)
+	}
+	sort.Strings(names)
+	for _, tag := range names {
+		g.Nodes = append(g.Nodes, Node{ID: NodeTag + ":" + tag, Type: NodeTag, Label: "#" + tag})
+	}
+	return g
+}
+
+// WriteJSON writes the graph as a JSON object with nodes and edges arrays.
+func WriteJSON(w io.Writer, g *Graph) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes the graph as an undirected Graphviz graph, with notes as
+// boxes and tags as ellipses.
+func WriteDOT(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "graph memo {")
+	fmt.Fprintln(bw, "  overlap=false;")
+	for _, n := range g.Nodes {
+		shape := "box"
+		if n.Type == NodeTag {
+			shape = "ellipse"
+		}
+		fmt.Fprintf(bw, "  %s [label=%s, shape=%s];\n", quote(n.ID), quote(n.Label), shape)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(bw, "  %s -- %s;\n", quote(e.Source), quote(e.Target))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// quote returns s as a DOT double-quoted string.
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,62 @@
+// ABOUTME: Tests for note graph export.
+// ABOUTME: Validates node and edge construction and the DOT and JSON encodings.
+
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	g := Build([]Entry{
+		{ID: "n1", Title: "Plan", Tags: []string{"work", "Work", "q3"}},
+		{ID: "n2", Title: "Notes", Tags: []string{"work"}},
+		{ID: "n3", Title: "Loose"},
+	})
+
+	if len(g.Nodes) != 5 {
+		t.Fatalf("expected 3 notes and 2 tags, got %+v", g.Nodes)
+	}
+	if g.Nodes[3].ID != "tag:q3" || g.Nodes[4].ID != "tag:work" {
+		t.Errorf("expected sorted tag nodes after notes, got %+v", g.Nodes[3:])
+	}
+	if len(g.Edges) != 3 {
+		t.Errorf("expected duplicate tags to share one edge, got %+v", g.Edges)
+	}
+}
+
+func TestWriteDOTEscapes(t *testing.T) {
+	g := Build([]Entry{{ID: "n1", Title: `Say "hi" \ bye`, Tags: []string{"a"}}})
+
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, g); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "graph memo {") || !strings.HasSuffix(out, "}\n") {
+		t.Errorf("expected a DOT graph, got %q", out)
+	}
+	if !strings.Contains(out, `label="Say \"hi\" \\ bye"`) {
+		t.Errorf("expected escaped label, got %q", out)
+	}
+	if !strings.Contains(out, `"note:n1" -- "tag:a";`) {
+		t.Errorf("expected edge, got %q", out)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, Build(nil)); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	var g Graph
+	if err := json.Unmarshal(buf.Bytes(), &g); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"nodes": []`) || !strings.Contains(buf.String(), `"edges": []`) {
+		t.Errorf("expected empty arrays rather than null, got %s", buf.String())
+	}
+}
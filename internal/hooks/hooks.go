@@ -0,0 +1,58 @@
+// ABOUTME: User-defined hook scripts run before and after commands.
+// ABOUTME: Hooks are executables in the hooks dir that receive JSON on stdin.
+
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// Runner executes hook scripts from a directory.
+type Runner struct {
+	Dir string
+}
+
+// NewRunner creates a runner for hooks stored in dir.
+func NewRunner(dir string) *Runner {
+	return &Runner{Dir: dir}
+}
+
+// Path returns the script path for a hook name, or "" if no runnable hook exists.
+func (r *Runner) Path(name string) string {
+	path := filepath.Join(r.Dir, name)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+		return ""
+	}
+	return path
+}
+
+// Run executes the named hook with payload on stdin and extra environment
+// variables. A missing hook is not an error.
+func (r *Runner) Run(name string, payload []byte, env ...string) error {
+	path := r.Path(name)
+	if path == "" {
+		return nil
+	}
+
+	cmd := exec.Command(path) //nolint:gosec // Hooks are user-installed scripts by design
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = os.Stderr // Keep stdout clean for memo's own output
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "MEMO_HOOK="+name)
+	cmd.Env = append(cmd.Env, env...)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("hook %s exited with status %d", name, exitErr.ExitCode())
+		}
+		return fmt.Errorf("hook %s: %w", name, err)
+	}
+	return nil
+}
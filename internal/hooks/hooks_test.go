@@ -0,0 +1,64 @@
+// ABOUTME: Tests for hook script execution.
+// ABOUTME: Validates stdin payload delivery, exit status, and missing hooks.
+
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func writeHook(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0700); err != nil { //nolint:gosec // Test hook must be executable
+		t.Fatal(err)
+	}
+}
+
+func TestRunReceivesPayload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell hooks not supported on windows")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.json")
+	writeHook(t, dir, "post-add", "#!/bin/sh\ncat > "+out+"\necho \"$MEMO_HOOK $MEMO_COMMAND\" >> "+out+"\n")
+
+	err := NewRunner(dir).Run("post-add", []byte(`{"id":"abc"}`), "MEMO_COMMAND=add")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(out) //nolint:gosec // Test file
+	if string(data) != "{\"id\":\"abc\"}post-add add\n" {
+		t.Errorf("unexpected hook output: %q", data)
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell hooks not supported on windows")
+	}
+	dir := t.TempDir()
+	writeHook(t, dir, "pre-rm", "#!/bin/sh\nexit 3\n")
+
+	if err := NewRunner(dir).Run("pre-rm", nil); err == nil {
+		t.Error("expected error from failing hook")
+	}
+}
+
+func TestRunMissingOrNotExecutable(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "post-edit"), []byte("#!/bin/sh\nexit 1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRunner(dir)
+	if err := r.Run("post-add", nil); err != nil {
+		t.Errorf("missing hook should be ignored: %v", err)
+	}
+	if err := r.Run("post-edit", nil); err != nil {
+		t.Errorf("non-executable hook should be ignored: %v", err)
+	}
+}
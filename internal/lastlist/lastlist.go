@@ -0,0 +1,71 @@
+// ABOUTME: Remembers the notes shown by the last listing, in order.
+// ABOUTME: Lets commands take a list position (memo show 3) in place of an ID.
+
+package lastlist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// ErrNoList is returned when no listing has been recorded yet.
+var ErrNoList = errors.New("no previous listing; run memo list first")
+
+// List is the on-disk record of a listing.
+type List struct {
+	Time time.Time `json:"time"`
+	IDs  []string  `json:"ids"`
+}
+
+// Save records ids, in the order they were shown, at path.
+func Save(path string, ids []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.Marshal(List{Time: time.Now(), IDs: ids})
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Lookup returns the ID shown at 1-based position n in the listing at path.
+func Lookup(path string, n int) (string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Path is memo's own state file
+	if os.IsNotExist(err) {
+		return "", ErrNoList
+	}
+	if err != nil {
+		return "", err
+	}
+	var l List
+	if err := json.Unmarshal(data, &l); err != nil {
+		return "", err
+	}
+	if n < 1 || n > len(l.IDs) {
+		return "", fmt.Errorf("no note #%d in the last listing (it had %d)", n, len(l.IDs))
+	}
+	return l.IDs[n-1], nil
+}
+
+// Position parses ref as a list position. Positions are short decimal
+// numbers; anything six characters or longer is left as an ID prefix.
+func Position(ref string) (int, bool) {
+	if len(ref) == 0 || len(ref) >= 6 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(ref)
+	if err != nil || n < 1 || strconv.Itoa(n) != ref {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,50 @@
+// ABOUTME: Tests for last-listing positions.
+// ABOUTME: Validates save/lookup round trips and which refs count as positions.
+
+package lastlist
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "last-list.json")
+
+	if _, err := Lookup(path, 1); !errors.Is(err, ErrNoList) {
+		t.Fatalf("expected ErrNoList, got %v", err)
+	}
+
+	if err := Save(path, []string{"aaa", "bbb", "ccc"}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if id, err := Lookup(path, 2); err != nil || id != "bbb" {
+		t.Errorf("expected bbb, got %q (%v)", id, err)
+	}
+	if _, err := Lookup(path, 4); err == nil {
+		t.Error("expected out-of-range error")
+	}
+	if _, err := Lookup(path, 0); err == nil {
+		t.Error("expected error for position 0")
+	}
+}
+
+func TestPosition(t *testing.T) {
+	cases := map[string]int{
+		"1":      1,
+		"42":     42,
+		"0":      0,
+		"03":     0,
+		"-1":     0,
+		"123456": 0, // Could be an ID prefix
+		"abc":    0,
+		"":       0,
+	}
+	for ref, want := range cases {
+		n, ok := Position(ref)
+		if ok != (want > 0) || n != want {
+			t.Errorf("Position(%q) = %d, %v; want %d", ref, n, ok, want)
+		}
+	}
+}
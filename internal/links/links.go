@@ -0,0 +1,208 @@
+// ABOUTME: Finds links in note content and checks whether they still resolve.
+// ABOUTME: Understands memo:// links, [[wiki]] links, and http(s) URLs outside code fences.
+
+package links
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind is the type of a link.
+type Kind int
+
+const (
+	KindMemo Kind = iota // memo://<id-or-alias>
+	KindWiki             // [[Title]] or [[Title|text]]
+	KindURL              // http:// or https://
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindMemo:
+		return "memo"
+	case KindWiki:
+		return "wiki"
+	default:
+		return "url"
+	}
+}
+
+// Link is a link found in note content.
+type Link struct {
+	Kind   Kind
+	Target string // Note reference for memo and wiki links, the URL otherwise
+	Text   string // What remains when the link is removed
+	Line   int    // One-based line number
+	Start  int    // Byte offsets of the whole link in the content
+	End    int
+	Bare   bool // A plain URL, not inside markdown link syntax
+}
+
+var (
+	markdownLink = regexp.MustCompile(`\[([^\]\n]*)\]\(((?:memo|https?)://[^)\s]+)\)`)
+	wikiLink     = regexp.MustCompile(`\[\[([^\]|\n]+)(?:\|([^\]\n]+))?\]\]`)
+	bareLink     = regexp.MustCompile(`(?:memo|https?)://[^\s)<>\]"'` + "`" + `]+`)
+)
+
+// Find returns the links in content in order of appearance. Links inside
+// fenced code blocks are skipped.
+func Find(content string) []Link {
+	var found []Link
+	taken := func(start, end int) bool {
+		for _, l := range found {
+			if start < l.End && end > l.Start {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, m := range markdownLink.FindAllStringSubmatchIndex(content, -1) {
+		text, url := content[m[2]:m[3]], content[m[4]:m[5]]
+		if text == "" {
+			text = url
+		}
+		found = append(found, newURLLink(url, text, m[0], m[1]))
+	}
+	for _, m := range wikiLink.FindAllStringSubmatchIndex(content, -1) {
+		target := strings.TrimSpace(content[m[2]:m[3]])
+		text := target
+		if m[4] >= 0 {
+			text = strings.TrimSpace(content[m[4]:m[5]])
+		}
+		found = append(found, Link{Kind: KindWiki, Target: target, Text: text, Start: m[0], End: m[1]})
+	}
+	for _, m := range bareLink.FindAllStringIndex(content, -1) {
+		url := strings.TrimRight(content[m[0]:m[1]], ".,;:!?")
+		if !taken(m[0], m[0]+len(url)) {
+			l := newURLLink(url, url, m[0], m[0]+len(url))
+			l.Bare = true
+			found = append(found, l)
+		}
+	}
+
+	fences := fencedRanges(content)
+	result := found[:0]
+	for _, l := range found {
+		if !inRanges(fences, l.Start) {
+			l.Line = strings.Count(content[:l.Start], "\n") + 1
+			result = append(result, l)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Start < result[j].Start })
+	return result
+}
+
+// newURLLink classifies a memo:// or http(s) URL.
+func newURLLink(url, text string, start, end int) Link {
+	if ref, ok := MemoRef(url); ok {
+		if text == url {
+			text = ref
+		}
+		return Link{Kind: KindMemo, Target: ref, Text: text, Start: start, End: end}
+	}
+	return Link{Kind: KindURL, Target: url, Text: text, Start: start, End: end}
+}
+
+// MemoRef returns the note reference (ID prefix or alias) in a memo://<ref>
+// or memo://note/<ref> URL. Any further path, query, or fragment is dropped.
+func MemoRef(url string) (string, bool) {
+	ref, ok := strings.CutPrefix(url, "memo://")
+	if !ok {
+		return "", false
+	}
+	ref = strings.TrimPrefix(ref, "note/")
+	if i := strings.IndexAny(ref, "/?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	return ref, true
+}
+
+// Unlink replaces each of the given links in content with its text.
+func Unlink(content string, remove []Link) string {
+	sorted := append([]Link(nil), remove...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start > sorted[j].Start })
+	for _, l := range sorted {
+		content = content[:l.Start] + l.Text + content[l.End:]
+	}
+	return content
+}
+
+// fencedRanges returns the byte ranges of fenced code blocks.
+func fencedRanges(content string) [][2]int {
+	var ranges [][2]int
+	start, offset := -1, 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if start < 0 {
+				start = offset
+			} else {
+				ranges = append(ranges, [2]int{start, offset + len(line)})
+				start = -1
+			}
+		}
+		offset += len(line)
+	}
+	if start >= 0 {
+		ranges = append(ranges, [2]int{start, len(content)})
+	}
+	return ranges
+}
+
+func inRanges(ranges [][2]int, pos int) bool {
+	for _, r := range ranges {
+		if pos >= r[0] && pos < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Checker tests whether URLs still respond and fetches page titles.
+type Checker struct {
+	Client *http.Client
+}
+
+// NewChecker creates a checker with a short timeout suited to a CLI process.
+func NewChecker() *Checker {
+	return &Checker{Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Check requests url and returns an error if it can't be reached or
+// answers 404 or 410. Other statuses, like 403 from sites that turn away
+// scripts, count as alive. HEAD is tried first, then GET for servers that
+// reject HEAD.
+func (c *Checker) Check(ctx context.Context, url string) error {
+	status, err := c.request(ctx, http.MethodHead, url)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented) {
+		status, err = c.request(ctx, http.MethodGet, url)
+	}
+	if err != nil {
+		return err
+	}
+	if status == http.StatusNotFound || status == http.StatusGone {
+		return fmt.Errorf("%d %s", status, http.StatusText(status))
+	}
+	return nil
+}
+
+func (c *Checker) request(ctx context.Context, method, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "memo-link-check")
+	resp, err := c.Client.Do(req) //nolint:gosec // Checking user-written URLs is the point
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
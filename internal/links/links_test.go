@@ -0,0 +1,74 @@
+// ABOUTME: Tests for link discovery and URL checking.
+// ABOUTME: Validates link kinds, code fences, unlinking, and HTTP status handling.
+
+package links
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	content := "See [[Roadmap]] and [[Q3 plan|the plan]].\n" +
+		"Spec: [design](memo://abc123def/section) or memo://roadmap, memo://note/fedcba987.\n" +
+		"Docs at https://example.com/docs, and [site](https://example.org).\n" +
+		"```\n[[Not a link]] https://skipped.example\n```\n"
+
+	got := Find(content)
+	want := []Link{
+		{Kind: KindWiki, Target: "Roadmap", Text: "Roadmap", Line: 1},
+		{Kind: KindWiki, Target: "Q3 plan", Text: "the plan", Line: 1},
+		{Kind: KindMemo, Target: "abc123def", Text: "design", Line: 2},
+		{Kind: KindMemo, Target: "roadmap", Text: "roadmap", Line: 2},
+		{Kind: KindMemo, Target: "fedcba987", Text: "fedcba987", Line: 2},
+		{Kind: KindURL, Target: "https://example.com/docs", Text: "https://example.com/docs", Line: 3},
+		{Kind: KindURL, Target: "https://example.org", Text: "site", Line: 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Find returned %d links, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Kind != w.Kind || g.Target != w.Target || g.Text != w.Text || g.Line != w.Line {
+			t.Errorf("link %d = %+v, want %+v", i, g, w)
+		}
+	}
+}
+
+func TestUnlink(t *testing.T) {
+	content := "Read [[Gone]] and [spec](memo://deadbeef) but keep [[Here]]."
+	found := Find(content)
+	got := Unlink(content, []Link{found[0], found[1]})
+	if want := "Read Gone and spec but keep [[Here]]."; got != want {
+		t.Errorf("Unlink = %q, want %q", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gone":
+			w.WriteHeader(http.StatusNotFound)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		case "/private":
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	c := &Checker{Client: srv.Client()}
+	ctx := context.Background()
+	for path, dead := range map[string]bool{"/ok": false, "/gone": true, "/no-head": false, "/private": false} {
+		if err := c.Check(ctx, srv.URL+path); (err != nil) != dead {
+			t.Errorf("Check(%s) = %v, want dead=%v", path, err, dead)
+		}
+	}
+	if err := c.Check(ctx, "http://127.0.0.1:1/unreachable"); err == nil {
+		t.Error("expected error for unreachable host")
+	}
+}
@@ -0,0 +1,149 @@
+// ABOUTME: Page title fetching and caching for turning bare URLs into markdown links.
+// ABOUTME: Titles come from the HTML <title> element and are cached on disk by URL.
+
+package links
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNoTitle is returned when a page has no usable title.
+var ErrNoTitle = errors.New("no page title")
+
+const (
+	maxTitleBody  = 512 << 10 // Bytes read looking for <title>
+	maxTitleRunes = 100
+
+	// FailureTTL is how long a failed title fetch is remembered before the
+	// URL is tried again.
+	FailureTTL = 24 * time.Hour
+)
+
+var titleElement = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// FetchTitle returns the title of the HTML page at url.
+func (c *Checker) FetchTitle(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "memo-link-check")
+	resp, err := c.Client.Do(req) //nolint:gosec // Fetching user-written URLs is the point
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "html") {
+		return "", ErrNoTitle
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTitleBody))
+	if err != nil {
+		return "", err
+	}
+
+	m := titleElement.FindSubmatch(body)
+	if m == nil {
+		return "", ErrNoTitle
+	}
+	title := strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+	if title == "" {
+		return "", ErrNoTitle
+	}
+	if r := []rune(title); len(r) > maxTitleRunes {
+		title = string(r[:maxTitleRunes-1]) + "…"
+	}
+	return title, nil
+}
+
+// Enrich turns bare http(s) URLs in content into markdown links titled by
+// title(url). URLs with an empty title are left alone. It returns the new
+// content and the number of URLs linked.
+func Enrich(content string, title func(url string) string) (string, int) {
+	var linked []Link
+	for _, l := range Find(content) {
+		if l.Kind != KindURL || !l.Bare {
+			continue
+		}
+		if t := title(l.Target); t != "" {
+			l.Text = "[" + escapeLinkText(t) + "](" + l.Target + ")"
+			linked = append(linked, l)
+		}
+	}
+	return Unlink(content, linked), len(linked)
+}
+
+// escapeLinkText escapes the characters that would end markdown link text.
+func escapeLinkText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(s)
+}
+
+// CachedTitle is a remembered title fetch; an empty Title records a failure.
+type CachedTitle struct {
+	Title     string    `json:"title,omitempty"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// TitleCache remembers page titles by URL so enrichment doesn't refetch.
+type TitleCache struct {
+	path    string
+	Entries map[string]CachedTitle
+}
+
+// LoadTitleCache reads the cache at path, starting empty if there is none.
+func LoadTitleCache(path string) (*TitleCache, error) {
+	tc := &TitleCache{path: path, Entries: make(map[string]CachedTitle)}
+	data, err := os.ReadFile(path) //nolint:gosec // Path is memo's own state file
+	if os.IsNotExist(err) {
+		return tc, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tc.Entries); err != nil {
+		return nil, err
+	}
+	return tc, nil
+}
+
+// Lookup returns a cached title and true when the URL was fetched before:
+// always for successes, and within FailureTTL for failures (with an empty
+// title).
+func (tc *TitleCache) Lookup(url string, now time.Time) (string, bool) {
+	e, ok := tc.Entries[url]
+	if !ok || (e.Title == "" && now.Sub(e.FetchedAt) > FailureTTL) {
+		return "", false
+	}
+	return e.Title, true
+}
+
+// Store records the outcome of fetching url's title.
+func (tc *TitleCache) Store(url, title string, now time.Time) {
+	tc.Entries[url] = CachedTitle{Title: title, FetchedAt: now}
+}
+
+// Save writes the cache back to its file.
+func (tc *TitleCache) Save() error {
+	if err := os.MkdirAll(filepath.Dir(tc.path), 0750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(tc.Entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(tc.path, data, 0600)
+}
@@ -0,0 +1,85 @@
+// ABOUTME: Tests for page title fetching, URL enrichment, and the title cache.
+// ABOUTME: Uses an httptest server and a temp directory for the cache file.
+
+package links
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFetchTitle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/page":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte("<html><head><TITLE>\n  Fish &amp; Chips\n</TITLE></head></html>"))
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"title": "nope"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := &Checker{Client: srv.Client()}
+	ctx := context.Background()
+	if got, err := c.FetchTitle(ctx, srv.URL+"/page"); err != nil || got != "Fish & Chips" {
+		t.Errorf("FetchTitle = %q, %v; want %q", got, err, "Fish & Chips")
+	}
+	if _, err := c.FetchTitle(ctx, srv.URL+"/json"); !errors.Is(err, ErrNoTitle) {
+		t.Errorf("expected ErrNoTitle for non-HTML, got %v", err)
+	}
+	if _, err := c.FetchTitle(ctx, srv.URL+"/missing"); err == nil {
+		t.Error("expected error for 404")
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	content := "Read https://a.example/post and [kept](https://b.example).\n" +
+		"Unknown: https://c.example\n"
+	titles := map[string]string{"https://a.example/post": "A [great] post", "https://b.example": "B"}
+
+	got, n := Enrich(content, func(url string) string { return titles[url] })
+	want := "Read [A \\[great\\] post](https://a.example/post) and [kept](https://b.example).\n" +
+		"Unknown: https://c.example\n"
+	if got != want || n != 1 {
+		t.Errorf("Enrich = %q (%d), want %q (1)", got, n, want)
+	}
+}
+
+func TestTitleCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "titles.json")
+	now := time.Now()
+
+	tc, err := LoadTitleCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.Store("https://ok.example", "OK", now.Add(-48*time.Hour))
+	tc.Store("https://failed.example", "", now.Add(-time.Hour))
+	tc.Store("https://stale.example", "", now.Add(-48*time.Hour))
+	if err := tc.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	tc, err = LoadTitleCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title, ok := tc.Lookup("https://ok.example", now); !ok || title != "OK" {
+		t.Errorf("expected cached title, got %q %v", title, ok)
+	}
+	if _, ok := tc.Lookup("https://failed.example", now); !ok {
+		t.Error("expected recent failure to be cached")
+	}
+	if _, ok := tc.Lookup("https://stale.example", now); ok {
+		t.Error("expected old failure to expire")
+	}
+}
@@ -0,0 +1,387 @@
+// ABOUTME: Minimal language server for note links in editor plugins.
+// ABOUTME: Completes [[note titles]], previews linked notes on hover, and opens them on go-to-definition.
+
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/links"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/vault"
+)
+
+const (
+	maxCompletions = 100
+	previewRunes   = 1500
+)
+
+// Server answers LSP requests about the notes in a repository.
+type Server struct {
+	repo     charm.Repository
+	notesDir string            // Where go-to-definition writes linked notes
+	docs     map[string]string // Open documents by URI
+	out      io.Writer
+	shutdown bool
+}
+
+// NewServer creates a language server over repo. Linked notes opened with
+// go-to-definition are written to notesDir as <id>.md, and saving one of
+// those files updates the note.
+func NewServer(repo charm.Repository, notesDir string) *Server {
+	return &Server{repo: repo, notesDir: notesDir, docs: make(map[string]string)}
+}
+
+// Serve reads requests from in and writes responses to out until the
+// client sends exit or closes the stream.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	r := bufio.NewReader(in)
+	for {
+		body, err := readMessage(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.reply(nil, nil, &rpcError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			return nil
+		}
+
+		result, rerr := s.handle(req)
+		if req.ID == nil {
+			continue // Notifications get no response
+		}
+		if err := s.reply(req.ID, result, rerr); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) reply(id json.RawMessage, result any, rerr *rpcError) error {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	resp := response{JSONRPC: "2.0", ID: id, Error: rerr}
+	if rerr == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		resp.Result = data
+	}
+	return writeMessage(s.out, resp)
+}
+
+// showError tells the user about a failure that has no request to answer.
+func (s *Server) showError(msg string) {
+	_ = writeMessage(s.out, notification{
+		JSONRPC: "2.0",
+		Method:  "window/showMessage",
+		Params:  map[string]any{"type": 1, "message": "memo: " + msg},
+	})
+}
+
+func (s *Server) handle(req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					"change":    1, // Full document text on each change
+					"save":      map[string]any{"includeText": true},
+				},
+				"completionProvider": map[string]any{"triggerCharacters": []string{"["}},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "memo"},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if err := json.Unmarshal(req.Params, &p); err == nil {
+			s.docs[p.TextDocument.URI] = p.TextDocument.Text
+		}
+		return nil, nil
+	case "textDocument/didChange":
+		var p didChangeParams
+		if err := json.Unmarshal(req.Params, &p); err == nil && len(p.ContentChanges) > 0 {
+			s.docs[p.TextDocument.URI] = p.ContentChanges[len(p.ContentChanges)-1].Text
+		}
+		return nil, nil
+	case "textDocument/didClose":
+		var p didOpenParams
+		if err := json.Unmarshal(req.Params, &p); err == nil {
+			delete(s.docs, p.TextDocument.URI)
+		}
+		return nil, nil
+	case "textDocument/didSave":
+		var p didSaveParams
+		if err := json.Unmarshal(req.Params, &p); err == nil {
+			if p.Text != nil {
+				s.docs[p.TextDocument.URI] = *p.Text
+			}
+			if err := s.saveNote(p.TextDocument.URI); err != nil {
+				s.showError(err.Error())
+			}
+		}
+		return nil, nil
+
+	case "textDocument/completion":
+		return s.withPosition(req, s.completion)
+	case "textDocument/hover":
+		return s.withPosition(req, s.hover)
+	case "textDocument/definition":
+		return s.withPosition(req, s.definition)
+	}
+
+	if req.ID == nil || strings.HasPrefix(req.Method, "$/") {
+		return nil, nil
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not supported: " + req.Method}
+}
+
+// withPosition decodes text document position params and calls fn with
+// the document's text and the byte offset of the position.
+func (s *Server) withPosition(req request, fn func(text string, offset int) (any, error)) (any, *rpcError) {
+	var p positionParams
+	if err := json.Unmarshal(req.Params, &p); err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	text, ok := s.docs[p.TextDocument.URI]
+	if !ok {
+		// Not opened through the client; fall back to the file on disk
+		path, err := uriPath(p.TextDocument.URI)
+		if err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		data, err := os.ReadFile(path) //nolint:gosec // Path comes from the editor
+		if err != nil {
+			return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		text = string(data)
+	}
+	result, err := fn(text, offsetAt(text, p.Position))
+	if err != nil {
+		return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+	}
+	return result, nil
+}
+
+// completion offers note titles inside an unclosed [[ before the cursor.
+func (s *Server) completion(text string, offset int) (any, error) {
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	before := text[lineStart:offset]
+	start := strings.LastIndex(before, "[[")
+	if start < 0 {
+		return nil, nil
+	}
+	partial := before[start+2:]
+	if strings.ContainsAny(partial, "]|") {
+		return nil, nil
+	}
+
+	notes, err := s.repo.ListNotesWithTags(&charm.NoteFilter{ContentLimit: charm.NoContent})
+	if err != nil {
+		return nil, err
+	}
+	closer := "]]"
+	if strings.HasPrefix(text[offset:], "]]") {
+		closer = ""
+	}
+	replace := Range{Start: positionAt(text, lineStart+start+2), End: positionAt(text, offset)}
+
+	list := completionList{Items: []completionItem{}}
+	seen := make(map[string]bool)
+	query := strings.ToLower(partial)
+	for _, nt := range notes {
+		title := strings.TrimSpace(nt.Note.Title)
+		key := strings.ToLower(title)
+		if title == "" || seen[key] || !strings.Contains(key, query) {
+			continue
+		}
+		if len(list.Items) == maxCompletions {
+			list.IsIncomplete = true
+			break
+		}
+		seen[key] = true
+		list.Items = append(list.Items, completionItem{
+			Label:      title,
+			Kind:       completionKindReference,
+			Detail:     noteDetail(nt.Note, nt.Tags),
+			FilterText: title,
+			TextEdit:   textEdit{Range: replace, NewText: title + closer},
+		})
+	}
+	return list, nil
+}
+
+// hover previews the note linked at offset.
+func (s *Server) hover(text string, offset int) (any, error) {
+	link, ok := linkAt(text, offset)
+	if !ok {
+		return nil, nil
+	}
+	note, tags, err := s.lookup(link)
+	if err != nil || note == nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**  \n%s\n\n---\n\n", note.Title, noteDetail(note, tags))
+	content := []rune(note.Content)
+	if len(content) > previewRunes {
+		b.WriteString(string(content[:previewRunes]) + "\n\n…")
+	} else {
+		b.WriteString(note.Content)
+	}
+	r := Range{Start: positionAt(text, link.Start), End: positionAt(text, link.End)}
+	return hover{Contents: markupContent{Kind: "markdown", Value: b.String()}, Range: &r}, nil
+}
+
+// definition writes the note linked at offset to the notes directory and
+// points the editor at it.
+func (s *Server) definition(text string, offset int) (any, error) {
+	link, ok := linkAt(text, offset)
+	if !ok {
+		return nil, nil
+	}
+	note, _, err := s.lookup(link)
+	if err != nil || note == nil {
+		return nil, err
+	}
+
+	path := filepath.Join(s.notesDir, note.ID.String()+".md")
+	if existing, err := os.ReadFile(path); err != nil || string(existing) != note.Content { //nolint:gosec // Path is under notesDir
+		if err := os.MkdirAll(s.notesDir, 0750); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(note.Content), 0600); err != nil {
+			return nil, err
+		}
+	}
+	return Location{URI: pathURI(path)}, nil
+}
+
+// saveNote copies a saved <id>.md file in the notes directory back into
+// its note. Saves of other files are ignored.
+func (s *Server) saveNote(uri string) error {
+	path, err := uriPath(uri)
+	if err != nil || filepath.Dir(path) != filepath.Clean(s.notesDir) {
+		return nil
+	}
+	id, err := uuid.Parse(strings.TrimSuffix(filepath.Base(path), ".md"))
+	if err != nil {
+		return nil
+	}
+
+	content, ok := s.docs[uri]
+	if !ok {
+		data, err := os.ReadFile(path) //nolint:gosec // Path is under notesDir
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		content = string(data)
+	}
+	note, tags, err := s.repo.GetNoteByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to get note: %w", err)
+	}
+	if note.Content == content {
+		return nil
+	}
+	if err := vault.CheckEdit(note.Title, note.Content); err != nil {
+		return err
+	}
+	note.Content = content
+	note.Touch()
+	if err := s.repo.UpdateNote(note, tags); err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+	return nil
+}
+
+// lookup finds the note a link points at: a [[wiki]] link by title
+// (case-insensitive), then any link by alias or ID prefix. It returns nil
+// when nothing matches.
+func (s *Server) lookup(link links.Link) (*models.Note, []string, error) {
+	target := strings.TrimSpace(link.Target)
+	if link.Kind == links.KindWiki {
+		notes, err := s.repo.ListNotesWithTags(&charm.NoteFilter{ContentLimit: charm.NoContent})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, nt := range notes {
+			if strings.EqualFold(strings.TrimSpace(nt.Note.Title), target) {
+				return s.repo.GetNoteByID(nt.Note.ID)
+			}
+		}
+	}
+	note, tags, err := s.repo.GetNoteByPrefix(target)
+	if errors.Is(err, charm.ErrNoteNotFound) || errors.Is(err, charm.ErrPrefixTooShort) || errors.Is(err, charm.ErrAmbiguousPrefix) {
+		return nil, nil, nil
+	}
+	return note, tags, err
+}
+
+// linkAt returns the memo:// or [[wiki]] link spanning offset.
+func linkAt(text string, offset int) (links.Link, bool) {
+	for _, l := range links.Find(text) {
+		if l.Kind != links.KindURL && offset >= l.Start && offset <= l.End {
+			return l, true
+		}
+	}
+	return links.Link{}, false
+}
+
+// noteDetail is a one-line summary of a note: short ID and tags.
+func noteDetail(note *models.Note, tags []string) string {
+	detail := "`" + note.ID.String()[:8] + "`"
+	for _, t := range tags {
+		if !strings.HasPrefix(strings.ToLower(t), "dir:") {
+			detail += " #" + t
+		}
+	}
+	return detail
+}
+
+func uriPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported URI %q", uri)
+	}
+	return filepath.FromSlash(u.Path), nil
+}
+
+func pathURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
@@ -0,0 +1,167 @@
+// ABOUTME: Tests for the language server over framed JSON-RPC against an in-memory repository.
+// ABOUTME: Covers completion, hover, go-to-definition, save-back, and UTF-16 positions.
+
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/harper/memo/internal/charm/charmtest"
+	"github.com/harper/memo/internal/models"
+)
+
+// session sends each request through Serve and returns the responses by ID.
+func session(t *testing.T, s *Server, msgs ...map[string]any) map[float64]map[string]any {
+	t.Helper()
+	var in bytes.Buffer
+	for _, m := range msgs {
+		m["jsonrpc"] = "2.0"
+		if err := writeMessage(&in, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out bytes.Buffer
+	if err := s.Serve(&in, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	responses := make(map[float64]map[string]any)
+	r := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(r)
+		if err != nil {
+			break
+		}
+		var resp map[string]any
+		if err := json.Unmarshal(body, &resp); err != nil {
+			t.Fatal(err)
+		}
+		if id, ok := resp["id"].(float64); ok {
+			responses[id] = resp
+		}
+	}
+	return responses
+}
+
+func open(uri, text string) map[string]any {
+	return map[string]any{"method": "textDocument/didOpen", "params": map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "markdown", "version": 1, "text": text},
+	}}
+}
+
+func at(id int, method, uri string, line, char int) map[string]any {
+	return map[string]any{"id": id, "method": method, "params": map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     map[string]any{"line": line, "character": char},
+	}}
+}
+
+func TestCompletionHoverDefinition(t *testing.T) {
+	store := charmtest.NewStore()
+	plan := models.NewNote("Q3 Plan", "# Goals\n\nship sync")
+	_ = store.CreateNote(plan, []string{"work"})
+	_ = store.CreateNote(models.NewNote("Groceries", "eggs"), nil)
+	dir := t.TempDir()
+	s := NewServer(store, dir)
+
+	const uri = "file:///tmp/today.md"
+	text := "See [[q3\nand [[Q3 Plan]] again"
+	got := session(t, s,
+		map[string]any{"id": 1, "method": "initialize", "params": map[string]any{}},
+		open(uri, text),
+		at(2, "textDocument/completion", uri, 0, 8),
+		at(3, "textDocument/hover", uri, 1, 8),
+		at(4, "textDocument/definition", uri, 1, 8),
+		at(5, "textDocument/hover", uri, 0, 1),
+		map[string]any{"id": 6, "method": "workspace/symbol", "params": map[string]any{}},
+		map[string]any{"method": "exit"},
+	)
+
+	caps := got[1]["result"].(map[string]any)["capabilities"].(map[string]any)
+	if caps["hoverProvider"] != true || caps["definitionProvider"] != true {
+		t.Errorf("capabilities = %v", caps)
+	}
+
+	items := got[2]["result"].(map[string]any)["items"].([]any)
+	if len(items) != 1 {
+		t.Fatalf("completion items = %v", items)
+	}
+	item := items[0].(map[string]any)
+	edit := item["textEdit"].(map[string]any)
+	start := edit["range"].(map[string]any)["start"].(map[string]any)
+	if item["label"] != "Q3 Plan" || edit["newText"] != "Q3 Plan]]" || start["character"] != float64(6) {
+		t.Errorf("completion item = %v", item)
+	}
+
+	value := got[3]["result"].(map[string]any)["contents"].(map[string]any)["value"].(string)
+	if !strings.Contains(value, "**Q3 Plan**") || !strings.Contains(value, "#work") || !strings.Contains(value, "ship sync") {
+		t.Errorf("hover = %q", value)
+	}
+
+	loc := got[4]["result"].(map[string]any)
+	path := filepath.Join(dir, plan.ID.String()+".md")
+	if loc["uri"] != "file://"+filepath.ToSlash(path) {
+		t.Errorf("definition = %v", loc)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != plan.Content {
+		t.Errorf("definition file = %q, %v", data, err)
+	}
+
+	if got[5]["result"] != nil {
+		t.Errorf("hover outside a link = %v", got[5]["result"])
+	}
+	if got[6]["error"] == nil {
+		t.Errorf("expected method not found, got %v", got[6])
+	}
+}
+
+func TestSaveUpdatesNote(t *testing.T) {
+	store := charmtest.NewStore()
+	note := models.NewNote("Trip", "day 1")
+	_ = store.CreateNote(note, []string{"travel"})
+	dir := t.TempDir()
+	s := NewServer(store, dir)
+
+	uri := pathURI(filepath.Join(dir, note.ID.String()+".md"))
+	session(t, s,
+		open(uri, "day 1"),
+		map[string]any{"method": "textDocument/didSave", "params": map[string]any{
+			"textDocument": map[string]any{"uri": uri},
+			"text":         "day 1\nday 2",
+		}},
+	)
+
+	got, tags, err := store.GetNoteByID(note.ID)
+	if err != nil || got.Content != "day 1\nday 2" || len(tags) != 1 {
+		t.Errorf("note after save = %+v %v, %v", got, tags, err)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	text := "héllo 😀 [[x\nnext"
+	for _, tt := range []struct {
+		pos    Position
+		offset int
+	}{
+		{Position{0, 0}, 0},
+		{Position{0, 2}, 3},  // After the two-byte é
+		{Position{0, 8}, 11}, // After the emoji, two UTF-16 units
+		{Position{0, 99}, 15},
+		{Position{1, 2}, 18},
+	} {
+		if got := offsetAt(text, tt.pos); got != tt.offset {
+			t.Errorf("offsetAt(%v) = %d, want %d", tt.pos, got, tt.offset)
+		}
+		if tt.pos.Character != 99 {
+			if got := positionAt(text, tt.offset); got != tt.pos {
+				t.Errorf("positionAt(%d) = %v, want %v", tt.offset, got, tt.pos)
+			}
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// ABOUTME: Builds MIME email messages for notes and delivers them by SMTP or sendmail.
+// ABOUTME: Messages carry a markdown text part, an HTML alternative, and file attachments.
+
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ErrNoRecipients is returned when a message has no To addresses.
+var ErrNoRecipients = errors.New("no recipients")
+
+// Attachment is a file sent with a message.
+type Attachment struct {
+	Filename string
+	MimeType string
+	Data     []byte
+}
+
+// Message is an email with a plain-text body and an optional HTML
+// alternative.
+type Message struct {
+	From        string
+	To          []string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+	Date        time.Time
+}
+
+// Bytes encodes the message as RFC 5322 text ready for delivery.
+func (m *Message) Bytes() ([]byte, error) {
+	if len(m.To) == 0 {
+		return nil, ErrNoRecipients
+	}
+	date := m.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var buf bytes.Buffer
+	header := func(k, v string) { fmt.Fprintf(&buf, "%s: %s\r\n", k, v) }
+	header("From", m.From)
+	header("To", strings.Join(m.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	top, err := m.body()
+	if err != nil {
+		return nil, err
+	}
+	writePartHeader(&buf, top)
+	buf.WriteString("\r\n")
+	buf.Write(top.body)
+	return buf.Bytes(), nil
+}
+
+// body returns the message's top-level part: the text alone, text and HTML
+// as alternatives, and those wrapped with attachments in a mixed multipart.
+func (m *Message) body() (part, error) {
+	content := textPart("text/plain", m.Text)
+	if m.HTML != "" {
+		alt, err := multipartOf("alternative", []part{content, textPart("text/html", m.HTML)})
+		if err != nil {
+			return part{}, err
+		}
+		content = alt
+	}
+	if len(m.Attachments) == 0 {
+		return content, nil
+	}
+
+	parts := []part{content}
+	for _, a := range m.Attachments {
+		parts = append(parts, attachmentPart(a))
+	}
+	return multipartOf("mixed", parts)
+}
+
+type part struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+func textPart(mediaType, text string) part {
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	_, _ = w.Write([]byte(text)) // Converts line breaks to CRLF
+	_ = w.Close()
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mediaType+"; charset=utf-8")
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	return part{header: h, body: buf.Bytes()}
+}
+
+func attachmentPart(a Attachment) part {
+	mimeType := a.MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mime.FormatMediaType(mimeType, map[string]string{"name": a.Filename}))
+	h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	h.Set("Content-Transfer-Encoding", "base64")
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	var buf bytes.Buffer
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded)
+	return part{header: h, body: buf.Bytes()}
+}
+
+// multipartOf joins parts into a multipart/<subtype> part.
+func multipartOf(subtype string, parts []part) (part, error) {
+	boundary, err := newBoundary()
+	if err != nil {
+		return part{}, err
+	}
+	var buf bytes.Buffer
+	for _, p := range parts {
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		writePartHeader(&buf, p)
+		buf.WriteString("\r\n")
+		buf.Write(p.body)
+		buf.WriteString("\r\n")
+	}
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": boundary}))
+	return part{header: h, body: buf.Bytes()}, nil
+}
+
+// writePartHeader writes a part's MIME headers in a fixed order.
+func writePartHeader(buf *bytes.Buffer, p part) {
+	for _, k := range []string{"Content-Type", "Content-Disposition", "Content-Transfer-Encoding"} {
+		if v := p.header.Get(k); v != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", k, v)
+		}
+	}
+}
+
+func newBoundary() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "memo-" + hex.EncodeToString(b), nil
+}
+
+// Sender delivers a message.
+type Sender interface {
+	Send(m *Message) error
+}
+
+// SMTP delivers through an SMTP server, using STARTTLS when offered and
+// PLAIN auth when a username is set.
+type SMTP struct {
+	Addr     string // host:port
+	Username string
+	Password string
+}
+
+// Send delivers m.
+func (s *SMTP) Send(m *Message) error {
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, err := net.SplitHostPort(s.Addr)
+		if err != nil {
+			return fmt.Errorf("smtp address %q: %w", s.Addr, err)
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	from, to, err := envelope(m)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(s.Addr, auth, from, to, data)
+}
+
+// Sendmail delivers by piping the message to a sendmail-compatible
+// program, which reads the recipients from the headers.
+type Sendmail struct {
+	Path string
+}
+
+// Send delivers m.
+func (s *Sendmail) Send(m *Message) error {
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+	path := s.Path
+	if path == "" {
+		path = "sendmail"
+	}
+	cmd := exec.Command(path, "-t", "-i") //nolint:gosec // Path comes from the user's config
+	cmd.Stdin = bytes.NewReader(data)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", path, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// envelope extracts the bare sender and recipient addresses SMTP needs.
+func envelope(m *Message) (string, []string, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid sender %q: %w", m.From, err)
+	}
+	to := make([]string, len(m.To))
+	for i, addr := range m.To {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+		to[i] = a.Address
+	}
+	return from.Address, to, nil
+}
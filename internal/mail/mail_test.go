@@ -0,0 +1,98 @@
+// ABOUTME: Tests for MIME message construction.
+// ABOUTME: Parses built messages back with net/mail and mime/multipart.
+
+package mail
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestMessageBytes(t *testing.T) {
+	m := &Message{
+		From:        "Memo <memo@example.com>",
+		To:          []string{"a@example.com", "b@example.com"},
+		Subject:     "Standup — Monday",
+		Text:        "# Standup\n\n- shipped",
+		HTML:        "<h1>Standup</h1>",
+		Attachments: []Attachment{{Filename: "plan.pdf", MimeType: "application/pdf", Data: []byte("%PDF-1.7")}},
+	}
+	data, err := m.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subject != m.Subject {
+		t.Errorf("Subject = %q", subject)
+	}
+	if to := msg.Header.Get("To"); to != "a@example.com, b@example.com" {
+		t.Errorf("To = %q", to)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mediaType != "multipart/mixed" {
+		t.Fatalf("Content-Type = %q", mediaType)
+	}
+	mixed := multipart.NewReader(msg.Body, params["boundary"])
+
+	alt, err := mixed.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	altType, altParams, _ := mime.ParseMediaType(alt.Header.Get("Content-Type"))
+	if altType != "multipart/alternative" {
+		t.Fatalf("first part = %q", altType)
+	}
+	var bodies []string
+	altReader := multipart.NewReader(alt, altParams["boundary"])
+	for {
+		p, err := altReader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(quotedprintable.NewReader(p))
+		bodies = append(bodies, p.Header.Get("Content-Type")+"|"+string(b))
+	}
+	want := []string{"text/plain; charset=utf-8|# Standup\r\n\r\n- shipped", "text/html; charset=utf-8|<h1>Standup</h1>"}
+	if strings.Join(bodies, "\n") != strings.Join(want, "\n") {
+		t.Errorf("alternative parts = %q, want %q", bodies, want)
+	}
+
+	att, err := mixed.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if att.FileName() != "plan.pdf" {
+		t.Errorf("attachment filename = %q", att.FileName())
+	}
+}
+
+func TestMessagePlainText(t *testing.T) {
+	data, err := (&Message{From: "memo@example.com", To: []string{"a@example.com"}, Text: "hi"}).Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := msg.Header.Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	if _, err := (&Message{From: "memo@example.com"}).Bytes(); !errors.Is(err, ErrNoRecipients) {
+		t.Errorf("expected ErrNoRecipients, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+// ABOUTME: Parses incoming RFC 822 messages into a subject, text body, and attachments.
+// ABOUTME: Walks nested multiparts, decodes transfer encodings and charsets, and falls back to stripped HTML.
+
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// ErrEmptyMessage is returned when a message has neither a body nor a subject.
+var ErrEmptyMessage = errors.New("message has no subject or body")
+
+// maxPartSize bounds each decoded part so a huge message can't exhaust memory.
+const maxPartSize = 64 << 20
+
+// Incoming is a parsed email.
+type Incoming struct {
+	From        string
+	Subject     string
+	Date        time.Time
+	Text        string
+	Attachments []Attachment
+}
+
+var headerDecoder = &mime.WordDecoder{CharsetReader: charsetReader}
+
+// Parse reads an RFC 822 message. The body is the first text/plain part,
+// or the first text/html part with tags removed; other parts with a
+// filename, and non-text parts, become attachments.
+func Parse(r io.Reader) (*Incoming, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, fmt.Errorf("read message: %w", err)
+	}
+
+	in := &Incoming{}
+	in.Subject, _ = headerDecoder.DecodeHeader(msg.Header.Get("Subject"))
+	in.Subject = strings.TrimSpace(in.Subject)
+	in.From, _ = headerDecoder.DecodeHeader(msg.Header.Get("From"))
+	in.Date, _ = msg.Header.Date()
+
+	var htmlBody string
+	err = walkPart(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), "", msg.Body,
+		func(mediaType, filename string, params map[string]string, data []byte) error {
+			switch {
+			case filename == "" && mediaType == "text/plain" && in.Text == "":
+				text, err := decodeCharset(data, params["charset"])
+				if err != nil {
+					return err
+				}
+				in.Text = text
+			case filename == "" && mediaType == "text/html" && htmlBody == "":
+				text, err := decodeCharset(data, params["charset"])
+				if err != nil {
+					return err
+				}
+				htmlBody = text
+			case filename != "" || !strings.HasPrefix(mediaType, "text/"):
+				if filename == "" {
+					filename = "attachment"
+					if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+						filename += exts[0]
+					}
+				}
+				in.Attachments = append(in.Attachments, Attachment{Filename: filename, MimeType: mediaType, Data: data})
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(in.Text) == "" && htmlBody != "" {
+		in.Text = StripHTML(htmlBody)
+	}
+	in.Text = strings.TrimSpace(strings.ReplaceAll(in.Text, "\r\n", "\n"))
+	if in.Text == "" && in.Subject == "" {
+		return nil, ErrEmptyMessage
+	}
+	return in, nil
+}
+
+// walkPart decodes a part and calls leaf for each non-multipart part in
+// order.
+func walkPart(contentType, encoding, disposition string, body io.Reader,
+	leaf func(mediaType, filename string, params map[string]string, data []byte) error) error {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			p, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("read %s part: %w", mediaType, err)
+			}
+			if err := walkPart(p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"),
+				p.Header.Get("Content-Disposition"), p, leaf); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(decodeTransfer(body, encoding), maxPartSize))
+	if err != nil {
+		return fmt.Errorf("decode %s part: %w", mediaType, err)
+	}
+	filename := params["name"]
+	if _, dparams, err := mime.ParseMediaType(disposition); err == nil && dparams["filename"] != "" {
+		filename = dparams["filename"]
+	}
+	if filename != "" {
+		filename, _ = headerDecoder.DecodeHeader(filename)
+	}
+	return leaf(mediaType, filename, params, data)
+}
+
+func decodeTransfer(r io.Reader, encoding string) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newlineStripper{r})
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+// newlineStripper drops CR and LF so wrapped base64 decodes.
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n newlineStripper) Read(p []byte) (int, error) {
+	for {
+		count, err := n.r.Read(p)
+		kept := 0
+		for _, b := range p[:count] {
+			if b != '\r' && b != '\n' {
+				p[kept] = b
+				kept++
+			}
+		}
+		if kept > 0 || err != nil {
+			return kept, err
+		}
+	}
+}
+
+func decodeCharset(data []byte, charset string) (string, error) {
+	r, err := charsetReader(charset, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	out, err := io.ReadAll(r)
+	return string(out), err
+}
+
+// charsetReader converts text in charset to UTF-8. Unknown charsets are
+// passed through unchanged rather than failing the whole message.
+func charsetReader(charset string, r io.Reader) (io.Reader, error) {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	if charset == "" || charset == "utf-8" || charset == "us-ascii" {
+		return r, nil
+	}
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return r, nil
+	}
+	return enc.NewDecoder().Reader(r), nil
+}
+
+var (
+	htmlDropped = regexp.MustCompile(`(?is)<(script|style|head)[^>]*>.*?</(script|style|head)>`)
+	htmlBreak   = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|h[1-6]|tr)>`)
+	htmlTag     = regexp.MustCompile(`<[^>]*>`)
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+)
+
+// StripHTML reduces an HTML email body to readable text.
+func StripHTML(s string) string {
+	s = htmlDropped.ReplaceAllString(s, "")
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
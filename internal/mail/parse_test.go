@@ -0,0 +1,80 @@
+// ABOUTME: Tests for parsing incoming email.
+// ABOUTME: Covers multipart bodies, transfer encodings, charsets, HTML fallback, and attachments.
+
+package mail
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func crlf(s string) string {
+	return strings.ReplaceAll(s, "\n", "\r\n")
+}
+
+func TestParseMultipart(t *testing.T) {
+	raw := crlf(`From: Alex <alex@example.com>
+Subject: =?utf-8?q?Caf=C3=A9_idea?=
+Date: Mon, 12 Oct 2026 09:30:00 -0700
+MIME-Version: 1.0
+Content-Type: multipart/mixed; boundary="outer"
+
+--outer
+Content-Type: multipart/alternative; boundary="inner"
+
+--inner
+Content-Type: text/plain; charset=iso-8859-1
+Content-Transfer-Encoding: quoted-printable
+
+Open a caf=E9 by the
+river.
+--inner
+Content-Type: text/html
+
+<p>ignored</p>
+--inner--
+--outer
+Content-Type: image/png; name="sketch.png"
+Content-Disposition: attachment; filename="sketch.png"
+Content-Transfer-Encoding: base64
+
+iVBORw0K
+GgA=
+--outer--
+`)
+	in, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Subject != "Café idea" || in.From != "Alex <alex@example.com>" || in.Date.IsZero() {
+		t.Errorf("unexpected headers: %+v", in)
+	}
+	if in.Text != "Open a café by the\nriver." {
+		t.Errorf("Text = %q", in.Text)
+	}
+	if len(in.Attachments) != 1 || in.Attachments[0].Filename != "sketch.png" || string(in.Attachments[0].Data) != "\x89PNG\r\n\x1a\x00" {
+		t.Errorf("unexpected attachments: %+v", in.Attachments)
+	}
+}
+
+func TestParseHTMLOnly(t *testing.T) {
+	raw := crlf(`Subject: Link
+Content-Type: text/html; charset=utf-8
+
+<html><head><style>p{}</style></head><body><p>Read this &amp; that</p><br>later</body></html>
+`)
+	in, err := Parse(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Text != "Read this & that\n\nlater" {
+		t.Errorf("Text = %q", in.Text)
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	if _, err := Parse(strings.NewReader("From: a@example.com\r\n\r\n  \r\n")); !errors.Is(err, ErrEmptyMessage) {
+		t.Errorf("expected ErrEmptyMessage, got %v", err)
+	}
+}
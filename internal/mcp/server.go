@@ -5,6 +5,7 @@ package mcp
 
 import (
 	"context"
+	"sync"
 
 	"github.com/harper/memo/internal/charm"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -12,10 +13,17 @@ import (
 
 type Server struct {
 	server *mcp.Server
-	client *charm.Client
+	client charm.Repository
+	mu     sync.Mutex // Serializes tool calls so each change is credited to its call
 }
 
-func NewServer(client *charm.Client) *Server {
+// actorSetter is implemented by stores that record who changes notes in an
+// audit log.
+type actorSetter interface {
+	SetActor(a charm.Actor)
+}
+
+func NewServer(client charm.Repository) *Server {
 	s := &Server{client: client}
 
 	s.server = mcp.NewServer(
@@ -33,10 +41,37 @@ func NewServer(client *charm.Client) *Server {
 	s.registerTools()
 	s.registerResources()
 	s.registerPrompts()
+	s.server.AddReceivingMiddleware(s.auditActor)
 
 	return s
 }
 
+// auditActor credits changes made by a tool call to the tool and the
+// calling client in the store's audit log.
+func (s *Server) auditActor(next mcp.MethodHandler) mcp.MethodHandler {
+	store, ok := s.client.(actorSetter)
+	if !ok {
+		return next
+	}
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if !ok || call.Params == nil {
+			return next(ctx, method, req)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		actor := charm.Actor{Source: charm.SourceMCP, Command: call.Params.Name}
+		if call.Session != nil {
+			if params := call.Session.InitializeParams(); params != nil && params.ClientInfo != nil {
+				actor.Client = params.ClientInfo.Name
+			}
+		}
+		store.SetActor(actor)
+		return next(ctx, method, req)
+	}
+}
+
 func (s *Server) Serve(ctx context.Context) error {
 	return s.server.Run(ctx, &mcp.StdioTransport{})
 }
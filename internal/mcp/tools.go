@@ -13,7 +13,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/mdsection"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/vault"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -42,7 +44,10 @@ func (s *Server) registerTools() {
 			"type": "object",
 			"properties": {
 				"tag": {"type": "string", "description": "Filter by tag"},
-				"limit": {"type": "integer", "description": "Max results", "default": 20}
+				"untagged": {"type": "boolean", "description": "Only notes with no tags (directory tags don't count)", "default": false},
+				"limit": {"type": "integer", "description": "Max results", "default": 20},
+				"offset": {"type": "integer", "description": "Skip this many notes, to fetch the next page", "default": 0},
+				"content_chars": {"type": "integer", "description": "Cap each note's content at this many characters (-1 omits content, 0 returns it all)", "default": 0}
 			}
 		}`),
 	}, s.handleListNotes)
@@ -50,11 +55,12 @@ func (s *Server) registerTools() {
 	// get_note
 	s.server.AddTool(&mcp.Tool{
 		Name:        "get_note",
-		Description: "Get a note by ID prefix",
+		Description: "Get a note by ID prefix, optionally only one markdown section of it",
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
-				"id": {"type": "string", "description": "Note ID or prefix (6+ chars)"}
+				"id": {"type": "string", "description": "Note ID or prefix (6+ chars)"},
+				"section": {"type": "string", "description": "Return only this heading and its subsections as the content"}
 			},
 			"required": ["id"]
 		}`),
@@ -227,8 +233,11 @@ func (s *Server) handleAddNote(ctx context.Context, req *mcp.CallToolRequest) (*
 
 func (s *Server) handleListNotes(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params struct {
-		Tag   *string `json:"tag"`
-		Limit int     `json:"limit"`
+		Tag          *string `json:"tag"`
+		Untagged     bool    `json:"untagged"`
+		Limit        int     `json:"limit"`
+		Offset       int     `json:"offset"`
+		ContentChars int     `json:"content_chars"`
 	}
 	params.Limit = 20 // default
 	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
@@ -236,8 +245,11 @@ func (s *Server) handleListNotes(ctx context.Context, req *mcp.CallToolRequest)
 	}
 
 	filter := &charm.NoteFilter{
-		Tag:   params.Tag,
-		Limit: params.Limit,
+		Tag:          params.Tag,
+		Untagged:     params.Untagged,
+		Limit:        params.Limit,
+		Offset:       params.Offset,
+		ContentLimit: params.ContentChars,
 	}
 	notes, err := s.client.ListNotes(filter)
 	if err != nil {
@@ -267,7 +279,8 @@ func (s *Server) handleListNotes(ctx context.Context, req *mcp.CallToolRequest)
 
 func (s *Server) handleGetNote(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	var params struct {
-		ID string `json:"id"`
+		ID      string `json:"id"`
+		Section string `json:"section"`
 	}
 	if err := json.Unmarshal(req.Params.Arguments, &params); err != nil {
 		return nil, err
@@ -293,6 +306,19 @@ func (s *Server) handleGetNote(ctx context.Context, req *mcp.CallToolRequest) (*
 		}, nil
 	}
 
+	if params.Section != "" {
+		section, err := mdsection.Extract(note.Content, params.Section)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: fmt.Sprintf("failed to get section %q: %v", params.Section, err)},
+				},
+				IsError: true,
+			}, nil
+		}
+		note.Content = section
+	}
+
 	data, err := json.MarshalIndent(note, "", "  ")
 	if err != nil {
 		return &mcp.CallToolResult{
@@ -350,6 +376,14 @@ func (s *Server) handleUpdateNote(ctx context.Context, req *mcp.CallToolRequest)
 				IsError: true,
 			}, nil
 		}
+		if err := vault.CheckEdit(note.Title, note.Content); err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					&mcp.TextContent{Text: err.Error()},
+				},
+				IsError: true,
+			}, nil
+		}
 		note.Content = *params.Content
 	}
 	note.UpdatedAt = time.Now()
@@ -630,6 +664,7 @@ func (s *Server) handleListAttachments(ctx context.Context, req *mcp.CallToolReq
 		Filename  string `json:"filename"`
 		MimeType  string `json:"mime_type"`
 		CreatedAt string `json:"created_at"`
+		Missing   bool   `json:"missing,omitempty"` // Data isn't on this device
 	}
 	infos := make([]AttachmentInfo, len(attachments))
 	for i, att := range attachments {
@@ -639,6 +674,7 @@ func (s *Server) handleListAttachments(ctx context.Context, req *mcp.CallToolReq
 			Filename:  att.Filename,
 			MimeType:  att.MimeType,
 			CreatedAt: att.CreatedAt.Format(time.RFC3339),
+			Missing:   att.Missing,
 		}
 	}
 
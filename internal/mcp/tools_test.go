@@ -0,0 +1,206 @@
+// ABOUTME: Tests for MCP tool handlers against an in-memory repository.
+// ABOUTME: Exercises note CRUD and tagging without a real Charm KV store.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harper/memo/internal/charm/charmtest"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/vault"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// callTool invokes a tool handler with JSON arguments.
+func callTool(t *testing.T, handler func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error), args string) *mcp.CallToolResult {
+	t.Helper()
+	result, err := handler(context.Background(), &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{Arguments: json.RawMessage(args)},
+	})
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	return result
+}
+
+// resultText returns the text of the first content item.
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	if len(result.Content) == 0 {
+		t.Fatal("expected content in result")
+	}
+	text, ok := result.Content[0].(*mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	return text.Text
+}
+
+func TestHandleAddNote(t *testing.T) {
+	store := charmtest.NewStore()
+	s := NewServer(store)
+
+	result := callTool(t, s.handleAddNote, `{"title": "Standup", "content": "notes", "tags": ["work"]}`)
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", resultText(t, result))
+	}
+
+	notes, _ := store.ListNotes(nil)
+	if len(notes) != 1 || notes[0].Title != "Standup" {
+		t.Fatalf("expected one note titled Standup, got %v", notes)
+	}
+}
+
+func TestHandleAddNoteEmptyContent(t *testing.T) {
+	s := NewServer(charmtest.NewStore())
+
+	result := callTool(t, s.handleAddNote, `{"title": "Empty", "content": "   "}`)
+	if !result.IsError {
+		t.Error("expected error for empty content")
+	}
+}
+
+func TestHandleUpdateAndDeleteNote(t *testing.T) {
+	store := charmtest.NewStore()
+	s := NewServer(store)
+	note := models.NewNote("Draft", "first")
+	_ = store.CreateNote(note, nil)
+	prefix := note.ID.String()[:8]
+
+	result := callTool(t, s.handleUpdateNote, `{"id": "`+prefix+`", "content": "second"}`)
+	if result.IsError {
+		t.Fatalf("update failed: %s", resultText(t, result))
+	}
+	updated, _, _ := store.GetNoteByID(note.ID)
+	if updated.Content != "second" {
+		t.Errorf("expected updated content, got %q", updated.Content)
+	}
+
+	result = callTool(t, s.handleDeleteNote, `{"id": "`+prefix+`"}`)
+	if result.IsError {
+		t.Fatalf("delete failed: %s", resultText(t, result))
+	}
+	if _, _, err := store.GetNoteByID(note.ID); err == nil {
+		t.Error("expected note to be deleted")
+	}
+}
+
+func TestHandleUpdateSealedNote(t *testing.T) {
+	store := charmtest.NewStore()
+	s := NewServer(store)
+	sealed, err := vault.Seal("api key", []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	note := models.NewNote("Keys", sealed)
+	_ = store.CreateNote(note, nil)
+
+	result := callTool(t, s.handleUpdateNote, `{"id": "`+note.ID.String()+`", "content": `+strconv.Quote(sealed+"\n- appended")+`}`)
+	if !result.IsError || !strings.Contains(resultText(t, result), "in the vault") {
+		t.Fatalf("expected a vault error, got %+v", result)
+	}
+	updated, _, _ := store.GetNoteByID(note.ID)
+	if _, err := vault.Open(updated.Content, []byte("pw")); err != nil {
+		t.Errorf("sealed note no longer opens: %v", err)
+	}
+}
+
+func TestHandleTagsAndSearch(t *testing.T) {
+	store := charmtest.NewStore()
+	s := NewServer(store)
+	note := models.NewNote("Go Programming", "Learn about goroutines")
+	_ = store.CreateNote(note, nil)
+	_ = store.CreateNote(models.NewNote("Cooking", "pasta"), nil)
+
+	result := callTool(t, s.handleAddTag, `{"id": "`+note.ID.String()+`", "tag": "Learning"}`)
+	if result.IsError {
+		t.Fatalf("add tag failed: %s", resultText(t, result))
+	}
+	tags, _ := store.GetNoteTags(note.ID)
+	if len(tags) != 1 || tags[0] != "learning" {
+		t.Errorf("expected normalized tag, got %v", tags)
+	}
+
+	text := resultText(t, callTool(t, s.handleSearchNotes, `{"query": "goroutines"}`))
+	if !strings.Contains(text, "Go Programming") || strings.Contains(text, "Cooking") {
+		t.Errorf("unexpected search results: %s", text)
+	}
+}
+
+func TestHandleListNotesPages(t *testing.T) {
+	store := charmtest.NewStore()
+	s := NewServer(store)
+	base := time.Now()
+	for i, title := range []string{"Oldest", "Middle", "Newest"} {
+		note := models.NewNote(title, "content")
+		note.UpdatedAt = base.Add(time.Duration(i) * time.Minute)
+		_ = store.CreateNote(note, nil)
+	}
+
+	var page []*models.Note
+	text := resultText(t, callTool(t, s.handleListNotes, `{"limit": 2, "offset": 1}`))
+	if err := json.Unmarshal([]byte(text), &page); err != nil {
+		t.Fatalf("invalid result: %v", err)
+	}
+	if len(page) != 2 || page[0].Title != "Middle" || page[1].Title != "Oldest" {
+		t.Errorf("expected second and third newest notes, got %s", text)
+	}
+
+	text = resultText(t, callTool(t, s.handleListNotes, `{"offset": 5}`))
+	if strings.TrimSpace(text) != "null" && strings.TrimSpace(text) != "[]" {
+		t.Errorf("expected empty page past the end, got %s", text)
+	}
+}
+
+func TestHandleListNotesUntagged(t *testing.T) {
+	store := charmtest.NewStore()
+	s := NewServer(store)
+	_ = store.CreateNote(models.NewNote("Tagged", "content"), []string{"work"})
+	_ = store.CreateNote(models.NewNote("Loose", "content"), nil)
+
+	var notes []*models.Note
+	text := resultText(t, callTool(t, s.handleListNotes, `{"untagged": true}`))
+	if err := json.Unmarshal([]byte(text), &notes); err != nil {
+		t.Fatalf("invalid result: %v", err)
+	}
+	if len(notes) != 1 || notes[0].Title != "Loose" {
+		t.Errorf("expected only the untagged note, got %s", text)
+	}
+}
+
+func TestHandleGetNoteSection(t *testing.T) {
+	store := charmtest.NewStore()
+	s := NewServer(store)
+	note := models.NewNote("Standup", "# Standup\n\n## Action Items\n\n- ship docs\n\n## Notes\n\nquiet week\n")
+	_ = store.CreateNote(note, nil)
+
+	var got models.Note
+	text := resultText(t, callTool(t, s.handleGetNote, fmt.Sprintf(`{"id": %q, "section": "action items"}`, note.ID)))
+	if err := json.Unmarshal([]byte(text), &got); err != nil {
+		t.Fatalf("invalid result: %v", err)
+	}
+	if want := "## Action Items\n\n- ship docs\n"; got.Content != want {
+		t.Errorf("expected section content %q, got %q", want, got.Content)
+	}
+
+	result := callTool(t, s.handleGetNote, fmt.Sprintf(`{"id": %q, "section": "Missing"}`, note.ID))
+	if !result.IsError {
+		t.Error("expected error for missing section")
+	}
+}
+
+func TestHandleGetNoteNotFound(t *testing.T) {
+	s := NewServer(charmtest.NewStore())
+
+	result := callTool(t, s.handleGetNote, `{"id": "abcdef"}`)
+	if !result.IsError {
+		t.Error("expected error for missing note")
+	}
+}
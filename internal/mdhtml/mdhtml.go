@@ -0,0 +1,49 @@
+// ABOUTME: Renders note markdown to HTML for sharing outside the terminal.
+// ABOUTME: Uses goldmark with GitHub-flavored extensions and wraps output in a standalone page.
+
+package mdhtml
+
+import (
+	"bytes"
+	"html"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// Render converts markdown to an HTML fragment. Raw HTML in the markdown is
+// omitted, so rendered notes are safe to publish.
+func Render(content string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(content), &buf); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Document wraps an HTML fragment in a minimal standalone page.
+func Document(title, body string) string {
+	return `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>` + html.EscapeString(title) + `</title>
+<style>body{max-width:46em;margin:2em auto;padding:0 1em;font-family:system-ui,sans-serif;line-height:1.5}pre{overflow-x:auto;background:#f6f8fa;padding:.8em}code{font-size:.9em}table{border-collapse:collapse}td,th{border:1px solid #ddd;padding:.3em .6em}</style>
+</head>
+<body>
+` + body + `</body>
+</html>
+`
+}
+
+// RenderDocument renders a note as a standalone HTML page titled title.
+func RenderDocument(title, content string) (string, error) {
+	body, err := Render(content)
+	if err != nil {
+		return "", err
+	}
+	return Document(title, body), nil
+}
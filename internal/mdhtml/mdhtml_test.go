@@ -0,0 +1,34 @@
+// ABOUTME: Tests for markdown to HTML rendering.
+// ABOUTME: Validates GFM extensions, raw HTML stripping, and page wrapping.
+
+package mdhtml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	got, err := Render("# Plan\n\n- [x] done\n\n| a | b |\n|---|---|\n| 1 | 2 |\n\n<script>alert(1)</script>\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<h1>Plan</h1>", `type="checkbox"`, "<table>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<script>") {
+		t.Errorf("Render kept raw HTML:\n%s", got)
+	}
+}
+
+func TestRenderDocument(t *testing.T) {
+	got, err := RenderDocument("Q3 <draft>", "Hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "<title>Q3 &lt;draft&gt;</title>") || !strings.Contains(got, "<p>Hello</p>") {
+		t.Errorf("unexpected document:\n%s", got)
+	}
+}
@@ -0,0 +1,96 @@
+// ABOUTME: Fenced code block extraction for using notes as a snippet store.
+// ABOUTME: Finds ``` and ~~~ blocks with their info-string language and one-based line.
+
+package mdsection
+
+import "strings"
+
+// CodeBlock is a fenced code block found in a document.
+type CodeBlock struct {
+	Lang string // First word of the info string, lowercased; "" if none
+	Code string // Block content with a trailing newline, fences excluded
+	Line int    // One-based line of the opening fence
+}
+
+// CodeBlocks returns the document's fenced code blocks in order. An
+// unclosed fence runs to the end of the document, as in CommonMark.
+func CodeBlocks(content string) []CodeBlock {
+	var blocks []CodeBlock
+	var current *CodeBlock
+	var fence string
+	var indent int
+	var body []string
+
+	for i, line := range strings.Split(content, "\n") {
+		if current == nil {
+			f, lang, n, ok := openingFence(line)
+			if ok {
+				current = &CodeBlock{Lang: lang, Line: i + 1}
+				fence, indent, body = f, n, nil
+			}
+			continue
+		}
+		if closesFence(line, fence) {
+			current.Code = joinCode(body)
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		// Content loses up to the opening fence's indentation
+		trim := 0
+		for trim < indent && trim < len(line) && line[trim] == ' ' {
+			trim++
+		}
+		body = append(body, line[trim:])
+	}
+	if current != nil {
+		for len(body) > 0 && body[len(body)-1] == "" {
+			body = body[:len(body)-1]
+		}
+		current.Code = joinCode(body)
+		blocks = append(blocks, *current)
+	}
+	return blocks
+}
+
+func joinCode(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// openingFence recognizes a code fence: up to three spaces of indent and at
+// least three backticks or tildes, followed by an optional info string.
+func openingFence(line string) (fence, lang string, indent int, ok bool) {
+	indent = len(line) - len(strings.TrimLeft(line, " "))
+	if indent > 3 {
+		return "", "", 0, false
+	}
+	rest := line[indent:]
+	if rest == "" || (rest[0] != '`' && rest[0] != '~') {
+		return "", "", 0, false
+	}
+	n := len(rest) - len(strings.TrimLeft(rest, rest[:1]))
+	if n < 3 {
+		return "", "", 0, false
+	}
+	info := strings.TrimSpace(rest[n:])
+	if rest[0] == '`' && strings.Contains(info, "`") {
+		return "", "", 0, false
+	}
+	if fields := strings.Fields(info); len(fields) > 0 {
+		lang = strings.ToLower(strings.Trim(fields[0], "{}."))
+	}
+	return rest[:n], lang, indent, true
+}
+
+// closesFence reports whether line closes a block opened with fence: the
+// same character, at least as many times, and nothing else.
+func closesFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	if len(line)-len(strings.TrimLeft(line, " ")) > 3 || len(trimmed) < len(fence) {
+		return false
+	}
+	return strings.Trim(trimmed, fence[:1]) == ""
+}
@@ -0,0 +1,38 @@
+// ABOUTME: Tests for fenced code block extraction.
+// ABOUTME: Covers languages, tilde and longer fences, indentation, and unclosed blocks.
+
+package mdsection
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCodeBlocks(t *testing.T) {
+	content := "# Snippets\n" +
+		"```go\nfunc main() {}\n```\n" +
+		"text\n" +
+		"~~~ Bash {title=x}\necho hi\n```\nstill bash\n~~~~\n" +
+		"  ```\n  indented\n    more\n  ```\n" +
+		"````markdown\n```py\nnested\n```\n````\n" +
+		"```\n```\n" +
+		"``` js\nunclosed()\n\n"
+
+	want := []CodeBlock{
+		{Lang: "go", Code: "func main() {}\n", Line: 2},
+		{Lang: "bash", Code: "echo hi\n```\nstill bash\n", Line: 6},
+		{Lang: "", Code: "indented\n  more\n", Line: 11},
+		{Lang: "markdown", Code: "```py\nnested\n```\n", Line: 15},
+		{Lang: "", Code: "", Line: 20},
+		{Lang: "js", Code: "unclosed()\n", Line: 22},
+	}
+	if got := CodeBlocks(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("CodeBlocks =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestCodeBlocksNone(t *testing.T) {
+	if got := CodeBlocks("just `inline` code\n``\nnot a fence\n"); len(got) != 0 {
+		t.Errorf("expected no blocks, got %+v", got)
+	}
+}
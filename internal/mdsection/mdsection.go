@@ -0,0 +1,107 @@
+// ABOUTME: Markdown heading parsing and section extraction for notes.
+// ABOUTME: Finds ATX headings outside code fences and returns a heading's subtree.
+
+package mdsection
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNotFound is returned when no heading matches the requested section.
+var ErrNotFound = errors.New("section not found")
+
+// Heading is an ATX heading ("## Title") found in a document.
+type Heading struct {
+	Level int
+	Text  string
+	Line  int // Zero-based line index
+}
+
+// Headings returns the document's headings in order, skipping lines inside
+// fenced code blocks.
+func Headings(content string) []Heading {
+	var headings []Heading
+	var fence string
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if f := fenceMarker(trimmed); f != "" {
+			fence = f
+			continue
+		}
+		if level, text, ok := parseHeading(line); ok {
+			headings = append(headings, Heading{Level: level, Text: text, Line: i})
+		}
+	}
+	return headings
+}
+
+// Extract returns the section under the first heading whose text matches
+// name, ignoring case and surrounding whitespace. The section runs from the
+// heading line up to the next heading of the same or a higher level, so it
+// includes any subheadings.
+func Extract(content, name string) (string, error) {
+	want := strings.ToLower(strings.TrimSpace(name))
+	headings := Headings(content)
+	lines := strings.Split(content, "\n")
+
+	for i, h := range headings {
+		if strings.ToLower(h.Text) != want {
+			continue
+		}
+		end := len(lines)
+		for _, next := range headings[i+1:] {
+			if next.Level <= h.Level {
+				end = next.Line
+				break
+			}
+		}
+		section := strings.Join(lines[h.Line:end], "\n")
+		return strings.TrimRight(section, "\n") + "\n", nil
+	}
+	return "", ErrNotFound
+}
+
+// parseHeading recognizes an ATX heading: up to three spaces of indent, one
+// to six '#', then a space or end of line. Closing '#'s are dropped.
+func parseHeading(line string) (int, string, bool) {
+	indent := len(line) - len(strings.TrimLeft(line, " "))
+	if indent > 3 {
+		return 0, "", false
+	}
+	line = line[indent:]
+
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 {
+		return 0, "", false
+	}
+	rest := line[level:]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return 0, "", false
+	}
+
+	text := strings.TrimSpace(rest)
+	if trimmed := strings.TrimRight(text, "#"); trimmed == "" || strings.HasSuffix(trimmed, " ") {
+		text = strings.TrimSpace(trimmed)
+	}
+	return level, text, true
+}
+
+// fenceMarker returns the fence that opens a code block on line, if any.
+func fenceMarker(line string) string {
+	for _, f := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, f) {
+			return f
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+// ABOUTME: Tests for markdown section extraction.
+// ABOUTME: Validates heading parsing, code fences, and subtree boundaries.
+
+package mdsection
+
+import (
+	"errors"
+	"testing"
+)
+
+const doc = `# Standup
+
+Intro text.
+
+## Action Items
+
+- [ ] ship docs
+
+### Owners
+
+Harper
+
+## Notes ##
+
+` + "```" + `
+# not a heading
+` + "```" + `
+Done.
+`
+
+func TestHeadings(t *testing.T) {
+	got := Headings(doc)
+	want := []Heading{
+		{Level: 1, Text: "Standup", Line: 0},
+		{Level: 2, Text: "Action Items", Line: 4},
+		{Level: 3, Text: "Owners", Line: 8},
+		{Level: 2, Text: "Notes", Line: 12},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d headings, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("heading %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExtract(t *testing.T) {
+	got, err := Extract(doc, "action items")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Action Items\n\n- [ ] ship docs\n\n### Owners\n\nHarper\n"
+	if got != want {
+		t.Errorf("Extract = %q, want %q", got, want)
+	}
+
+	got, err = Extract(doc, "Notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "## Notes ##\n\n```\n# not a heading\n```\nDone.\n"; got != want {
+		t.Errorf("Extract last section = %q, want %q", got, want)
+	}
+
+	if _, err := Extract(doc, "not a heading"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for heading inside a code fence, got %v", err)
+	}
+}
+
+func TestParseHeading(t *testing.T) {
+	tests := []struct {
+		line  string
+		level int
+		text  string
+		ok    bool
+	}{
+		{"# Title", 1, "Title", true},
+		{"   ### Deep", 3, "Deep", true},
+		{"#hashtag", 0, "", false},
+		{"    # code", 0, "", false},
+		{"####### seven", 0, "", false},
+		{"## C# tips", 2, "C# tips", true},
+	}
+	for _, tt := range tests {
+		level, text, ok := parseHeading(tt.line)
+		if level != tt.level || text != tt.text || ok != tt.ok {
+			t.Errorf("parseHeading(%q) = %d %q %v, want %d %q %v", tt.line, level, text, ok, tt.level, tt.text, tt.ok)
+		}
+	}
+}
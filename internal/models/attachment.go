@@ -16,6 +16,7 @@ type Attachment struct {
 	MimeType  string
 	Data      []byte
 	CreatedAt time.Time
+	Missing   bool // Data is stored elsewhere and isn't on this device; Data is nil
 }
 
 func NewAttachment(noteID uuid.UUID, filename, mimeType string, data []byte) *Attachment {
@@ -4,6 +4,9 @@
 package models
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +18,37 @@ type Note struct {
 	Content   string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	Context   *Context // Where the note was written; nil when not recorded
+	Starred   bool     // Favorite; changed only with the repository's SetStarred
+
+	// Read-later queue status and when the note joined the queue; changed
+	// only with the repository's SetStatus
+	Status   string
+	QueuedAt time.Time
+}
+
+// Read-later queue statuses. Notes outside the queue have no status.
+const (
+	StatusUnread  = "unread"
+	StatusReading = "reading"
+	StatusDone    = "done"
+)
+
+// ValidStatus reports whether s is a queue status, or empty for none.
+func ValidStatus(s string) bool {
+	switch s {
+	case "", StatusUnread, StatusReading, StatusDone:
+		return true
+	}
+	return false
+}
+
+// Context records the machine and code checkout a note was written in.
+// Branch and Commit are empty outside a git repository.
+type Context struct {
+	Host   string
+	Branch string
+	Commit string
 }
 
 func NewNote(title, content string) *Note {
@@ -31,3 +65,10 @@ func NewNote(title, content string) *Note {
 func (n *Note) Touch() {
 	n.UpdatedAt = time.Now()
 }
+
+// ContentHash identifies a note by its title and content, ignoring
+// surrounding whitespace, so re-imported copies can be detected.
+func (n *Note) ContentHash() string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(n.Title) + "\n" + strings.TrimSpace(n.Content)))
+	return hex.EncodeToString(sum[:])
+}
@@ -42,3 +42,17 @@ func TestNoteTouch(t *testing.T) {
 		t.Error("expected UpdatedAt to be updated")
 	}
 }
+
+func TestNoteContentHash(t *testing.T) {
+	a := NewNote("Ideas", "one\ntwo")
+	b := NewNote(" Ideas", "one\ntwo\n")
+
+	if a.ContentHash() != b.ContentHash() {
+		t.Error("expected notes differing only in whitespace to share a hash")
+	}
+
+	c := NewNote("Ideas", "one\nthree")
+	if a.ContentHash() == c.ContentHash() {
+		t.Error("expected different content to change the hash")
+	}
+}
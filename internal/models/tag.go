@@ -1,9 +1,21 @@
 // ABOUTME: Tag model for categorizing notes.
-// ABOUTME: Normalizes tag names to lowercase with trimmed whitespace.
+// ABOUTME: Defines the one normalization (NFC, trim, lowercase) and validation applied to every tag.
 
 package models
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrInvalidTag is returned for tags that are empty or contain commas or
+// control characters.
+var ErrInvalidTag = errors.New("tag must be non-empty and contain no commas or control characters")
 
 type Tag struct {
 	ID   int64
@@ -12,6 +24,42 @@ type Tag struct {
 
 func NewTag(name string) *Tag {
 	return &Tag{
-		Name: strings.ToLower(strings.TrimSpace(name)),
+		Name: NormalizeTag(name),
+	}
+}
+
+// NormalizeTag returns the canonical form of a tag: Unicode NFC, trimmed,
+// lowercase, with a leading '#' dropped and runs of whitespace collapsed to
+// one space. Two tags are the same tag exactly when they normalize equally.
+func NormalizeTag(name string) string {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+	name = strings.Join(strings.Fields(name), " ")
+	return norm.NFC.String(strings.ToLower(name))
+}
+
+// ValidateTag checks a normalized tag. Commas separate tags on the command
+// line, so only directory tags (dir:<path>) may contain them.
+func ValidateTag(name string) error {
+	if name == "" || strings.ContainsFunc(name, unicode.IsControl) {
+		return ErrInvalidTag
+	}
+	if strings.Contains(name, ",") && !strings.HasPrefix(name, "dir:") {
+		return ErrInvalidTag
+	}
+	return nil
+}
+
+// NormalizeTags normalizes and validates tags, dropping duplicates.
+func NormalizeTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if err := ValidateTag(tag); err != nil {
+			return nil, fmt.Errorf("%w: %q", err, tag)
+		}
+		if !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
 	}
+	return result, nil
 }
@@ -20,3 +20,36 @@ func TestNewTagWithSpaces(t *testing.T) {
 		t.Errorf("expected trimmed lowercase 'my tag', got %q", tag.Name)
 	}
 }
+
+func TestNormalizeTag(t *testing.T) {
+	cases := map[string]string{
+		" #Work ":       "work",
+		"Follow   Up":   "follow up",
+		"CAFE\u0301":    "caf\u00e9", // Decomposed é composes to NFC
+		"dir:/Users/Me": "dir:/users/me",
+	}
+	for in, want := range cases {
+		if got := NormalizeTag(in); got != want {
+			t.Errorf("NormalizeTag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{"Work", "work", " #work", "ideas"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "work" || tags[1] != "ideas" {
+		t.Errorf("expected [work ideas], got %v", tags)
+	}
+
+	for _, bad := range []string{"", "  ", "a,b", "bell\ab"} {
+		if _, err := NormalizeTags([]string{bad}); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+	if _, err := NormalizeTags([]string{"dir:/tmp/a,b"}); err != nil {
+		t.Errorf("expected commas allowed in dir tags, got %v", err)
+	}
+}
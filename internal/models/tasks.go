@@ -0,0 +1,74 @@
+// ABOUTME: Markdown task checkbox counting for note progress.
+// ABOUTME: Recognizes "- [ ]" and "- [x]" list items outside code fences.
+
+package models
+
+import "strings"
+
+// TaskCount is how many of a note's checkboxes are ticked.
+type TaskCount struct {
+	Done  int
+	Total int
+}
+
+// CountTasks counts the task list items in markdown content. Items use any
+// bullet ("-", "*", "+") or ordered marker ("1." or "1)") followed by "[ ]"
+// or "[x]"; lines inside fenced code blocks are ignored.
+func CountTasks(content string) TaskCount {
+	var tc TaskCount
+	if !strings.Contains(content, "[") {
+		return tc // Most notes have no tasks; skip the line scan
+	}
+
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if done, ok := taskItem(trimmed); ok {
+			tc.Total++
+			if done {
+				tc.Done++
+			}
+		}
+	}
+	return tc
+}
+
+// taskItem reports whether line is a task list item and whether it is done.
+func taskItem(line string) (done, ok bool) {
+	rest, ok := cutListMarker(line)
+	if !ok || len(rest) < 3 || rest[0] != '[' || rest[2] != ']' {
+		return false, false
+	}
+	if len(rest) > 3 && rest[3] != ' ' && rest[3] != '\t' {
+		return false, false
+	}
+	switch rest[1] {
+	case ' ':
+		return false, true
+	case 'x', 'X':
+		return true, true
+	}
+	return false, false
+}
+
+// cutListMarker strips a bullet or ordered list marker and the space after it.
+func cutListMarker(line string) (string, bool) {
+	if len(line) >= 2 && strings.ContainsRune("-*+", rune(line[0])) && line[1] == ' ' {
+		return strings.TrimLeft(line[2:], " "), true
+	}
+	digits := 0
+	for digits < len(line) && digits < 9 && line[digits] >= '0' && line[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 || len(line) < digits+2 || (line[digits] != '.' && line[digits] != ')') || line[digits+1] != ' ' {
+		return "", false
+	}
+	return strings.TrimLeft(line[digits+2:], " "), true
+}
@@ -0,0 +1,33 @@
+// ABOUTME: Tests for task checkbox counting.
+// ABOUTME: Validates list markers, checked states, and code fence handling.
+
+package models
+
+import "testing"
+
+func TestCountTasks(t *testing.T) {
+	content := `# Release
+
+- [x] write changelog
+- [ ] tag release
+* [X] bump version
+  + [ ] nested item
+1. [ ] announce
+2) [x] update docs
+- [link](https://example.com)
+- [ ]not a task
+[ ] no marker
+
+` + "```" + `
+- [ ] example in code
+` + "```" + `
+`
+	got := CountTasks(content)
+	if want := (TaskCount{Done: 3, Total: 6}); got != want {
+		t.Errorf("CountTasks = %+v, want %+v", got, want)
+	}
+
+	if got := CountTasks("plain note, no boxes"); got != (TaskCount{}) {
+		t.Errorf("expected no tasks, got %+v", got)
+	}
+}
@@ -0,0 +1,66 @@
+// ABOUTME: Captures where a note is being written: hostname and git checkout.
+// ABOUTME: Git details are best-effort and left empty outside a repository.
+
+package notecontext
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/harper/memo/internal/models"
+)
+
+// Capture returns the context for a note written in dir, or nil when
+// neither the hostname nor a git checkout could be determined.
+func Capture(dir string) *models.Context {
+	ctx := &models.Context{}
+	ctx.Host, _ = os.Hostname()
+	// symbolic-ref works on a branch without commits and is empty when detached
+	ctx.Branch = git(dir, "symbolic-ref", "--short", "-q", "HEAD")
+	ctx.Commit = git(dir, "rev-parse", "-q", "--verify", "HEAD")
+	if *ctx == (models.Context{}) {
+		return nil
+	}
+	return ctx
+}
+
+// ShortCommit abbreviates a commit hash for display.
+func ShortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// Format renders a context on one line, e.g. "laptop · feature/sync @ 3f2a9c1".
+func Format(ctx *models.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	var parts []string
+	if ctx.Host != "" {
+		parts = append(parts, ctx.Host)
+	}
+	switch {
+	case ctx.Branch != "" && ctx.Commit != "":
+		parts = append(parts, ctx.Branch+" @ "+ShortCommit(ctx.Commit))
+	case ctx.Branch != "":
+		parts = append(parts, ctx.Branch)
+	case ctx.Commit != "":
+		parts = append(parts, ShortCommit(ctx.Commit))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// git runs a git command in dir and returns its trimmed output, or "" if
+// git is missing or the command fails.
+func git(dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,69 @@
+// ABOUTME: Tests for note context capture and formatting.
+// ABOUTME: Uses a scratch git repository when git is installed.
+
+package notecontext
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/harper/memo/internal/models"
+)
+
+func TestCaptureGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	if ctx := Capture(dir); ctx != nil && (ctx.Branch != "" || ctx.Commit != "") {
+		t.Errorf("expected no git details outside a repo, got %+v", ctx)
+	}
+
+	run("init", "-q", "-b", "feature/sync")
+	ctx := Capture(dir)
+	if ctx == nil || ctx.Branch != "feature/sync" || ctx.Commit != "" {
+		t.Fatalf("unborn branch: got %+v", ctx)
+	}
+
+	run("commit", "-q", "--allow-empty", "-m", "first")
+	ctx = Capture(dir)
+	if ctx.Branch != "feature/sync" || len(ctx.Commit) != 40 {
+		t.Fatalf("after commit: got %+v", ctx)
+	}
+
+	run("checkout", "-q", "--detach")
+	if ctx := Capture(dir); ctx.Branch != "" || len(ctx.Commit) != 40 {
+		t.Errorf("detached: got %+v", ctx)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		ctx  *models.Context
+		want string
+	}{
+		{nil, ""},
+		{&models.Context{Host: "laptop"}, "laptop"},
+		{&models.Context{Host: "laptop", Branch: "main", Commit: "3f2a9c1d0e"}, "laptop · main @ 3f2a9c1"},
+		{&models.Context{Commit: "3f2a9c1d0e"}, "3f2a9c1"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.ctx); got != tt.want {
+			t.Errorf("Format(%+v) = %q, want %q", tt.ctx, got, tt.want)
+		}
+	}
+	if !strings.HasPrefix(ShortCommit("abc"), "abc") {
+		t.Error("ShortCommit should keep short hashes")
+	}
+}
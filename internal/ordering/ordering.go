@@ -0,0 +1,113 @@
+// ABOUTME: Manual ordering of notes by fractional rank.
+// ABOUTME: Moving an item usually rewrites only its own rank; ranks are renumbered when they run out of room.
+
+package ordering
+
+import "sort"
+
+// Spacing is the gap between neighboring ranks after renumbering, and
+// between the ends of the order and an item moved past them.
+const Spacing = 1024.0
+
+// minGap is the smallest gap between neighbors worth splitting; below it
+// the order is renumbered.
+const minGap = 1e-6
+
+// Sort orders ids by rank, lowest first. Ids without a rank follow the
+// ranked ones in their original order.
+func Sort[T comparable](ids []T, ranks map[T]float64) {
+	SortBy(ids, ranks, func(id T) T { return id })
+}
+
+// SortBy orders items by the rank of their key, like Sort.
+func SortBy[T any, K comparable](items []T, ranks map[K]float64, key func(T) K) {
+	sort.SliceStable(items, func(i, j int) bool {
+		ri, iok := ranks[key(items[i])]
+		rj, jok := ranks[key(items[j])]
+		if iok && jok {
+			return ri < rj
+		}
+		return iok && !jok
+	})
+}
+
+// Move returns the rank changes that place id right before anchor, or
+// right after it when after is set. An unranked anchor is first added to
+// the end of the order. With no anchor (the zero value), id moves to the
+// top, or to the bottom when after is set.
+func Move[T comparable](ranks map[T]float64, id, anchor T, after bool) map[T]float64 {
+	var zero T
+	updates := make(map[T]float64)
+
+	var order []T
+	for k := range ranks {
+		if k != id {
+			order = append(order, k)
+		}
+	}
+	Sort(order, ranks)
+	rank := func(k T) float64 {
+		if r, ok := updates[k]; ok {
+			return r
+		}
+		return ranks[k]
+	}
+
+	pos := 0
+	if after {
+		pos = len(order)
+	}
+	if anchor != zero {
+		i := indexOf(order, anchor)
+		if i < 0 {
+			r := Spacing
+			if len(order) > 0 {
+				r = rank(order[len(order)-1]) + Spacing
+			}
+			updates[anchor] = r
+			order = append(order, anchor)
+			i = len(order) - 1
+		}
+		pos = i
+		if after {
+			pos++
+		}
+	}
+
+	var r float64
+	switch {
+	case len(order) == 0:
+		r = Spacing
+	case pos == 0:
+		r = rank(order[0]) - Spacing
+	case pos == len(order):
+		r = rank(order[pos-1]) + Spacing
+	default:
+		prev, next := rank(order[pos-1]), rank(order[pos])
+		if next-prev < minGap {
+			return renumber(order, id, pos)
+		}
+		r = prev + (next-prev)/2
+	}
+	updates[id] = r
+	return updates
+}
+
+// renumber spaces out the whole order with id inserted at pos.
+func renumber[T comparable](order []T, id T, pos int) map[T]float64 {
+	all := append(append(append([]T{}, order[:pos]...), id), order[pos:]...)
+	updates := make(map[T]float64, len(all))
+	for i, k := range all {
+		updates[k] = float64(i+1) * Spacing
+	}
+	return updates
+}
+
+func indexOf[T comparable](items []T, want T) int {
+	for i, item := range items {
+		if item == want {
+			return i
+		}
+	}
+	return -1
+}
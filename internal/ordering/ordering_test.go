@@ -0,0 +1,92 @@
+// ABOUTME: Tests for manual ordering by fractional rank.
+// ABOUTME: Checks sorting, moves before and after anchors, and renumbering.
+
+package ordering
+
+import (
+	"slices"
+	"testing"
+)
+
+// apply merges updates into ranks and returns the resulting order of ids.
+func apply(ranks, updates map[string]float64, ids []string) []string {
+	for k, r := range updates {
+		ranks[k] = r
+	}
+	out := slices.Clone(ids)
+	Sort(out, ranks)
+	return out
+}
+
+func TestSort(t *testing.T) {
+	ids := []string{"new", "b", "old", "a"}
+	Sort(ids, map[string]float64{"a": 1, "b": 2})
+	if want := []string{"a", "b", "new", "old"}; !slices.Equal(ids, want) {
+		t.Errorf("Sort = %v, want %v", ids, want)
+	}
+}
+
+func TestMove(t *testing.T) {
+	ids := []string{"a", "b", "c", "d"}
+	ranks := map[string]float64{"a": 1024, "b": 2048, "c": 3072}
+
+	got := apply(ranks, Move(ranks, "c", "a", false), ids)
+	if want := []string{"c", "a", "b", "d"}; !slices.Equal(got, want) {
+		t.Errorf("c before a = %v, want %v", got, want)
+	}
+
+	updates := Move(ranks, "c", "a", true)
+	if len(updates) != 1 {
+		t.Errorf("expected a single rank change, got %v", updates)
+	}
+	got = apply(ranks, updates, ids)
+	if want := []string{"a", "c", "b", "d"}; !slices.Equal(got, want) {
+		t.Errorf("c after a = %v, want %v", got, want)
+	}
+
+	// An unranked anchor joins the end of the order first
+	got = apply(ranks, Move(ranks, "a", "d", false), ids)
+	if want := []string{"c", "b", "a", "d"}; !slices.Equal(got, want) {
+		t.Errorf("a before d = %v, want %v", got, want)
+	}
+
+	got = apply(ranks, Move(ranks, "d", "", false), ids)
+	if want := []string{"d", "c", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("d to top = %v, want %v", got, want)
+	}
+	got = apply(ranks, Move(ranks, "d", "", true), ids)
+	if want := []string{"c", "b", "a", "d"}; !slices.Equal(got, want) {
+		t.Errorf("d to bottom = %v, want %v", got, want)
+	}
+}
+
+func TestMoveRenumbers(t *testing.T) {
+	ids := []string{"a", "b", "c"}
+	ranks := map[string]float64{"a": 1, "b": 1 + 1e-9}
+
+	updates := Move(ranks, "c", "b", false)
+	if len(updates) != 3 {
+		t.Errorf("expected the order to be renumbered, got %v", updates)
+	}
+	if got, want := apply(ranks, updates, ids), []string{"a", "c", "b"}; !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	// Inserting into the same gap halves it each time until it's renumbered
+	ranks = map[string]float64{"first": Spacing, "last": 2 * Spacing}
+	want := []string{"first"}
+	for i := range 100 {
+		id := string(rune('A' + i))
+		for k, r := range Move(ranks, id, "last", false) {
+			ranks[k] = r
+		}
+		want = append(want, id)
+	}
+	want = append(want, "last")
+	got := slices.Clone(want)
+	slices.Reverse(got)
+	Sort(got, ranks)
+	if !slices.Equal(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,248 @@
+// ABOUTME: Org-mode conversion for importing and exporting notes.
+// ABOUTME: Maps top-level headings, TODO keywords, tags, and property drawers to notes.
+
+package org
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Keywords are the TODO states recognized on headings. A note tagged with the
+// lowercase form of a keyword is exported with that keyword on its heading.
+var Keywords = []string{"TODO", "DONE"}
+
+// tagProperty holds tags that cannot be written as Org heading tags (e.g. dir:/path).
+const tagProperty = "MEMO_TAG"
+
+// timestampLayout is Org's inactive timestamp format, accepted on import.
+const timestampLayout = "[2006-01-02 Mon 15:04]"
+
+// Entry is a note in Org form. Content is markdown.
+type Entry struct {
+	ID        string
+	Title     string
+	Content   string
+	Tags      []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+var (
+	headingRe = regexp.MustCompile(`^(\*+)\s+(.*)$`)
+	tagsRe    = regexp.MustCompile(`\s+(:[\w@#%:]+:)\s*$`)
+	orgTagRe  = regexp.MustCompile(`^[\w@#%]+$`)
+	mdHeadRe  = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	propRe    = regexp.MustCompile(`^\s*:([^:\s]+):\s*(.*)$`)
+)
+
+// Write renders entries as an Org document, one top-level heading per note.
+func Write(w io.Writer, entries []Entry) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range entries {
+		writeEntry(bw, e)
+	}
+	return bw.Flush()
+}
+
+func writeEntry(w *bufio.Writer, e Entry) {
+	var keyword string
+	var headTags, extraTags []string
+	for _, t := range e.Tags {
+		switch {
+		case keyword == "" && isKeyword(t):
+			keyword = strings.ToUpper(t)
+		case orgTagRe.MatchString(t):
+			headTags = append(headTags, t)
+		default:
+			extraTags = append(extraTags, t)
+		}
+	}
+
+	w.WriteString("* ")
+	if keyword != "" {
+		w.WriteString(keyword + " ")
+	}
+	w.WriteString(e.Title)
+	if len(headTags) > 0 {
+		w.WriteString(" :" + strings.Join(headTags, ":") + ":")
+	}
+	w.WriteString("\n:PROPERTIES:\n")
+	if e.ID != "" {
+		fmt.Fprintf(w, ":ID: %s\n", e.ID)
+	}
+	if !e.CreatedAt.IsZero() {
+		fmt.Fprintf(w, ":CREATED: %s\n", e.CreatedAt.Format(time.RFC3339))
+	}
+	if !e.UpdatedAt.IsZero() {
+		fmt.Fprintf(w, ":UPDATED: %s\n", e.UpdatedAt.Format(time.RFC3339))
+	}
+	for _, t := range extraTags {
+		fmt.Fprintf(w, ":%s: %s\n", tagProperty, t)
+	}
+	w.WriteString(":END:\n")
+
+	body := toOrgBody(e.Content)
+	if body != "" {
+		w.WriteString(body + "\n")
+	}
+	w.WriteString("\n")
+}
+
+// Parse reads an Org document, returning one entry per top-level heading.
+// Text before the first heading is ignored.
+func Parse(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	var cur *Entry
+	var body []string
+	inDrawer := false
+
+	flush := func() {
+		if cur != nil {
+			cur.Content = fromOrgBody(strings.Join(body, "\n"))
+			entries = append(entries, *cur)
+		}
+		body = nil
+	}
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+
+		if m := headingRe.FindStringSubmatch(line); m != nil && len(m[1]) == 1 {
+			flush()
+			cur = parseHeading(m[2])
+			inDrawer = false
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if len(body) == 0 && !inDrawer && strings.EqualFold(trimmed, ":PROPERTIES:") {
+			inDrawer = true
+			continue
+		}
+		if inDrawer {
+			if strings.EqualFold(trimmed, ":END:") {
+				inDrawer = false
+			} else if m := propRe.FindStringSubmatch(line); m != nil {
+				applyProperty(cur, m[1], strings.TrimSpace(m[2]))
+			}
+			continue
+		}
+		body = append(body, line)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return entries, nil
+}
+
+// parseHeading splits a top-level heading into keyword, title, and tags.
+func parseHeading(text string) *Entry {
+	e := &Entry{}
+	if m := tagsRe.FindStringSubmatchIndex(text); m != nil {
+		for _, t := range strings.Split(strings.Trim(text[m[2]:m[3]], ":"), ":") {
+			if t != "" {
+				e.Tags = append(e.Tags, t)
+			}
+		}
+		text = text[:m[0]]
+	}
+	for _, kw := range Keywords {
+		if text == kw || strings.HasPrefix(text, kw+" ") {
+			e.Tags = append([]string{strings.ToLower(kw)}, e.Tags...)
+			text = strings.TrimPrefix(text, kw)
+			break
+		}
+	}
+	e.Title = strings.TrimSpace(text)
+	return e
+}
+
+func applyProperty(e *Entry, key, value string) {
+	switch strings.ToUpper(key) {
+	case "ID":
+		e.ID = value
+	case "CREATED":
+		e.CreatedAt = parseTime(value)
+	case "UPDATED":
+		e.UpdatedAt = parseTime(value)
+	case tagProperty:
+		if value != "" {
+			e.Tags = append(e.Tags, value)
+		}
+	}
+}
+
+// parseTime accepts RFC 3339 or an Org inactive timestamp, returning zero on failure.
+func parseTime(value string) time.Time {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation(timestampLayout, value, time.Local); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+func isKeyword(tag string) bool {
+	for _, kw := range Keywords {
+		if strings.EqualFold(tag, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// toOrgBody converts markdown headings to nested Org headings and escapes
+// lines that Org would otherwise read as headings (e.g. "* " list items).
+func toOrgBody(content string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+		}
+		if !inFence {
+			if m := mdHeadRe.FindStringSubmatch(line); m != nil {
+				lines[i] = strings.Repeat("*", len(m[1])+1) + " " + m[2]
+				continue
+			}
+		}
+		if isEscapable(line) {
+			lines[i] = "," + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isEscapable reports whether line starts with "*" after any leading commas,
+// following Org's comma-escaping convention.
+func isEscapable(line string) bool {
+	return strings.HasPrefix(strings.TrimLeft(line, ","), "*")
+}
+
+// fromOrgBody reverses toOrgBody, mapping nested Org headings back to markdown.
+func fromOrgBody(body string) string {
+	lines := strings.Split(strings.Trim(body, "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ",") && isEscapable(line[1:]) {
+			lines[i] = line[1:]
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			level := min(len(m[1])-1, 6)
+			lines[i] = strings.Repeat("#", level) + " " + m[2]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,83 @@
+// ABOUTME: Tests for Org-mode note conversion.
+// ABOUTME: Validates round-trips and parsing of hand-written Org files.
+
+package org
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	in := []Entry{
+		{
+			ID:        "0b7c1a9e-4c1d-4c8e-9a55-2f0f6f1c2d3e",
+			Title:     "Plan launch",
+			Content:   "# Goals\n\n* ship it\n,* literal\n\n```\n# not a heading\n```",
+			Tags:      []string{"todo", "work", "dir:/home/me/proj"},
+			CreatedAt: created,
+			UpdatedAt: created.Add(time.Hour),
+		},
+		{Title: "Plain", Content: "just text"},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, in); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "* TODO Plan launch :work:\n") {
+		t.Errorf("unexpected heading:\n%s", buf.String())
+	}
+
+	out, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip mismatch:\nin:  %+v\nout: %+v", in, out)
+	}
+}
+
+func TestParseHandWritten(t *testing.T) {
+	src := `#+TITLE: My notes
+
+* DONE Buy milk                                          :errand:home:
+:PROPERTIES:
+:CREATED:  [2024-05-02 Thu 18:00]
+:END:
+Got the oat kind.
+** Receipt
+$3.50
+
+* Ideas
+Something`
+
+	entries, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	e := entries[0]
+	if e.Title != "Buy milk" {
+		t.Errorf("unexpected title %q", e.Title)
+	}
+	if !reflect.DeepEqual(e.Tags, []string{"done", "errand", "home"}) {
+		t.Errorf("unexpected tags %v", e.Tags)
+	}
+	if e.CreatedAt.Year() != 2024 || e.CreatedAt.Hour() != 18 {
+		t.Errorf("unexpected created time %v", e.CreatedAt)
+	}
+	if e.Content != "Got the oat kind.\n# Receipt\n$3.50" {
+		t.Errorf("unexpected content %q", e.Content)
+	}
+	if entries[1].Title != "Ideas" || entries[1].Content != "Something" {
+		t.Errorf("unexpected second entry %+v", entries[1])
+	}
+}
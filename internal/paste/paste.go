@@ -0,0 +1,212 @@
+// ABOUTME: Uploads note content to paste services and revokes uploads later.
+// ABOUTME: Supports GitHub Gists (token auth) and 0x0.st-style file hosts.
+
+package paste
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Service names accepted by `memo share --to`.
+const (
+	ServiceGist  = "gist"
+	ServicePaste = "paste"
+)
+
+// Default endpoints.
+const (
+	DefaultGistAPI  = "https://api.github.com"
+	DefaultPasteURL = "https://0x0.st"
+)
+
+// ErrNoToken is returned when a gist is requested without a GitHub token.
+var ErrNoToken = errors.New("no GitHub token: set gist_token or GITHUB_TOKEN")
+
+// Upload is a file published to a paste service. Token is whatever the
+// service needs to delete it again, if anything beyond the account token.
+type Upload struct {
+	ID    string
+	URL   string
+	Token string
+}
+
+// Service publishes files and takes them down again.
+type Service interface {
+	Upload(ctx context.Context, filename, content string) (*Upload, error)
+	Revoke(ctx context.Context, u *Upload) error
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// Gist publishes files as GitHub Gists.
+type Gist struct {
+	Token  string
+	Public bool
+	API    string
+	Client *http.Client
+}
+
+// NewGist creates a secret-gist uploader authenticated with token.
+func NewGist(token string) *Gist {
+	return &Gist{Token: token, API: DefaultGistAPI, Client: newHTTPClient()}
+}
+
+// Upload creates a gist holding one file.
+func (g *Gist) Upload(ctx context.Context, filename, content string) (*Upload, error) {
+	body, err := json.Marshal(map[string]any{
+		"public": g.Public,
+		"files":  map[string]any{filename: map[string]string{"content": content}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.do(ctx, http.MethodPost, g.API+"/gists", body)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusCreated {
+		return nil, statusError(resp)
+	}
+
+	var created struct {
+		ID      string `json:"id"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("decode gist response: %w", err)
+	}
+	return &Upload{ID: created.ID, URL: created.HTMLURL}, nil
+}
+
+// Revoke deletes the gist. A gist that is already gone counts as revoked.
+func (g *Gist) Revoke(ctx context.Context, u *Upload) error {
+	resp, err := g.do(ctx, http.MethodDelete, g.API+"/gists/"+url.PathEscape(u.ID), nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return statusError(resp)
+	}
+	return nil
+}
+
+func (g *Gist) do(ctx context.Context, method, endpoint string, body []byte) (*http.Response, error) {
+	if g.Token == "" {
+		return nil, ErrNoToken
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+g.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	return g.Client.Do(req)
+}
+
+// Host publishes files to a 0x0.st-compatible file host: a multipart POST
+// of "file" answered with the URL, and an X-Token header for deletion.
+type Host struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHost creates an uploader for the file host at baseURL.
+func NewHost(baseURL string) *Host {
+	if baseURL == "" {
+		baseURL = DefaultPasteURL
+	}
+	return &Host{URL: strings.TrimRight(baseURL, "/"), Client: newHTTPClient()}
+}
+
+// Upload posts the file. The "secret" field asks for a hard-to-guess URL.
+func (h *Host) Upload(ctx context.Context, filename, content string) (*Upload, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(part, content); err != nil {
+		return nil, err
+	}
+	if err := w.WriteField("secret", ""); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.Header.Set("User-Agent", "memo-share")
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return nil, err
+	}
+	link := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(link, "http") {
+		return nil, fmt.Errorf("unexpected response from %s: %q", h.URL, link)
+	}
+	return &Upload{ID: link, URL: link, Token: resp.Header.Get("X-Token")}, nil
+}
+
+// Revoke deletes the file using the token the host returned on upload.
+func (h *Host) Revoke(ctx context.Context, u *Upload) error {
+	if u.Token == "" {
+		return fmt.Errorf("no deletion token recorded for %s", u.URL)
+	}
+	form := url.Values{"token": {u.Token}, "delete": {""}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "memo-share")
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return statusError(resp)
+	}
+	return nil
+}
+
+// statusError describes an unexpected response, including a short excerpt
+// of the body since services explain failures there.
+func statusError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	msg := strings.TrimSpace(string(data))
+	if msg == "" {
+		return fmt.Errorf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return fmt.Errorf("%d %s: %s", resp.StatusCode, http.StatusText(resp.StatusCode), msg)
+}
@@ -0,0 +1,104 @@
+// ABOUTME: Tests for paste service uploads and revocation.
+// ABOUTME: Runs each service against an httptest server imitating its API.
+
+package paste
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGist(t *testing.T) {
+	var deleted string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/gists":
+			var req struct {
+				Public bool                         `json:"public"`
+				Files  map[string]map[string]string `json:"files"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Files["plan.md"]["content"] != "# Plan" || req.Public {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "abc", "html_url": "https://gist.example/abc"}`))
+		case r.Method == http.MethodDelete:
+			deleted = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	g := &Gist{Token: "tok", API: srv.URL, Client: srv.Client()}
+	ctx := context.Background()
+	u, err := g.Upload(ctx, "plan.md", "# Plan")
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if u.ID != "abc" || u.URL != "https://gist.example/abc" {
+		t.Errorf("unexpected upload %+v", u)
+	}
+	if err := g.Revoke(ctx, u); err != nil || deleted != "/gists/abc" {
+		t.Errorf("Revoke = %v, deleted %q", err, deleted)
+	}
+
+	g.Token = ""
+	if _, err := g.Upload(ctx, "plan.md", "# Plan"); !errors.Is(err, ErrNoToken) {
+		t.Errorf("expected ErrNoToken, got %v", err)
+	}
+}
+
+func TestHost(t *testing.T) {
+	var revokedToken string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			f, _, err := r.FormFile("file")
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(f)
+			if string(data) != "hello" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("X-Token", "secret-token")
+			_, _ = w.Write([]byte(srv.URL + "/x1.md\n"))
+		case "/x1.md":
+			if r.FormValue("token") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			revokedToken = r.FormValue("token")
+		}
+	}))
+	defer srv.Close()
+
+	h := &Host{URL: srv.URL + "/", Client: srv.Client()}
+	ctx := context.Background()
+	u, err := h.Upload(ctx, "note.md", "hello")
+	if err != nil {
+		t.Fatalf("Upload: %v", err)
+	}
+	if u.URL != srv.URL+"/x1.md" || u.Token != "secret-token" {
+		t.Errorf("unexpected upload %+v", u)
+	}
+	if err := h.Revoke(ctx, u); err != nil || revokedToken != "secret-token" {
+		t.Errorf("Revoke = %v, token %q", err, revokedToken)
+	}
+	if err := h.Revoke(ctx, &Upload{URL: u.URL}); err == nil {
+		t.Error("expected error revoking without a token")
+	}
+}
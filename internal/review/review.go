@@ -0,0 +1,186 @@
+// ABOUTME: Code review notes: a structured note per PR or branch with file/line comments.
+// ABOUTME: Parses review targets, places comments under per-file headings, and exports the review.
+
+package review
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/mdsection"
+)
+
+// Tag marks review notes.
+const Tag = "review"
+
+const (
+	summarySection = "Summary"
+	filesSection   = "Files"
+)
+
+// State records the review that `memo review add-file` appends to.
+type State struct {
+	NoteID string `json:"note_id"`
+}
+
+// Load reads the review state at path. It returns nil and no error when no
+// review has been started.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Path is memo's own state file
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes the review state to path.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// pullPath matches GitHub pull request and GitLab merge request paths.
+var pullPath = regexp.MustCompile(`^/(.+?)/(?:-/)?(pull|merge_requests)/(\d+)`)
+
+// Name returns a short name for a review target: "owner/repo#123" for a
+// GitHub pull request URL, "group/repo!123" for a GitLab merge request,
+// and the target itself (a branch, usually) otherwise.
+func Name(target string) string {
+	u, err := url.Parse(target)
+	if err != nil || u.Host == "" {
+		return target
+	}
+	m := pullPath.FindStringSubmatch(u.Path)
+	if m == nil {
+		return target
+	}
+	if m[2] == "merge_requests" {
+		return m[1] + "!" + m[3]
+	}
+	return m[1] + "#" + m[3]
+}
+
+// Title is the note title for reviewing target.
+func Title(target string) string {
+	return "Review: " + Name(target)
+}
+
+// Template returns the initial content of a review note.
+func Template(target string, started time.Time) string {
+	return fmt.Sprintf("Target: %s\nStarted: %s\n\n## %s\n\n## %s\n", target, started.Format("2006-01-02"), summarySection, filesSection)
+}
+
+// Comment is a remark on a file, optionally anchored to a line or range.
+type Comment struct {
+	Path string
+	Line string // "42" or "42-50"; empty for the whole file
+	Text string
+}
+
+var lineRef = regexp.MustCompile(`^[1-9]\d*(-[1-9]\d*)?$`)
+
+// ValidLine reports whether line is a line number or a range like "42-50".
+func ValidLine(line string) bool {
+	return lineRef.MatchString(line)
+}
+
+// item formats the comment as a list item under its file heading.
+func (c Comment) item() string {
+	if c.Line == "" {
+		return "- " + c.Text
+	}
+	return "- **L" + c.Line + "**: " + c.Text
+}
+
+// AddComment adds c to a review note's content, under a "### `path`"
+// heading in the Files section, creating the heading (and section) as
+// needed. Comments on a file keep the order they were added in.
+func AddComment(content string, c Comment) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	files, ok := find(lines, 2, filesSection, -1)
+	if !ok {
+		lines = append(lines, "", "## "+filesSection)
+		files = mdsection.Heading{Level: 2, Text: filesSection, Line: len(lines) - 1}
+	}
+	filesEnd := sectionEnd(lines, files)
+
+	// New lines go after the last non-blank line of the file's subsection,
+	// or of the Files section for a file without comments yet
+	section, insert := files, []string{"", "### `" + c.Path + "`", c.item()}
+	if file, ok := find(lines, 3, "`"+c.Path+"`", files.Line); ok && file.Line < filesEnd {
+		section, insert = file, []string{c.item()}
+	}
+	at := sectionEnd(lines, section)
+	for at > section.Line+1 && strings.TrimSpace(lines[at-1]) == "" {
+		at--
+	}
+	if section == files && at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+		insert = append(insert, "")
+	}
+
+	lines = append(lines[:at], append(insert, lines[at:]...)...)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// Export returns the review as markdown to post on the pull request: the
+// summary (when written) and the file comments, without the note's
+// metadata lines.
+func Export(content string) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	var out []string
+	for _, name := range []string{summarySection, filesSection} {
+		h, ok := find(lines, 2, name, -1)
+		if !ok {
+			continue
+		}
+		if body := strings.TrimSpace(strings.Join(lines[h.Line+1:sectionEnd(lines, h)], "\n")); body != "" {
+			out = append(out, body)
+		}
+	}
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n\n") + "\n"
+}
+
+// find returns the first heading of level with text after line after.
+func find(lines []string, level int, text string, after int) (mdsection.Heading, bool) {
+	for _, h := range mdsection.Headings(strings.Join(lines, "\n")) {
+		if h.Line > after && h.Level == level && h.Text == text {
+			return h, true
+		}
+	}
+	return mdsection.Heading{}, false
+}
+
+// sectionEnd returns the line index where h's section ends: the next
+// heading of the same or a higher level, or len(lines).
+func sectionEnd(lines []string, h mdsection.Heading) int {
+	for _, next := range mdsection.Headings(strings.Join(lines, "\n")) {
+		if next.Line > h.Line && next.Level <= h.Level {
+			return next.Line
+		}
+	}
+	return len(lines)
+}
@@ -0,0 +1,86 @@
+// ABOUTME: Tests for review note targets, comment placement, and export.
+// ABOUTME: Builds review notes from the template and checks the resulting markdown.
+
+package review
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestName(t *testing.T) {
+	for target, want := range map[string]string{
+		"https://github.com/harperreed/memo/pull/42":           "harperreed/memo#42",
+		"https://github.com/harperreed/memo/pull/42/files":     "harperreed/memo#42",
+		"https://gitlab.com/group/sub/repo/-/merge_requests/7": "group/sub/repo!7",
+		"feature/sync":                   "feature/sync",
+		"https://example.com/not/a/pull": "https://example.com/not/a/pull",
+	} {
+		if got := Name(target); got != want {
+			t.Errorf("Name(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestAddComment(t *testing.T) {
+	content := Template("feature/sync", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+	content = AddComment(content, Comment{Path: "sync.go", Line: "42", Text: "Retry here?"})
+	content = AddComment(content, Comment{Path: "api.go", Text: "Needs docs."})
+	content = AddComment(content, Comment{Path: "sync.go", Line: "88-90", Text: "Off by one."})
+
+	want := "Target: feature/sync\nStarted: 2026-10-16\n\n## Summary\n\n## Files\n\n" +
+		"### `sync.go`\n- **L42**: Retry here?\n- **L88-90**: Off by one.\n\n" +
+		"### `api.go`\n- Needs docs.\n"
+	if content != want {
+		t.Errorf("content =\n%s\nwant\n%s", content, want)
+	}
+}
+
+func TestAddCommentKeepsLaterSections(t *testing.T) {
+	content := "## Files\n\n### `a.go`\n- one\n\n## Follow-ups\n- ship it\n"
+	got := AddComment(content, Comment{Path: "a.go", Line: "3", Text: "two"})
+	got = AddComment(got, Comment{Path: "b.go", Text: "three"})
+
+	want := "## Files\n\n### `a.go`\n- one\n- **L3**: two\n\n### `b.go`\n- three\n\n## Follow-ups\n- ship it\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	if got := AddComment("Just notes", Comment{Path: "c.go", Text: "x"}); got != "Just notes\n\n## Files\n\n### `c.go`\n- x\n" {
+		t.Errorf("missing Files section: got %q", got)
+	}
+}
+
+func TestExport(t *testing.T) {
+	content := Template("feature/sync", time.Now())
+	if got := Export(content); got != "" {
+		t.Errorf("empty review exported %q", got)
+	}
+
+	content = AddComment(content, Comment{Path: "sync.go", Line: "42", Text: "Retry here?"})
+	if got, want := Export(content), "### `sync.go`\n- **L42**: Retry here?\n"; got != want {
+		t.Errorf("Export = %q, want %q", got, want)
+	}
+}
+
+func TestValidLine(t *testing.T) {
+	for line, want := range map[string]bool{"42": true, "42-50": true, "0": false, "x": false, "4-": false} {
+		if got := ValidLine(line); got != want {
+			t.Errorf("ValidLine(%q) = %v", line, got)
+		}
+	}
+}
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "review.json")
+	if s, err := Load(path); s != nil || err != nil {
+		t.Fatalf("Load missing = %+v, %v", s, err)
+	}
+	if err := (&State{NoteID: "abc"}).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := Load(path); err != nil || s.NoteID != "abc" {
+		t.Errorf("Load = %+v, %v", s, err)
+	}
+}
@@ -0,0 +1,143 @@
+// ABOUTME: Rollup note generation for weekly and monthly summaries.
+// ABOUTME: Computes period bounds and renders notes grouped by tag as markdown.
+
+package rollup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/models"
+)
+
+// TagPrefix marks rollup notes so they can be found and excluded from later rollups.
+const TagPrefix = "rollup:"
+
+// untaggedGroup is the heading used for notes without tags.
+const untaggedGroup = "untagged"
+
+// Period is a half-open time range [Start, End) with a stable label.
+type Period struct {
+	Kind  string // "week" or "month"
+	Label string // e.g. 2024-W32 or 2024-08
+	Start time.Time
+	End   time.Time
+}
+
+// Tag returns the tag applied to the rollup note for this period.
+func (p Period) Tag() string {
+	return TagPrefix + strings.ToLower(p.Label)
+}
+
+// Title returns the rollup note title for this period.
+func (p Period) Title() string {
+	if p.Kind == "month" {
+		return "Monthly rollup " + p.Label
+	}
+	return "Weekly rollup " + p.Label
+}
+
+// Contains reports whether t falls within the period.
+func (p Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// WeekOf returns the ISO week containing t, starting Monday 00:00 local time.
+func WeekOf(t time.Time) Period {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+	start := day.AddDate(0, 0, -offset)
+	year, week := start.ISOWeek()
+	return Period{
+		Kind:  "week",
+		Label: fmt.Sprintf("%d-W%02d", year, week),
+		Start: start,
+		End:   start.AddDate(0, 0, 7),
+	}
+}
+
+// MonthOf returns the calendar month containing t.
+func MonthOf(t time.Time) Period {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return Period{
+		Kind:  "month",
+		Label: start.Format("2006-01"),
+		Start: start,
+		End:   start.AddDate(0, 1, 0),
+	}
+}
+
+// Entry is a note considered for a rollup along with its tags.
+type Entry struct {
+	Note *models.Note
+	Tags []string
+}
+
+// Select returns entries created within the period, skipping earlier rollup notes.
+func Select(p Period, entries []Entry) []Entry {
+	var result []Entry
+	for _, e := range entries {
+		if !p.Contains(e.Note.CreatedAt) || isRollup(e.Tags) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// Render builds the markdown body of a rollup note, grouping entries by tag.
+func Render(p Period, entries []Entry) string {
+	groups := make(map[string][]Entry)
+	for _, e := range entries {
+		if len(e.Tags) == 0 {
+			groups[untaggedGroup] = append(groups[untaggedGroup], e)
+			continue
+		}
+		for _, tag := range e.Tags {
+			groups[strings.ToLower(tag)] = append(groups[strings.ToLower(tag)], e)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		if name != untaggedGroup {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := groups[untaggedGroup]; ok {
+		names = append(names, untaggedGroup)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Notes created %s – %s (%d total)\n",
+		p.Start.Format("2006-01-02"),
+		p.End.AddDate(0, 0, -1).Format("2006-01-02"),
+		len(entries)))
+
+	for _, name := range names {
+		group := groups[name]
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].Note.CreatedAt.Before(group[j].Note.CreatedAt)
+		})
+		sb.WriteString(fmt.Sprintf("\n## %s\n\n", name))
+		for _, e := range group {
+			sb.WriteString(fmt.Sprintf("- [%s](memo://note/%s) %s\n",
+				e.Note.Title, e.Note.ID.String(), e.Note.CreatedAt.Format("2006-01-02")))
+		}
+	}
+
+	return sb.String()
+}
+
+// isRollup reports whether the tags mark a rollup note.
+func isRollup(tags []string) bool {
+	for _, t := range tags {
+		if strings.HasPrefix(strings.ToLower(t), TagPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// ABOUTME: Tests for rollup period computation and rendering.
+// ABOUTME: Validates ISO week bounds, month bounds, and tag grouping.
+
+package rollup
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harper/memo/internal/models"
+)
+
+func TestWeekOf(t *testing.T) {
+	// Wednesday 2024-08-07 is in ISO week 32
+	p := WeekOf(time.Date(2024, 8, 7, 15, 30, 0, 0, time.UTC))
+
+	if p.Label != "2024-W32" {
+		t.Errorf("expected label 2024-W32, got %q", p.Label)
+	}
+	if !p.Start.Equal(time.Date(2024, 8, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected week to start Monday 2024-08-05, got %v", p.Start)
+	}
+	if p.Tag() != "rollup:2024-w32" {
+		t.Errorf("expected tag rollup:2024-w32, got %q", p.Tag())
+	}
+}
+
+func TestWeekOfSunday(t *testing.T) {
+	p := WeekOf(time.Date(2024, 8, 11, 23, 0, 0, 0, time.UTC))
+
+	if p.Label != "2024-W32" {
+		t.Errorf("expected Sunday to belong to 2024-W32, got %q", p.Label)
+	}
+}
+
+func TestMonthOf(t *testing.T) {
+	p := MonthOf(time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC))
+
+	if p.Label != "2024-12" {
+		t.Errorf("expected label 2024-12, got %q", p.Label)
+	}
+	if !p.End.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected month to end 2025-01-01, got %v", p.End)
+	}
+}
+
+func TestSelectAndRender(t *testing.T) {
+	p := WeekOf(time.Date(2024, 8, 7, 0, 0, 0, 0, time.UTC))
+
+	inWeek := models.NewNote("Standup", "content")
+	inWeek.CreatedAt = time.Date(2024, 8, 6, 9, 0, 0, 0, time.UTC)
+	untagged := models.NewNote("Loose thought", "content")
+	untagged.CreatedAt = time.Date(2024, 8, 8, 9, 0, 0, 0, time.UTC)
+	outside := models.NewNote("Old", "content")
+	outside.CreatedAt = time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	previous := models.NewNote("Weekly rollup", "content")
+	previous.CreatedAt = time.Date(2024, 8, 6, 9, 0, 0, 0, time.UTC)
+
+	selected := Select(p, []Entry{
+		{Note: inWeek, Tags: []string{"work"}},
+		{Note: untagged},
+		{Note: outside, Tags: []string{"work"}},
+		{Note: previous, Tags: []string{"rollup:2024-w31"}},
+	})
+
+	if len(selected) != 2 {
+		t.Fatalf("expected 2 selected notes, got %d", len(selected))
+	}
+
+	out := Render(p, selected)
+	if !strings.Contains(out, "## work") {
+		t.Error("expected work group heading")
+	}
+	if !strings.Contains(out, "memo://note/"+inWeek.ID.String()) {
+		t.Error("expected link to note")
+	}
+	if strings.Index(out, "## work") > strings.Index(out, "## untagged") {
+		t.Error("expected untagged group last")
+	}
+}
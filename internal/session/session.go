@@ -0,0 +1,170 @@
+// ABOUTME: Terminal work sessions logged to per-day journal notes.
+// ABOUTME: Keeps session state and a spool of timestamped entries that memo later appends to notes.
+
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Tag marks session journal notes.
+const Tag = "session"
+
+// Entry kinds.
+const (
+	KindStart   = "start"
+	KindStop    = "stop"
+	KindCommand = "command"
+	KindNote    = "note"
+)
+
+// State describes the running session.
+type State struct {
+	StartedAt time.Time `json:"started_at"`
+	Dir       string    `json:"dir"`
+}
+
+// Load reads the session state at path. It returns nil and no error when
+// no session is running.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Path is memo's own state file
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes the session state to path, marking a session as running.
+func (s *State) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// Entry is one line of a session journal.
+type Entry struct {
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"`
+	Text string    `json:"text"` // The command line, or a description
+	Dir  string    `json:"dir,omitempty"`
+	Exit int       `json:"exit,omitempty"` // Commands only
+}
+
+// Append adds entries to the spool file at path.
+func Append(path string, entries ...Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) //nolint:gosec // Path is memo's own state file
+	if err != nil {
+		return err
+	}
+	var buf []byte
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+		buf = append(append(buf, line...), '\n')
+	}
+	if _, err := f.Write(buf); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Drain removes the spool file at path and returns its entries. The file
+// is renamed before reading, so entries appended meanwhile start a new
+// spool instead of being lost.
+func Drain(path string) ([]Entry, error) {
+	draining := path + ".draining"
+	if err := os.Rename(path, draining); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	f, err := os.Open(draining) //nolint:gosec // Path is memo's own state file
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(draining)
+	}()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil {
+			entries = append(entries, e) // Skip torn lines
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Pending reports whether the spool at path has entries waiting.
+func Pending(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Size() > 0
+}
+
+// Title is the title of the journal note for day.
+func Title(day time.Time) string {
+	return "Session " + day.Format("2006-01-02")
+}
+
+// Day is the local date an entry belongs to, as YYYY-MM-DD.
+func (e Entry) Day() string {
+	return e.Time.Local().Format("2006-01-02")
+}
+
+// Format renders entries as markdown list items. A command's directory
+// is shown only when it differs from the one before.
+func Format(entries []Entry) string {
+	var b strings.Builder
+	lastDir := ""
+	for _, e := range entries {
+		fmt.Fprintf(&b, "- %s ", e.Time.Local().Format("15:04"))
+		switch e.Kind {
+		case KindCommand:
+			fmt.Fprintf(&b, "`%s`", strings.ReplaceAll(e.Text, "`", "'"))
+			if e.Exit != 0 {
+				fmt.Fprintf(&b, " (exit %d)", e.Exit)
+			}
+			if e.Dir != "" && e.Dir != lastDir {
+				fmt.Fprintf(&b, " in %s", e.Dir)
+			}
+		default:
+			b.WriteString(e.Text)
+		}
+		b.WriteString("\n")
+		if e.Dir != "" {
+			lastDir = e.Dir
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+// ABOUTME: Tests for session state, the entry spool, and journal formatting.
+// ABOUTME: Uses temp dirs for state files and local times for entries.
+
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStateAndSpool(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "session.json")
+	spool := filepath.Join(dir, "session.jsonl")
+
+	if s, err := Load(statePath); s != nil || err != nil {
+		t.Fatalf("Load before start = %v, %v", s, err)
+	}
+	start := time.Date(2026, 4, 2, 9, 0, 0, 0, time.Local)
+	if err := (&State{StartedAt: start, Dir: "/src"}).Save(statePath); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := Load(statePath); err != nil || !s.StartedAt.Equal(start) || s.Dir != "/src" {
+		t.Fatalf("Load = %+v, %v", s, err)
+	}
+
+	if Pending(spool) {
+		t.Fatal("empty spool reported pending")
+	}
+	_ = Append(spool, Entry{Time: start, Kind: KindStart, Text: "Session started"})
+	_ = Append(spool, Entry{Time: start.Add(time.Minute), Kind: KindCommand, Text: "make", Dir: "/src"})
+	if !Pending(spool) {
+		t.Fatal("spool not pending after append")
+	}
+	entries, err := Drain(spool)
+	if err != nil || len(entries) != 2 || entries[1].Text != "make" {
+		t.Fatalf("Drain = %+v, %v", entries, err)
+	}
+	if Pending(spool) {
+		t.Error("spool still pending after drain")
+	}
+	if entries, err := Drain(spool); entries != nil || err != nil {
+		t.Errorf("Drain of empty spool = %v, %v", entries, err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2026, 4, 2, h, m, 0, 0, time.Local) }
+	entries := []Entry{
+		{Time: at(9, 0), Kind: KindStart, Text: "Session started in /src", Dir: "/src"},
+		{Time: at(9, 5), Kind: KindCommand, Text: "go test ./...", Dir: "/src", Exit: 1},
+		{Time: at(9, 7), Kind: KindCommand, Text: "echo `date`", Dir: "/tmp"},
+		{Time: at(9, 8), Kind: KindNote, Text: "Added note [Fix](memo://abc12345)"},
+	}
+	want := "- 09:00 Session started in /src\n" +
+		"- 09:05 `go test ./...` (exit 1)\n" +
+		"- 09:07 `echo 'date'` in /tmp\n" +
+		"- 09:08 Added note [Fix](memo://abc12345)\n"
+	if got := Format(entries); got != want {
+		t.Errorf("Format =\n%s\nwant\n%s", got, want)
+	}
+	if got := Title(at(0, 0)); got != "Session 2026-04-02" {
+		t.Errorf("Title = %q", got)
+	}
+}
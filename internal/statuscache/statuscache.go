@@ -0,0 +1,110 @@
+// ABOUTME: Cached note counts for fast status-line output.
+// ABOUTME: Stores per-directory counts and note update times so status needs no database access.
+
+package statuscache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is what the cache needs to know about one note.
+type Entry struct {
+	Dirs      []string // Directories from the note's dir: tags
+	UpdatedAt time.Time
+}
+
+// Cache is a snapshot of note counts.
+type Cache struct {
+	BuiltAt   time.Time      `json:"built_at"`
+	Total     int            `json:"total"`
+	Dirs      map[string]int `json:"dirs"`
+	UpdatedAt []int64        `json:"updated_at"` // Unix seconds per note, for unsynced counts
+}
+
+// Build computes a cache from the current notes.
+func Build(entries []Entry, now time.Time) *Cache {
+	c := &Cache{BuiltAt: now, Total: len(entries), Dirs: make(map[string]int)}
+	for _, e := range entries {
+		for _, d := range e.Dirs {
+			c.Dirs[d]++
+		}
+		c.UpdatedAt = append(c.UpdatedAt, e.UpdatedAt.Unix())
+	}
+	return c
+}
+
+// Here returns the number of notes tagged with dir.
+func (c *Cache) Here(dir string) int {
+	return c.Dirs[dir]
+}
+
+// Unsynced returns the number of notes changed after lastSync.
+func (c *Cache) Unsynced(lastSync time.Time) int {
+	n := 0
+	for _, t := range c.UpdatedAt {
+		if t > lastSync.Unix() {
+			n++
+		}
+	}
+	return n
+}
+
+// Short formats a one-line status like "memo: 3 here · 2 unsynced",
+// leaving out zero counts, or the total when both are zero.
+func (c *Cache) Short(dir string, lastSync time.Time) string {
+	var parts []string
+	if n := c.Here(dir); n > 0 {
+		parts = append(parts, strconv.Itoa(n)+" here")
+	}
+	if n := c.Unsynced(lastSync); n > 0 {
+		parts = append(parts, strconv.Itoa(n)+" unsynced")
+	}
+	if len(parts) == 0 {
+		parts = append(parts, strconv.Itoa(c.Total)+" notes")
+	}
+	return "memo: " + strings.Join(parts, " · ")
+}
+
+// Load reads the cache at path. A missing file returns nil and no error.
+func Load(path string) (*Cache, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Path is memo's own state file
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c Cache
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Save writes the cache to path atomically.
+func (c *Cache) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Exists reports whether a cache has been written at path, so writers
+// only keep it fresh for users of status.
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,58 @@
+// ABOUTME: Tests for the status cache counts, one-line format, and persistence.
+// ABOUTME: Uses a temp dir for the cache file.
+
+package statuscache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestShort(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := Build([]Entry{
+		{Dirs: []string{"/src/memo"}, UpdatedAt: now.Add(-time.Hour)},
+		{Dirs: []string{"/src/memo", "/src/other"}, UpdatedAt: now.Add(-3 * time.Hour)},
+		{UpdatedAt: now.Add(-time.Minute)},
+	}, now)
+
+	tests := []struct {
+		dir      string
+		lastSync time.Time
+		want     string
+	}{
+		{"/src/memo", now.Add(-2 * time.Hour), "memo: 2 here · 2 unsynced"},
+		{"/src/memo", now, "memo: 2 here"},
+		{"/elsewhere", now.Add(-2 * time.Hour), "memo: 2 unsynced"},
+		{"/elsewhere", now, "memo: 3 notes"},
+		{"/src/other", time.Time{}, "memo: 1 here · 3 unsynced"},
+	}
+	for _, tt := range tests {
+		if got := c.Short(tt.dir, tt.lastSync); got != tt.want {
+			t.Errorf("Short(%q, %v) = %q, want %q", tt.dir, tt.lastSync, got, tt.want)
+		}
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "status.json")
+	if Exists(path) {
+		t.Fatal("cache exists before save")
+	}
+	if c, err := Load(path); c != nil || err != nil {
+		t.Fatalf("Load missing = %v, %v", c, err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	if err := Build([]Entry{{Dirs: []string{"/a"}, UpdatedAt: now}}, now).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Exists(path) || c.Total != 1 || c.Here("/a") != 1 || c.Unsynced(now.Add(-time.Second)) != 1 {
+		t.Errorf("loaded cache = %+v", c)
+	}
+}
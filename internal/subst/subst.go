@@ -0,0 +1,174 @@
+// ABOUTME: sed-style substitutions for rewriting note content.
+// ABOUTME: Parses s/old/new/flags expressions and applies them with Go regexps.
+
+package subst
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrSyntax is returned for malformed s/old/new/ expressions.
+var ErrSyntax = errors.New("invalid substitution")
+
+// Sub is a compiled substitution.
+type Sub struct {
+	re      *regexp.Regexp
+	repl    string // In regexp.Expand syntax
+	literal bool   // repl is inserted as is
+	global  bool   // Replace every match, not just the first
+}
+
+// Parse compiles a sed expression: s, a delimiter, the pattern, the
+// replacement, and optional flags g (every match) and i (ignore case). Any
+// punctuation can be the delimiter, e.g. s|/old|/new|g. In the replacement
+// & is the whole match and \1-\9 are groups, as in sed.
+func Parse(expr string) (*Sub, error) {
+	if len(expr) < 2 || expr[0] != 's' {
+		return nil, fmt.Errorf("%w: %q must look like s/old/new/", ErrSyntax, expr)
+	}
+	delim := expr[1]
+	if delim == '\\' || delim == '\n' || isWordByte(delim) {
+		return nil, fmt.Errorf("%w: bad delimiter %q", ErrSyntax, delim)
+	}
+
+	parts, err := splitUnescaped(expr[2:], delim)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: %q must look like s%cold%cnew%c", ErrSyntax, expr, delim, delim, delim)
+	}
+	pattern, repl, flags := parts[0], parts[1], parts[2]
+
+	global, fold := false, false
+	for _, f := range flags {
+		switch f {
+		case 'g':
+			global = true
+		case 'i':
+			fold = true
+		default:
+			return nil, fmt.Errorf("%w: unknown flag %q", ErrSyntax, f)
+		}
+	}
+	if fold {
+		pattern = "(?i)" + pattern
+	}
+	return New(pattern, sedReplacement(repl), global)
+}
+
+// New compiles a substitution from a Go regexp and a replacement in
+// regexp.Expand syntax ($1, ${name}).
+func New(pattern, repl string, global bool) (*Sub, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("%w: empty pattern", ErrSyntax)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSyntax, err)
+	}
+	return &Sub{re: re, repl: repl, global: global}, nil
+}
+
+// Literal returns a substitution that replaces every occurrence of find
+// with replace, taking both literally.
+func Literal(find, replace string) (*Sub, error) {
+	if find == "" {
+		return nil, fmt.Errorf("%w: empty search text", ErrSyntax)
+	}
+	return &Sub{re: regexp.MustCompile(regexp.QuoteMeta(find)), repl: replace, literal: true, global: true}, nil
+}
+
+// Apply returns s with the substitution applied and the number of
+// replacements made.
+func (sub *Sub) Apply(s string) (string, int) {
+	matches := sub.re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, 0
+	}
+	if !sub.global {
+		matches = matches[:1]
+	}
+
+	var out []byte
+	last := 0
+	for _, m := range matches {
+		out = append(out, s[last:m[0]]...)
+		if sub.literal {
+			out = append(out, sub.repl...)
+		} else {
+			out = sub.re.ExpandString(out, sub.repl, s, m)
+		}
+		last = m[1]
+	}
+	out = append(out, s[last:]...)
+	return string(out), len(matches)
+}
+
+// splitUnescaped splits s on unescaped delim bytes. An escaped delimiter
+// becomes the plain delimiter; other escapes are kept for the regexp.
+func splitUnescaped(s string, delim byte) ([]string, error) {
+	var parts []string
+	var cur strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && s[i+1] == delim:
+			cur.WriteByte(delim)
+			i++
+		case c == '\\' && i+1 < len(s):
+			cur.WriteByte(c)
+			cur.WriteByte(s[i+1])
+			i++
+		case c == delim:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("%w: missing delimiter %q", ErrSyntax, delim)
+	}
+	return append(parts, cur.String()), nil
+}
+
+// sedReplacement converts a sed replacement (& and \1) to regexp.Expand
+// syntax, escaping literal dollar signs.
+func sedReplacement(repl string) string {
+	var sb strings.Builder
+	for i := 0; i < len(repl); i++ {
+		c := repl[i]
+		switch {
+		case c == '$':
+			sb.WriteString("$$")
+		case c == '&':
+			sb.WriteString("${0}")
+		case c == '\\' && i+1 < len(repl):
+			next := repl[i+1]
+			i++
+			switch {
+			case next >= '0' && next <= '9':
+				sb.WriteString("${" + string(next) + "}")
+			case next == 'n':
+				sb.WriteByte('\n')
+			case next == 't':
+				sb.WriteByte('\t')
+			case next == '$':
+				sb.WriteString("$$")
+			default:
+				sb.WriteByte(next) // \& and \\ are literal
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == ' '
+}
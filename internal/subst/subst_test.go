@@ -0,0 +1,66 @@
+// ABOUTME: Tests for sed-style substitutions.
+// ABOUTME: Validates expression parsing, flags, group references, and literal replacement.
+
+package subst
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAndApply(t *testing.T) {
+	tests := []struct {
+		expr, in, want string
+		count          int
+	}{
+		{"s/cat/dog/", "cat cat", "dog cat", 1},
+		{"s/cat/dog/g", "cat cat", "dog dog", 2},
+		{"s/CAT/dog/gi", "Cat cAt", "dog dog", 2},
+		{`s/(\w+)@old\.com/\1@new.com/g`, "a@old.com b@old.com", "a@new.com b@new.com", 2},
+		{"s/v[0-9]+/[&]/", "ship v12 now", "ship [v12] now", 1},
+		{`s|/usr/local|/opt|`, "/usr/local/bin", "/opt/bin", 1},
+		{`s/a\/b/c/`, "a/b", "c", 1},
+		{"s/price/$5/", "the price", "the $5", 1},
+		{`s/price/\$5 \& up/`, "the price", "the $5 & up", 1},
+		{"s/none/x/", "nothing here", "nothing here", 0},
+	}
+	for _, tt := range tests {
+		sub, err := Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.expr, err)
+			continue
+		}
+		got, n := sub.Apply(tt.in)
+		if got != tt.want || n != tt.count {
+			t.Errorf("%q on %q = %q (%d), want %q (%d)", tt.expr, tt.in, got, n, tt.want, tt.count)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{"", "x/a/b/", "s/a/b", "s/a/b/q", "s//b/", "s/(/b/", "sxaxbx"} {
+		if _, err := Parse(expr); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Parse(%q) = %v, want ErrSyntax", expr, err)
+		}
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	sub, err := Literal("db.old-host.internal", "db.new-host.internal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, n := sub.Apply("ssh db.old-host.internal; ping dbXold-host.internal; $1 db.old-host.internal")
+	if want := "ssh db.new-host.internal; ping dbXold-host.internal; $1 db.new-host.internal"; got != want || n != 2 {
+		t.Errorf("Apply = %q (%d), want %q (2)", got, n, want)
+	}
+
+	dollar, _ := Literal("cost", "$1")
+	if got, _ := dollar.Apply("cost"); got != "$1" {
+		t.Errorf("expected literal replacement, got %q", got)
+	}
+
+	if _, err := Literal("", "x"); !errors.Is(err, ErrSyntax) {
+		t.Errorf("expected ErrSyntax for empty search, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+// ABOUTME: Retry policy, persistent health state, and attempt log for Charm sync.
+// ABOUTME: Retries transient failures with jittered backoff and records each sync.
+
+package synchealth
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"syscall"
+	"time"
+)
+
+// FailureThreshold is the number of consecutive failed syncs after which
+// sync is reported as failing.
+const FailureThreshold = 3
+
+// State is the on-disk record of recent sync outcomes.
+type State struct {
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastFailureAt       time.Time `json:"last_failure_at,omitempty"`
+	LastSuccessAt       time.Time `json:"last_success_at,omitempty"`
+}
+
+// Failing reports whether sync has failed FailureThreshold times in a row.
+func (s *State) Failing() bool {
+	return s.ConsecutiveFailures >= FailureThreshold
+}
+
+// Load reads the state from path, returning an empty state if none exists.
+func Load(path string) (*State, error) {
+	s := &State{}
+	data, err := os.ReadFile(path) //nolint:gosec // Path is memo's own state file
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Save writes the state to path atomically.
+func Save(path string, s *State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Record updates the state at path with the outcome of a sync.
+func Record(path string, syncErr error) error {
+	s, err := Load(path)
+	if err != nil {
+		s = &State{} // Start over rather than get stuck on a corrupt file
+	}
+	if syncErr != nil {
+		s.ConsecutiveFailures++
+		s.LastError = syncErr.Error()
+		s.LastFailureAt = time.Now()
+	} else {
+		s.ConsecutiveFailures = 0
+		s.LastError = ""
+		s.LastSuccessAt = time.Now()
+	}
+	return Save(path, s)
+}
+
+// MaxLogEntries is how many sync attempts the log keeps.
+const MaxLogEntries = 200
+
+// Sync triggers recorded in the log.
+const (
+	TriggerManual = "manual" // memo sync now, imports, fs sync
+	TriggerWrite  = "write"  // auto_sync after a write
+	TriggerStale  = "stale"  // Data older than stale_threshold before a read
+)
+
+// LogEntry is one sync attempt.
+type LogEntry struct {
+	Time     time.Time     `json:"time"`
+	Trigger  string        `json:"trigger"`
+	Duration time.Duration `json:"duration"`
+	Attempts int           `json:"attempts"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// AppendLog adds an entry to the JSON-lines log at path, dropping the
+// oldest entries beyond MaxLogEntries.
+func AppendLog(path string, entry LogEntry) error {
+	entries, err := ReadLog(path, 0)
+	if err != nil {
+		entries = nil // Start over rather than get stuck on a corrupt file
+	}
+	entries = append(entries, entry)
+	if len(entries) > MaxLogEntries {
+		entries = entries[len(entries)-MaxLogEntries:]
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadLog returns the last n log entries, oldest first (all when n <= 0).
+// A missing log is empty.
+func ReadLog(path string, n int) ([]LogEntry, error) {
+	f, err := os.Open(path) //nolint:gosec // Path is memo's own state file
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip lines cut short by a crash
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+	return entries, nil
+}
+
+// Retry runs an operation again after transient failures.
+type Retry struct {
+	Attempts int           // Total tries, including the first
+	Backoff  time.Duration // Base delay, doubled after each failure
+	Sleep    func(time.Duration)
+}
+
+// DefaultRetry returns the retry policy used for sync.
+func DefaultRetry() *Retry {
+	return &Retry{Attempts: 4, Backoff: 500 * time.Millisecond, Sleep: time.Sleep}
+}
+
+// Do calls fn until it succeeds, returns a non-transient error, or runs out
+// of attempts. Each wait is a random duration between half and all of the
+// current backoff so devices that failed together don't retry together.
+func (r *Retry) Do(fn func() error) error {
+	delay := r.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= r.Attempts || !Transient(err) {
+			return err
+		}
+		r.Sleep(delay/2 + rand.N(delay/2+1)) //nolint:gosec // Jitter doesn't need a secure source
+		delay *= 2
+	}
+}
+
+// serverError matches the error the Charm client returns for 5xx and 429
+// responses.
+var serverError = regexp.MustCompile(`server error: (5\d\d|429)\b`)
+
+// Transient reports whether err looks like a network problem or a
+// temporary server failure worth retrying.
+func Transient(err error) bool {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return false
+	case errors.As(err, &netErr),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNREFUSED):
+		return true
+	}
+	return serverError.MatchString(err.Error())
+}
@@ -0,0 +1,128 @@
+// ABOUTME: Tests for sync retry and health state.
+// ABOUTME: Validates backoff behaviour, error classification, failure tracking, and the log.
+
+package synchealth
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryStopsOnSuccess(t *testing.T) {
+	var waits []time.Duration
+	r := &Retry{Attempts: 4, Backoff: 100 * time.Millisecond, Sleep: func(d time.Duration) { waits = append(waits, d) }}
+
+	calls := 0
+	err := r.Do(func() error {
+		calls++
+		if calls < 3 {
+			return syscall.ECONNRESET
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("expected success on third call, got %d calls (%v)", calls, err)
+	}
+	if len(waits) != 2 {
+		t.Fatalf("expected 2 waits, got %v", waits)
+	}
+	if waits[0] < 50*time.Millisecond || waits[0] > 100*time.Millisecond ||
+		waits[1] < 100*time.Millisecond || waits[1] > 200*time.Millisecond {
+		t.Errorf("waits outside jittered backoff: %v", waits)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	r := &Retry{Attempts: 3, Backoff: time.Millisecond, Sleep: func(time.Duration) {}}
+
+	calls := 0
+	err := r.Do(func() error {
+		calls++
+		return fmt.Errorf("push: server error: 503 Service Unavailable")
+	})
+	if err == nil || calls != 3 {
+		t.Errorf("expected 3 failed calls, got %d (%v)", calls, err)
+	}
+
+	calls = 0
+	_ = r.Do(func() error {
+		calls++
+		return errors.New("missing ssh key")
+	})
+	if calls != 1 {
+		t.Errorf("expected permanent error not to be retried, got %d calls", calls)
+	}
+}
+
+func TestTransient(t *testing.T) {
+	cases := map[error]bool{
+		nil:                  false,
+		syscall.ECONNREFUSED: true,
+		fmt.Errorf("sync: %w", syscall.ECONNRESET):        true,
+		errors.New("server error: 502 Bad Gateway"):       true,
+		errors.New("server error: 429 Too Many Requests"): true,
+		errors.New("server error: 401 Unauthorized"):      false,
+		errors.New("decrypt: bad key"):                    false,
+	}
+	for err, want := range cases {
+		if got := Transient(err); got != want {
+			t.Errorf("Transient(%v) = %v, want %v", err, got, want)
+		}
+	}
+}
+
+func TestRecordTracksConsecutiveFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync-state.json")
+
+	for i := 0; i < FailureThreshold; i++ {
+		_ = Record(path, errors.New("offline"))
+	}
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !s.Failing() || s.LastError != "offline" {
+		t.Errorf("expected failing state, got %+v", s)
+	}
+
+	_ = Record(path, nil)
+	s, _ = Load(path)
+	if s.Failing() || s.ConsecutiveFailures != 0 || s.LastError != "" || s.LastSuccessAt.IsZero() {
+		t.Errorf("expected success to clear failures, got %+v", s)
+	}
+}
+
+func TestLogKeepsRecentEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync-log.jsonl")
+
+	if entries, err := ReadLog(path, 0); err != nil || len(entries) != 0 {
+		t.Fatalf("expected empty log, got %v (%v)", entries, err)
+	}
+
+	for i := 0; i < MaxLogEntries+5; i++ {
+		entry := LogEntry{Time: time.Unix(int64(i), 0), Trigger: TriggerWrite, Attempts: 1}
+		if i%2 == 1 {
+			entry.Error = "offline"
+		}
+		if err := AppendLog(path, entry); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	all, err := ReadLog(path, 0)
+	if err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	if len(all) != MaxLogEntries || all[0].Time.Unix() != 5 {
+		t.Errorf("expected the %d newest entries, got %d starting at %v", MaxLogEntries, len(all), all[0].Time.Unix())
+	}
+
+	last, _ := ReadLog(path, 2)
+	if len(last) != 2 || last[1].Time.Unix() != MaxLogEntries+4 || last[0].Error != "offline" {
+		t.Errorf("unexpected tail: %+v", last)
+	}
+}
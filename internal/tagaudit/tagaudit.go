@@ -0,0 +1,132 @@
+// ABOUTME: Tag hygiene report: near-duplicate and single-use tags.
+// ABOUTME: Groups tags that differ only by case, separators, or a one-letter typo.
+
+package tagaudit
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// minTypoLen is the shortest tag checked for typos; shorter tags like
+// "q3" and "q4" differ by one letter on purpose.
+const minTypoLen = 4
+
+// Tag is a tag name with the number of notes carrying it.
+type Tag struct {
+	Name  string
+	Count int
+}
+
+// Report is the result of an audit.
+type Report struct {
+	// Duplicates are groups of tags that look like the same tag, most
+	// used first. The first tag is the suggested merge target.
+	Duplicates [][]Tag
+	// Singletons are tags used on exactly one note and not part of a
+	// duplicate group, sorted by name.
+	Singletons []Tag
+}
+
+// Audit examines tag usage counts, keyed by the tag as stored.
+func Audit(counts map[string]int) *Report {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Union-find over tags that look alike
+	parent := make([]int, len(names))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			if Similar(names[i], names[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]Tag)
+	for i, name := range names {
+		root := find(i)
+		groups[root] = append(groups[root], Tag{Name: name, Count: counts[name]})
+	}
+
+	r := &Report{}
+	for i, name := range names {
+		group := groups[find(i)]
+		switch {
+		case len(group) > 1 && find(i) == i:
+			sort.SliceStable(group, func(a, b int) bool { return group[a].Count > group[b].Count })
+			r.Duplicates = append(r.Duplicates, group)
+		case len(group) == 1 && counts[name] == 1:
+			r.Singletons = append(r.Singletons, group[0])
+		}
+	}
+	return r
+}
+
+// Similar reports whether two tags look like the same tag: equal ignoring
+// case and separators, or (for longer tags without digits) one edit apart.
+func Similar(a, b string) bool {
+	sa, sb := squash(a), squash(b)
+	if sa == sb {
+		return true
+	}
+	if len([]rune(sa)) < minTypoLen || len([]rune(sb)) < minTypoLen || hasDigit(sa) || hasDigit(sb) {
+		return false
+	}
+	return editDistance([]rune(sa), []rune(sb)) <= 1
+}
+
+// squash lowercases s and drops separators.
+func squash(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ', '.':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+func hasDigit(s string) bool {
+	return strings.IndexFunc(s, unicode.IsDigit) >= 0
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: insertions, deletions, substitutions, and adjacent transpositions.
+func editDistance(a, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
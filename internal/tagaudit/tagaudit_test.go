@@ -0,0 +1,47 @@
+// ABOUTME: Tests for the tag hygiene report.
+// ABOUTME: Validates similarity rules and duplicate and singleton grouping.
+
+package tagaudit
+
+import "testing"
+
+func TestSimilar(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"work", "Work", true},
+		{"work", "wrok", true},
+		{"follow-up", "followup", true},
+		{"meeting", "meetings", true},
+		{"q3", "q4", false},
+		{"2023", "2024", false},
+		{"work", "home", false},
+	}
+	for _, c := range cases {
+		if got := Similar(c.a, c.b); got != c.want {
+			t.Errorf("Similar(%q, %q) = %v, want %v", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestAudit(t *testing.T) {
+	r := Audit(map[string]int{
+		"work":   5,
+		"Work":   1,
+		"wrok":   2,
+		"ideas":  3,
+		"orphan": 1,
+	})
+
+	if len(r.Duplicates) != 1 {
+		t.Fatalf("expected one duplicate group, got %+v", r.Duplicates)
+	}
+	group := r.Duplicates[0]
+	if len(group) != 3 || group[0].Name != "work" {
+		t.Errorf("expected work first in a group of 3, got %+v", group)
+	}
+	if len(r.Singletons) != 1 || r.Singletons[0].Name != "orphan" {
+		t.Errorf("expected only orphan as a singleton, got %+v", r.Singletons)
+	}
+}
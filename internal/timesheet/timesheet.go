@@ -0,0 +1,152 @@
+// ABOUTME: Time sheet summaries of work intervals timed against notes.
+// ABOUTME: Splits intervals across days within a period and renders per-note totals as a table.
+
+package timesheet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/rollup"
+)
+
+// maxDayColumns is the longest period shown with a column per day.
+const maxDayColumns = 7
+
+// Interval is time spent on a note. A zero End means still running.
+type Interval struct {
+	NoteID string
+	Start  time.Time
+	End    time.Time
+}
+
+// Row is one note's time within a period.
+type Row struct {
+	NoteID string
+	Days   []time.Duration // Parallel to Summary.Days
+	Total  time.Duration
+}
+
+// Summary is the time spent per note and day within a period.
+type Summary struct {
+	Period    rollup.Period
+	Days      []time.Time // Local midnight of each day in the period
+	Rows      []Row       // Most time first
+	DayTotals []time.Duration
+	Total     time.Duration
+}
+
+// Summarize totals intervals within period. Running intervals count up to
+// now, and intervals crossing midnight or the period's bounds are split.
+func Summarize(period rollup.Period, intervals []Interval, now time.Time) *Summary {
+	s := &Summary{Period: period}
+	for d := period.Start; d.Before(period.End); d = d.AddDate(0, 0, 1) {
+		s.Days = append(s.Days, d)
+	}
+	s.DayTotals = make([]time.Duration, len(s.Days))
+
+	rows := make(map[string]*Row)
+	for _, iv := range intervals {
+		end := iv.End
+		if end.IsZero() {
+			end = now
+		}
+		for i, day := range s.Days {
+			dayEnd := day.AddDate(0, 0, 1)
+			if i == len(s.Days)-1 && period.End.Before(dayEnd) {
+				dayEnd = period.End
+			}
+			d := overlap(iv.Start, end, day, dayEnd)
+			if d <= 0 {
+				continue
+			}
+			row := rows[iv.NoteID]
+			if row == nil {
+				row = &Row{NoteID: iv.NoteID, Days: make([]time.Duration, len(s.Days))}
+				rows[iv.NoteID] = row
+			}
+			row.Days[i] += d
+			row.Total += d
+			s.DayTotals[i] += d
+			s.Total += d
+		}
+	}
+
+	for _, row := range rows {
+		s.Rows = append(s.Rows, *row)
+	}
+	sort.Slice(s.Rows, func(i, j int) bool {
+		if s.Rows[i].Total != s.Rows[j].Total {
+			return s.Rows[i].Total > s.Rows[j].Total
+		}
+		return s.Rows[i].NoteID < s.Rows[j].NoteID
+	})
+	return s
+}
+
+func overlap(start, end, from, to time.Time) time.Duration {
+	if start.Before(from) {
+		start = from
+	}
+	if end.After(to) {
+		end = to
+	}
+	return end.Sub(start)
+}
+
+// FormatHours shows a duration as hours and minutes, like "2:05".
+func FormatHours(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%d:%02d", m/60, m%60)
+}
+
+// Format renders the summary as a table of notes by day (or just totals
+// for periods longer than a week), with titles looked up by note ID.
+func (s *Summary) Format(titles map[string]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Time sheet: %s\n\n", s.Period.Label)
+	if len(s.Rows) == 0 {
+		b.WriteString("No time tracked.\n")
+		return b.String()
+	}
+
+	perDay := len(s.Days) <= maxDayColumns
+	const titleWidth = 30
+	fmt.Fprintf(&b, "%-*s", titleWidth, "Note")
+	if perDay {
+		for _, d := range s.Days {
+			fmt.Fprintf(&b, "%7s", d.Format("Mon"))
+		}
+	}
+	fmt.Fprintf(&b, "%8s\n", "Total")
+
+	writeRow := func(label string, days []time.Duration, total time.Duration) {
+		r := []rune(label)
+		if len(r) > titleWidth-2 {
+			label = string(r[:titleWidth-3]) + "…"
+		}
+		fmt.Fprintf(&b, "%-*s", titleWidth+len(label)-len([]rune(label)), label)
+		if perDay {
+			for _, d := range days {
+				cell := "-"
+				if d > 0 {
+					cell = FormatHours(d)
+				}
+				fmt.Fprintf(&b, "%7s", cell)
+			}
+		}
+		fmt.Fprintf(&b, "%8s\n", FormatHours(total))
+	}
+
+	for _, row := range s.Rows {
+		title := titles[row.NoteID]
+		if title == "" {
+			title = "(deleted note " + row.NoteID[:min(8, len(row.NoteID))] + ")"
+		}
+		writeRow(title, row.Days, row.Total)
+	}
+	writeRow("Total", s.DayTotals, s.Total)
+	return b.String()
+}
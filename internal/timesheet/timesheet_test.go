@@ -0,0 +1,66 @@
+// ABOUTME: Tests for time sheet summaries and table rendering.
+// ABOUTME: Covers day splitting, period clipping, running timers, and sorting.
+
+package timesheet
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harper/memo/internal/rollup"
+)
+
+func TestSummarize(t *testing.T) {
+	at := func(day, h, m int) time.Time { return time.Date(2026, 3, day, h, m, 0, 0, time.Local) }
+	week := rollup.WeekOf(at(4, 12, 0)) // Mon 2 Mar - Sun 8 Mar
+
+	s := Summarize(week, []Interval{
+		{NoteID: "a", Start: at(2, 9, 0), End: at(2, 10, 30)},
+		{NoteID: "b", Start: at(3, 23, 0), End: at(4, 1, 0)},  // Crosses midnight
+		{NoteID: "a", Start: at(1, 23, 0), End: at(2, 0, 30)}, // Starts the week before
+		{NoteID: "c", Start: at(8, 22, 0)},                    // Still running
+		{NoteID: "d", Start: at(9, 9, 0), End: at(9, 10, 0)},  // Next week
+	}, at(8, 23, 15))
+
+	if len(s.Days) != 7 || len(s.Rows) != 3 {
+		t.Fatalf("got %d days, rows %+v", len(s.Days), s.Rows)
+	}
+	if r := s.Rows[0]; r.NoteID != "a" || r.Total != 2*time.Hour || r.Days[0] != 2*time.Hour {
+		t.Errorf("row a = %+v", r)
+	}
+	if r := s.Rows[1]; r.NoteID != "b" || r.Days[1] != time.Hour || r.Days[2] != time.Hour {
+		t.Errorf("row b = %+v", r)
+	}
+	if r := s.Rows[2]; r.NoteID != "c" || r.Days[6] != 75*time.Minute {
+		t.Errorf("row c = %+v", r)
+	}
+	if s.Total != 5*time.Hour+15*time.Minute || s.DayTotals[0] != 2*time.Hour {
+		t.Errorf("totals = %v, %v", s.Total, s.DayTotals)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	week := rollup.WeekOf(time.Date(2026, 3, 4, 12, 0, 0, 0, time.Local))
+	s := Summarize(week, []Interval{
+		{NoteID: "aaaaaaaa-1", Start: week.Start.Add(9 * time.Hour), End: week.Start.Add(11*time.Hour + 5*time.Minute)},
+		{NoteID: "bbbbbbbb-2", Start: week.Start.Add(33 * time.Hour), End: week.Start.Add(34 * time.Hour)},
+	}, week.End)
+	out := s.Format(map[string]string{"aaaaaaaa-1": "Q3 planning"})
+
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 6 || !strings.HasPrefix(lines[2], "Note") || !strings.Contains(lines[2], "Mon") {
+		t.Fatalf("unexpected table:\n%s", out)
+	}
+	if !strings.HasPrefix(lines[3], "Q3 planning") || !strings.HasSuffix(lines[3], "2:05") {
+		t.Errorf("row = %q", lines[3])
+	}
+	if !strings.Contains(lines[4], "(deleted note bbbbbbbb)") || !strings.HasSuffix(lines[5], "3:05") {
+		t.Errorf("rows = %q, %q", lines[4], lines[5])
+	}
+
+	empty := Summarize(week, nil, week.End).Format(nil)
+	if !strings.Contains(empty, "No time tracked.") {
+		t.Errorf("empty = %q", empty)
+	}
+}
@@ -0,0 +1,263 @@
+// ABOUTME: Word-level diff of note content for terminal display.
+// ABOUTME: Myers diff over word and whitespace tokens, shown in color or with markers.
+
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/fatih/color"
+)
+
+// DiffOp says whether a diff chunk is unchanged, added, or removed.
+type DiffOp int
+
+const (
+	DiffEqual DiffOp = iota
+	DiffInsert
+	DiffDelete
+)
+
+// DiffChunk is a run of text sharing one DiffOp.
+type DiffChunk struct {
+	Op   DiffOp
+	Text string
+}
+
+var (
+	diffInsert = color.New(color.FgGreen).SprintFunc()
+	diffDelete = color.New(color.FgRed, color.CrossedOut).SprintFunc()
+)
+
+// WordDiff returns the chunks that turn a into b, comparing whole words so a
+// changed word shows as one deletion and one insertion.
+func WordDiff(a, b string) []DiffChunk {
+	ta, tb := splitWords(a), splitWords(b)
+
+	// Trim the common ends; notes usually change in one place
+	pre := 0
+	for pre < len(ta) && pre < len(tb) && ta[pre] == tb[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(ta)-pre && suf < len(tb)-pre && ta[len(ta)-1-suf] == tb[len(tb)-1-suf] {
+		suf++
+	}
+
+	var chunks []DiffChunk
+	add := func(op DiffOp, text string) {
+		if n := len(chunks); n > 0 && chunks[n-1].Op == op {
+			chunks[n-1].Text += text
+			return
+		}
+		chunks = append(chunks, DiffChunk{Op: op, Text: text})
+	}
+
+	add(DiffEqual, strings.Join(ta[:pre], ""))
+	for _, c := range myers(ta[pre:len(ta)-suf], tb[pre:len(tb)-suf]) {
+		add(c.Op, c.Text)
+	}
+	add(DiffEqual, strings.Join(ta[len(ta)-suf:], ""))
+
+	// Drop empty chunks left by the joins above
+	result := chunks[:0]
+	for _, c := range chunks {
+		if c.Text != "" {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// FormatWordDiff renders chunks with removed text in red and added text in
+// green. Without color it falls back to git's [-removed-]{+added+} markers.
+func FormatWordDiff(chunks []DiffChunk) string {
+	var sb strings.Builder
+	for _, c := range chunks {
+		switch {
+		case c.Op == DiffEqual:
+			sb.WriteString(c.Text)
+		case color.NoColor && c.Op == DiffInsert:
+			sb.WriteString("{+" + c.Text + "+}")
+		case color.NoColor:
+			sb.WriteString("[-" + c.Text + "-]")
+		case c.Op == DiffInsert:
+			sb.WriteString(diffInsert(c.Text))
+		default:
+			sb.WriteString(diffDelete(c.Text))
+		}
+	}
+	return sb.String()
+}
+
+// FormatWordDiffContext renders chunks like FormatWordDiff but keeps only
+// changed lines and up to context lines around each, marking skipped runs
+// with a faint "···".
+func FormatWordDiffContext(chunks []DiffChunk, context int) string {
+	// Render line by line, noting which lines contain a change
+	var lines []string
+	var changed []bool
+	var cur strings.Builder
+	curChanged := false
+	for _, c := range chunks {
+		parts := strings.Split(c.Text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, cur.String())
+				changed = append(changed, curChanged)
+				cur.Reset()
+				curChanged = false
+			}
+			if part != "" {
+				cur.WriteString(FormatWordDiff([]DiffChunk{{Op: c.Op, Text: part}}))
+			}
+			curChanged = curChanged || c.Op != DiffEqual
+		}
+	}
+	lines = append(lines, cur.String())
+	changed = append(changed, curChanged)
+
+	keep := make([]bool, len(lines))
+	for i, ch := range changed {
+		if !ch {
+			continue
+		}
+		for j := max(0, i-context); j <= min(len(lines)-1, i+context); j++ {
+			keep[j] = true
+		}
+	}
+
+	var sb strings.Builder
+	skipped := false
+	for i, line := range lines {
+		if !keep[i] {
+			skipped = true
+			continue
+		}
+		if skipped && sb.Len() > 0 {
+			sb.WriteString(faint("···") + "\n")
+		}
+		skipped = false
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// FormatDiffHeader names the two sides of a diff, old first.
+func FormatDiffHeader(from, to string) string {
+	return bold("--- "+from) + "\n" + bold("+++ "+to)
+}
+
+// DiffStat counts the words added and removed in chunks.
+func DiffStat(chunks []DiffChunk) (added, removed int) {
+	for _, c := range chunks {
+		switch c.Op {
+		case DiffInsert:
+			added += len(strings.Fields(c.Text))
+		case DiffDelete:
+			removed += len(strings.Fields(c.Text))
+		}
+	}
+	return added, removed
+}
+
+// splitWords breaks s into alternating runs of whitespace and non-whitespace,
+// so joining the tokens gives back s exactly.
+func splitWords(s string) []string {
+	var tokens []string
+	start := 0
+	for i, r := range s {
+		if i > start && unicode.IsSpace(r) != isSpaceAt(s, start) {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
+
+func isSpaceAt(s string, i int) bool {
+	for _, r := range s[i:] {
+		return unicode.IsSpace(r)
+	}
+	return false
+}
+
+// myers computes a shortest edit script between token slices a and b with
+// Myers' O(ND) algorithm, one chunk per token.
+func myers(a, b []string) []DiffChunk {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	// v[k] is the furthest x reached on diagonal k; trace keeps the
+	// diagonals -d-1..d+1 of v from before each step d for backtracking.
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return nil // Unreachable: d = n+m always reaches the end
+}
+
+// backtrack walks trace from the end of both slices to the start and
+// returns the edit script in order.
+func backtrack(a, b []string, trace [][]int) []DiffChunk {
+	var rev []DiffChunk
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			rev = append(rev, DiffChunk{Op: DiffEqual, Text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				rev = append(rev, DiffChunk{Op: DiffInsert, Text: b[y-1]})
+			} else {
+				rev = append(rev, DiffChunk{Op: DiffDelete, Text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(rev)-1; i < j; i, j = i+1, j-1 {
+		rev[i], rev[j] = rev[j], rev[i]
+	}
+	return rev
+}
@@ -0,0 +1,88 @@
+// ABOUTME: Tests for word-level diffs.
+// ABOUTME: Validates edit scripts, round-tripping, and the plain-text markers.
+
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// apply rebuilds both sides of a diff from its chunks.
+func apply(chunks []DiffChunk) (string, string) {
+	var a, b strings.Builder
+	for _, c := range chunks {
+		if c.Op != DiffInsert {
+			a.WriteString(c.Text)
+		}
+		if c.Op != DiffDelete {
+			b.WriteString(c.Text)
+		}
+	}
+	return a.String(), b.String()
+}
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"the quick fox", "the quick fox", "the quick fox"},
+		{"the quick fox", "the slow fox", "the [-quick-]{+slow+} fox"},
+		{"one two", "one two three", "one two{+ three+}"},
+		{"alpha beta gamma", "alpha gamma", "alpha [-beta -]gamma"},
+		{"", "new note", "{+new note+}"},
+		{"line one\nline two\n", "line one\nline 2\n", "line one\nline [-two-]{+2+}\n"},
+	}
+
+	color.NoColor = true
+	for _, tt := range tests {
+		chunks := WordDiff(tt.a, tt.b)
+		if got := FormatWordDiff(chunks); got != tt.want {
+			t.Errorf("WordDiff(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+		if a, b := apply(chunks); a != tt.a || b != tt.b {
+			t.Errorf("chunks for (%q, %q) rebuild to (%q, %q)", tt.a, tt.b, a, b)
+		}
+	}
+}
+
+func TestWordDiffRoundTrip(t *testing.T) {
+	a := "We met on Monday to plan the release.\n\n- ship docs\n- fix sync\n"
+	b := "We met on Tuesday to plan the beta release.\n\n- fix sync\n- ship docs\n- tag it\n"
+	chunks := WordDiff(a, b)
+	if gotA, gotB := apply(chunks); gotA != a || gotB != b {
+		t.Errorf("chunks rebuild to (%q, %q)", gotA, gotB)
+	}
+	for i := 1; i < len(chunks); i++ {
+		if chunks[i].Op == chunks[i-1].Op {
+			t.Errorf("adjacent chunks %d and %d share an op", i-1, i)
+		}
+	}
+}
+
+func TestDiffStat(t *testing.T) {
+	added, removed := DiffStat(WordDiff("a b c d", "a x y d"))
+	if added != 2 || removed != 2 {
+		t.Errorf("DiffStat = +%d -%d, want +2 -2", added, removed)
+	}
+}
+
+func TestFormatWordDiffContext(t *testing.T) {
+	color.NoColor = true
+	a := "one\ntwo\nthree\nfour\nfive\nsix\nseven\n"
+	b := "one\ntwo\nthree\nFOUR\nfive\nsix\nseven\n"
+
+	got := FormatWordDiffContext(WordDiff(a, b), 1)
+	if want := "three\n[-four-]{+FOUR+}\nfive\n"; got != want {
+		t.Errorf("FormatWordDiffContext = %q, want %q", got, want)
+	}
+
+	b = "ONE\ntwo\nthree\nfour\nfive\nsix\nSEVEN\n"
+	got = FormatWordDiffContext(WordDiff(a, b), 0)
+	if want := "[-one-]{+ONE+}\n···\n[-seven-]{+SEVEN+}\n"; got != want {
+		t.Errorf("FormatWordDiffContext with gap = %q, want %q", got, want)
+	}
+}
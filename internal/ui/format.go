@@ -4,31 +4,103 @@
 package ui
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/x/term"
 	"github.com/fatih/color"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/notecontext"
+	"github.com/harper/memo/internal/vault"
+	"github.com/muesli/termenv"
 )
 
 var (
-	faint = color.New(color.Faint).SprintFunc()
-	bold  = color.New(color.Bold).SprintFunc()
-	cyan  = color.New(color.FgCyan).SprintFunc()
+	faint  = color.New(color.Faint).SprintFunc()
+	bold   = color.New(color.Bold).SprintFunc()
+	cyan   = color.New(color.FgCyan).SprintFunc()
+	green  = color.New(color.FgGreen).SprintFunc()
+	yellow = color.New(color.FgYellow).SprintFunc()
 )
 
+// RenderOptions configure how FormatNoteContent renders markdown.
+type RenderOptions struct {
+	// Style is a glamour style name or the path to a JSON style file;
+	// empty or "auto" picks dark or light from the terminal.
+	Style string
+	// Width is the word-wrap column; 0 means 80.
+	Width int
+	// CodeTheme is a chroma theme for code blocks; empty keeps the style's.
+	CodeTheme string
+}
+
+// renderOptions are the options used by FormatNoteContent.
+var renderOptions RenderOptions
+
+// CurrentRenderOptions returns the options used by FormatNoteContent.
+func CurrentRenderOptions() RenderOptions {
+	return renderOptions
+}
+
+// SetRenderOptions sets the options used by FormatNoteContent.
+func SetRenderOptions(opts RenderOptions) {
+	if opts.Style == styles.AutoStyle {
+		opts.Style = ""
+	}
+	renderOptions = opts
+}
+
 type TagCount struct {
 	Name  string
 	Count int
 }
 
 func FormatNoteListItem(note *models.Note, tags []*models.Tag) string {
+	return formatNoteListItem("  ", note, tags, models.TaskCount{})
+}
+
+// FormatNumberedNoteListItem formats a list item prefixed with its position
+// in the listing, with a checklist badge when the note has tasks.
+func FormatNumberedNoteListItem(n int, note *models.Note, tags []*models.Tag, tasks models.TaskCount) string {
+	return formatNoteListItem(fmt.Sprintf("%3d ", n), note, tags, tasks)
+}
+
+// FormatTaskBadge formats checklist progress as "[done/total]", green once
+// every task is done. It is empty for notes without tasks.
+func FormatTaskBadge(tasks models.TaskCount) string {
+	if tasks.Total == 0 {
+		return ""
+	}
+	badge := fmt.Sprintf("[%d/%d]", tasks.Done, tasks.Total)
+	if tasks.Done == tasks.Total {
+		return green(badge)
+	}
+	return faint(badge)
+}
+
+// formatNoteListItem formats a list item after lead, indenting the detail
+// lines to sit under the title.
+func formatNoteListItem(lead string, note *models.Note, tags []*models.Tag, tasks models.TaskCount) string {
 	var sb strings.Builder
+	indent := strings.Repeat(" ", len(lead)+7)
 
-	// ID prefix and title
+	// ID prefix, title, and checklist progress
 	idPrefix := note.ID.String()[:6]
-	sb.WriteString(fmt.Sprintf("  %s  %s\n", faint(idPrefix), bold(note.Title)))
+	sb.WriteString(fmt.Sprintf("%s%s  %s", lead, faint(idPrefix), bold(note.Title)))
+	if note.Starred {
+		sb.WriteString(" " + yellow("★"))
+	}
+	if badge := FormatTaskBadge(tasks); badge != "" {
+		sb.WriteString(" " + badge)
+	}
+	sb.WriteString("\n")
 
 	// Tags line if present
 	if len(tags) > 0 {
@@ -36,23 +108,96 @@ func FormatNoteListItem(note *models.Note, tags []*models.Tag) string {
 		for _, t := range tags {
 			tagNames = append(tagNames, t.Name)
 		}
-		sb.WriteString(fmt.Sprintf("         %s %s\n",
+		sb.WriteString(fmt.Sprintf("%s%s %s\n",
+			indent,
 			faint("Tags:"),
 			cyan(strings.Join(tagNames, ", "))))
 	}
 
 	// Date
-	sb.WriteString(fmt.Sprintf("         %s %s\n",
+	sb.WriteString(fmt.Sprintf("%s%s %s\n",
+		indent,
 		faint("Updated:"),
 		faint(note.UpdatedAt.Format("2006-01-02 15:04"))))
 
 	return sb.String()
 }
 
+// FormatNotePreview formats a preview line shown under a numbered list item.
+func FormatNotePreview(snippet string) string {
+	if snippet == "" {
+		return ""
+	}
+	return fmt.Sprintf("           %s\n", faint(snippet))
+}
+
+// Snippet returns up to width characters of content for a list preview:
+// the text around the first case-insensitive match of query, or the first
+// non-blank line when query is empty or not found. Elided text is marked
+// with "…". Vault notes have no snippet: their content is ciphertext.
+func Snippet(content, query string, width int) string {
+	if vault.IsSealed(content) {
+		return ""
+	}
+	if query != "" {
+		flat := []rune(strings.Join(strings.Fields(content), " "))
+		if at := indexFold(flat, []rune(query)); at >= 0 {
+			start := max(0, at-width/3)
+			end := min(len(flat), start+width)
+			out := string(flat[start:end])
+			if start > 0 {
+				out = "…" + out
+			}
+			if end < len(flat) {
+				out += "…"
+			}
+			return out
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if r := []rune(line); len(r) > width {
+			return string(r[:width]) + "…"
+		}
+		return line
+	}
+	return ""
+}
+
+// indexFold returns the rune index of the first case-insensitive match of
+// sub in s, or -1.
+func indexFold(s, sub []rune) int {
+	if len(sub) == 0 {
+		return -1
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		match := true
+		for j, r := range sub {
+			if unicode.ToLower(s[i+j]) != unicode.ToLower(r) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
 func FormatNoteContent(content string) (string, error) {
+	width := renderOptions.Width
+	if width <= 0 {
+		width = 80
+	}
+
 	renderer, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-		glamour.WithWordWrap(80),
+		styleOption(renderOptions),
+		glamour.WithWordWrap(width),
 	)
 	if err != nil {
 		// Fallback to raw content if renderer fails
@@ -67,13 +212,68 @@ func FormatNoteContent(content string) (string, error) {
 	return out, nil
 }
 
+// styleOption picks the glamour style for opts, swapping in the code theme
+// when one is set.
+func styleOption(opts RenderOptions) glamour.TermRendererOption {
+	if opts.CodeTheme == "" {
+		if opts.Style == "" {
+			return glamour.WithAutoStyle()
+		}
+		return glamour.WithStylePath(opts.Style) // Standard name or JSON file
+	}
+
+	cfg, err := loadStyle(opts.Style)
+	if err != nil {
+		// Let glamour report the bad style the usual way
+		return glamour.WithStylePath(opts.Style)
+	}
+	cfg.CodeBlock.Theme = opts.CodeTheme
+	cfg.CodeBlock.Chroma = nil // Chroma colors take precedence over Theme
+	return glamour.WithStyles(cfg)
+}
+
+// loadStyle returns a copy of a standard style or one read from a JSON
+// file, resolving "auto" the same way glamour does.
+func loadStyle(style string) (ansi.StyleConfig, error) {
+	if style == "" {
+		switch {
+		case !term.IsTerminal(os.Stdout.Fd()):
+			return styles.NoTTYStyleConfig, nil
+		case termenv.HasDarkBackground():
+			return styles.DarkStyleConfig, nil
+		default:
+			return styles.LightStyleConfig, nil
+		}
+	}
+	if cfg, ok := styles.DefaultStyles[style]; ok {
+		return *cfg, nil
+	}
+
+	var cfg ansi.StyleConfig
+	data, err := os.ReadFile(style) //nolint:gosec // Style path comes from the user's config
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, json.Unmarshal(data, &cfg)
+}
+
 func FormatNoteHeader(note *models.Note, tags []*models.Tag) string {
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf("%s\n", bold(note.Title)))
+	sb.WriteString(bold(note.Title))
+	if note.Starred {
+		sb.WriteString(" " + yellow("★"))
+	}
+	sb.WriteString("\n")
 	sb.WriteString(fmt.Sprintf("%s %s\n", faint("ID:"), faint(note.ID.String())))
 	sb.WriteString(fmt.Sprintf("%s %s\n", faint("Created:"), faint(note.CreatedAt.Format("2006-01-02 15:04"))))
 	sb.WriteString(fmt.Sprintf("%s %s\n", faint("Updated:"), faint(note.UpdatedAt.Format("2006-01-02 15:04"))))
+	if note.Context != nil {
+		sb.WriteString(fmt.Sprintf("%s %s\n", faint("Context:"), faint(notecontext.Format(note.Context))))
+	}
+	if note.Status != "" {
+		sb.WriteString(fmt.Sprintf("%s %s\n", faint("Status:"), faint(note.Status)))
+	}
 
 	if len(tags) > 0 {
 		var tagNames []string
@@ -104,10 +304,14 @@ func FormatAttachmentList(attachments []AttachmentInfo) string {
 
 	sb.WriteString(fmt.Sprintf("\n%s\n", bold("Attachments:")))
 	for _, a := range attachments {
-		sb.WriteString(fmt.Sprintf("  %s  %s %s\n",
+		sb.WriteString(fmt.Sprintf("  %s  %s %s",
 			faint(a.ID[:6]),
 			a.Filename,
 			faint(fmt.Sprintf("[%s]", a.MimeType))))
+		if a.Missing {
+			sb.WriteString(" " + yellow("(data not on this device)"))
+		}
+		sb.WriteString("\n")
 	}
 
 	return sb.String()
@@ -117,6 +321,77 @@ type AttachmentInfo struct {
 	ID       string
 	Filename string
 	MimeType string
+	Missing  bool // Data isn't on this device
+}
+
+// FormatSize renders a byte count in human-readable units.
+func FormatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// NoteStats are the details shown in a note's footer.
+type NoteStats struct {
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Words           int
+	Attachments     int
+	AttachmentBytes int64
+	SyncStatus      string // e.g. "synced" or "pending"; empty hides it
+}
+
+// FormatNoteFooter formats a note's stats as a separator and one line.
+func FormatNoteFooter(stats NoteStats) string {
+	now := time.Now()
+	parts := []string{
+		"Created " + FormatRelativeTime(stats.CreatedAt, now),
+		"Updated " + FormatRelativeTime(stats.UpdatedAt, now),
+		Plural(stats.Words, "word"),
+	}
+	if stats.Attachments > 0 {
+		parts = append(parts, fmt.Sprintf("%s (%s)", Plural(stats.Attachments, "attachment"), FormatSize(stats.AttachmentBytes)))
+	}
+	if stats.SyncStatus != "" {
+		parts = append(parts, "Sync: "+stats.SyncStatus)
+	}
+	return "\n" + Separator() + faint(strings.Join(parts, " · ")) + "\n"
+}
+
+// FormatRelativeTime describes t relative to now, e.g. "3 days ago".
+func FormatRelativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < 0:
+		return "in the future"
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return Plural(int(d/time.Minute), "minute") + " ago"
+	case d < 24*time.Hour:
+		return Plural(int(d/time.Hour), "hour") + " ago"
+	case d < 30*24*time.Hour:
+		return Plural(int(d/(24*time.Hour)), "day") + " ago"
+	case d < 365*24*time.Hour:
+		return Plural(int(d/(30*24*time.Hour)), "month") + " ago"
+	default:
+		return Plural(int(d/(365*24*time.Hour)), "year") + " ago"
+	}
+}
+
+// Plural formats a count with a noun, adding "s" unless the count is 1.
+func Plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
 }
 
 func Separator() string {
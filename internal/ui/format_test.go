@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/vault"
 )
 
 func TestFormatNoteListItem(t *testing.T) {
@@ -47,6 +48,82 @@ func TestFormatNoteContent(t *testing.T) {
 	}
 }
 
+func TestFormatNoteContentWidth(t *testing.T) {
+	defer SetRenderOptions(RenderOptions{})
+	SetRenderOptions(RenderOptions{Style: "notty", Width: 30})
+
+	out, err := FormatNoteContent(strings.Repeat("word ", 40))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if n := len(strings.TrimRight(line, " ")); n > 30 {
+			t.Errorf("line wider than 30 columns (%d): %q", n, line)
+		}
+	}
+}
+
+func TestLoadStyle(t *testing.T) {
+	cfg, err := loadStyle("dracula")
+	if err != nil {
+		t.Fatalf("expected standard style, got %v", err)
+	}
+	cfg.CodeBlock.Theme = "monokai"
+	if again, _ := loadStyle("dracula"); again.CodeBlock.Theme == "monokai" {
+		t.Error("expected loadStyle to return a copy of the standard style")
+	}
+
+	if _, err := loadStyle("/nonexistent/style.json"); err == nil {
+		t.Error("expected error for missing style file")
+	}
+}
+
+func TestFormatTaskBadge(t *testing.T) {
+	if got := FormatTaskBadge(models.TaskCount{}); got != "" {
+		t.Errorf("expected no badge without tasks, got %q", got)
+	}
+	if got := FormatTaskBadge(models.TaskCount{Done: 2, Total: 2}); !strings.Contains(got, "[2/2]") {
+		t.Errorf("expected [2/2], got %q", got)
+	}
+}
+
+func TestFormatRelativeTime(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{10 * time.Second, "just now"},
+		{time.Minute, "1 minute ago"},
+		{5 * time.Hour, "5 hours ago"},
+		{3 * 24 * time.Hour, "3 days ago"},
+		{65 * 24 * time.Hour, "2 months ago"},
+		{800 * 24 * time.Hour, "2 years ago"},
+		{-time.Hour, "in the future"},
+	}
+	for _, tt := range tests {
+		if got := FormatRelativeTime(now.Add(-tt.ago), now); got != tt.want {
+			t.Errorf("FormatRelativeTime(-%v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
+func TestFormatNoteFooter(t *testing.T) {
+	output := FormatNoteFooter(NoteStats{
+		CreatedAt:       time.Now().Add(-72 * time.Hour),
+		UpdatedAt:       time.Now(),
+		Words:           1,
+		Attachments:     2,
+		AttachmentBytes: 2048,
+		SyncStatus:      "pending",
+	})
+	for _, want := range []string{"Created 3 days ago", "Updated just now", "1 word", "2 attachments (2.0 KiB)", "Sync: pending"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected footer to contain %q, got %q", want, output)
+		}
+	}
+}
+
 func TestFormatTagList(t *testing.T) {
 	tags := []TagCount{
 		{Name: "work", Count: 5},
@@ -99,3 +176,58 @@ func TestFormatShowMorePrompt(t *testing.T) {
 		t.Error("expected output to contain 'y/n'")
 	}
 }
+
+func TestFormatSize(t *testing.T) {
+	cases := map[int64]string{
+		0:               "0 B",
+		512:             "512 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+	}
+	for n, want := range cases {
+		if got := FormatSize(n); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	content := "# Heading\n\nFirst paragraph about nothing.\nLater we discuss Goroutines and channels in depth."
+
+	if got := Snippet(content, "", 40); got != "# Heading" {
+		t.Errorf("expected first line, got %q", got)
+	}
+	if got := Snippet(content, "goroutines", 20); !strings.Contains(got, "Goroutines") || !strings.HasPrefix(got, "…") {
+		t.Errorf("expected snippet around match, got %q", got)
+	}
+	if got := Snippet(content, "missing", 40); got != "# Heading" {
+		t.Errorf("expected first line when query not found, got %q", got)
+	}
+	if got := Snippet("a very long single line", "", 6); got != "a very…" {
+		t.Errorf("expected truncated line, got %q", got)
+	}
+	if got := Snippet("   \n  ", "", 10); got != "" {
+		t.Errorf("expected empty snippet for blank content, got %q", got)
+	}
+	sealed, _ := vault.Seal("secret", []byte("pw"))
+	if got := Snippet(sealed, "", 40); got != "" {
+		t.Errorf("expected empty snippet for vault content, got %q", got)
+	}
+}
+
+func TestFormatNumberedNoteListItem(t *testing.T) {
+	note := &models.Note{ID: uuid.New(), Title: "Numbered", UpdatedAt: time.Now()}
+
+	output := FormatNumberedNoteListItem(12, note, []*models.Tag{{Name: "work"}}, models.TaskCount{Done: 3, Total: 7})
+
+	if !strings.HasPrefix(output, " 12 ") {
+		t.Errorf("expected output to start with the position, got %q", output)
+	}
+	lines := strings.Split(output, "\n")
+	if !strings.Contains(lines[0], "[3/7]") {
+		t.Errorf("expected a checklist badge on the title line, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], strings.Repeat(" ", 11)) {
+		t.Errorf("expected detail lines indented under the title, got %q", lines[1])
+	}
+}
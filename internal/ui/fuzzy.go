@@ -0,0 +1,44 @@
+// ABOUTME: Fuzzy matching for the interactive note picker.
+// ABOUTME: Scores subsequence matches, favouring consecutive runs and word starts.
+
+package ui
+
+import (
+	"unicode"
+)
+
+// FuzzyScore reports whether every rune of query appears in target in
+// order (case-insensitive) and, if so, a score where higher is a better
+// match. Consecutive matches and matches at the start of a word score
+// higher, so "mtg" ranks "Meeting notes" below "MTG prep". An empty query
+// matches everything with score 0.
+func FuzzyScore(query, target string) (int, bool) {
+	q := []rune(query)
+	if len(q) == 0 {
+		return 0, true
+	}
+
+	score, qi := 0, 0
+	prevMatch := false
+	prev := ' '
+	for _, r := range target {
+		if qi < len(q) && unicode.ToLower(r) == unicode.ToLower(q[qi]) {
+			score++
+			if prevMatch {
+				score += 3
+			}
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 2
+			}
+			qi++
+			prevMatch = true
+		} else {
+			prevMatch = false
+		}
+		prev = r
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
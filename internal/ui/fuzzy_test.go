@@ -0,0 +1,33 @@
+// ABOUTME: Tests for fuzzy matching.
+// ABOUTME: Validates subsequence matching and ranking.
+
+package ui
+
+import "testing"
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := FuzzyScore("xyz", "Meeting notes"); ok {
+		t.Error("expected no match for missing runes")
+	}
+	if _, ok := FuzzyScore("ntm", "Meeting notes"); ok {
+		t.Error("expected no match for out-of-order runes")
+	}
+	if s, ok := FuzzyScore("", "anything"); !ok || s != 0 {
+		t.Errorf("expected empty query to match with 0, got %d %v", s, ok)
+	}
+
+	run, ok1 := FuzzyScore("mtg", "MTG prep")
+	spread, ok2 := FuzzyScore("mtg", "Meeting notes")
+	if !ok1 || !ok2 {
+		t.Fatal("expected both targets to match")
+	}
+	if run <= spread {
+		t.Errorf("expected consecutive match to rank higher: %d <= %d", run, spread)
+	}
+
+	start, _ := FuzzyScore("n", "notes")
+	mid, _ := FuzzyScore("n", "Meeting")
+	if start <= mid {
+		t.Errorf("expected word-start match to rank higher: %d <= %d", start, mid)
+	}
+}
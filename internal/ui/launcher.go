@@ -0,0 +1,128 @@
+// ABOUTME: JSON output for launcher script filters (Alfred and Raycast).
+// ABOUTME: Maps listed notes to each launcher's item schema with title, subtitle, arg, and icon.
+
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/models"
+)
+
+// Launcher output formats for `memo list --format`.
+const (
+	LauncherAlfred  = "alfred"
+	LauncherRaycast = "raycast"
+)
+
+// LauncherNote is a listed note to show in a launcher.
+type LauncherNote struct {
+	Note    *models.Note
+	Tags    []string
+	Tasks   models.TaskCount
+	Snippet string
+}
+
+type alfredIcon struct {
+	Type string `json:"type,omitempty"`
+	Path string `json:"path"`
+}
+
+type alfredText struct {
+	Copy      string `json:"copy,omitempty"`
+	LargeType string `json:"largetype,omitempty"`
+}
+
+type alfredItem struct {
+	UID          string     `json:"uid"`
+	Title        string     `json:"title"`
+	Subtitle     string     `json:"subtitle"`
+	Arg          string     `json:"arg"`
+	Autocomplete string     `json:"autocomplete"`
+	Match        string     `json:"match"`
+	Icon         alfredIcon `json:"icon"`
+	Text         alfredText `json:"text"`
+}
+
+type raycastAccessory struct {
+	Text string `json:"text,omitempty"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+type raycastItem struct {
+	ID          string             `json:"id"`
+	Title       string             `json:"title"`
+	Subtitle    string             `json:"subtitle"`
+	Arg         string             `json:"arg"`
+	Icon        string             `json:"icon"`
+	Keywords    []string           `json:"keywords,omitempty"`
+	Accessories []raycastAccessory `json:"accessories,omitempty"`
+}
+
+// FormatLauncherJSON renders notes as a launcher script-filter document.
+// Each item's arg is the full note ID, for passing to `memo show`.
+func FormatLauncherJSON(format string, notes []LauncherNote, now time.Time) ([]byte, error) {
+	switch format {
+	case LauncherAlfred:
+		items := make([]alfredItem, 0, len(notes))
+		for _, n := range notes {
+			id := n.Note.ID.String()
+			items = append(items, alfredItem{
+				UID:          id,
+				Title:        n.Note.Title,
+				Subtitle:     launcherSubtitle(n, now),
+				Arg:          id,
+				Autocomplete: n.Note.Title,
+				Match:        strings.Join(append([]string{n.Note.Title}, n.Tags...), " "),
+				Icon:         alfredIcon{Type: "filetype", Path: "public.plain-text"},
+				Text:         alfredText{Copy: id, LargeType: n.Note.Title},
+			})
+		}
+		return json.MarshalIndent(map[string]any{"items": items}, "", "  ")
+
+	case LauncherRaycast:
+		items := make([]raycastItem, 0, len(notes))
+		for _, n := range notes {
+			item := raycastItem{
+				ID:       n.Note.ID.String(),
+				Title:    n.Note.Title,
+				Subtitle: n.Snippet,
+				Arg:      n.Note.ID.String(),
+				Icon:     "📝",
+				Keywords: n.Tags,
+			}
+			if n.Tasks.Total > 0 {
+				item.Accessories = append(item.Accessories, raycastAccessory{Text: fmt.Sprintf("%d/%d", n.Tasks.Done, n.Tasks.Total)})
+			}
+			for _, t := range n.Tags {
+				item.Accessories = append(item.Accessories, raycastAccessory{Tag: t})
+			}
+			item.Accessories = append(item.Accessories, raycastAccessory{Text: FormatRelativeTime(n.Note.UpdatedAt, now)})
+			items = append(items, item)
+		}
+		return json.MarshalIndent(map[string]any{"items": items}, "", "  ")
+
+	default:
+		return nil, fmt.Errorf("unknown launcher format %q", format)
+	}
+}
+
+// launcherSubtitle summarizes a note on one line: tags, task progress,
+// last update, and the preview snippet.
+func launcherSubtitle(n LauncherNote, now time.Time) string {
+	var parts []string
+	if len(n.Tags) > 0 {
+		parts = append(parts, "#"+strings.Join(n.Tags, " #"))
+	}
+	if n.Tasks.Total > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d tasks", n.Tasks.Done, n.Tasks.Total))
+	}
+	parts = append(parts, FormatRelativeTime(n.Note.UpdatedAt, now))
+	if n.Snippet != "" {
+		parts = append(parts, n.Snippet)
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,69 @@
+// ABOUTME: Tests for launcher script-filter JSON output.
+// ABOUTME: Decodes the Alfred and Raycast documents and checks item fields.
+
+package ui
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/models"
+)
+
+func launcherNotes(now time.Time) []LauncherNote {
+	return []LauncherNote{{
+		Note:    &models.Note{ID: uuid.New(), Title: "Q3 plan", UpdatedAt: now.Add(-2 * time.Hour)},
+		Tags:    []string{"work"},
+		Tasks:   models.TaskCount{Done: 1, Total: 3},
+		Snippet: "Goals for the quarter",
+	}}
+}
+
+func TestFormatLauncherJSONAlfred(t *testing.T) {
+	now := time.Now()
+	notes := launcherNotes(now)
+	data, err := FormatLauncherJSON(LauncherAlfred, notes, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Items []struct {
+			UID, Title, Subtitle, Arg string
+			Icon                      struct{ Type, Path string }
+		}
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(doc.Items))
+	}
+	item := doc.Items[0]
+	if item.Arg != notes[0].Note.ID.String() || item.Title != "Q3 plan" || item.Icon.Path == "" {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if want := "#work · 1/3 tasks · 2 hours ago · Goals for the quarter"; item.Subtitle != want {
+		t.Errorf("Subtitle = %q, want %q", item.Subtitle, want)
+	}
+}
+
+func TestFormatLauncherJSONRaycast(t *testing.T) {
+	now := time.Now()
+	data, err := FormatLauncherJSON(LauncherRaycast, launcherNotes(now), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"subtitle": "Goals for the quarter"`, `"tag": "work"`, `"text": "1/3"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %s:\n%s", want, data)
+		}
+	}
+
+	if _, err := FormatLauncherJSON("spotlight", nil, now); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
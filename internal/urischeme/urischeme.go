@@ -0,0 +1,167 @@
+// ABOUTME: Registers memo as the operating system's handler for memo:// links.
+// ABOUTME: Writes a .desktop entry on Linux, an AppleScript app on macOS, and registry keys on Windows.
+
+package urischeme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Scheme is the URI scheme memo handles.
+const Scheme = "memo"
+
+// ErrUnsupported is returned by Install on platforms without a known
+// registration mechanism.
+var ErrUnsupported = errors.New("registering URI handlers isn't supported on " + runtime.GOOS)
+
+const desktopFile = "memo-uri.desktop"
+
+// Install registers exe (the memo binary) for memo:// links for the current
+// user, so opening one runs `memo uri handle <uri>` in a terminal. It
+// returns the file or registry key it wrote.
+func Install(exe string) (string, error) {
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return installDesktop(exe)
+	case "darwin":
+		return installMacApp(exe)
+	case "windows":
+		return installRegistry(exe)
+	default:
+		return "", ErrUnsupported
+	}
+}
+
+// DesktopEntry returns a freedesktop.org .desktop entry that opens memo://
+// links with exe in a terminal.
+func DesktopEntry(exe string) string {
+	return `[Desktop Entry]
+Type=Application
+Name=memo
+Comment=Open memo:// note links
+Exec=` + desktopQuote(exe) + ` uri handle %u
+Terminal=true
+NoDisplay=true
+MimeType=x-scheme-handler/` + Scheme + `;
+`
+}
+
+// desktopQuote quotes an Exec argument per the Desktop Entry spec.
+func desktopQuote(arg string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`)
+	return `"` + r.Replace(arg) + `"`
+}
+
+func installDesktop(exe string) (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	dir := filepath.Join(dataHome, "applications")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, desktopFile)
+	if err := os.WriteFile(path, []byte(DesktopEntry(exe)), 0600); err != nil {
+		return "", err
+	}
+	if err := run("xdg-mime", "default", desktopFile, "x-scheme-handler/"+Scheme); err != nil {
+		return path, err
+	}
+	_ = run("update-desktop-database", dir) // Optional; not every desktop needs it
+	return path, nil
+}
+
+// AppleScript returns the source of a macOS app that opens memo:// links
+// with exe in a new Terminal window.
+func AppleScript(exe string) string {
+	return `on open location theURL
+	tell application "Terminal"
+		activate
+		do script (quoted form of "` + appleEscape(exe) + `") & " uri handle " & (quoted form of theURL)
+	end tell
+end open location
+`
+}
+
+func appleEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+func installMacApp(exe string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	app := filepath.Join(home, "Applications", "memo-uri.app")
+	if err := os.MkdirAll(filepath.Dir(app), 0750); err != nil {
+		return "", err
+	}
+
+	script, err := os.CreateTemp("", "memo-uri-*.applescript")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.Remove(script.Name()) // Best-effort cleanup
+	}()
+	if _, err := script.WriteString(AppleScript(exe)); err != nil {
+		_ = script.Close()
+		return "", err
+	}
+	if err := script.Close(); err != nil {
+		return "", err
+	}
+
+	_ = os.RemoveAll(app) // osacompile won't replace an existing bundle
+	if err := run("osacompile", "-o", app, script.Name()); err != nil {
+		return "", err
+	}
+	plist := filepath.Join(app, "Contents", "Info.plist")
+	if err := run("plutil", "-replace", "CFBundleIdentifier", "-string", "com.github.harperreed.memo-uri", plist); err != nil {
+		return "", err
+	}
+	urlTypes := `[{"CFBundleURLName":"memo note link","CFBundleURLSchemes":["` + Scheme + `"]}]`
+	if err := run("plutil", "-replace", "CFBundleURLTypes", "-json", urlTypes, plist); err != nil {
+		return "", err
+	}
+	lsregister := "/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister"
+	if err := run(lsregister, "-f", app); err != nil {
+		return app, err
+	}
+	return app, nil
+}
+
+func installRegistry(exe string) (string, error) {
+	key := `HKCU\Software\Classes\` + Scheme
+	command := `"` + exe + `" uri handle "%1"`
+	for _, args := range [][]string{
+		{"add", key, "/ve", "/d", "URL:memo note link", "/f"},
+		{"add", key, "/v", "URL Protocol", "/d", "", "/f"},
+		{"add", key + `\shell\open\command`, "/ve", "/d", command, "/f"},
+	} {
+		if err := run("reg", args...); err != nil {
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+// run executes a registration helper, including its output in errors.
+func run(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput() //nolint:gosec // Fixed system tools
+	if err != nil {
+		return fmt.Errorf("%s: %w: %s", filepath.Base(name), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
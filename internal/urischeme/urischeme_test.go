@@ -0,0 +1,29 @@
+// ABOUTME: Tests for the generated handler definitions.
+// ABOUTME: Checks quoting of the memo binary path in .desktop entries and AppleScript.
+
+package urischeme
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDesktopEntry(t *testing.T) {
+	entry := DesktopEntry(`/home/me/my "bin"/$memo`)
+	if !strings.Contains(entry, `Exec="/home/me/my \"bin\"/\$memo" uri handle %u`+"\n") {
+		t.Errorf("unexpected Exec line:\n%s", entry)
+	}
+	if !strings.Contains(entry, "MimeType=x-scheme-handler/memo;\n") || !strings.Contains(entry, "Terminal=true\n") {
+		t.Errorf("missing handler keys:\n%s", entry)
+	}
+}
+
+func TestAppleScript(t *testing.T) {
+	script := AppleScript(`/Users/me/bin/"memo"`)
+	if !strings.Contains(script, `quoted form of "/Users/me/bin/\"memo\""`) {
+		t.Errorf("unexpected script:\n%s", script)
+	}
+	if !strings.Contains(script, "on open location theURL") {
+		t.Errorf("script doesn't handle open location:\n%s", script)
+	}
+}
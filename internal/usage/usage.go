@@ -0,0 +1,127 @@
+// ABOUTME: Local, opt-in usage metrics with no network reporting.
+// ABOUTME: Records command counts and sync durations in a JSON state file.
+
+package usage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Metrics is the on-disk usage record.
+type Metrics struct {
+	Since    time.Time      `json:"since"`
+	Commands map[string]int `json:"commands"`
+	Sync     SyncStats      `json:"sync"`
+}
+
+// SyncStats aggregates sync timings.
+type SyncStats struct {
+	Count     int           `json:"count"`
+	Failures  int           `json:"failures"`
+	Total     time.Duration `json:"total"`
+	Last      time.Duration `json:"last"`
+	Longest   time.Duration `json:"longest"`
+	LastRunAt time.Time     `json:"last_run_at"`
+}
+
+// Average returns the mean sync duration.
+func (s SyncStats) Average() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// CommandCount is a command name with its run count.
+type CommandCount struct {
+	Name  string
+	Count int
+}
+
+// TopCommands returns commands sorted by count descending, then name.
+func (m *Metrics) TopCommands() []CommandCount {
+	result := make([]CommandCount, 0, len(m.Commands))
+	for name, count := range m.Commands {
+		result = append(result, CommandCount{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Load reads metrics from path, returning an empty record if none exists.
+func Load(path string) (*Metrics, error) {
+	m := &Metrics{Since: time.Now(), Commands: make(map[string]int)}
+
+	data, err := os.ReadFile(path) //nolint:gosec // Path is memo's own state file
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	if m.Commands == nil {
+		m.Commands = make(map[string]int)
+	}
+	return m, nil
+}
+
+// Save writes metrics to path atomically.
+func Save(path string, m *Metrics) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RecordCommand increments the run count for a command.
+func RecordCommand(path, name string) error {
+	return update(path, func(m *Metrics) {
+		m.Commands[name]++
+	})
+}
+
+// RecordSync adds a sync timing to the metrics.
+func RecordSync(path string, d time.Duration, syncErr error) error {
+	return update(path, func(m *Metrics) {
+		m.Sync.Count++
+		if syncErr != nil {
+			m.Sync.Failures++
+		}
+		m.Sync.Total += d
+		m.Sync.Last = d
+		if d > m.Sync.Longest {
+			m.Sync.Longest = d
+		}
+		m.Sync.LastRunAt = time.Now()
+	})
+}
+
+// update loads, mutates, and saves the metrics file.
+func update(path string, fn func(*Metrics)) error {
+	m, err := Load(path)
+	if err != nil {
+		return err
+	}
+	fn(m)
+	return Save(path, m)
+}
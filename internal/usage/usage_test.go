@@ -0,0 +1,58 @@
+// ABOUTME: Tests for local usage metrics.
+// ABOUTME: Validates command counting, sync timing, and persistence.
+
+package usage
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+
+	for _, name := range []string{"list", "add", "list"} {
+		if err := RecordCommand(path, name); err != nil {
+			t.Fatalf("record failed: %v", err)
+		}
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	top := m.TopCommands()
+	if len(top) != 2 || top[0].Name != "list" || top[0].Count != 2 {
+		t.Errorf("unexpected top commands: %+v", top)
+	}
+}
+
+func TestRecordSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+
+	_ = RecordSync(path, 100*time.Millisecond, nil)
+	_ = RecordSync(path, 300*time.Millisecond, errors.New("offline"))
+
+	m, _ := Load(path)
+	if m.Sync.Count != 2 || m.Sync.Failures != 1 {
+		t.Errorf("unexpected sync counts: %+v", m.Sync)
+	}
+	if m.Sync.Average() != 200*time.Millisecond {
+		t.Errorf("expected 200ms average, got %v", m.Sync.Average())
+	}
+	if m.Sync.Longest != 300*time.Millisecond {
+		t.Errorf("expected 300ms longest, got %v", m.Sync.Longest)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	m, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(m.Commands) != 0 {
+		t.Error("expected empty metrics")
+	}
+}
@@ -0,0 +1,147 @@
+// ABOUTME: Passphrase encryption for vault notes using scrypt and NaCl secretbox.
+// ABOUTME: Sealed content is an armored text block, so it stores and syncs like any note content.
+
+package vault
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	beginLine = "-----BEGIN MEMO VAULT-----"
+	endLine   = "-----END MEMO VAULT-----"
+
+	version  = 1
+	saltSize = 16
+	keySize  = 32
+
+	// scrypt parameters recommended for interactive logins
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	lineWidth = 64 // Base64 characters per armored line
+)
+
+var (
+	ErrNotSealed        = errors.New("content is not a vault block")
+	ErrWrongPassphrase  = errors.New("wrong passphrase or corrupted vault block")
+	ErrEmptyPassphrase  = errors.New("passphrase cannot be empty")
+	errUnsupportedBlock = errors.New("unsupported vault block version")
+)
+
+// IsSealed reports whether content is a vault block. It only looks at the
+// start, so it works on content truncated for listings.
+func IsSealed(content string) bool {
+	return strings.HasPrefix(content, beginLine)
+}
+
+// CheckEdit returns an error naming the note if content is a vault block.
+// Text added to a block stops it from opening, so a vault note's content
+// can only change after 'memo vault rm'.
+func CheckEdit(title, content string) error {
+	if !IsSealed(content) {
+		return nil
+	}
+	return fmt.Errorf("%s is in the vault: run 'memo vault rm' to decrypt it before editing", title)
+}
+
+// Seal encrypts plaintext with a key derived from passphrase and returns
+// it as an armored block.
+func Seal(plaintext string, passphrase []byte) (string, error) {
+	if len(passphrase) == 0 {
+		return "", ErrEmptyPassphrase
+	}
+
+	var salt [saltSize]byte
+	var nonce [24]byte
+	if _, err := rand.Read(salt[:]); err != nil {
+		return "", err
+	}
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", err
+	}
+	key, err := deriveKey(passphrase, salt[:])
+	if err != nil {
+		return "", err
+	}
+
+	// version | salt | nonce | box
+	out := make([]byte, 0, 1+saltSize+len(nonce)+len(plaintext)+secretbox.Overhead)
+	out = append(out, version)
+	out = append(out, salt[:]...)
+	out = append(out, nonce[:]...)
+	out = secretbox.Seal(out, []byte(plaintext), &nonce, key)
+	return armor(out), nil
+}
+
+// Open decrypts an armored block made by Seal.
+func Open(sealed string, passphrase []byte) (string, error) {
+	data, err := dearmor(sealed)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < 1+saltSize+24+secretbox.Overhead {
+		return "", ErrWrongPassphrase
+	}
+	if data[0] != version {
+		return "", fmt.Errorf("%w: %d", errUnsupportedBlock, data[0])
+	}
+	salt := data[1 : 1+saltSize]
+	var nonce [24]byte
+	copy(nonce[:], data[1+saltSize:])
+	box := data[1+saltSize+len(nonce):]
+
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	plaintext, ok := secretbox.Open(nil, box, &nonce, key)
+	if !ok {
+		return "", ErrWrongPassphrase
+	}
+	return string(plaintext), nil
+}
+
+func deriveKey(passphrase, salt []byte) (*[keySize]byte, error) {
+	k, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, err
+	}
+	var key [keySize]byte
+	copy(key[:], k)
+	return &key, nil
+}
+
+func armor(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var sb strings.Builder
+	sb.WriteString(beginLine + "\n")
+	for len(encoded) > lineWidth {
+		sb.WriteString(encoded[:lineWidth] + "\n")
+		encoded = encoded[lineWidth:]
+	}
+	sb.WriteString(encoded + "\n")
+	sb.WriteString(endLine + "\n")
+	return sb.String()
+}
+
+func dearmor(sealed string) ([]byte, error) {
+	sealed = strings.TrimSpace(sealed)
+	if !IsSealed(sealed) || !strings.HasSuffix(sealed, endLine) {
+		return nil, ErrNotSealed
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(sealed, beginLine), endLine)
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotSealed, err)
+	}
+	return data, nil
+}
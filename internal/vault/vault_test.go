@@ -0,0 +1,99 @@
+// ABOUTME: Tests for vault note encryption.
+// ABOUTME: Checks round trips, wrong passphrases, tampering, and detecting sealed content.
+
+package vault
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSealOpen(t *testing.T) {
+	plaintext := "# Bank\n\nPIN: 1234\n"
+	sealed, err := Seal(plaintext, []byte("hunter2"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if strings.Contains(sealed, "PIN") {
+		t.Errorf("sealed block contains plaintext:\n%s", sealed)
+	}
+	if !IsSealed(sealed) {
+		t.Errorf("IsSealed(sealed) = false")
+	}
+
+	got, err := Open(sealed, []byte("hunter2"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if got != plaintext {
+		t.Errorf("Open = %q, want %q", got, plaintext)
+	}
+}
+
+func TestSealUsesFreshSalt(t *testing.T) {
+	a, _ := Seal("same", []byte("pw"))
+	b, _ := Seal("same", []byte("pw"))
+	if a == b {
+		t.Error("sealing twice produced the same block")
+	}
+}
+
+func TestOpenWrongPassphrase(t *testing.T) {
+	sealed, _ := Seal("secret", []byte("right"))
+	if _, err := Open(sealed, []byte("wrong")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Open with wrong passphrase = %v, want ErrWrongPassphrase", err)
+	}
+}
+
+func TestOpenTampered(t *testing.T) {
+	sealed, _ := Seal("secret", []byte("pw"))
+	lines := strings.Split(sealed, "\n")
+	body := []byte(lines[1])
+	if body[10] == 'A' {
+		body[10] = 'B'
+	} else {
+		body[10] = 'A'
+	}
+	lines[1] = string(body)
+	if _, err := Open(strings.Join(lines, "\n"), []byte("pw")); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("Open tampered block = %v, want ErrWrongPassphrase", err)
+	}
+}
+
+func TestOpenNotSealed(t *testing.T) {
+	if _, err := Open("just a note", []byte("pw")); !errors.Is(err, ErrNotSealed) {
+		t.Errorf("Open plain content = %v, want ErrNotSealed", err)
+	}
+}
+
+func TestSealEmptyPassphrase(t *testing.T) {
+	if _, err := Seal("secret", nil); !errors.Is(err, ErrEmptyPassphrase) {
+		t.Errorf("Seal with empty passphrase = %v, want ErrEmptyPassphrase", err)
+	}
+}
+
+func TestIsSealedTruncated(t *testing.T) {
+	sealed, _ := Seal(strings.Repeat("x", 1000), []byte("pw"))
+	if !IsSealed(sealed[:40]) {
+		t.Error("IsSealed should detect a truncated block")
+	}
+	if IsSealed("notes about " + beginLine) {
+		t.Error("IsSealed matched a block that doesn't start the content")
+	}
+}
+
+func TestCheckEditAppendedBlock(t *testing.T) {
+	sealed, _ := Seal("secret", []byte("pw"))
+	if err := CheckEdit("Keys", "plain"); err != nil {
+		t.Errorf("CheckEdit plain content = %v, want nil", err)
+	}
+	if err := CheckEdit("Keys", sealed); err == nil || !strings.Contains(err.Error(), "Keys is in the vault") {
+		t.Errorf("CheckEdit sealed content = %v, want a vault error", err)
+	}
+
+	// What an unchecked append would leave behind: a block that won't open
+	if _, err := Open(sealed+"\n- appended line\n", []byte("pw")); !errors.Is(err, ErrNotSealed) {
+		t.Errorf("Open appended block = %v, want ErrNotSealed", err)
+	}
+}
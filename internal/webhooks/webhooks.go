@@ -0,0 +1,167 @@
+// ABOUTME: Outgoing webhooks fired on note and sync events.
+// ABOUTME: Posts signed JSON payloads to configured URLs with retry, pausing hooks that fail.
+
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Event names delivered in the X-Memo-Event header and payload.
+const (
+	EventNoteCreated   = "note.created"
+	EventNoteUpdated   = "note.updated"
+	EventNoteDeleted   = "note.deleted"
+	EventSyncCompleted = "sync.completed"
+)
+
+// Hook is a configured webhook endpoint.
+type Hook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret,omitempty"` // Signs bodies with HMAC-SHA256 when set
+	Events []string `json:"events,omitempty"` // Empty means all events
+}
+
+// Wants reports whether the hook subscribes to event.
+func (h *Hook) Wants(event string) bool {
+	if len(h.Events) == 0 {
+		return true
+	}
+	for _, e := range h.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the JSON body posted to webhooks.
+type Payload struct {
+	Event     string    `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+	Data      any       `json:"data,omitempty"`
+}
+
+// Sender delivers payloads to webhook endpoints. A hook whose delivery
+// fails is skipped for Cooldown, so a batch write touching many notes
+// waits out a down endpoint once rather than once per note.
+type Sender struct {
+	Client   *http.Client
+	Attempts int
+	Backoff  time.Duration // Doubled after each failed attempt
+	Cooldown time.Duration
+
+	mu   sync.Mutex
+	down map[string]time.Time // Hook URL to when it may be tried again
+}
+
+// NewSender creates a sender with short timeouts suited to a CLI process.
+func NewSender() *Sender {
+	return &Sender{
+		Client:   &http.Client{Timeout: 5 * time.Second},
+		Attempts: 3,
+		Backoff:  500 * time.Millisecond,
+		Cooldown: 5 * time.Minute,
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed by secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send posts event to every hook subscribed to it. Each hook is tried
+// independently; failures are joined into the returned error. Hooks
+// cooling down after a failure are skipped without error.
+func (s *Sender) Send(hooks []*Hook, event string, data any) error {
+	body, err := json.Marshal(Payload{Event: event, Timestamp: time.Now().UTC(), Data: data})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	var errs []error
+	for _, h := range hooks {
+		if h == nil || h.URL == "" || !h.Wants(event) || s.coolingDown(h.URL) {
+			continue
+		}
+		if err := s.deliver(h, event, body); err != nil {
+			s.markDown(h.URL)
+			errs = append(errs, fmt.Errorf("webhook %s: %w (not retried for %v)", h.URL, err, s.Cooldown))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Sender) coolingDown(url string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Now().Before(s.down[url])
+}
+
+func (s *Sender) markDown(url string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down == nil {
+		s.down = make(map[string]time.Time)
+	}
+	s.down[url] = time.Now().Add(s.Cooldown)
+}
+
+// deliver posts body to a single hook, retrying network errors and 5xx/429 responses.
+func (s *Sender) deliver(h *Hook, event string, body []byte) error {
+	backoff := s.Backoff
+	var lastErr error
+	for attempt := 0; attempt < max(s.Attempts, 1); attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		retry, err := s.post(h, event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+// post makes one delivery attempt and reports whether a failure is retryable.
+func (s *Sender) post(h *Hook, event string, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "memo-webhook")
+	req.Header.Set("X-Memo-Event", event)
+	if h.Secret != "" {
+		req.Header.Set("X-Memo-Signature", "sha256="+Sign(h.Secret, body))
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("status %d", resp.StatusCode)
+}
@@ -0,0 +1,124 @@
+// ABOUTME: Tests for outgoing webhook delivery.
+// ABOUTME: Validates signing, event filtering, retry behavior, and cooldown after failures.
+
+package webhooks
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testSender() *Sender {
+	s := NewSender()
+	s.Backoff = 0
+	return s
+}
+
+func TestSendSignsPayload(t *testing.T) {
+	var gotSig, gotEvent string
+	var payload Payload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotEvent = r.Header.Get("X-Memo-Event")
+		gotSig = r.Header.Get("X-Memo-Signature")
+		if gotSig != "sha256="+Sign("s3cret", body) {
+			t.Errorf("signature %q does not match body", gotSig)
+		}
+		_ = json.Unmarshal(body, &payload)
+	}))
+	defer srv.Close()
+
+	hooks := []*Hook{{URL: srv.URL, Secret: "s3cret"}}
+	if err := testSender().Send(hooks, EventNoteCreated, map[string]string{"id": "abc"}); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if gotEvent != EventNoteCreated || payload.Event != EventNoteCreated {
+		t.Errorf("unexpected event: header %q, payload %q", gotEvent, payload.Event)
+	}
+	if data, _ := payload.Data.(map[string]any); data["id"] != "abc" {
+		t.Errorf("unexpected data: %v", payload.Data)
+	}
+}
+
+func TestSendFiltersEvents(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer srv.Close()
+
+	hooks := []*Hook{{URL: srv.URL, Events: []string{EventNoteDeleted}}}
+	if err := testSender().Send(hooks, EventNoteCreated, nil); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("expected filtered event not to be delivered, got %d calls", calls.Load())
+	}
+}
+
+func TestSendRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	if err := testSender().Send([]*Hook{{URL: srv.URL}}, EventSyncCompleted, nil); err != nil {
+		t.Fatalf("expected success after retries: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+}
+
+func TestSendDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	if err := testSender().Send([]*Hook{{URL: srv.URL}}, EventNoteUpdated, nil); err == nil {
+		t.Error("expected error for 404 response")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 attempt, got %d", calls.Load())
+	}
+}
+
+func TestSendSkipsFailedHookDuringCooldown(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	s := testSender()
+	hooks := []*Hook{{URL: srv.URL}}
+	if err := s.Send(hooks, EventNoteUpdated, nil); err == nil {
+		t.Fatal("expected error for 503 response")
+	}
+	// The rest of a batch: skipped without waiting or reporting again
+	for i := 0; i < 5; i++ {
+		if err := s.Send(hooks, EventNoteUpdated, nil); err != nil {
+			t.Errorf("send during cooldown = %v, want nil", err)
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts for the first note only, got %d", calls.Load())
+	}
+
+	s.down[srv.URL] = time.Now().Add(-time.Second) // Cooldown over
+	if err := s.Send(hooks, EventNoteUpdated, nil); err == nil {
+		t.Error("expected the hook to be tried again once the cooldown is over")
+	}
+}
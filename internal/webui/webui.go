@@ -0,0 +1,207 @@
+// ABOUTME: Lightweight web front end for browsing, searching, and adding notes.
+// ABOUTME: Serves embedded templates over a charm.Repository, guarded by an access token cookie.
+
+package webui
+
+import (
+	"crypto/subtle"
+	"embed"
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/mdhtml"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/ui"
+	"github.com/harper/memo/internal/vault"
+)
+
+//go:embed templates static
+var assets embed.FS
+
+const (
+	tokenCookie  = "memo_token"
+	listLimit    = 50
+	excerptRunes = 160
+)
+
+// Server is the web UI's HTTP handler.
+type Server struct {
+	repo  charm.Repository
+	token string
+	tmpl  *template.Template
+	mux   *http.ServeMux
+}
+
+// NewServer creates a web UI over repo. When token is non-empty, every
+// request must carry it, either as ?token= (which sets a cookie) or in
+// the cookie.
+func NewServer(repo charm.Repository, token string) *Server {
+	s := &Server{
+		repo:  repo,
+		token: token,
+		tmpl: template.Must(template.New("").Funcs(template.FuncMap{
+			"ago": func(t time.Time) string { return ui.FormatRelativeTime(t, time.Now()) },
+		}).ParseFS(assets, "templates/*.html")),
+		mux: http.NewServeMux(),
+	}
+
+	static, _ := fs.Sub(assets, "static")
+	s.mux.Handle("GET /static/", http.StripPrefix("/static/", http.FileServerFS(static)))
+	s.mux.HandleFunc("GET /{$}", s.handleList)
+	s.mux.HandleFunc("GET /notes/{id}", s.handleNote)
+	s.mux.HandleFunc("POST /notes", s.handleAdd)
+	return s
+}
+
+// ServeHTTP checks the access token and dispatches the request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.token != "" {
+		if t := r.URL.Query().Get("token"); t != "" && s.validToken(t) {
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookie,
+				Value:    t,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+				MaxAge:   int((90 * 24 * time.Hour).Seconds()),
+			})
+			q := r.URL.Query()
+			q.Del("token")
+			r.URL.RawQuery = q.Encode()
+			http.Redirect(w, r, r.URL.String(), http.StatusSeeOther)
+			return
+		}
+		c, err := r.Cookie(tokenCookie)
+		if err != nil || !s.validToken(c.Value) {
+			s.render(w, http.StatusUnauthorized, "error.html", errorPage{
+				Title:   "Access token required",
+				Message: "Open the link printed by `memo serve`, which includes the token.",
+			})
+			return
+		}
+	}
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; img-src 'self' https: data:")
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) validToken(t string) bool {
+	return subtle.ConstantTimeCompare([]byte(t), []byte(s.token)) == 1
+}
+
+type listItem struct {
+	ID      string
+	Title   string
+	Excerpt string
+	Tags    []string
+	Updated time.Time
+}
+
+type listPage struct {
+	Query string
+	Tag   string
+	Notes []listItem
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	page := listPage{Query: strings.TrimSpace(r.URL.Query().Get("q")), Tag: strings.TrimSpace(r.URL.Query().Get("tag"))}
+	filter := &charm.NoteFilter{Search: page.Query, Limit: listLimit, ContentLimit: excerptRunes}
+	if page.Tag != "" {
+		filter.Tag = &page.Tag
+	}
+
+	notes, err := s.repo.ListNotesWithTags(filter)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	for _, nt := range notes {
+		item := listItem{
+			ID:      nt.Note.ID.String(),
+			Title:   nt.Note.Title,
+			Tags:    nt.Tags,
+			Updated: nt.Note.UpdatedAt,
+		}
+		// Vault notes list by title only: their content is ciphertext
+		if !vault.IsSealed(nt.Note.Content) {
+			item.Excerpt = strings.Join(strings.Fields(nt.Note.Content), " ")
+		}
+		page.Notes = append(page.Notes, item)
+	}
+	s.render(w, http.StatusOK, "list.html", page)
+}
+
+type notePage struct {
+	Note *models.Note
+	Tags []string
+	Body template.HTML
+}
+
+func (s *Server) handleNote(w http.ResponseWriter, r *http.Request) {
+	note, tags, err := s.repo.GetNoteByPrefix(r.PathValue("id"))
+	if errors.Is(err, charm.ErrNoteNotFound) || errors.Is(err, charm.ErrPrefixTooShort) || errors.Is(err, charm.ErrAmbiguousPrefix) {
+		s.render(w, http.StatusNotFound, "error.html", errorPage{Title: "Not found", Message: "No note with that ID."})
+		return
+	}
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	content := note.Content
+	if vault.IsSealed(content) {
+		content = "*This note is in the vault. Run `memo vault show " + note.ID.String()[:6] + "` to read it.*"
+	}
+	body, err := mdhtml.Render(content)
+	if err != nil {
+		s.fail(w, err)
+		return
+	}
+	// mdhtml omits raw HTML and unsafe link schemes, so its output is trusted
+	s.render(w, http.StatusOK, "note.html", notePage{Note: note, Tags: tags, Body: template.HTML(body)}) //nolint:gosec // See above
+}
+
+func (s *Server) handleAdd(w http.ResponseWriter, r *http.Request) {
+	title := strings.TrimSpace(r.FormValue("title"))
+	content := r.FormValue("content")
+	if strings.TrimSpace(content) == "" {
+		s.render(w, http.StatusBadRequest, "error.html", errorPage{Title: "Empty note", Message: "Note content cannot be empty."})
+		return
+	}
+	if title == "" {
+		title, _, _ = strings.Cut(strings.TrimSpace(content), "\n")
+	}
+
+	var tags []string
+	for _, t := range strings.Split(r.FormValue("tags"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+
+	note := models.NewNote(title, content)
+	if err := s.repo.CreateNote(note, tags); err != nil {
+		s.render(w, http.StatusBadRequest, "error.html", errorPage{Title: "Couldn't add note", Message: err.Error()})
+		return
+	}
+	http.Redirect(w, r, "/notes/"+note.ID.String(), http.StatusSeeOther)
+}
+
+type errorPage struct {
+	Title   string
+	Message string
+}
+
+func (s *Server) fail(w http.ResponseWriter, err error) {
+	s.render(w, http.StatusInternalServerError, "error.html", errorPage{Title: "Something went wrong", Message: err.Error()})
+}
+
+func (s *Server) render(w http.ResponseWriter, status int, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = s.tmpl.ExecuteTemplate(w, name, data)
+}
@@ -0,0 +1,120 @@
+// ABOUTME: Tests for the web UI handlers against an in-memory repository.
+// ABOUTME: Covers the token cookie, listing, search, rendering, vault notes, and quick add.
+
+package webui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/harper/memo/internal/charm/charmtest"
+	"github.com/harper/memo/internal/models"
+	"github.com/harper/memo/internal/vault"
+)
+
+func get(t *testing.T, h http.Handler, target string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestToken(t *testing.T) {
+	s := NewServer(charmtest.NewStore(), "secret")
+
+	if rec := get(t, s, "/"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without token, got %d", rec.Code)
+	}
+	if rec := get(t, s, "/?token=wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with wrong token, got %d", rec.Code)
+	}
+
+	rec := get(t, s, "/?token=secret&q=plan")
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/?q=plan" {
+		t.Fatalf("expected redirect dropping the token, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value != "secret" {
+		t.Fatalf("expected token cookie, got %v", cookies)
+	}
+	if rec := get(t, s, "/", cookies[0]); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 with cookie, got %d", rec.Code)
+	}
+}
+
+func TestListAndShow(t *testing.T) {
+	store := charmtest.NewStore()
+	plan := models.NewNote("Q3 plan", "# Goals\n\n- ship **sync**\n\n<script>alert(1)</script>")
+	_ = store.CreateNote(plan, []string{"work"})
+	_ = store.CreateNote(models.NewNote("Groceries", "eggs"), nil)
+	s := NewServer(store, "")
+
+	body := get(t, s, "/").Body.String()
+	if !strings.Contains(body, "Q3 plan") || !strings.Contains(body, "Groceries") {
+		t.Errorf("list missing notes:\n%s", body)
+	}
+	body = get(t, s, "/?tag=work").Body.String()
+	if !strings.Contains(body, "Q3 plan") || strings.Contains(body, "Groceries") {
+		t.Errorf("tag filter not applied:\n%s", body)
+	}
+
+	rec := get(t, s, "/notes/"+plan.ID.String())
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<strong>sync</strong>") {
+		t.Errorf("expected rendered note, got %d:\n%s", rec.Code, rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "<script>") {
+		t.Error("rendered note kept raw HTML")
+	}
+	if rec := get(t, s, "/notes/ffffffff"); rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestSealedNoteHidesCiphertext(t *testing.T) {
+	store := charmtest.NewStore()
+	sealed, err := vault.Seal("api key", []byte("pw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := models.NewNote("Keys", sealed)
+	_ = store.CreateNote(keys, nil)
+	s := NewServer(store, "")
+
+	body := get(t, s, "/").Body.String()
+	if !strings.Contains(body, "Keys") || strings.Contains(body, "MEMO VAULT") {
+		t.Errorf("list should show the title without ciphertext:\n%s", body)
+	}
+	body = get(t, s, "/notes/"+keys.ID.String()).Body.String()
+	if strings.Contains(body, "MEMO VAULT") || !strings.Contains(body, "memo vault show") {
+		t.Errorf("note page should point to 'memo vault show':\n%s", body)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	store := charmtest.NewStore()
+	s := NewServer(store, "")
+
+	form := url.Values{"content": {"Call the bank\nabout the card"}, "tags": {"todo, home"}}
+	req := httptest.NewRequest(http.MethodPost, "/notes", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected redirect, got %d:\n%s", rec.Code, rec.Body.String())
+	}
+
+	notes, _ := store.ListNotesWithTags(nil)
+	if len(notes) != 1 || notes[0].Note.Title != "Call the bank" || len(notes[0].Tags) != 2 {
+		t.Fatalf("unexpected notes: %+v", notes)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/notes/"+notes[0].Note.ID.String() {
+		t.Errorf("Location = %q", loc)
+	}
+}
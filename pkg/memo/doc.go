@@ -0,0 +1,29 @@
+// ABOUTME: Package documentation and API version for the public memo library.
+// ABOUTME: Describes the compatibility promise for programs embedding memo's store.
+
+// Package memo is the public Go API to memo's note store, for programs that
+// want to read and write notes without exec'ing the memo binary.
+//
+// A Store opens the same Charm KV database the CLI uses, so notes written
+// through it show up in `memo list` and sync like any other:
+//
+//	store, err := memo.Open()
+//	if err != nil {
+//		return err
+//	}
+//	defer store.Close()
+//
+//	note, err := store.Create("Standup", "- shipped sync", "work")
+//	...
+//	notes, err := store.List(memo.ListOptions{Search: "sync", Limit: 10})
+//
+// # Compatibility
+//
+// The types and methods in this package follow semantic versioning by
+// APIVersion: within a major version they only gain fields, methods, and
+// options, never lose or change them. Everything under internal/ may change
+// at any time and is reached only through this package.
+package memo
+
+// APIVersion is the version of this package's API.
+const APIVersion = "1.0.0"
@@ -0,0 +1,300 @@
+// ABOUTME: Public Store API over memo's Charm KV note storage.
+// ABOUTME: Converts between internal models and the stable Note, Tag, and Attachment types.
+
+package memo
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harper/memo/internal/charm"
+	"github.com/harper/memo/internal/models"
+)
+
+// Errors returned by Store methods; compare with errors.Is.
+var (
+	ErrNotFound           = charm.ErrNoteNotFound
+	ErrAmbiguousPrefix    = charm.ErrAmbiguousPrefix
+	ErrPrefixTooShort     = charm.ErrPrefixTooShort
+	ErrAttachmentNotFound = charm.ErrAttachmentNotFound
+	ErrAttachmentTooLarge = charm.ErrAttachmentTooLarge
+)
+
+// Note is a markdown note with its tags.
+type Note struct {
+	ID        uuid.UUID
+	Title     string
+	Content   string
+	Tags      []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Tag is a tag name and how many notes carry it.
+type Tag struct {
+	Name  string
+	Count int
+}
+
+// Attachment is a file stored with a note.
+type Attachment struct {
+	ID        uuid.UUID
+	NoteID    uuid.UUID
+	Filename  string
+	MimeType  string
+	Data      []byte
+	CreatedAt time.Time
+	Missing   bool // Data isn't on this device; Data is nil
+}
+
+// ListOptions narrows List. The zero value lists every note.
+type ListOptions struct {
+	Tag    string    // Only notes with this tag
+	Search string    // Only notes whose title or content contains this
+	Since  time.Time // Only notes created at or after this time
+	Until  time.Time // Only notes created before this time
+	Limit  int       // At most this many notes (0 = no limit)
+	Offset int       // Skip this many matches first
+}
+
+// Store reads and writes notes in a memo database.
+type Store struct {
+	repo   charm.Repository
+	client *charm.Client // Nil for stores without sync, like tests
+}
+
+// Option configures Open.
+type Option func(*openOptions)
+
+type openOptions struct {
+	dbName   string
+	autoSync *bool
+}
+
+// WithDatabase opens a named database instead of the default one, like
+// `memo --db`.
+func WithDatabase(name string) Option {
+	return func(o *openOptions) { o.dbName = name }
+}
+
+// WithAutoSync overrides the auto_sync setting: whether each write is
+// synced to the Charm server right away.
+func WithAutoSync(enabled bool) Option {
+	return func(o *openOptions) { o.autoSync = &enabled }
+}
+
+// Open opens the memo database using the user's memo config.
+func Open(opts ...Option) (*Store, error) {
+	var o openOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var clientOpts []charm.Option
+	if o.dbName != "" {
+		if err := charm.ValidateDBName(o.dbName); err != nil {
+			return nil, err
+		}
+		clientOpts = append(clientOpts, charm.WithDBName(o.dbName))
+	}
+	if o.autoSync != nil {
+		clientOpts = append(clientOpts, charm.WithAutoSync(*o.autoSync))
+	}
+
+	client, err := charm.NewClient(clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{repo: client, client: client}, nil
+}
+
+// newStore wraps a repository without sync support.
+func newStore(repo charm.Repository) *Store {
+	return &Store{repo: repo}
+}
+
+// Close releases the store.
+func (s *Store) Close() error {
+	if s.client == nil {
+		return nil
+	}
+	return s.client.Close()
+}
+
+// Sync pushes and pulls changes with the Charm server.
+func (s *Store) Sync() error {
+	if s.client == nil {
+		return nil
+	}
+	return s.client.Sync()
+}
+
+// Create adds a note.
+func (s *Store) Create(title, content string, tags ...string) (*Note, error) {
+	m := models.NewNote(title, content)
+	if err := s.repo.CreateNote(m, tags); err != nil {
+		return nil, err
+	}
+	return s.GetByID(m.ID)
+}
+
+// Get finds a note by ID prefix (at least 6 characters) or alias.
+func (s *Store) Get(ref string) (*Note, error) {
+	m, tags, err := s.repo.GetNoteByPrefix(ref)
+	if err != nil {
+		return nil, err
+	}
+	return newNote(m, tags), nil
+}
+
+// GetByID finds a note by its full ID.
+func (s *Store) GetByID(id uuid.UUID) (*Note, error) {
+	m, tags, err := s.repo.GetNoteByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return newNote(m, tags), nil
+}
+
+// Update saves a note's title, content, and tags, and sets UpdatedAt.
+func (s *Store) Update(n *Note) error {
+	m := n.model()
+	m.Touch()
+	if err := s.repo.UpdateNote(m, n.Tags); err != nil {
+		return err
+	}
+	n.UpdatedAt = m.UpdatedAt
+	return nil
+}
+
+// Delete removes a note with its attachments and aliases.
+func (s *Store) Delete(id uuid.UUID) error {
+	return s.repo.DeleteNote(id)
+}
+
+// List returns notes matching opts, most recently updated first.
+func (s *Store) List(opts ListOptions) ([]*Note, error) {
+	filter := &charm.NoteFilter{
+		Search: opts.Search,
+		Since:  opts.Since,
+		Until:  opts.Until,
+		Limit:  opts.Limit,
+		Offset: opts.Offset,
+	}
+	if opts.Tag != "" {
+		filter.Tag = &opts.Tag
+	}
+	listed, err := s.repo.ListNotesWithTags(filter)
+	if err != nil {
+		return nil, err
+	}
+	notes := make([]*Note, len(listed))
+	for i, nt := range listed {
+		notes[i] = newNote(nt.Note, nt.Tags)
+	}
+	return notes, nil
+}
+
+// AddTag tags a note.
+func (s *Store) AddTag(noteID uuid.UUID, tag string) error {
+	return s.repo.AddTagToNote(noteID, tag)
+}
+
+// RemoveTag untags a note.
+func (s *Store) RemoveTag(noteID uuid.UUID, tag string) error {
+	return s.repo.RemoveTagFromNote(noteID, tag)
+}
+
+// Tags returns every tag in use with its note count, sorted by name.
+func (s *Store) Tags() ([]Tag, error) {
+	listed, err := s.repo.ListNotesWithTags(&charm.NoteFilter{ContentLimit: charm.NoContent})
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, nt := range listed {
+		for _, t := range nt.Tags {
+			counts[t]++
+		}
+	}
+	tags := make([]Tag, 0, len(counts))
+	for name, n := range counts {
+		tags = append(tags, Tag{Name: name, Count: n})
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// Attach stores a file with a note. Data over the max_attachment_size
+// setting is rejected with ErrAttachmentTooLarge.
+func (s *Store) Attach(noteID uuid.UUID, filename, mimeType string, data []byte) (*Attachment, error) {
+	if _, _, err := s.repo.GetNoteByID(noteID); err != nil {
+		return nil, err
+	}
+	m := models.NewAttachment(noteID, filename, mimeType, data)
+	if err := s.repo.CreateAttachment(m); err != nil {
+		return nil, err
+	}
+	return newAttachment(m), nil
+}
+
+// Attachments returns a note's attachments.
+func (s *Store) Attachments(noteID uuid.UUID) ([]*Attachment, error) {
+	listed, err := s.repo.ListAttachmentsByNote(noteID)
+	if err != nil {
+		return nil, err
+	}
+	atts := make([]*Attachment, len(listed))
+	for i, m := range listed {
+		atts[i] = newAttachment(m)
+	}
+	return atts, nil
+}
+
+// GetAttachment finds an attachment by ID prefix (at least 6 characters).
+func (s *Store) GetAttachment(ref string) (*Attachment, error) {
+	m, err := s.repo.GetAttachmentByPrefix(ref)
+	if err != nil {
+		return nil, err
+	}
+	return newAttachment(m), nil
+}
+
+// DeleteAttachment removes an attachment.
+func (s *Store) DeleteAttachment(id uuid.UUID) error {
+	return s.repo.DeleteAttachment(id)
+}
+
+func newNote(m *models.Note, tags []string) *Note {
+	return &Note{
+		ID:        m.ID,
+		Title:     m.Title,
+		Content:   m.Content,
+		Tags:      append([]string(nil), tags...),
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+func (n *Note) model() *models.Note {
+	return &models.Note{
+		ID:        n.ID,
+		Title:     n.Title,
+		Content:   n.Content,
+		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
+	}
+}
+
+func newAttachment(m *models.Attachment) *Attachment {
+	return &Attachment{
+		ID:        m.ID,
+		NoteID:    m.NoteID,
+		Filename:  m.Filename,
+		MimeType:  m.MimeType,
+		Data:      m.Data,
+		CreatedAt: m.CreatedAt,
+		Missing:   m.Missing,
+	}
+}
@@ -0,0 +1,94 @@
+// ABOUTME: Tests for the public Store API against an in-memory repository.
+// ABOUTME: Covers note CRUD, listing, tags, and attachments through the public types.
+
+package memo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/harper/memo/internal/charm/charmtest"
+)
+
+func TestStoreNotes(t *testing.T) {
+	s := newStore(charmtest.NewStore())
+
+	n, err := s.Create("Standup", "- shipped sync", "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Title != "Standup" || len(n.Tags) != 1 || n.Tags[0] != "work" {
+		t.Fatalf("unexpected note %+v", n)
+	}
+	if _, err := s.Create("Groceries", "eggs"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Get(n.ID.String()[:8])
+	if err != nil || got.ID != n.ID {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+
+	got.Content = "- shipped sync\n- fixed tags"
+	got.Tags = append(got.Tags, "done")
+	before := got.UpdatedAt
+	if err := s.Update(got); err != nil {
+		t.Fatal(err)
+	}
+	if got.UpdatedAt.Before(before) {
+		t.Error("Update didn't advance UpdatedAt")
+	}
+
+	listed, err := s.List(ListOptions{Tag: "done"})
+	if err != nil || len(listed) != 1 || listed[0].Content != got.Content {
+		t.Fatalf("List by tag = %+v, %v", listed, err)
+	}
+	if listed, _ := s.List(ListOptions{Search: "eggs"}); len(listed) != 1 || listed[0].Title != "Groceries" {
+		t.Errorf("List by search = %+v", listed)
+	}
+
+	tags, err := s.Tags()
+	if err != nil || len(tags) != 2 || tags[0] != (Tag{Name: "done", Count: 1}) {
+		t.Errorf("Tags = %+v, %v", tags, err)
+	}
+
+	if err := s.Delete(n.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetByID(n.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestStoreAttachments(t *testing.T) {
+	s := newStore(charmtest.NewStore())
+	n, err := s.Create("Trip", "itinerary attached")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	att, err := s.Attach(n.ID, "plan.txt", "text/plain", []byte("day 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed, err := s.Attachments(n.ID)
+	if err != nil || len(listed) != 1 || string(listed[0].Data) != "day 1" {
+		t.Fatalf("Attachments = %+v, %v", listed, err)
+	}
+	if got, err := s.GetAttachment(att.ID.String()[:8]); err != nil || got.Filename != "plan.txt" {
+		t.Errorf("GetAttachment = %+v, %v", got, err)
+	}
+	if err := s.DeleteAttachment(att.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Attach(n.ID, "x", "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Delete(n.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Attach(n.ID, "y", "", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound attaching to a deleted note, got %v", err)
+	}
+}